@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+var alternativesCmd = &cobra.Command{
+	Use:   "alternatives",
+	Short: "Choose which tool provides a binary name",
+	Long: `Manage binaries that more than one installed tool provides.
+
+Each entry in $TSUKU_HOME/tools/current belongs to one tool. When a tool is
+installed with a binary that another tool already provides, the existing
+owner keeps it and tsuku prints a warning. Use "tsuku alternatives set" to
+switch the provider. Removing the owner hands the binary back to the
+previous provider.`,
+}
+
+var alternativesListCmd = &cobra.Command{
+	Use:   "list [binary]",
+	Short: "List binaries provided by more than one tool",
+	Long: `List binaries that more than one installed tool provides, marking the
+active provider with *. With a binary name, list its providers even if there
+is only one.`,
+	Example: `  tsuku alternatives list
+  tsuku alternatives list go --json`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runAlternativesList,
+}
+
+var alternativesSetCmd = &cobra.Command{
+	Use:   "set <binary> <tool>",
+	Short: "Make a tool the provider of a binary",
+	Example: `  tsuku alternatives set go golang
+  tsuku alternatives set gitleaks gitleaks-cli`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		binary, tool := args[0], args[1]
+
+		cfg, err := config.DefaultConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get config: %v\n", err)
+			exitWithCode(ExitGeneral)
+		}
+
+		if err := install.New(cfg).SetAlternative(binary, tool); err != nil {
+			printError(err)
+			exitWithCode(ExitGeneral)
+		}
+		printInfof("%s now provided by %s\n", binary, tool)
+	},
+}
+
+func init() {
+	alternativesListCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	alternativesCmd.AddCommand(alternativesListCmd)
+	alternativesCmd.AddCommand(alternativesSetCmd)
+}
+
+// alternativeOutput is one binary in the output of "tsuku alternatives list"
+type alternativeOutput struct {
+	Binary    string   `json:"binary"`
+	Active    string   `json:"active"`
+	Providers []string `json:"providers"`
+}
+
+func runAlternativesList(cmd *cobra.Command, args []string) {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	state := loadInstallState()
+	var binary string
+	if len(args) == 1 {
+		binary = args[0]
+		if _, ok := state.Alternatives[binary]; !ok {
+			fmt.Fprintf(os.Stderr, "No installed tool provides %s\n", binary)
+			exitWithCode(ExitGeneral)
+		}
+	}
+
+	alternatives := newAlternativesOutput(state.Alternatives, binary)
+	if jsonOutput {
+		printJSON(alternatives)
+		return
+	}
+	if len(alternatives) == 0 {
+		printInfo("No binary is provided by more than one tool.")
+		return
+	}
+	writeAlternatives(os.Stdout, alternatives)
+}
+
+// newAlternativesOutput returns binary's providers, or every binary with more
+// than one provider when binary is empty, sorted by binary name
+func newAlternativesOutput(alternatives map[string]install.BinaryAlternatives, binary string) []alternativeOutput {
+	output := []alternativeOutput{}
+	for name, alt := range alternatives {
+		if binary != "" && name != binary {
+			continue
+		}
+		if binary == "" && len(alt.Providers) < 2 {
+			continue
+		}
+		output = append(output, alternativeOutput{Binary: name, Active: alt.Active, Providers: alt.Providers})
+	}
+	sort.Slice(output, func(i, j int) bool { return output[i].Binary < output[j].Binary })
+	return output
+}
+
+func writeAlternatives(w io.Writer, alternatives []alternativeOutput) {
+	for _, alt := range alternatives {
+		fmt.Fprintln(w, alt.Binary)
+		for _, p := range alt.Providers {
+			marker := " "
+			if p == alt.Active {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "  %s %s\n", marker, p)
+		}
+	}
+}
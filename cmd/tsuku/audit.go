@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/audit"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/sbom"
+)
+
+var auditDB string
+var auditUpdate bool
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [tools...]",
+	Short: "Check installed tools for known vulnerabilities",
+	Long: `Check installed tools against the OSV vulnerability database.
+
+Tools installed through npm, PyPI, crates.io, RubyGems or Go modules are
+identified by their package URL, and the packages pinned by their captured
+lockfiles (package-lock.json, Python requirements, Cargo.lock,
+Gemfile.lock, go.sum) are checked along with them. Tools installed from
+release binaries carry no package information and are not audited.
+
+By default the advisories of the ecosystems in use are downloaded to
+$TSUKU_HOME/cache/osv on first use; --update refreshes them. With --db,
+a local mirror is used instead: a directory holding one subdirectory per
+OSV ecosystem (npm, PyPI, crates.io, RubyGems, Go) with either the
+all.zip archive published by OSV or the advisories as JSON files.
+
+Exits with code 7 when a vulnerability is found, so the command can gate
+CI pipelines.`,
+	Example: `  tsuku audit
+  tsuku audit ripgrep prettier
+  tsuku audit --update --json
+  tsuku audit --db /srv/osv-mirror`,
+	Run: runAudit,
+}
+
+func init() {
+	auditCmd.Flags().StringVar(&auditDB, "db", "", "Use a local OSV database directory instead of the cache")
+	auditCmd.Flags().BoolVar(&auditUpdate, "update", false, "Download the latest advisories before auditing")
+	auditCmd.Flags().Bool("json", false, "Output in JSON format")
+}
+
+func runAudit(cmd *cobra.Command, args []string) {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if auditDB != "" && auditUpdate {
+		printError(fmt.Errorf("--update cannot be used with --db"))
+		exitWithCode(ExitUsage)
+	}
+
+	doc, err := sbom.Build(loadInstallState(), args)
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitGeneral)
+	}
+
+	dir := auditDB
+	if dir == "" {
+		cfg, err := config.DefaultConfig()
+		if err != nil {
+			printError(fmt.Errorf("failed to get config: %w", err))
+			exitWithCode(ExitGeneral)
+		}
+		dir = cfg.OSVCacheDir
+
+		ecosystems := audit.RequiredEcosystems(doc)
+		if !auditUpdate {
+			ecosystems = audit.MissingArchives(dir, ecosystems)
+		}
+		if len(ecosystems) > 0 {
+			if !jsonOutput {
+				printInfof("Downloading advisories for %s...\n", strings.Join(ecosystems, ", "))
+			}
+			if err := audit.Fetch(audit.DefaultOSVURL, dir, ecosystems); err != nil {
+				printError(err)
+				exitWithCode(ExitNetwork)
+			}
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			printError(err)
+			exitWithCode(ExitGeneral)
+		}
+	}
+
+	db, err := audit.OpenDatabase(dir)
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitGeneral)
+	}
+	report, err := audit.Audit(doc, db)
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitGeneral)
+	}
+
+	if jsonOutput {
+		printJSON(report)
+	} else {
+		printAuditReport(report)
+	}
+
+	if report.Vulnerable() {
+		exitWithCode(ExitVerifyFailed)
+	}
+}
+
+func printAuditReport(report *audit.Report) {
+	if report.Tools == 0 {
+		printInfo("No installed tools carry package information to audit.")
+		return
+	}
+	if !report.Vulnerable() {
+		printInfof("No known vulnerabilities in %d packages of %d tools.\n", report.Packages, report.Tools)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tPACKAGE\tVERSION\tADVISORY\tFIXED IN")
+	for _, f := range report.Findings {
+		fixed := strings.Join(f.Fixed, ", ")
+		if fixed == "" {
+			fixed = "-"
+		}
+		id := f.ID
+		if len(f.Aliases) > 0 {
+			id += " (" + strings.Join(f.Aliases, ", ") + ")"
+		}
+		fmt.Fprintf(w, "%s@%s\t%s\t%s\t%s\t%s\n", f.Tool, f.ToolVersion, f.Name, f.Version, id, fixed)
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Printf("%d vulnerabilities found in %s (%d packages checked)\n",
+		len(report.Findings), strings.Join(report.VulnerableTools(), ", "), report.Packages)
+}
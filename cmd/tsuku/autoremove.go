@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+var autoremoveCmd = &cobra.Command{
+	Use:   "autoremove",
+	Short: "Remove dependencies no longer needed by any installed tool",
+	Long: `Remove hidden dependencies and libraries that are no longer required by
+any explicitly installed tool.
+
+Execution dependencies (nodejs, python, rust toolchains) and libraries are
+installed on demand and stay behind when the tools that needed them are
+removed. autoremove lists them with the disk space they use and asks for
+confirmation before deleting them.
+
+Use "tsuku why <tool>" to see why a dependency is still kept.`,
+	Example: `  tsuku autoremove
+  tsuku autoremove --dry-run
+  tsuku autoremove --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		cfg, err := config.DefaultConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get config: %v\n", err)
+			exitWithCode(ExitGeneral)
+		}
+		mgr := install.New(cfg)
+
+		plan, release, err := planAndLockRemoval(cfg, mgr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to find orphaned dependencies: %v\n", err)
+			exitWithCode(ExitGeneral)
+		}
+		defer release()
+		if plan.IsEmpty() {
+			printInfo("No orphaned dependencies to remove.")
+			return
+		}
+
+		size := mgr.RemovalSize(plan)
+		if !confirmRemovalPlan(plan, size, dryRun, yes) {
+			return
+		}
+		applyRemovalPlan(mgr, plan, size)
+	},
+}
+
+func init() {
+	autoremoveCmd.Flags().Bool("dry-run", false, "List what would be removed without removing it")
+	autoremoveCmd.Flags().BoolP("yes", "y", false, "Remove without asking for confirmation")
+}
+
+// writeRemovalPlan lists the tools and library versions in a removal plan
+func writeRemovalPlan(w io.Writer, plan *install.RemovalPlan, size int64) {
+	fmt.Fprintln(w, "The following will be removed:")
+	for _, name := range plan.Tools {
+		fmt.Fprintf(w, "  %s\n", name)
+	}
+	for _, lib := range plan.Libraries {
+		fmt.Fprintf(w, "  %s@%s (library)\n", lib.Name, lib.Version)
+	}
+	fmt.Fprintf(w, "\nDisk space reclaimed: %s\n", formatBytes(size))
+}
+
+// confirmRemovalPlan shows a removal plan and returns true if it should be
+// applied. Non-interactive sessions must pass --yes.
+func confirmRemovalPlan(plan *install.RemovalPlan, size int64, dryRun, yes bool) bool {
+	writeRemovalPlan(os.Stdout, plan, size)
+	if dryRun {
+		return false
+	}
+	if yes {
+		return true
+	}
+	if !isInteractive() {
+		fmt.Fprintf(os.Stderr, "Use --yes to remove without confirmation.\n")
+		exitWithCode(ExitUsage)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprint(os.Stderr, "Proceed with removal? [y/N] ")
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// applyRemovalPlan removes everything in plan and reports the result
+func applyRemovalPlan(mgr *install.Manager, plan *install.RemovalPlan, size int64) {
+	if err := mgr.ApplyRemoval(plan); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to remove: %v\n", err)
+		exitWithCode(ExitGeneral)
+	}
+	printInfof("Removed %d tool(s) and %d library version(s), reclaimed %s\n",
+		len(plan.Tools), len(plan.Libraries), formatBytes(size))
+}
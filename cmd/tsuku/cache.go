@@ -7,28 +7,34 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/tsukumogami/tsuku/internal/actions"
 	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/userconfig"
 	"github.com/tsukumogami/tsuku/internal/version"
 )
 
 var cacheCmd = &cobra.Command{
 	Use:   "cache",
 	Short: "Manage tsuku caches",
-	Long:  `Manage tsuku caches including download and version caches.`,
+	Long:  `Manage tsuku caches including download, version, binary and compiler caches.`,
 }
 
 var cacheClearCmd = &cobra.Command{
 	Use:   "clear",
 	Short: "Clear all caches",
-	Long: `Clear all tsuku caches (downloads and versions).
+	Long: `Clear all tsuku caches (downloads, versions, cached builds and
+compiler caches).
 
-This removes cached downloads and version information,
-forcing fresh downloads and version lookups on next use.`,
+This removes cached downloads, version information, prebuilt
+install trees and ccache/sccache data, forcing fresh downloads,
+version lookups and builds on next use.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		downloadsOnly, _ := cmd.Flags().GetBool("downloads")
 		versionsOnly, _ := cmd.Flags().GetBool("versions")
+		artifactsOnly, _ := cmd.Flags().GetBool("builds")
+		compilerOnly, _ := cmd.Flags().GetBool("compiler")
 
 		// If no specific flag, clear all
-		clearAll := !downloadsOnly && !versionsOnly
+		clearAll := !downloadsOnly && !versionsOnly && !artifactsOnly && !compilerOnly
 
 		cfg, err := config.DefaultConfig()
 		if err != nil {
@@ -53,6 +59,22 @@ forcing fresh downloads and version lookups on next use.`,
 			}
 			fmt.Println("Version cache cleared")
 		}
+
+		if clearAll || artifactsOnly {
+			if err := executor.NewArtifactCache(cfg.ArtifactCacheDir, "").Clear(); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to clear binary cache: %v\n", err)
+				exitWithCode(ExitGeneral)
+			}
+			fmt.Println("Binary cache cleared")
+		}
+
+		if clearAll || compilerOnly {
+			if err := os.RemoveAll(cfg.BuildCacheDir); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to clear compiler cache: %v\n", err)
+				exitWithCode(ExitGeneral)
+			}
+			fmt.Println("Compiler cache cleared")
+		}
 	},
 }
 
@@ -83,7 +105,21 @@ var cacheInfoCmd = &cobra.Command{
 			exitWithCode(ExitGeneral)
 		}
 
+		artifactInfo, err := executor.NewArtifactCache(cfg.ArtifactCacheDir, "").Info()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get binary cache info: %v\n", err)
+			exitWithCode(ExitGeneral)
+		}
+
+		compilerStats := actions.GetBuildCacheStats(cfg.BuildCacheDir, cfg.ToolsDir)
+
 		if jsonOutput {
+			type compilerCacheOutput struct {
+				Name   string `json:"name"`
+				Size   int64  `json:"size_bytes"`
+				Hits   *int64 `json:"hits,omitempty"`
+				Misses *int64 `json:"misses,omitempty"`
+			}
 			type cacheInfoOutput struct {
 				Downloads struct {
 					Entries int64 `json:"entries"`
@@ -93,12 +129,26 @@ var cacheInfoCmd = &cobra.Command{
 					Entries int64 `json:"entries"`
 					Size    int64 `json:"size_bytes"`
 				} `json:"versions"`
+				Builds struct {
+					Entries int64 `json:"entries"`
+					Size    int64 `json:"size_bytes"`
+				} `json:"builds"`
+				Compiler []compilerCacheOutput `json:"compiler"`
 			}
 			output := cacheInfoOutput{}
 			output.Downloads.Entries = int64(downloadInfo.EntryCount)
 			output.Downloads.Size = downloadInfo.TotalSize
 			output.Versions.Entries = int64(versionInfo.EntryCount)
 			output.Versions.Size = versionInfo.TotalSize
+			output.Builds.Entries = int64(artifactInfo.EntryCount)
+			output.Builds.Size = artifactInfo.TotalSize
+			for _, s := range compilerStats {
+				entry := compilerCacheOutput{Name: s.Name, Size: s.Size}
+				if s.Hits >= 0 {
+					entry.Hits, entry.Misses = &s.Hits, &s.Misses
+				}
+				output.Compiler = append(output.Compiler, entry)
+			}
 			printJSON(output)
 			return
 		}
@@ -114,9 +164,44 @@ var cacheInfoCmd = &cobra.Command{
 		fmt.Printf("  Entries: %d\n", versionInfo.EntryCount)
 		fmt.Printf("  Size:    %s\n", formatBytes(versionInfo.TotalSize))
 		fmt.Printf("  Path:    %s\n", cfg.VersionCacheDir)
+		fmt.Println()
+		fmt.Println("Builds:")
+		fmt.Printf("  Entries: %d\n", artifactInfo.EntryCount)
+		fmt.Printf("  Size:    %s\n", formatBytes(artifactInfo.TotalSize))
+		fmt.Printf("  Path:    %s\n", cfg.ArtifactCacheDir)
+		fmt.Println()
+		fmt.Println("Compiler:")
+		for _, s := range compilerStats {
+			fmt.Printf("  %-8s %s", s.Name+":", formatBytes(s.Size))
+			if s.Hits >= 0 {
+				fmt.Printf(" (%d hits, %d misses)", s.Hits, s.Misses)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("  Path:    %s\n", cfg.BuildCacheDir)
 	},
 }
 
+// configureArtifactCache enables the binary cache on an executor when
+// binary_cache.enabled or binary_cache.url is set in config.toml
+func configureArtifactCache(exec *executor.Executor, cfg *config.Config) {
+	userCfg, err := userconfig.Load()
+	if err != nil || (!userCfg.BinaryCache.Enabled && userCfg.BinaryCache.URL == "") {
+		return
+	}
+	exec.SetArtifactCache(executor.NewArtifactCache(cfg.ArtifactCacheDir, userCfg.BinaryCache.URL))
+}
+
+// configureBuildCache enables ccache/sccache for source builds on an executor
+// when build_cache.enabled is set in config.toml
+func configureBuildCache(exec *executor.Executor, cfg *config.Config) {
+	userCfg, err := userconfig.Load()
+	if err != nil || !userCfg.BuildCache.Enabled {
+		return
+	}
+	exec.SetBuildCache(cfg.BuildCacheDir, userCfg.BuildCacheMaxSize())
+}
+
 // formatBytes formats a byte count as a human-readable string
 func formatBytes(bytes int64) string {
 	const (
@@ -145,6 +230,8 @@ func init() {
 	// Flags for cache clear
 	cacheClearCmd.Flags().Bool("downloads", false, "Clear only download cache")
 	cacheClearCmd.Flags().Bool("versions", false, "Clear only version cache")
+	cacheClearCmd.Flags().Bool("builds", false, "Clear only binary cache of prebuilt install trees")
+	cacheClearCmd.Flags().Bool("compiler", false, "Clear only ccache/sccache compiler caches")
 
 	// Flags for cache info
 	cacheInfoCmd.Flags().Bool("json", false, "Output in JSON format")
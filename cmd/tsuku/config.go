@@ -20,9 +20,15 @@ When invoked without a subcommand, displays current configuration values.
 Configuration is stored in ~/.tsuku/config.toml.
 
 Available settings:
-  telemetry      Enable anonymous usage statistics (true/false)
-  llm.enabled    Enable LLM features for recipe generation (true/false)
-  llm.providers  Preferred LLM provider order (comma-separated, e.g., claude,gemini)
+  telemetry             Enable anonymous usage statistics (true/false)
+  llm.enabled           Enable LLM features for recipe generation (true/false)
+  llm.providers         Preferred LLM provider order (comma-separated, e.g., claude,gemini)
+  store.enabled         Deduplicate tool files through the content-addressed store (true/false)
+  binary_cache.enabled  Reuse cached build results instead of rebuilding (true/false)
+  binary_cache.url      HTTPS binary cache consulted on local misses
+  build_cache.enabled   Cache compiler output of source builds with ccache/sccache (true/false)
+  build_cache.max_size  Size limit of each compiler cache (default: 5G)
+  confinement.enabled   Confine build and installer commands with Landlock on Linux (true/false)
 
 Examples:
   tsuku config
@@ -40,9 +46,15 @@ var configGetCmd = &cobra.Command{
 	Long: `Get the current value of a configuration setting.
 
 Available keys:
-  telemetry      Enable anonymous usage statistics (true/false)
-  llm.enabled    Enable LLM features for recipe generation (true/false)
-  llm.providers  Preferred LLM provider order (comma-separated)`,
+  telemetry             Enable anonymous usage statistics (true/false)
+  llm.enabled           Enable LLM features for recipe generation (true/false)
+  llm.providers         Preferred LLM provider order (comma-separated)
+  store.enabled         Deduplicate tool files through the content-addressed store (true/false)
+  binary_cache.enabled  Reuse cached build results instead of rebuilding (true/false)
+  binary_cache.url      HTTPS binary cache consulted on local misses
+  build_cache.enabled   Cache compiler output of source builds with ccache/sccache (true/false)
+  build_cache.max_size  Size limit of each compiler cache (default: 5G)
+  confinement.enabled   Confine build and installer commands with Landlock on Linux (true/false)`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		key := args[0]
@@ -71,9 +83,15 @@ var configSetCmd = &cobra.Command{
 	Long: `Set a configuration value.
 
 Available keys:
-  telemetry      Enable anonymous usage statistics (true/false)
-  llm.enabled    Enable LLM features for recipe generation (true/false)
-  llm.providers  Preferred LLM provider order (comma-separated)
+  telemetry             Enable anonymous usage statistics (true/false)
+  llm.enabled           Enable LLM features for recipe generation (true/false)
+  llm.providers         Preferred LLM provider order (comma-separated)
+  store.enabled         Deduplicate tool files through the content-addressed store (true/false)
+  binary_cache.enabled  Reuse cached build results instead of rebuilding (true/false)
+  binary_cache.url      HTTPS binary cache consulted on local misses
+  build_cache.enabled   Cache compiler output of source builds with ccache/sccache (true/false)
+  build_cache.max_size  Size limit of each compiler cache (default: 5G)
+  confinement.enabled   Confine build and installer commands with Landlock on Linux (true/false)
 
 Examples:
   tsuku config set telemetry false
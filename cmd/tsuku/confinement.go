@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/userconfig"
+)
+
+// configureConfinement confines the commands started by an executor's
+// actions with Landlock when confinement.enabled is set in config.toml.
+// The tsuku binary itself serves as the launcher applying the rules.
+func configureConfinement(exec *executor.Executor) {
+	userCfg, err := userconfig.Load()
+	if err != nil || !userCfg.Confinement.Enabled {
+		return
+	}
+	launcher, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: confinement disabled, cannot locate the tsuku binary: %v\n", err)
+		return
+	}
+	exec.SetConfinement(launcher)
+}
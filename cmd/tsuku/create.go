@@ -13,6 +13,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/actions"
 	"github.com/tsukumogami/tsuku/internal/builders"
 	"github.com/tsukumogami/tsuku/internal/config"
 	"github.com/tsukumogami/tsuku/internal/install"
@@ -65,6 +66,8 @@ Supported sources:
   pypi                Python packages from pypi.org
   npm                 Node.js packages from npmjs.com
   github:owner/repo      GitHub releases (uses LLM to analyze assets)
+  gitlab:group/project   GitLab releases (gitlab.com, or https://host/group/project)
+  gitea:host/owner/repo  Gitea or Forgejo releases (e.g., codeberg.org/owner/repo)
   homebrew:formula       Homebrew formulas (uses LLM to generate recipes)
   homebrew:formula:source  Force source build even if bottles available
 
@@ -76,6 +79,8 @@ Examples:
   tsuku create prettier --from npm
   tsuku create gh --from github:cli/cli
   tsuku create age --from github:FiloSottile/age
+  tsuku create glab --from gitlab:gitlab-org/cli
+  tsuku create forgejo-runner --from gitea:codeberg.org/forgejo/runner
   tsuku create jq --from homebrew:jq
   tsuku create ripgrep --from homebrew:ripgrep`,
 	Args: cobra.ExactArgs(1),
@@ -90,7 +95,7 @@ var (
 )
 
 func init() {
-	createCmd.Flags().StringVar(&createFrom, "from", "", "Source: ecosystem name or github:owner/repo (required)")
+	createCmd.Flags().StringVar(&createFrom, "from", "", "Source: ecosystem name, github:owner/repo, or gitlab:group/project (required)")
 	createCmd.Flags().BoolVar(&createForce, "force", false, "Overwrite existing local recipe")
 	createCmd.Flags().BoolVar(&createAutoApprove, "yes", false, "Skip recipe preview confirmation")
 	createCmd.Flags().BoolVar(&createSkipSandbox, "skip-sandbox", false, "Skip container sandbox testing (use when Docker is unavailable)")
@@ -163,6 +168,8 @@ func normalizeEcosystem(name string) string {
 		return "pypi"
 	case "npm", "npmjs", "npmjs.com", "node", "nodejs":
 		return "npm"
+	case "gitea", "forgejo", "codeberg":
+		return "gitea"
 	default:
 		return normalized
 	}
@@ -200,6 +207,8 @@ func runCreate(cmd *cobra.Command, args []string) {
 	builderRegistry.Register(builders.NewPyPIBuilder(nil))
 	builderRegistry.Register(builders.NewNpmBuilder(nil))
 	builderRegistry.Register(builders.NewGitHubReleaseBuilder())
+	builderRegistry.Register(builders.NewGitLabReleaseBuilder(nil))
+	builderRegistry.Register(builders.NewGiteaReleaseBuilder(nil))
 	builderRegistry.Register(builders.NewHomebrewBuilder())
 
 	// Get the builder
@@ -211,6 +220,8 @@ func runCreate(cmd *cobra.Command, args []string) {
 			fmt.Fprintf(os.Stderr, "  %s\n", name)
 		}
 		fmt.Fprintf(os.Stderr, "  github:owner/repo\n")
+		fmt.Fprintf(os.Stderr, "  gitlab:group/project\n")
+		fmt.Fprintf(os.Stderr, "  gitea:host/owner/repo\n")
 		fmt.Fprintf(os.Stderr, "  homebrew:formula\n")
 		exitWithCode(ExitUsage)
 	}
@@ -542,6 +553,16 @@ func extractDownloadURLs(r *recipe.Recipe) []string {
 					urls = append(urls, fmt.Sprintf("github.com/%s/releases/.../%s", repo, pattern))
 				}
 			}
+		case "gitlab_archive", "gitlab_file", "gitea_archive", "gitea_file":
+			if repo, ok := step.Params["repo"].(string); ok {
+				if pattern, ok := step.Params["asset_pattern"].(string); ok {
+					host := "gitlab.com"
+					if baseURL, ok := step.Params["base_url"].(string); ok {
+						host = strings.TrimPrefix(baseURL, "https://")
+					}
+					urls = append(urls, fmt.Sprintf("%s/%s/releases/.../%s", host, repo, pattern))
+				}
+			}
 		case "homebrew":
 			if formula, ok := step.Params["formula"].(string); ok {
 				urls = append(urls, fmt.Sprintf("ghcr.io/homebrew/core/%s:...", formula))
@@ -566,6 +587,24 @@ func describeStep(step recipe.Step) string {
 		return fmt.Sprintf("Download and extract %s archive from GitHub", format)
 	case "github_file":
 		return "Download binary from GitHub releases"
+	case "gitlab_archive", "gitea_archive":
+		forge := "GitLab"
+		if step.Action == "gitea_archive" {
+			forge = "Gitea"
+		}
+		format := "tar.gz"
+		if f, ok := step.Params["archive_format"].(string); ok {
+			format = f
+		} else if pattern, ok := step.Params["asset_pattern"].(string); ok {
+			if detected := actions.DetectArchiveFormat(pattern); detected != "" {
+				format = detected
+			}
+		}
+		return fmt.Sprintf("Download and extract %s archive from %s", format, forge)
+	case "gitlab_file":
+		return "Download binary from GitLab releases"
+	case "gitea_file":
+		return "Download binary from Gitea releases"
 	case "homebrew":
 		if formula, ok := step.Params["formula"].(string); ok {
 			return fmt.Sprintf("Download Homebrew bottle for %s", formula)
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+var depsTreeOS string
+var depsGraphFormat string
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Inspect the dependency graph",
+	Long: `Inspect dependencies between recipes and installed tools.
+
+Use "tsuku deps tree <tool>" for the dependencies a recipe declares and
+"tsuku deps graph" for the dependency graph of everything installed.
+"tsuku why <tool>" explains why a dependency is installed.`,
+}
+
+var depsTreeCmd = &cobra.Command{
+	Use:   "tree <tool>",
+	Short: "Show the dependency tree of a recipe",
+	Long: `Show the dependencies a recipe pulls in, recursively, whether or not the
+tool is installed. Each dependency is marked as needed at install time,
+at runtime, or both, with its installed version if any.
+
+Dependencies can differ between platforms; use --os to show the tree
+for another operating system. Dependencies that appear more than once are
+expanded at their first occurrence and marked (*) afterwards.`,
+	Example: `  tsuku deps tree ruby
+  tsuku deps tree ruby --os darwin
+  tsuku deps tree ruby --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runDepsTree,
+}
+
+var depsGraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the dependency graph of installed tools",
+	Long: `Export the dependency graph of all installed tools and libraries.
+
+Nodes are tools and library versions; edges point from a tool to what it
+depends on and are labeled install, runtime or library. Explicitly
+installed tools are drawn bold in DOT output and dependencies that are
+no longer installed are dashed.`,
+	Example: `  tsuku deps graph | dot -Tsvg > deps.svg
+  tsuku deps graph --format json`,
+	Args: cobra.NoArgs,
+	Run:  runDepsGraph,
+}
+
+var whyCmd = &cobra.Command{
+	Use:   "why <tool>",
+	Short: "Explain why a tool or library is installed",
+	Long: `Show the chains of dependencies from explicitly installed tools to a
+hidden dependency or library.
+
+A tool that is installed but not reachable from any explicitly installed
+tool is reported as orphaned.`,
+	Example: `  tsuku why libyaml
+  tsuku why nodejs --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runWhy,
+}
+
+func init() {
+	depsTreeCmd.Flags().StringVar(&depsTreeOS, "os", "", "Target operating system (linux, darwin)")
+	depsTreeCmd.Flags().Bool("json", false, "Output in JSON format")
+	depsGraphCmd.Flags().StringVar(&depsGraphFormat, "format", "dot", "Output format (dot, json)")
+	whyCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	depsCmd.AddCommand(depsTreeCmd)
+	depsCmd.AddCommand(depsGraphCmd)
+}
+
+// loadInstallState loads state.json for the dependency commands
+func loadInstallState() *install.State {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		exitWithCode(ExitGeneral)
+	}
+	state, err := install.New(cfg).GetState().Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load state: %v\n", err)
+		exitWithCode(ExitGeneral)
+	}
+	return state
+}
+
+func runDepsTree(cmd *cobra.Command, args []string) {
+	toolName := args[0]
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if err := ValidateOS(depsTreeOS); err != nil {
+		printError(err)
+		exitWithCode(ExitUsage)
+	}
+	targetOS := depsTreeOS
+	if targetOS == "" {
+		targetOS = runtime.GOOS
+	}
+
+	r, err := loader.Get(toolName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Recipe '%s' not found in registry.\n", toolName)
+		exitWithCode(ExitRecipeNotFound)
+	}
+
+	tree, err := actions.BuildDependencyTree(globalCtx, loader, r, targetOS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve dependencies: %v\n", err)
+		exitWithCode(ExitDependencyFailed)
+	}
+
+	installed := installedVersions(loadInstallState())
+	if jsonOutput {
+		printJSON(depsTreeOutput{OS: targetOS, Tree: newDepsTreeJSON(tree, installed)})
+		return
+	}
+	writeDepsTree(os.Stdout, tree, installed)
+}
+
+func runDepsGraph(cmd *cobra.Command, args []string) {
+	graph := install.BuildGraph(loadInstallState())
+
+	switch depsGraphFormat {
+	case "json":
+		printJSON(graph)
+	case "dot":
+		if err := graph.WriteDOT(os.Stdout); err != nil {
+			printError(err)
+			exitWithCode(ExitGeneral)
+		}
+	default:
+		printError(fmt.Errorf("invalid format %q: must be one of dot, json", depsGraphFormat))
+		exitWithCode(ExitUsage)
+	}
+}
+
+// whyOutput is the JSON output of "tsuku why"
+type whyOutput struct {
+	Name     string     `json:"name"`
+	Explicit bool       `json:"explicit"`
+	Orphaned bool       `json:"orphaned"`
+	Paths    [][]string `json:"paths"`
+}
+
+func runWhy(cmd *cobra.Command, args []string) {
+	name := args[0]
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	graph := install.BuildGraph(loadInstallState())
+	if !graphHasInstalled(graph, name) {
+		fmt.Fprintf(os.Stderr, "%s is not installed\n", name)
+		exitWithCode(ExitGeneral)
+	}
+
+	output := newWhyOutput(graph, name)
+	if jsonOutput {
+		printJSON(output)
+		return
+	}
+	writeWhy(os.Stdout, output)
+}
+
+// graphHasInstalled reports whether an installed tool or library is called name
+func graphHasInstalled(graph *install.Graph, name string) bool {
+	for _, n := range graph.Nodes {
+		if n.Name == name && n.Installed {
+			return true
+		}
+	}
+	return false
+}
+
+func newWhyOutput(graph *install.Graph, name string) whyOutput {
+	output := whyOutput{Name: name, Paths: [][]string{}}
+	for _, path := range graph.Why(name) {
+		if len(path) == 1 {
+			output.Explicit = true
+			continue
+		}
+		output.Paths = append(output.Paths, path)
+	}
+	output.Orphaned = !output.Explicit && len(output.Paths) == 0
+	return output
+}
+
+func writeWhy(w io.Writer, output whyOutput) {
+	if output.Explicit {
+		fmt.Fprintf(w, "%s was installed explicitly\n", output.Name)
+	}
+	if output.Orphaned {
+		fmt.Fprintf(w, "%s is not required by any explicitly installed tool\n", output.Name)
+		fmt.Fprintf(w, "Remove it with: tsuku remove %s\n", output.Name)
+		return
+	}
+	if len(output.Paths) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s is required by:\n", output.Name)
+	for _, path := range output.Paths {
+		fmt.Fprintf(w, "  %s\n", strings.Join(path, " -> "))
+	}
+}
+
+// installedVersions maps installed tool and library names to their versions.
+// Libraries can have several versions installed at once.
+func installedVersions(state *install.State) map[string][]string {
+	installed := make(map[string][]string)
+	for name, ts := range state.Installed {
+		v := ts.ActiveVersion
+		if v == "" {
+			v = ts.Version
+		}
+		installed[name] = []string{v}
+	}
+	for name, versions := range state.Libs {
+		for v := range versions {
+			installed[name] = append(installed[name], v)
+		}
+		sort.Strings(installed[name])
+	}
+	return installed
+}
+
+// depsTreeOutput is the JSON output of "tsuku deps tree"
+type depsTreeOutput struct {
+	OS   string        `json:"os"`
+	Tree *depsTreeJSON `json:"tree"`
+}
+
+type depsTreeJSON struct {
+	*actions.DependencyNode
+	Installed    []string        `json:"installed,omitempty"`
+	Dependencies []*depsTreeJSON `json:"dependencies,omitempty"`
+}
+
+func newDepsTreeJSON(node *actions.DependencyNode, installed map[string][]string) *depsTreeJSON {
+	out := &depsTreeJSON{DependencyNode: node, Installed: installed[node.Name]}
+	for _, child := range node.Dependencies {
+		out.Dependencies = append(out.Dependencies, newDepsTreeJSON(child, installed))
+	}
+	return out
+}
+
+// writeDepsTree prints a dependency tree with box-drawing connectors
+func writeDepsTree(w io.Writer, tree *actions.DependencyNode, installed map[string][]string) {
+	fmt.Fprintln(w, depsTreeLabel(tree, installed))
+	seen := map[string]bool{tree.Name: true}
+	writeDepsTreeChildren(w, tree, installed, "", seen)
+}
+
+func writeDepsTreeChildren(w io.Writer, node *actions.DependencyNode, installed map[string][]string, prefix string, seen map[string]bool) {
+	for i, child := range node.Dependencies {
+		connector, childPrefix := "├── ", "│   "
+		if i == len(node.Dependencies)-1 {
+			connector, childPrefix = "└── ", "    "
+		}
+
+		label := depsTreeLabel(child, installed)
+		if seen[child.Name] && len(child.Dependencies) > 0 {
+			fmt.Fprintf(w, "%s%s%s (*)\n", prefix, connector, label)
+			continue
+		}
+		seen[child.Name] = true
+		fmt.Fprintf(w, "%s%s%s\n", prefix, connector, label)
+		writeDepsTreeChildren(w, child, installed, prefix+childPrefix, seen)
+	}
+}
+
+func depsTreeLabel(node *actions.DependencyNode, installed map[string][]string) string {
+	label := node.Name
+	if node.Version != "" && node.Version != "latest" {
+		label += "@" + node.Version
+	}
+
+	var tags []string
+	if node.Type != "" {
+		tags = append(tags, node.Type)
+	}
+	if node.Library {
+		tags = append(tags, "library")
+	}
+	if len(tags) > 0 {
+		label += " [" + strings.Join(tags, ", ") + "]"
+	}
+
+	switch {
+	case node.NotFound:
+		label += " (no recipe)"
+	case len(installed[node.Name]) > 0:
+		label += " (installed: " + strings.Join(installed[node.Name], ", ") + ")"
+	default:
+		label += " (not installed)"
+	}
+	return label
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+func TestWriteDepsTree(t *testing.T) {
+	openssl := &actions.DependencyNode{Name: "openssl", Type: actions.DepTypeRuntime, Version: "latest",
+		Dependencies: []*actions.DependencyNode{{Name: "perl", Type: actions.DepTypeInstall, NotFound: true}}}
+	tree := &actions.DependencyNode{
+		Name: "ruby",
+		Dependencies: []*actions.DependencyNode{
+			{Name: "libyaml", Type: actions.DepTypeInstall, Version: "0.2", Library: true},
+			openssl,
+			{Name: "zig", Type: actions.DepTypeInstall, Dependencies: []*actions.DependencyNode{openssl}},
+		},
+	}
+	installed := map[string][]string{"ruby": {"3.4.0"}, "libyaml": {"0.2.5"}}
+
+	var b strings.Builder
+	writeDepsTree(&b, tree, installed)
+	want := `ruby (installed: 3.4.0)
+├── libyaml@0.2 [install, library] (installed: 0.2.5)
+├── openssl [runtime] (not installed)
+│   └── perl [install] (no recipe)
+└── zig [install] (not installed)
+    └── openssl [runtime] (not installed) (*)
+`
+	if b.String() != want {
+		t.Errorf("writeDepsTree() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWhyOutput(t *testing.T) {
+	graph := install.BuildGraph(&install.State{
+		Installed: map[string]install.ToolState{
+			"ruby":   {ActiveVersion: "3.4.0", IsExplicit: true, InstallDependencies: []string{"zig"}},
+			"zig":    {ActiveVersion: "0.13.0", IsHidden: true},
+			"orphan": {ActiveVersion: "1.0.0"},
+		},
+	})
+
+	var b strings.Builder
+	writeWhy(&b, newWhyOutput(graph, "zig"))
+	if want := "zig is required by:\n  ruby -> zig\n"; b.String() != want {
+		t.Errorf("writeWhy(zig) = %q, want %q", b.String(), want)
+	}
+
+	b.Reset()
+	writeWhy(&b, newWhyOutput(graph, "ruby"))
+	if want := "ruby was installed explicitly\n"; b.String() != want {
+		t.Errorf("writeWhy(ruby) = %q, want %q", b.String(), want)
+	}
+
+	output := newWhyOutput(graph, "orphan")
+	if !output.Orphaned {
+		t.Error("orphan should be reported as orphaned")
+	}
+	if graphHasInstalled(graph, "missing") {
+		t.Error("graphHasInstalled() = true for a tool that isn't installed")
+	}
+}
+
+func TestWriteRemovalPlan(t *testing.T) {
+	plan := &install.RemovalPlan{
+		Tools:     []string{"nodejs", "serverless"},
+		Libraries: []install.LibraryVersion{{Name: "openssl", Version: "3.0.0"}},
+	}
+	var b strings.Builder
+	writeRemovalPlan(&b, plan, 2048)
+	want := `The following will be removed:
+  nodejs
+  serverless
+  openssl@3.0.0 (library)
+
+Disk space reclaimed: 2.00 KB
+`
+	if b.String() != want {
+		t.Errorf("writeRemovalPlan() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteAlternatives(t *testing.T) {
+	alternatives := map[string]install.BinaryAlternatives{
+		"go":      {Active: "golang", Providers: []string{"golang", "go"}},
+		"gofmt":   {Active: "golang", Providers: []string{"golang"}},
+		"kubectl": {Active: "kubectl", Providers: []string{"kubectl"}},
+	}
+
+	var b strings.Builder
+	writeAlternatives(&b, newAlternativesOutput(alternatives, ""))
+	if want := "go\n  * golang\n    go\n"; b.String() != want {
+		t.Errorf("writeAlternatives() = %q, want %q", b.String(), want)
+	}
+
+	output := newAlternativesOutput(alternatives, "gofmt")
+	if len(output) != 1 || output[0].Active != "golang" {
+		t.Errorf("newAlternativesOutput(gofmt) = %+v", output)
+	}
+}
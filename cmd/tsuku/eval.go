@@ -4,12 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tsukumogami/tsuku/internal/actions"
 	"github.com/tsukumogami/tsuku/internal/config"
 	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/platform"
 	"github.com/tsukumogami/tsuku/internal/recipe"
 	"github.com/tsukumogami/tsuku/internal/validate"
 )
@@ -30,6 +32,7 @@ func makeSet(items []string) map[string]bool {
 
 var evalOS string
 var evalArch string
+var evalLibc string
 var evalYes bool
 var evalRecipePath string
 
@@ -45,7 +48,9 @@ needed to reproduce the installation. This enables:
   - Cross-platform plan generation
 
 By default, plans are generated for the current platform. Use --os and --arch
-to generate plans for other platforms.
+to generate plans for other platforms, and --libc to choose between glibc and
+musl builds for Linux (defaults to the current system's C library on Linux
+hosts, glibc otherwise).
 
 Some tools require dependencies at eval time (e.g., npm packages need nodejs
 to generate package-lock.json). If these dependencies are missing, you will
@@ -59,6 +64,7 @@ Examples:
   tsuku eval kubectl
   tsuku eval kubectl@v1.29.0
   tsuku eval ripgrep --os linux --arch arm64
+  tsuku eval ripgrep --os linux --libc musl
   tsuku eval netlify-cli --yes
   tsuku eval --recipe ./my-recipe.toml --os darwin --arch arm64`,
 	Args: cobra.MaximumNArgs(1),
@@ -68,6 +74,7 @@ Examples:
 func init() {
 	evalCmd.Flags().StringVar(&evalOS, "os", "", "Target operating system (linux, darwin)")
 	evalCmd.Flags().StringVar(&evalArch, "arch", "", "Target architecture (amd64, arm64)")
+	evalCmd.Flags().StringVar(&evalLibc, "libc", "", "Target C library for Linux (glibc, musl)")
 	evalCmd.Flags().BoolVar(&evalYes, "yes", false, "Auto-accept installation of eval-time dependencies")
 	evalCmd.Flags().StringVar(&evalRecipePath, "recipe", "", "Path to a local recipe file (for testing)")
 }
@@ -96,6 +103,24 @@ func ValidateArch(arch string) error {
 	return nil
 }
 
+// ValidateLibc validates a libc value for the target OS.
+// Returns an error if the value is invalid or the target is not Linux.
+func ValidateLibc(libc, targetOS string) error {
+	if libc == "" {
+		return nil // Empty is valid (uses the default for the target)
+	}
+	if !platform.IsValidLibc(libc) {
+		return fmt.Errorf("invalid libc value %q: must be one of %s", libc, strings.Join(platform.SupportedLibc(), ", "))
+	}
+	if targetOS == "" {
+		targetOS = runtime.GOOS
+	}
+	if targetOS != "linux" {
+		return fmt.Errorf("--libc only applies to linux targets, not %s", targetOS)
+	}
+	return nil
+}
+
 func runEval(cmd *cobra.Command, args []string) {
 	// Validate platform flags early
 	if err := ValidateOS(evalOS); err != nil {
@@ -106,6 +131,10 @@ func runEval(cmd *cobra.Command, args []string) {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		exitWithCode(ExitUsage)
 	}
+	if err := ValidateLibc(evalLibc, evalOS); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		exitWithCode(ExitUsage)
+	}
 
 	// Validate mutually exclusive options
 	if evalRecipePath != "" && len(args) > 0 {
@@ -196,6 +225,7 @@ func runEval(cmd *cobra.Command, args []string) {
 	planCfg := executor.PlanConfig{
 		OS:                 evalOS,
 		Arch:               evalArch,
+		Libc:               evalLibc,
 		RecipeSource:       recipeSource,
 		Downloader:         downloader,
 		DownloadCache:      downloadCache,
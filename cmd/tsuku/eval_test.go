@@ -61,3 +61,28 @@ func TestValidateArch(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateLibc(t *testing.T) {
+	tests := []struct {
+		name     string
+		libc     string
+		targetOS string
+		wantErr  bool
+	}{
+		{"empty is valid", "", "darwin", false},
+		{"glibc on linux", "glibc", "linux", false},
+		{"musl on linux", "musl", "linux", false},
+		{"invalid libc rejected", "uclibc", "linux", true},
+		{"gnu alias rejected", "gnu", "linux", true},
+		{"musl on darwin rejected", "musl", "darwin", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateLibc(tt.libc, tt.targetOS)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateLibc(%q, %q) error = %v, wantErr %v", tt.libc, tt.targetOS, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package main
 
-import "os"
+import (
+	"errors"
+	"os"
+
+	"github.com/tsukumogami/tsuku/internal/policy"
+)
 
 // Exit codes for different error types.
 // These enable scripts to distinguish between failure modes.
@@ -32,6 +37,11 @@ const (
 	// ExitDependencyFailed indicates dependency resolution failed
 	ExitDependencyFailed = 8
 
+	// ExitPolicyViolation indicates the install policy blocks the operation.
+	// Install, update and reinstall use it instead of ExitInstallFailed, so
+	// scripts can tell a policy block from a failed build.
+	ExitPolicyViolation = 9
+
 	// ExitCancelled indicates the operation was canceled by the user (Ctrl+C)
 	ExitCancelled = 130
 )
@@ -40,3 +50,13 @@ const (
 func exitWithCode(code int) {
 	os.Exit(code)
 }
+
+// installExitCode returns the exit code for a failed install: ExitPolicyViolation
+// when the install policy blocked it, ExitInstallFailed otherwise
+func installExitCode(err error) int {
+	var policyErr *policy.Error
+	if errors.As(err, &policyErr) {
+		return ExitPolicyViolation
+	}
+	return ExitInstallFailed
+}
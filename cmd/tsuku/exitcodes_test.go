@@ -0,0 +1,30 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/policy"
+)
+
+func TestInstallExitCode(t *testing.T) {
+	blocked := &policy.Error{Tool: "jq", Violations: []policy.Violation{{Rule: "deny"}}}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"policy violation", blocked, ExitPolicyViolation},
+		{"wrapped policy violation", fmt.Errorf("failed to install dependency 'oniguruma': %w", blocked), ExitPolicyViolation},
+		{"build failure", errors.New("make: *** [all] Error 2"), ExitInstallFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := installExitCode(tt.err); got != tt.want {
+				t.Errorf("installExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
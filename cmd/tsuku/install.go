@@ -67,7 +67,7 @@ Test installation in a sandbox container:
 
 			if err := runSandboxInstall(toolName, installPlanPath, installRecipePath); err != nil {
 				printError(err)
-				exitWithCode(ExitInstallFailed)
+				exitWithCode(installExitCode(err))
 			}
 			return
 		}
@@ -100,7 +100,7 @@ Test installation in a sandbox container:
 
 			if err := runPlanBasedInstall(installPlanPath, toolName); err != nil {
 				printError(err)
-				exitWithCode(ExitInstallFailed)
+				exitWithCode(installExitCode(err))
 			}
 			return
 		}
@@ -134,14 +134,14 @@ Test installation in a sandbox container:
 			if installDryRun {
 				if err := runDryRun(toolName, resolveVersion); err != nil {
 					printError(err)
-					exitWithCode(ExitInstallFailed)
+					exitWithCode(installExitCode(err))
 				}
 			} else {
 				if err := runInstallWithTelemetry(toolName, resolveVersion, versionConstraint, true, "", telemetryClient); err != nil {
 					// Continue installing other tools even if one fails?
 					// For now, exit on first failure to be safe
 					printError(err)
-					exitWithCode(ExitInstallFailed)
+					exitWithCode(installExitCode(err))
 				}
 			}
 		}
@@ -14,6 +14,7 @@ import (
 	"github.com/tsukumogami/tsuku/internal/config"
 	"github.com/tsukumogami/tsuku/internal/executor"
 	"github.com/tsukumogami/tsuku/internal/install"
+	"github.com/tsukumogami/tsuku/internal/platform"
 	"github.com/tsukumogami/tsuku/internal/recipe"
 	"github.com/tsukumogami/tsuku/internal/telemetry"
 	"github.com/tsukumogami/tsuku/internal/validate"
@@ -26,6 +27,7 @@ type planRetrievalConfig struct {
 	Fresh             bool   // If true, skip cache and regenerate plan
 	OS                string // Target OS (defaults to runtime.GOOS)
 	Arch              string // Target arch (defaults to runtime.GOARCH)
+	Libc              string // Target libc on Linux (defaults to the host's)
 	RecipeHash        string // SHA256 hash of recipe TOML
 	DownloadCacheDir  string // Directory for download cache (enables caching during Decompose)
 }
@@ -74,6 +76,10 @@ func getOrGeneratePlanWith(
 	if targetArch == "" {
 		targetArch = runtime.GOARCH
 	}
+	targetLibc := cfg.Libc
+	if targetLibc == "" {
+		targetLibc = platform.DefaultLibc(targetOS)
+	}
 
 	// Phase 1: Version Resolution (ALWAYS runs)
 	resolvedVersion, err := resolver.ResolveVersion(ctx, cfg.VersionConstraint)
@@ -85,7 +91,8 @@ func getOrGeneratePlanWith(
 	}
 
 	// Generate cache key from resolution output
-	cacheKey := executor.CacheKeyFor(cfg.Tool, resolvedVersion, targetOS, targetArch, cfg.RecipeHash)
+	cacheKey := executor.CacheKeyForPlatform(cfg.Tool, resolvedVersion,
+		executor.Platform{OS: targetOS, Arch: targetArch, Libc: targetLibc}, cfg.RecipeHash)
 
 	// Check cache (unless --fresh)
 	if !cfg.Fresh {
@@ -120,6 +127,7 @@ func getOrGeneratePlanWith(
 	return generator.GeneratePlan(ctx, executor.PlanConfig{
 		OS:            targetOS,
 		Arch:          targetArch,
+		Libc:          targetLibc,
 		RecipeSource:  "registry",
 		Downloader:    downloader,
 		DownloadCache: downloadCache,
@@ -137,6 +145,18 @@ func computeRecipeHashForPlan(r *recipe.Recipe) (string, error) {
 }
 
 func runInstallWithTelemetry(toolName, reqVersion, versionConstraint string, isExplicit bool, parent string, client *telemetry.Client) error {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Lock the tool and its whole dependency chain before installing anything
+	release, err := lockInstall(cfg, installLockNames(toolName, loader.Get)...)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	return installWithDependencies(toolName, reqVersion, versionConstraint, isExplicit, parent, make(map[string]bool), client)
 }
 
@@ -236,6 +256,14 @@ func installWithDependencies(toolName, reqVersion, versionConstraint string, isE
 	}
 	mgr := install.New(cfg)
 
+	// Dependencies discovered during install (e.g. bootstrapped package
+	// managers) may not be covered by the locks taken up front
+	release, err := lockInstall(cfg, toolName)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// If explicit install, check if tool is hidden and just expose it
 	if isExplicit && parent == "" {
 		wasHidden, err := install.CheckAndExposeHidden(mgr, toolName)
@@ -332,6 +360,11 @@ func installWithDependencies(toolName, reqVersion, versionConstraint string, isE
 		}
 	}
 
+	// Reject recipes the install policy forbids before pulling in dependencies
+	if err := enforcePolicy(toolName, r, nil); err != nil {
+		return err
+	}
+
 	// Check if this is a library recipe
 	if r.IsLibrary() {
 		return installLibrary(toolName, reqVersion, parent, mgr, telemetryClient)
@@ -423,6 +456,12 @@ func installWithDependencies(toolName, reqVersion, versionConstraint string, isE
 
 	// Set download cache directory
 	exec.SetDownloadCacheDir(cfg.DownloadCacheDir)
+	exec.SetVersionCacheDir(cfg.VersionCacheDir)
+
+	// Reuse cached builds for source and ecosystem builds (binary_cache.*)
+	configureArtifactCache(exec, cfg)
+	configureBuildCache(exec, cfg)
+	configureConfinement(exec)
 
 	// Get or generate installation plan (two-phase flow)
 	planCfg := planRetrievalConfig{
@@ -438,6 +477,11 @@ func installWithDependencies(toolName, reqVersion, versionConstraint string, isE
 		return err
 	}
 
+	// The plan shows what will actually run (run_command, network actions, downloads)
+	if err := enforcePolicy(toolName, r, plan); err != nil {
+		return err
+	}
+
 	// Execute the plan
 	if err := exec.ExecutePlan(globalCtx, plan); err != nil {
 		// Handle ChecksumMismatchError specially - it has a user-friendly message
@@ -499,6 +543,7 @@ func installWithDependencies(toolName, reqVersion, versionConstraint string, isE
 		installOpts := install.DefaultInstallOptions()
 		installOpts.Binaries = binaries
 		installOpts.RequestedVersion = versionConstraint // Record what user asked for ("17", "@lts", "")
+		installOpts.UseStore = storeEnabled()
 
 		// Store the plan using canonical conversion
 		installOpts.Plan = executor.ToStoragePlan(plan)
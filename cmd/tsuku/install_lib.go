@@ -46,6 +46,8 @@ func installLibrary(libName, reqVersion, parent string, mgr *install.Manager, te
 	cfg, _ := config.DefaultConfig()
 	exec.SetToolsDir(cfg.ToolsDir)
 	exec.SetDownloadCacheDir(cfg.DownloadCacheDir)
+	exec.SetVersionCacheDir(cfg.VersionCacheDir)
+	configureConfinement(exec)
 
 	// Create downloader and cache for plan generation
 	// Downloader enables Decompose to download files (e.g., GHCR bottles with auth)
@@ -70,6 +72,10 @@ func installLibrary(libName, reqVersion, parent string, mgr *install.Manager, te
 		return fmt.Errorf("failed to generate library plan: %w", err)
 	}
 
+	if err := enforcePolicy(libName, r, plan); err != nil {
+		return err
+	}
+
 	// Execute the plan
 	if err := exec.ExecutePlan(globalCtx, plan); err != nil {
 		return fmt.Errorf("library installation failed: %w", err)
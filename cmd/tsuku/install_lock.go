@@ -0,0 +1,119 @@
+package main
+
+import (
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/install"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// installLocks holds the per-tool install locks taken by this process
+var installLocks *install.ToolLocks
+
+// lockInstall takes the install locks for the given tools, waiting for other
+// tsuku processes that are installing or removing them. Tools already locked by this
+// process are skipped, so nested dependency installs reuse the locks taken
+// for the whole chain. The returned function releases the locks taken.
+func lockInstall(cfg *config.Config, names ...string) (func(), error) {
+	if installLocks == nil {
+		installLocks = install.NewToolLocks(cfg, config.GetLockTimeout())
+		installLocks.OnWait = func(tool string, waited time.Duration) {
+			printInfof("Waiting for another tsuku process to finish with %s (%s)...\n",
+				tool, waited.Round(time.Second))
+		}
+	}
+	return installLocks.Acquire(globalCtx, names...)
+}
+
+// installLockNames returns the tool and every dependency it may install,
+// found by walking the dependency recipes. Locking the whole set up front, in
+// sorted order, keeps concurrent installs of overlapping chains from
+// deadlocking. Recipes that can't be loaded are skipped; the install reports
+// those errors itself.
+func installLockNames(toolName string, get func(string) (*recipe.Recipe, error)) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	var walk func(name string, depth int)
+	walk = func(name string, depth int) {
+		if seen[name] || depth > actions.MaxTransitiveDepth {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+
+		r, err := get(name)
+		if err != nil {
+			return
+		}
+		resolved := actions.ResolveDependencies(r)
+		deps := append([]string{}, r.Metadata.Dependencies...)
+		deps = append(deps, r.Metadata.RuntimeDependencies...)
+		deps = append(deps, mapKeys(resolved.InstallTime)...)
+		deps = append(deps, mapKeys(resolved.Runtime)...)
+		for _, dep := range deps {
+			walk(dep, depth+1)
+		}
+	}
+	walk(toolName, 0)
+
+	return names
+}
+
+// planLockNames returns the tool of an installation plan and every
+// dependency the plan installs
+func planLockNames(toolName string, plan *executor.InstallationPlan) []string {
+	names := []string{toolName}
+	var walk func(deps []executor.DependencyPlan)
+	walk = func(deps []executor.DependencyPlan) {
+		for _, dep := range deps {
+			names = append(names, dep.Tool)
+			walk(dep.Dependencies)
+		}
+	}
+	walk(plan.Dependencies)
+	return names
+}
+
+// removalLockNames returns the tools and libraries a removal plan deletes
+func removalLockNames(plan *install.RemovalPlan) []string {
+	names := append([]string{}, plan.Tools...)
+	for _, lib := range plan.Libraries {
+		names = append(names, lib.Name)
+	}
+	return names
+}
+
+// planAndLockRemoval computes a removal plan for the named tools (the current
+// orphans when none are named) and locks everything it deletes. The plan is
+// recomputed under the locks, since an install that finished while we waited
+// may need a dependency again; anything it newly orphans is locked as well.
+func planAndLockRemoval(cfg *config.Config, mgr *install.Manager, names ...string) (*install.RemovalPlan, func(), error) {
+	plan, err := mgr.PlanRemoval(names...)
+	if err != nil {
+		return nil, nil, err
+	}
+	release, err := lockInstall(cfg, removalLockNames(plan)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan, err = mgr.PlanRemoval(names...)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	releaseMore, err := lockInstall(cfg, removalLockNames(plan)...)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	return plan, func() {
+		releaseMore()
+		release()
+	}, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/install"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+func TestInstallLockNames(t *testing.T) {
+	recipes := map[string]*recipe.Recipe{
+		"serverless": {
+			Metadata: recipe.MetadataSection{Name: "serverless", Dependencies: []string{"nodejs"}},
+			Steps:    []recipe.Step{{Action: "npm_install", Params: map[string]interface{}{"package": "serverless"}}},
+		},
+		"nodejs": {
+			Metadata: recipe.MetadataSection{Name: "nodejs", RuntimeDependencies: []string{"missing"}},
+		},
+	}
+	get := func(name string) (*recipe.Recipe, error) {
+		if r, ok := recipes[name]; ok {
+			return r, nil
+		}
+		return nil, fmt.Errorf("recipe %s not found", name)
+	}
+
+	got := installLockNames("serverless", get)
+	sort.Strings(got)
+	want := []string{"missing", "nodejs", "serverless"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("installLockNames() = %v, want %v", got, want)
+	}
+}
+
+func TestPlanLockNames(t *testing.T) {
+	plan := &executor.InstallationPlan{
+		Tool: "serverless",
+		Dependencies: []executor.DependencyPlan{{
+			Tool:         "nodejs",
+			Dependencies: []executor.DependencyPlan{{Tool: "gcc-libs"}},
+		}},
+	}
+
+	got := planLockNames("serverless", plan)
+	want := []string{"serverless", "nodejs", "gcc-libs"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("planLockNames() = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveInstalled_WaitsForConcurrentInstall(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+
+	origLocks, origCtx := installLocks, globalCtx
+	installLocks, globalCtx = nil, context.Background()
+	defer func() { installLocks, globalCtx = origLocks, origCtx }()
+
+	mgr := install.New(cfg)
+	workDir := t.TempDir()
+	binDir := filepath.Join(workDir, ".install", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, "mytool"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process is installing the tool
+	other := install.NewToolLocks(cfg, time.Minute)
+	releaseOther, err := other.Acquire(context.Background(), "mytool")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- removeInstalled(cfg, mgr, "mytool", "")
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("remove finished while the tool was being installed: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	opts := install.DefaultInstallOptions()
+	opts.Binaries = []string{"bin/mytool"}
+	if err := mgr.InstallWithOptions("mytool", "1.0.0", workDir, opts); err != nil {
+		t.Fatalf("InstallWithOptions() error = %v", err)
+	}
+	releaseOther()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("removeInstalled() error = %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("remove did not proceed after the install released its lock")
+	}
+
+	if _, err := os.Stat(cfg.ToolDir("mytool", "1.0.0")); !os.IsNotExist(err) {
+		t.Errorf("tool directory still exists after remove: %v", err)
+	}
+	ts, err := mgr.GetState().GetToolState("mytool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts != nil {
+		t.Errorf("tool still in state after remove: %+v", ts)
+	}
+}
@@ -132,6 +132,9 @@ func runSandboxInstall(toolName, planPath, recipePath string) error {
 			printInfo("Container output:")
 			printInfo(result.Stdout)
 		}
+		if result.Error != nil {
+			return fmt.Errorf("sandbox test failed: %w", result.Error)
+		}
 		return fmt.Errorf("sandbox test failed with exit code %d", result.ExitCode)
 	}
 
@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/tsukumogami/tsuku/internal/buildinfo"
 	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/landlock"
 	"github.com/tsukumogami/tsuku/internal/log"
 	"github.com/tsukumogami/tsuku/internal/recipe"
 	"github.com/tsukumogami/tsuku/internal/registry"
@@ -21,6 +22,7 @@ var (
 	quietFlag   bool
 	verboseFlag bool
 	debugFlag   bool
+	offlineFlag bool
 )
 
 // globalCtx is the application-level context that is canceled on SIGINT/SIGTERM.
@@ -43,9 +45,13 @@ func init() {
 	rootCmd.PersistentFlags().BoolVarP(&quietFlag, "quiet", "q", false, "Show errors only")
 	rootCmd.PersistentFlags().BoolVarP(&verboseFlag, "verbose", "v", false, "Show verbose output (INFO level)")
 	rootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false, "Show debug output (includes timestamps and source locations)")
+	rootCmd.PersistentFlags().BoolVar(&offlineFlag, "offline", false, "Use only cached recipes, versions and downloads (or set TSUKU_OFFLINE=1)")
 
-	// Initialize logger before command execution
-	rootCmd.PersistentPreRun = initLogger
+	// Initialize logger and offline mode before command execution
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		initLogger(cmd, args)
+		initOffline()
+	}
 
 	// Set version from build info (handles tagged releases and dev builds)
 	rootCmd.Version = buildinfo.Version()
@@ -68,15 +74,25 @@ func init() {
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(autoremoveCmd)
+	rootCmd.AddCommand(reinstallCmd)
 	rootCmd.AddCommand(recipesCmd)
 	rootCmd.AddCommand(versionsCmd)
 	rootCmd.AddCommand(searchCmd)
 	rootCmd.AddCommand(infoCmd)
 	rootCmd.AddCommand(outdatedCmd)
+	rootCmd.AddCommand(depsCmd)
+	rootCmd.AddCommand(whyCmd)
 	rootCmd.AddCommand(planCmd)
 	rootCmd.AddCommand(verifyCmd)
 	rootCmd.AddCommand(updateRegistryCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(stateCmd)
+	rootCmd.AddCommand(alternativesCmd)
+	rootCmd.AddCommand(storeCmd)
+	rootCmd.AddCommand(sbomCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(policyCmd)
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(validateCmd)
@@ -84,6 +100,11 @@ func init() {
 }
 
 func main() {
+	// Act as the launcher of a confined action command (see internal/landlock)
+	if len(os.Args) > 1 && os.Args[1] == landlock.LauncherArg {
+		landlock.Main(os.Args[2:])
+	}
+
 	// Set up cancellable context with signal handling
 	globalCtx, globalCancel = context.WithCancel(context.Background())
 	defer globalCancel()
@@ -128,6 +149,14 @@ func initLogger(cmd *cobra.Command, args []string) {
 	}
 }
 
+// initOffline turns on offline mode for --offline. It is exported through
+// the environment so every package and child tsuku process sees it.
+func initOffline() {
+	if offlineFlag {
+		_ = os.Setenv(config.EnvOffline, "1")
+	}
+}
+
 // determineLogLevel returns the appropriate slog.Level based on flags and environment variables.
 // Priority: flags > environment variables > default (WARN)
 func determineLogLevel() slog.Level {
@@ -4,6 +4,10 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/tsukumogami/tsuku/internal/config"
@@ -11,10 +15,26 @@ import (
 	"github.com/tsukumogami/tsuku/internal/version"
 )
 
+const (
+	// outdatedWorkers bounds the number of concurrent version lookups
+	outdatedWorkers = 8
+
+	// outdatedPerHost bounds concurrent lookups against a single version source
+	outdatedPerHost = 2
+
+	// outdatedHostInterval is the minimum spacing between lookups against a single version source
+	outdatedHostInterval = 100 * time.Millisecond
+)
+
 var outdatedCmd = &cobra.Command{
 	Use:   "outdated",
 	Short: "Check for outdated tools",
-	Long:  `Check for newer versions of installed tools.`,
+	Long: `Check for newer versions of installed tools.
+
+Every installed tool is checked against its recipe's version source. Tools
+installed with a version constraint (e.g. "tsuku install nodejs@20") show both
+the newest version within that constraint (WANTED) and the newest version
+overall (LATEST).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 
@@ -43,88 +63,89 @@ var outdatedCmd = &cobra.Command{
 			return
 		}
 
-		if !jsonOutput {
-			printInfo("Checking for updates...")
+		state, err := mgr.GetState().Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading state: %v\n", err)
+			exitWithCode(ExitGeneral)
 		}
-		res := version.New()
-		ctx := context.Background()
 
-		type updateInfo struct {
-			Name    string `json:"name"`
-			Current string `json:"current"`
-			Latest  string `json:"latest"`
-			Repo    string `json:"-"`
+		if !jsonOutput {
+			printInfo("Checking for updates...")
 		}
-		var updates []updateInfo
 
+		// Recipes and providers are resolved up front: the loader is not safe for concurrent use
+		res := version.New()
+		factory := version.NewProviderFactory()
+		var targets []outdatedTarget
+		var failures []outdatedFailure
 		for _, tool := range tools {
-			// Load recipe to find repo
-			r, err := loader.Get(tool.Name)
-			if err != nil {
+			if !tool.IsActive {
 				continue
 			}
 
-			// Find repo
-			var repo string
-			for _, step := range r.Steps {
-				if step.Action == "github_archive" || step.Action == "github_file" {
-					if r, ok := step.Params["repo"].(string); ok {
-						repo = r
-						break
-					}
-				}
+			r, err := loader.Get(tool.Name)
+			if err != nil {
+				failures = append(failures, outdatedFailure{Name: tool.Name, Error: err.Error()})
+				continue
 			}
-
-			if repo == "" {
+			provider, err := factory.ProviderFromRecipe(res, r)
+			if err != nil {
+				failures = append(failures, outdatedFailure{Name: tool.Name, Error: err.Error()})
 				continue
 			}
 
-			// Check latest version
-			if !jsonOutput {
-				printInfof("Checking %s...\n", tool.Name)
+			var requested string
+			if ts, ok := state.Installed[tool.Name]; ok {
+				requested = ts.Versions[tool.Version].Requested
 			}
-			latest, err := res.ResolveGitHub(ctx, repo)
-			if err != nil {
-				printError(err)
+			targets = append(targets, outdatedTarget{
+				Name:      tool.Name,
+				Current:   tool.Version,
+				Requested: requested,
+				Provider:  provider,
+			})
+		}
+
+		results := checkOutdated(context.Background(), targets, outdatedWorkers, newHostLimiter(outdatedPerHost, outdatedHostInterval))
+
+		var updates []outdatedUpdate
+		for _, result := range results {
+			if result.Err != nil {
+				failures = append(failures, outdatedFailure{Name: result.Name, Source: result.Source, Error: result.Err.Error()})
 				continue
 			}
-
-			if latest.Version != tool.Version {
-				// Simple string comparison for now.
-				// Ideally should use semver, but this is a good start.
-				// We assume if strings differ, and we just fetched latest, it's likely an update.
-				// But to be safe, we only show if they are different.
-				updates = append(updates, updateInfo{
-					Name:    tool.Name,
-					Current: tool.Version,
-					Latest:  latest.Version,
-					Repo:    repo,
-				})
+			if result.isOutdated() {
+				updates = append(updates, result.outdatedUpdate)
 			}
 		}
 
 		// JSON output mode
 		if jsonOutput {
 			type outdatedOutput struct {
-				Updates []updateInfo `json:"updates"`
+				Updates []outdatedUpdate  `json:"updates"`
+				Errors  []outdatedFailure `json:"errors,omitempty"`
 			}
-			output := outdatedOutput{Updates: updates}
+			output := outdatedOutput{Updates: updates, Errors: failures}
 			if output.Updates == nil {
-				output.Updates = []updateInfo{}
+				output.Updates = []outdatedUpdate{}
 			}
 			printJSON(output)
 			return
 		}
 
+		for _, f := range failures {
+			fmt.Fprintf(os.Stderr, "Warning: could not check %s: %s\n", f.Name, f.Error)
+		}
+
 		printInfo()
 		if len(updates) == 0 {
 			printInfo("All tools are up to date!")
 			return
 		}
 
-		fmt.Printf("%-15s  %-15s  %-15s\n", "TOOL", "CURRENT", "LATEST")
+		fmt.Printf("%-15s  %-15s  %-15s  %-15s  %s\n", "TOOL", "CURRENT", "WANTED", "LATEST", "SOURCE")
 		for _, u := range updates {
-			fmt.Printf("%-15s  %-15s  %-15s\n", u.Name, u.Current, u.Latest)
+			fmt.Printf("%-15s  %-15s  %-15s  %-15s  %s\n", u.Name, u.Current, u.Wanted, u.Latest, u.Source)
 		}
 		printInfo("\nTo update, run: tsuku update <tool>")
 	},
@@ -133,3 +154,175 @@ var outdatedCmd = &cobra.Command{
 func init() {
 	outdatedCmd.Flags().Bool("json", false, "Output in JSON format")
 }
+
+// outdatedTarget is an installed tool to check for updates
+type outdatedTarget struct {
+	Name      string
+	Current   string
+	Requested string // Version constraint the tool was installed with ("" for latest)
+	Provider  version.VersionResolver
+}
+
+// outdatedUpdate is a tool with a newer version available
+type outdatedUpdate struct {
+	Name      string `json:"name"`
+	Current   string `json:"current"`
+	Requested string `json:"requested,omitempty"`
+	Wanted    string `json:"wanted"` // Newest version within the requested constraint
+	Latest    string `json:"latest"` // Newest version overall
+	Source    string `json:"source"`
+}
+
+// outdatedFailure is a tool whose version source could not be checked
+type outdatedFailure struct {
+	Name   string `json:"name"`
+	Source string `json:"source,omitempty"`
+	Error  string `json:"error"`
+}
+
+// outdatedResult is the outcome of checking a single tool
+type outdatedResult struct {
+	outdatedUpdate
+	Err error
+}
+
+// isOutdated reports whether a newer version exists within the constraint or overall
+func (r outdatedResult) isOutdated() bool {
+	return version.CompareVersions(r.Wanted, r.Current) > 0 || version.CompareVersions(r.Latest, r.Current) > 0
+}
+
+// checkOutdated resolves the wanted and latest version of each target using a bounded
+// worker pool. Results are sorted by tool name.
+func checkOutdated(ctx context.Context, targets []outdatedTarget, workers int, limiter *hostLimiter) []outdatedResult {
+	jobs := make(chan outdatedTarget)
+	results := make([]outdatedResult, 0, len(targets))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				res := checkOutdatedTarget(ctx, t, limiter)
+				mu.Lock()
+				results = append(results, res)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, t := range targets {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results
+}
+
+// checkOutdatedTarget resolves the latest version, and the newest version within the
+// requested constraint if the tool was installed with one
+func checkOutdatedTarget(ctx context.Context, t outdatedTarget, limiter *hostLimiter) outdatedResult {
+	source := t.Provider.SourceDescription()
+	res := outdatedResult{outdatedUpdate: outdatedUpdate{
+		Name:      t.Name,
+		Current:   t.Current,
+		Requested: t.Requested,
+		Source:    source,
+	}}
+	host := sourceHost(source)
+
+	release := limiter.acquire(ctx, host)
+	latest, err := t.Provider.ResolveLatest(ctx)
+	release()
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Latest = latest.Version
+	res.Wanted = latest.Version
+
+	// Requested values like "@lts" carry the install syntax prefix
+	constraint := strings.TrimPrefix(t.Requested, "@")
+	if constraint == "" || constraint == "latest" {
+		return res
+	}
+
+	release = limiter.acquire(ctx, host)
+	wanted, err := t.Provider.ResolveVersion(ctx, constraint)
+	release()
+	if err != nil {
+		res.Err = fmt.Errorf("failed to resolve %q: %w", constraint, err)
+		return res
+	}
+	res.Wanted = wanted.Version
+	return res
+}
+
+// sourceHost groups version sources for rate limiting. Source descriptions have the
+// form "<kind>:<detail>" (e.g., "GitHub:cli/cli", "npm:turbo"), so the kind identifies
+// the upstream service.
+func sourceHost(source string) string {
+	kind, _, _ := strings.Cut(source, ":")
+	return kind
+}
+
+// hostLimiter bounds concurrency and request rate per version source
+type hostLimiter struct {
+	perHost  int
+	interval time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostSlot
+}
+
+type hostSlot struct {
+	sem  chan struct{}
+	next time.Time // earliest start time of the next request
+}
+
+func newHostLimiter(perHost int, interval time.Duration) *hostLimiter {
+	return &hostLimiter{
+		perHost:  perHost,
+		interval: interval,
+		hosts:    make(map[string]*hostSlot),
+	}
+}
+
+// acquire blocks until a request to host may start and returns the release function
+func (l *hostLimiter) acquire(ctx context.Context, host string) func() {
+	l.mu.Lock()
+	slot, ok := l.hosts[host]
+	if !ok {
+		slot = &hostSlot{sem: make(chan struct{}, l.perHost)}
+		l.hosts[host] = slot
+	}
+	l.mu.Unlock()
+
+	select {
+	case slot.sem <- struct{}{}:
+	case <-ctx.Done():
+		return func() {}
+	}
+
+	// Reserve the next start time under the lock, then wait outside it
+	l.mu.Lock()
+	now := time.Now()
+	start := slot.next
+	if start.Before(now) {
+		start = now
+	}
+	slot.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait := time.Until(start); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+		}
+	}
+
+	return func() { <-slot.sem }
+}
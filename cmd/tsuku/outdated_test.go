@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+// fakeOutdatedProvider resolves from a fixed version list (newest first) and
+// records peak concurrency
+type fakeOutdatedProvider struct {
+	source   string
+	versions []string
+	delay    time.Duration
+	inFlight *int32
+	peak     *int32
+}
+
+func (p *fakeOutdatedProvider) enter() func() {
+	if p.inFlight == nil {
+		return func() {}
+	}
+	n := atomic.AddInt32(p.inFlight, 1)
+	for {
+		peak := atomic.LoadInt32(p.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(p.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(p.delay)
+	return func() { atomic.AddInt32(p.inFlight, -1) }
+}
+
+func (p *fakeOutdatedProvider) ResolveLatest(ctx context.Context) (*version.VersionInfo, error) {
+	defer p.enter()()
+	if len(p.versions) == 0 {
+		return nil, fmt.Errorf("no versions")
+	}
+	return &version.VersionInfo{Version: p.versions[0]}, nil
+}
+
+func (p *fakeOutdatedProvider) ResolveVersion(ctx context.Context, v string) (*version.VersionInfo, error) {
+	defer p.enter()()
+	for _, candidate := range p.versions {
+		if candidate == v || strings.HasPrefix(candidate, v+".") {
+			return &version.VersionInfo{Version: candidate}, nil
+		}
+	}
+	return nil, fmt.Errorf("version %s not found", v)
+}
+
+func (p *fakeOutdatedProvider) SourceDescription() string { return p.source }
+
+func TestCheckOutdated(t *testing.T) {
+	targets := []outdatedTarget{
+		{Name: "ripgrep", Current: "1.9.0", Provider: &fakeOutdatedProvider{source: "GitHub:BurntSushi/ripgrep", versions: []string{"1.10.0", "1.9.0"}}},
+		{Name: "nodejs", Current: "20.9.0", Requested: "20", Provider: &fakeOutdatedProvider{source: "nodejs_dist", versions: []string{"22.1.0", "20.12.2", "20.9.0"}}},
+		{Name: "jq", Current: "1.7.1", Provider: &fakeOutdatedProvider{source: "GitHub:jqlang/jq", versions: []string{"1.7.1"}}},
+		{Name: "broken", Current: "1.0.0", Provider: &fakeOutdatedProvider{source: "npm:broken"}},
+		{Name: "pinned", Current: "2.0.0", Requested: "@2.0.0", Provider: &fakeOutdatedProvider{source: "crates.io:pinned", versions: []string{"2.1.0", "2.0.0"}}},
+	}
+
+	results := checkOutdated(context.Background(), targets, 3, newHostLimiter(2, 0))
+	if len(results) != len(targets) {
+		t.Fatalf("got %d results, want %d", len(results), len(targets))
+	}
+
+	byName := make(map[string]outdatedResult)
+	var names []string
+	for _, r := range results {
+		byName[r.Name] = r
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "broken,jq,nodejs,pinned,ripgrep" {
+		t.Errorf("results not sorted by name: %s", got)
+	}
+
+	tests := []struct {
+		name         string
+		wantWanted   string
+		wantLatest   string
+		wantOutdated bool
+	}{
+		{"ripgrep", "1.10.0", "1.10.0", true}, // 1.10 > 1.9 (not a string comparison)
+		{"nodejs", "20.12.2", "22.1.0", true},
+		{"jq", "1.7.1", "1.7.1", false},
+		{"pinned", "2.0.0", "2.1.0", true},
+	}
+	for _, tt := range tests {
+		r := byName[tt.name]
+		if r.Err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, r.Err)
+			continue
+		}
+		if r.Wanted != tt.wantWanted || r.Latest != tt.wantLatest {
+			t.Errorf("%s: wanted/latest = %s/%s, want %s/%s", tt.name, r.Wanted, r.Latest, tt.wantWanted, tt.wantLatest)
+		}
+		if r.isOutdated() != tt.wantOutdated {
+			t.Errorf("%s: isOutdated() = %v, want %v", tt.name, r.isOutdated(), tt.wantOutdated)
+		}
+	}
+
+	if byName["broken"].Err == nil {
+		t.Error("broken: expected error")
+	}
+	if byName["nodejs"].Source != "nodejs_dist" {
+		t.Errorf("nodejs: source = %q", byName["nodejs"].Source)
+	}
+}
+
+func TestCheckOutdated_PerHostLimit(t *testing.T) {
+	var inFlight, peak int32
+	var targets []outdatedTarget
+	for i := 0; i < 8; i++ {
+		targets = append(targets, outdatedTarget{
+			Name:    fmt.Sprintf("tool%d", i),
+			Current: "1.0.0",
+			Provider: &fakeOutdatedProvider{
+				source:   fmt.Sprintf("GitHub:owner/tool%d", i),
+				versions: []string{"1.0.0"},
+				delay:    20 * time.Millisecond,
+				inFlight: &inFlight,
+				peak:     &peak,
+			},
+		})
+	}
+
+	checkOutdated(context.Background(), targets, 8, newHostLimiter(2, 0))
+	if got := atomic.LoadInt32(&peak); got > 2 {
+		t.Errorf("peak concurrency against one host = %d, want <= 2", got)
+	}
+}
+
+func TestHostLimiter_Interval(t *testing.T) {
+	limiter := newHostLimiter(4, 30*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		limiter.acquire(ctx, "npm")()
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("3 requests to one host took %v, want >= 60ms spacing", elapsed)
+	}
+
+	// Other hosts are not delayed
+	start = time.Now()
+	limiter.acquire(ctx, "PyPI")()
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("first request to a new host took %v", elapsed)
+	}
+}
+
+func TestSourceHost(t *testing.T) {
+	tests := map[string]string{
+		"GitHub:cli/cli":  "GitHub",
+		"npm:turbo":       "npm",
+		"nodejs_dist":     "nodejs_dist",
+		"hashicorp:vault": "hashicorp",
+	}
+	for source, want := range tests {
+		if got := sourceHost(source); got != want {
+			t.Errorf("sourceHost(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
@@ -31,6 +31,10 @@ func runPlanBasedInstall(planPath, toolName string) error {
 		effectiveToolName = plan.Tool
 	}
 
+	if err := enforcePlanPolicy(effectiveToolName, plan); err != nil {
+		return err
+	}
+
 	// Initialize config and manager
 	cfg, err := config.DefaultConfig()
 	if err != nil {
@@ -38,6 +42,13 @@ func runPlanBasedInstall(planPath, toolName string) error {
 	}
 	mgr := install.New(cfg)
 
+	// Lock the tool and every dependency the plan installs
+	release, err := lockInstall(cfg, planLockNames(effectiveToolName, plan)...)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Create minimal recipe for executor context
 	// The executor needs a recipe to set up paths, but the plan contains all actual steps
 	minimalRecipe := &recipe.Recipe{
@@ -58,6 +69,9 @@ func runPlanBasedInstall(planPath, toolName string) error {
 
 	// Set tools directory for finding other installed tools
 	exec.SetToolsDir(cfg.ToolsDir)
+	configureArtifactCache(exec, cfg)
+	configureBuildCache(exec, cfg)
+	configureConfinement(exec)
 
 	printInfof("Installing %s@%s from plan...\n", effectiveToolName, plan.Version)
 
@@ -80,6 +94,7 @@ func runPlanBasedInstall(planPath, toolName string) error {
 		// Prepare install options
 		installOpts := install.DefaultInstallOptions()
 		installOpts.Plan = executor.ToStoragePlan(plan)
+		installOpts.UseStore = storeEnabled()
 
 		// Install to permanent location
 		if err := mgr.InstallWithOptions(effectiveToolName, plan.Version, exec.WorkDir(), installOpts); err != nil {
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/platform"
+	"github.com/tsukumogami/tsuku/internal/policy"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/validate"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Inspect the install policy",
+	Long: `Inspect the policy that restricts what tsuku may install.
+
+Policies are read from /etc/tsuku/policy.toml and $TSUKU_HOME/policy.toml.
+Both files are enforced: the user policy can add restrictions but cannot
+lift those of the system policy. Available rules:
+
+  allowed_recipes        Recipes that may be installed (glob patterns)
+  denied_recipes         Recipes that may not be installed (glob patterns)
+  allowed_registries     Registry URLs recipes may be fetched from
+  allow_local_recipes    Allow recipes from $TSUKU_HOME/recipes (default: true)
+  max_tier               Highest installation tier (1=binary, 2=package manager, 3=nix)
+  forbid_run_command     Reject plans with run_command steps
+  forbid_network_actions Reject plans with steps that need network access
+  require_signatures     Require signature-verified downloads
+  forbid_sudo            Reject recipes that require sudo
+
+Every install checks the recipe and the generated plan against the policy
+before anything is executed.`,
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check <tool>",
+	Short: "Check whether a tool may be installed",
+	Long: `Check a tool against the install policy without installing it.
+
+The recipe is loaded and an installation plan generated for the current
+platform, the same way 'tsuku install' does, and every rule violation is
+reported. Exits with code 9 when the policy blocks the tool.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runPolicyCheck,
+}
+
+func init() {
+	policyCheckCmd.Flags().Bool("json", false, "Output in JSON format")
+	policyCmd.AddCommand(policyCheckCmd)
+}
+
+// loadPolicy reads the system and user policy files
+func loadPolicy() (*policy.Policy, error) {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return policy.Load(cfg.PolicyFile)
+}
+
+// policySubject describes a tool for policy evaluation. The recipe source
+// is resolved through the shared loader.
+func policySubject(name string, r *recipe.Recipe, plan *executor.InstallationPlan) policy.Subject {
+	s := policy.Subject{Name: name, Recipe: r, Plan: plan}
+	if loader != nil {
+		s.Source = loader.Source(name)
+		if reg := loader.Registry(); reg != nil {
+			s.Registry = reg.BaseURL
+		}
+	}
+	return s
+}
+
+// enforcePolicy returns a *policy.Error when the policy blocks installing a
+// tool. The plan may be nil to check the recipe alone before its
+// dependencies are installed.
+func enforcePolicy(name string, r *recipe.Recipe, plan *executor.InstallationPlan) error {
+	p, err := loadPolicy()
+	if err != nil {
+		return err
+	}
+	return p.Check(policySubject(name, r, plan))
+}
+
+// enforcePlanPolicy checks a plan installed without going through its
+// recipe (external and stored plans). Recipe rules are applied when the
+// recipe is still available.
+func enforcePlanPolicy(name string, plan *executor.InstallationPlan) error {
+	p, err := loadPolicy()
+	if err != nil || p.Empty() {
+		return err
+	}
+	r, _ := loader.Get(name)
+	return p.Check(policySubject(name, r, plan))
+}
+
+func runPolicyCheck(cmd *cobra.Command, args []string) {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	toolName := args[0]
+
+	p, err := loadPolicy()
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitGeneral)
+	}
+
+	r, err := loader.Get(toolName)
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitRecipeNotFound)
+	}
+
+	plan, err := generatePolicyPlan(r)
+	if err != nil {
+		printError(fmt.Errorf("failed to generate plan: %w", err))
+		exitWithCode(ExitGeneral)
+	}
+
+	var violations []policy.Violation
+	checkErr := p.Check(policySubject(toolName, r, plan))
+	var policyErr *policy.Error
+	if errors.As(checkErr, &policyErr) {
+		violations = policyErr.Violations
+	}
+
+	if jsonOutput {
+		files := policyFiles(p)
+		if files == nil {
+			files = []string{}
+		}
+		if violations == nil {
+			violations = []policy.Violation{}
+		}
+		printJSON(struct {
+			Tool       string             `json:"tool"`
+			Version    string             `json:"version"`
+			Allowed    bool               `json:"allowed"`
+			Policies   []string           `json:"policies"`
+			Violations []policy.Violation `json:"violations"`
+		}{toolName, plan.Version, len(violations) == 0, files, violations})
+	} else {
+		switch {
+		case p.Empty():
+			printInfof("No install policy in effect; %s@%s may be installed\n", toolName, plan.Version)
+		case len(violations) == 0:
+			printInfof("%s@%s is allowed by %s\n", toolName, plan.Version, strings.Join(policyFiles(p), " and "))
+		default:
+			printError(checkErr)
+		}
+	}
+
+	if len(violations) > 0 {
+		exitWithCode(ExitPolicyViolation)
+	}
+}
+
+// generatePolicyPlan creates the installation plan 'tsuku install' would
+// execute on this platform
+func generatePolicyPlan(r *recipe.Recipe) (*executor.InstallationPlan, error) {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		return nil, err
+	}
+	exec, err := executor.New(r)
+	if err != nil {
+		return nil, err
+	}
+	defer exec.Cleanup()
+
+	return exec.GeneratePlan(globalCtx, executor.PlanConfig{
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		Libc:          platform.DefaultLibc(runtime.GOOS),
+		RecipeSource:  "registry",
+		Downloader:    validate.NewPreDownloaderAdapter(validate.NewPreDownloader()),
+		DownloadCache: actions.NewDownloadCache(cfg.DownloadCacheDir),
+		RecipeLoader:  loader,
+		OnWarning: func(action, message string) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", message)
+		},
+	})
+}
+
+// policyFiles lists the paths of the policy files in effect
+func policyFiles(p *policy.Policy) []string {
+	var paths []string
+	for _, f := range p.Files {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/install"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+var reinstallCmd = &cobra.Command{
+	Use:   "reinstall <tool>[@version]",
+	Short: "Reinstall a tool from its stored installation plan",
+	Long: `Reinstall an installed tool by replaying the installation plan recorded
+when it was installed.
+
+The plan pins exact download URLs and checksums, so the tool is rebuilt
+byte-for-byte from the download cache (or re-downloaded and verified against
+the plan checksums) without re-resolving versions or re-evaluating the recipe.
+Use this to restore a tool whose files were modified or deleted.
+
+Without a version, the active version is reinstalled.
+
+Examples:
+  tsuku reinstall ripgrep
+  tsuku reinstall nodejs@20.12.2`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		arg := args[0]
+
+		// Parse tool@version syntax
+		toolName := arg
+		targetVersion := ""
+		if strings.Contains(arg, "@") {
+			parts := strings.SplitN(arg, "@", 2)
+			toolName = parts[0]
+			targetVersion = parts[1]
+		}
+
+		cfg, err := config.DefaultConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get config: %v\n", err)
+			exitWithCode(ExitGeneral)
+		}
+
+		mgr := install.New(cfg)
+		if err := reinstallFromPlan(cfg, mgr, toolName, targetVersion); err != nil {
+			printError(err)
+			exitWithCode(installExitCode(err))
+		}
+	},
+}
+
+// reinstallFromPlan re-executes the stored plan of an installed tool version and
+// atomically replaces its tool directory with the result. An empty version selects
+// the active version.
+func reinstallFromPlan(cfg *config.Config, mgr *install.Manager, toolName, version string) error {
+	release, err := lockInstall(cfg, toolName)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	toolState, err := mgr.GetState().GetToolState(toolName)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if toolState == nil {
+		return fmt.Errorf("tool '%s' is not installed", toolName)
+	}
+	if version == "" {
+		version = toolState.ActiveVersion
+		if version == "" {
+			version = toolState.Version
+		}
+	}
+
+	versionState, ok := toolState.Versions[version]
+	if !ok {
+		return fmt.Errorf("version %s of '%s' is not installed", version, toolName)
+	}
+	if versionState.Plan == nil {
+		return fmt.Errorf("no stored installation plan for %s@%s (installed before plans were recorded)\nRun 'tsuku remove %s@%s' and 'tsuku install %s@%s' instead",
+			toolName, version, toolName, version, toolName, version)
+	}
+
+	plan := executor.FromStoragePlan(versionState.Plan)
+
+	// The plan may also reinstall dependencies
+	releaseDeps, err := lockInstall(cfg, planLockNames(toolName, plan)...)
+	if err != nil {
+		return err
+	}
+	defer releaseDeps()
+
+	// The policy may have changed since the version was installed
+	if err := enforcePlanPolicy(toolName, plan); err != nil {
+		return err
+	}
+
+	// Create minimal recipe for executor context; the plan contains all actual steps
+	minimalRecipe := &recipe.Recipe{
+		Metadata: recipe.MetadataSection{
+			Name: toolName,
+		},
+	}
+
+	exec, err := executor.NewWithVersion(minimalRecipe, plan.Version)
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+	defer exec.Cleanup()
+
+	// Downloads are served from the cache and verified against the plan checksums
+	exec.SetDownloadCacheDir(cfg.DownloadCacheDir)
+	exec.SetToolsDir(cfg.ToolsDir)
+	configureArtifactCache(exec, cfg)
+	configureBuildCache(exec, cfg)
+	configureConfinement(exec)
+
+	printInfof("Reinstalling %s@%s from stored plan...\n", toolName, version)
+
+	if err := exec.ExecutePlan(globalCtx, plan); err != nil {
+		var checksumErr *executor.ChecksumMismatchError
+		if errors.As(err, &checksumErr) {
+			fmt.Fprintf(os.Stderr, "\n%s\n", checksumErr.Error())
+			return err
+		}
+		return fmt.Errorf("plan execution failed: %w", err)
+	}
+
+	if err := mgr.Reinstall(toolName, version, exec.WorkDir()); err != nil {
+		return fmt.Errorf("failed to replace installation: %w", err)
+	}
+
+	printInfof("Reinstalled %s@%s\n", toolName, version)
+	return nil
+}
@@ -19,14 +19,21 @@ var removeCmd = &cobra.Command{
 Without a version, removes all installed versions of the tool.
 With @version syntax, removes only the specified version.
 
+With --cascade, also removes the dependencies and libraries that are no
+longer required by any explicitly installed tool, after listing them with
+the disk space reclaimed.
+
 Examples:
   tsuku remove kubectl           # Remove all versions
   tsuku remove kubectl@1.29.0    # Remove specific version
-  tsuku remove terraform`,
+  tsuku remove terraform
+  tsuku remove serverless --cascade   # Also remove nodejs if nothing else needs it`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		arg := args[0]
 		forceRemove, _ := cmd.Flags().GetBool("force")
+		cascade, _ := cmd.Flags().GetBool("cascade")
+		yes, _ := cmd.Flags().GetBool("yes")
 
 		// Parse tool@version syntax
 		toolName := arg
@@ -36,6 +43,10 @@ Examples:
 			toolName = parts[0]
 			targetVersion = parts[1]
 		}
+		if cascade && targetVersion != "" {
+			printError(fmt.Errorf("--cascade removes all versions and cannot be used with @version"))
+			exitWithCode(ExitUsage)
+		}
 
 		// Initialize telemetry
 		telemetryClient := telemetry.NewClient()
@@ -49,6 +60,15 @@ Examples:
 
 		mgr := install.New(cfg)
 
+		// Hold the tool's install lock so the removal can't interleave with
+		// an install of the same tool in another process
+		release, err := lockInstall(cfg, toolName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to remove %s: %v\n", toolName, err)
+			exitWithCode(ExitGeneral)
+		}
+		defer release()
+
 		// Check if tool is required by others (only when removing all versions)
 		state, err := mgr.GetState().Load()
 		if err == nil {
@@ -75,17 +95,26 @@ Examples:
 			}
 		}
 
-		// Perform removal
-		var removeErr error
-		if targetVersion != "" {
-			// Remove specific version
-			removeErr = mgr.RemoveVersion(toolName, targetVersion)
-		} else {
-			// Remove all versions
-			removeErr = mgr.RemoveAllVersions(toolName)
+		if cascade {
+			plan, releasePlan, err := planAndLockRemoval(cfg, mgr, toolName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to remove %s: %v\n", toolName, err)
+				exitWithCode(ExitGeneral)
+			}
+			defer releasePlan()
+			size := mgr.RemovalSize(plan)
+			if !confirmRemovalPlan(plan, size, false, yes) {
+				return
+			}
+			applyRemovalPlan(mgr, plan, size)
+			if telemetryClient != nil && removedVersion != "" {
+				telemetryClient.Send(telemetry.NewRemoveEvent(toolName, removedVersion))
+			}
+			return
 		}
 
-		if removeErr != nil {
+		// Perform removal
+		if removeErr := removeInstalled(cfg, mgr, toolName, targetVersion); removeErr != nil {
 			fmt.Fprintf(os.Stderr, "Failed to remove %s: %v\n", toolName, removeErr)
 			exitWithCode(ExitGeneral)
 		}
@@ -106,7 +135,7 @@ Examples:
 						printInfof("Warning: failed to update dependency state for %s: %v\n", dep, err)
 					}
 					// Try to cleanup orphan
-					cleanupOrphans(mgr, dep)
+					cleanupOrphans(cfg, mgr, dep)
 				}
 			}
 		}
@@ -119,7 +148,31 @@ Examples:
 	},
 }
 
-func cleanupOrphans(mgr *install.Manager, toolName string) {
+// removeInstalled removes one installed version of a tool, or all of them
+// when version is empty, under the tool's install lock
+func removeInstalled(cfg *config.Config, mgr *install.Manager, toolName, version string) error {
+	release, err := lockInstall(cfg, toolName)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if version != "" {
+		return mgr.RemoveVersion(toolName, version)
+	}
+	return mgr.RemoveAllVersions(toolName)
+}
+
+func cleanupOrphans(cfg *config.Config, mgr *install.Manager, toolName string) {
+	// Lock before reading state: an install of the dependency may be
+	// adding a RequiredBy entry right now
+	release, err := lockInstall(cfg, toolName)
+	if err != nil {
+		printInfof("Warning: failed to lock %s: %v\n", toolName, err)
+		return
+	}
+	defer release()
+
 	state, err := mgr.GetState().Load()
 	if err != nil {
 		return
@@ -141,27 +194,25 @@ func cleanupOrphans(mgr *install.Manager, toolName string) {
 	}
 
 	printInfof("Auto-removing orphaned dependency: %s\n", toolName)
-	if err := mgr.Remove(toolName); err != nil {
+	// Remove every version along with the state entry
+	if err := mgr.RemoveAllVersions(toolName); err != nil {
 		printInfof("Warning: failed to auto-remove %s: %v\n", toolName, err)
 		return
 	}
 
-	// Remove from state
-	if err := mgr.GetState().RemoveTool(toolName); err != nil {
-		printInfof("Warning: failed to remove tool from state: %v\n", err)
-	}
-
 	// Recursively clean up its dependencies
 	if r, err := loader.Get(toolName); err == nil {
 		for _, dep := range r.Metadata.Dependencies {
 			if err := mgr.GetState().RemoveRequiredBy(dep, toolName); err != nil {
 				printInfof("Warning: failed to update dependency state for %s: %v\n", dep, err)
 			}
-			cleanupOrphans(mgr, dep)
+			cleanupOrphans(cfg, mgr, dep)
 		}
 	}
 }
 
 func init() {
 	removeCmd.Flags().BoolP("force", "f", false, "Force removal even if other tools depend on this one")
+	removeCmd.Flags().Bool("cascade", false, "Also remove dependencies no longer required by any explicitly installed tool")
+	removeCmd.Flags().BoolP("yes", "y", false, "Skip the --cascade confirmation prompt")
 }
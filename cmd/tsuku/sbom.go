@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/sbom"
+)
+
+var sbomFormat string
+var sbomOutput string
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom [tools...]",
+	Short: "Export a software bill of materials for installed tools",
+	Long: `Export a software bill of materials (SBOM) of installed tools and
+libraries, built from state.json and the installation plan stored with
+each tool version.
+
+Every installed version is a component with its download URLs and SHA-256
+checksums, recipe hash and source, and the tools and libraries it depends
+on. Tools installed through npm, PyPI, crates.io, RubyGems, Go modules or
+CPAN carry a package URL (purl), and the packages pinned by their captured
+lockfiles are listed as components of the tool.
+
+Without arguments, everything installed is included. With tool names,
+only those tools and their dependencies are included.`,
+	Example: `  tsuku sbom > tsuku.cdx.json
+  tsuku sbom --format spdx --output tsuku.spdx.json
+  tsuku sbom ripgrep prettier`,
+	Run: runSBOM,
+}
+
+func init() {
+	sbomCmd.Flags().StringVar(&sbomFormat, "format", "cyclonedx", "Output format (cyclonedx, spdx)")
+	sbomCmd.Flags().StringVarP(&sbomOutput, "output", "o", "", "Write the SBOM to a file instead of stdout")
+}
+
+func runSBOM(cmd *cobra.Command, args []string) {
+	var write func(io.Writer, *sbom.Document) error
+	switch sbomFormat {
+	case "cyclonedx":
+		write = sbom.WriteCycloneDX
+	case "spdx":
+		write = sbom.WriteSPDX
+	default:
+		printError(fmt.Errorf("invalid format %q: must be one of cyclonedx, spdx", sbomFormat))
+		exitWithCode(ExitUsage)
+	}
+
+	doc, err := sbom.Build(loadInstallState(), args)
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitGeneral)
+	}
+
+	if sbomOutput == "" {
+		if err := write(os.Stdout, doc); err != nil {
+			printError(fmt.Errorf("failed to write SBOM: %w", err))
+			exitWithCode(ExitGeneral)
+		}
+		return
+	}
+
+	f, err := os.Create(sbomOutput)
+	if err != nil {
+		printError(fmt.Errorf("failed to create %s: %w", sbomOutput, err))
+		exitWithCode(ExitGeneral)
+	}
+	// Close before any exit: exitWithCode skips deferred calls, and a failed
+	// close can mean the SBOM was never fully written
+	err = write(f, doc)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		printError(fmt.Errorf("failed to write SBOM to %s: %w", sbomOutput, err))
+		exitWithCode(ExitGeneral)
+	}
+}
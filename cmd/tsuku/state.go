@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage the installation state file",
+	Long:  `Inspect and maintain $TSUKU_HOME/state.json, which records installed tools and libraries.`,
+}
+
+var stateMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate state.json to the current schema",
+	Long: `Migrate state.json to the schema version used by this tsuku.
+
+tsuku migrates state written by older versions automatically the first time
+it loads it, keeping the previous file as state.json.v<N>.bak. This command
+runs the migration explicitly; with --dry-run it only lists the migrations
+that would run.
+
+State written by a newer tsuku is never modified.`,
+	Example: `  tsuku state migrate --dry-run
+  tsuku state migrate`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		cfg, err := config.DefaultConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to get config: %v\n", err)
+			exitWithCode(ExitGeneral)
+		}
+
+		result, err := install.NewStateManager(cfg).Migrate(dryRun)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to migrate state: %v\n", err)
+			exitWithCode(ExitGeneral)
+		}
+
+		if len(result.Applied) == 0 {
+			printInfof("state.json is up to date (schema version %d)\n", result.ToVersion)
+			return
+		}
+
+		printInfof("state.json schema version %d -> %d\n", result.FromVersion, result.ToVersion)
+		for _, m := range result.Applied {
+			printInfof("  %d: %s\n", m.To, m.Description)
+		}
+		if dryRun {
+			printInfo("Dry run: no changes written.")
+			return
+		}
+		printInfof("Previous state backed up to %s\n", result.BackupPath)
+	},
+}
+
+func init() {
+	stateMigrateCmd.Flags().Bool("dry-run", false, "List the migrations that would run without writing")
+	stateCmd.AddCommand(stateMigrateCmd)
+}
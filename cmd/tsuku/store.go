@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/install"
+	"github.com/tsukumogami/tsuku/internal/userconfig"
+)
+
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Manage the content-addressed file store",
+	Long: `Manage the content-addressed file store in $TSUKU_HOME/store.
+
+With store.enabled set, installed files are kept once in the store, keyed by
+their SHA-256, and hardlinked into each tool directory, so versions sharing
+files share disk space. Enable it with:
+
+  tsuku config set store.enabled true
+
+Objects no installed tool uses are removed when a store-backed tool version
+is removed, or with "tsuku store gc".`,
+}
+
+var storeInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show store size and savings",
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		cfg := storeConfig()
+		stats, err := install.NewStore(cfg).Stats()
+		if err != nil {
+			printError(err)
+			exitWithCode(ExitGeneral)
+		}
+
+		if jsonOutput {
+			type storeInfoOutput struct {
+				Enabled      bool  `json:"enabled"`
+				Objects      int   `json:"objects"`
+				Size         int64 `json:"size_bytes"`
+				Unreferenced int   `json:"unreferenced"`
+				Saved        int64 `json:"saved_bytes"`
+			}
+			printJSON(storeInfoOutput{
+				Enabled:      storeEnabled(),
+				Objects:      stats.Objects,
+				Size:         stats.Size,
+				Unreferenced: stats.Unreferenced,
+				Saved:        stats.Saved,
+			})
+			return
+		}
+
+		fmt.Println("Store Information")
+		fmt.Println()
+		fmt.Printf("  Enabled:      %t\n", storeEnabled())
+		fmt.Printf("  Objects:      %d\n", stats.Objects)
+		fmt.Printf("  Size:         %s\n", formatBytes(stats.Size))
+		fmt.Printf("  Saved:        %s\n", formatBytes(stats.Saved))
+		fmt.Printf("  Unreferenced: %d\n", stats.Unreferenced)
+		fmt.Printf("  Path:         %s\n", cfg.StoreDir)
+	},
+}
+
+var storeGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove store objects no tool uses",
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, freed, err := install.NewStore(storeConfig()).GC()
+		if err != nil {
+			printError(err)
+			exitWithCode(ExitGeneral)
+		}
+		printInfof("Removed %d unused objects, freed %s\n", removed, formatBytes(freed))
+	},
+}
+
+var storeVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check store objects against their checksums",
+	Long: `Rehash every store object and compare it with the SHA-256 it is stored
+under. A corrupted object affects every tool version linking it; reinstall
+those tools after removing the object.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		mismatches, err := install.NewStore(storeConfig()).Verify()
+		if err != nil {
+			printError(err)
+			exitWithCode(ExitGeneral)
+		}
+		if len(mismatches) == 0 {
+			printInfo("All store objects match their checksums.")
+			return
+		}
+		for _, m := range mismatches {
+			if m.Error != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", m.Path, m.Error)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "%s: checksum %s\n", m.Path, m.Actual)
+		}
+		fmt.Fprintf(os.Stderr, "%d store objects are corrupted\n", len(mismatches))
+		exitWithCode(ExitGeneral)
+	},
+}
+
+func init() {
+	storeInfoCmd.Flags().Bool("json", false, "Output in JSON format")
+
+	storeCmd.AddCommand(storeInfoCmd)
+	storeCmd.AddCommand(storeGCCmd)
+	storeCmd.AddCommand(storeVerifyCmd)
+}
+
+// storeConfig returns the tsuku config, exiting on failure
+func storeConfig() *config.Config {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitGeneral)
+	}
+	return cfg
+}
+
+// storeEnabled reports whether installs go through the content-addressed
+// store (store.enabled in config.toml)
+func storeEnabled() bool {
+	userCfg, err := userconfig.Load()
+	if err != nil {
+		return false
+	}
+	return userCfg.Store.Enabled
+}
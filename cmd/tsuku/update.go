@@ -61,14 +61,14 @@ Examples:
 			printInfof("Checking updates for %s...\n", toolName)
 			if err := runDryRun(toolName, ""); err != nil {
 				printError(err)
-				exitWithCode(ExitInstallFailed)
+				exitWithCode(installExitCode(err))
 			}
 			return
 		}
 
 		printInfof("Updating %s...\n", toolName)
 		if err := runInstallWithTelemetry(toolName, "", "", true, "", telemetryClient); err != nil {
-			exitWithCode(ExitInstallFailed)
+			exitWithCode(installExitCode(err))
 		}
 
 		// Get the new version after update
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/tsukumogami/tsuku/internal/config"
 )
 
 var updateRegistryCmd = &cobra.Command{
@@ -21,6 +22,12 @@ automatic cache expiration.`,
 			return
 		}
 
+		// The cleared recipes could not be fetched again
+		if config.IsOffline() {
+			fmt.Fprintln(os.Stderr, "Error: update-registry is not available in offline mode: it would clear recipes that can't be fetched again")
+			exitWithCode(ExitGeneral)
+		}
+
 		printInfo("Clearing recipe cache...")
 		if err := reg.ClearCache(); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to clear cache: %v\n", err)
@@ -9,10 +9,57 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/executor"
 	"github.com/tsukumogami/tsuku/internal/install"
 	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/verify"
 )
 
+var (
+	verifyRepair       bool
+	verifyPlanPath     string
+	verifyReproducible bool
+)
+
+// verifyFunctionalTests runs the recipe's functional test cases with the tool's
+// bin directory on PATH. Returns true if all tests passed or none are defined.
+func verifyFunctionalTests(r *recipe.Recipe, version, installDir string) bool {
+	tests := r.Verify.Tests
+	if len(tests) == 0 {
+		return true
+	}
+
+	printInfof("  Functional tests: running %d tests...\n", len(tests))
+
+	env := append(os.Environ(), "PATH="+filepath.Join(installDir, "bin")+":"+os.Getenv("PATH"))
+	results := verify.RunFunctionalTests(globalCtx, tests, verify.Options{
+		Env: env,
+		Vars: map[string]string{
+			"version":     version,
+			"install_dir": installDir,
+		},
+	})
+
+	failed := 0
+	for i := range results {
+		res := &results[i]
+		if res.Passed() {
+			printInfof("    PASS %s\n", res.Name)
+			continue
+		}
+		failed++
+		fmt.Fprintf(os.Stderr, "    FAIL %s\n", res.Name)
+		fmt.Fprintf(os.Stderr, "%s", res.Report())
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "  Functional tests: %d of %d failed\n", failed, len(results))
+		return false
+	}
+	printInfof("  Functional tests: OK (%d passed)\n", len(results))
+	return true
+}
+
 // verifyBinaryIntegrity verifies the integrity of installed binaries using stored checksums.
 // Returns true if verification passed, false if there were mismatches or errors.
 // If no checksums are stored (pre-feature installation), prints a skip message and returns true.
@@ -46,10 +93,22 @@ func verifyBinaryIntegrity(toolDir string, versionState *install.VersionState) b
 		}
 	}
 	fmt.Fprintf(os.Stderr, "    WARNING: Binary may have been modified after installation.\n")
-	fmt.Fprintf(os.Stderr, "    Run 'tsuku install <tool> --reinstall' to restore original.\n")
+	if !verifyRepair {
+		fmt.Fprintf(os.Stderr, "    Run 'tsuku reinstall <tool>' (or 'tsuku verify <tool> --repair') to restore original.\n")
+	}
 	return false
 }
 
+// printOutputMatch reports which pattern and version the verification output matched
+func printOutputMatch(indent string, match *verify.OutputMatch) {
+	if match.Pattern != "" {
+		printInfof("%sPattern matched: %s\n", indent, match.Pattern)
+	}
+	if match.Version != "" {
+		printInfof("%sVersion matched: %s\n", indent, match.Version)
+	}
+}
+
 // truncateChecksum returns the first 12 characters of a checksum for display.
 func truncateChecksum(hash string) string {
 	if len(hash) > 12 {
@@ -64,10 +123,6 @@ func verifyWithAbsolutePath(r *recipe.Recipe, toolName, version, installDir stri
 	command = strings.ReplaceAll(command, "{version}", version)
 	command = strings.ReplaceAll(command, "{install_dir}", installDir)
 
-	pattern := r.Verify.Pattern
-	pattern = strings.ReplaceAll(pattern, "{version}", version)
-	pattern = strings.ReplaceAll(pattern, "{install_dir}", installDir)
-
 	printInfof("  Running: %s\n", command)
 
 	cmdExec := exec.Command("sh", "-c", command)
@@ -80,12 +135,15 @@ func verifyWithAbsolutePath(r *recipe.Recipe, toolName, version, installDir stri
 	outputStr := strings.TrimSpace(string(output))
 	printInfof("  Output: %s\n", outputStr)
 
-	if pattern != "" {
-		if !strings.Contains(outputStr, pattern) {
-			fmt.Fprintf(os.Stderr, "Output does not match expected pattern\n  Expected: %s\n  Got: %s\n", pattern, outputStr)
-			exitWithCode(ExitVerifyFailed)
-		}
-		printInfof("  Pattern matched: %s\n", pattern)
+	match, err := verify.MatchOutput(r.Verify, outputStr, version, installDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Output does not match expected pattern: %v\n  Got: %s\n", err, outputStr)
+		exitWithCode(ExitVerifyFailed)
+	}
+	printOutputMatch("  ", match)
+
+	if !verifyFunctionalTests(r, version, installDir) {
+		exitWithCode(ExitVerifyFailed)
 	}
 
 	// Binary integrity verification
@@ -100,15 +158,12 @@ func verifyVisibleTool(r *recipe.Recipe, toolName string, toolState *install.Too
 	printInfo("  Step 1: Verifying installation via symlink...")
 
 	command := r.Verify.Command
-	pattern := r.Verify.Pattern
 
 	// For visible tools, use the binary name directly (will resolve via current/)
 	// But first verify the symlink works by using absolute path
 	version := toolState.Version
 	command = strings.ReplaceAll(command, "{version}", version)
 	command = strings.ReplaceAll(command, "{install_dir}", installDir)
-	pattern = strings.ReplaceAll(pattern, "{version}", version)
-	pattern = strings.ReplaceAll(pattern, "{install_dir}", installDir)
 
 	printInfof("    Running: %s\n", command)
 	cmdExec := exec.Command("sh", "-c", command)
@@ -130,12 +185,13 @@ func verifyVisibleTool(r *recipe.Recipe, toolName string, toolState *install.Too
 	outputStr := strings.TrimSpace(string(output))
 	printInfof("    Output: %s\n", outputStr)
 
-	if pattern != "" && !strings.Contains(outputStr, pattern) {
-		fmt.Fprintf(os.Stderr, "    Pattern mismatch\n")
-		fmt.Fprintf(os.Stderr, "    Expected: %s\n", pattern)
+	match, err := verify.MatchOutput(r.Verify, outputStr, version, installDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "    Pattern mismatch: %v\n", err)
 		fmt.Fprintf(os.Stderr, "    Got: %s\n", outputStr)
 		exitWithCode(ExitVerifyFailed)
 	}
+	printOutputMatch("    ", match)
 	printInfo("    Installation verified\n")
 
 	// Step 2: Check if current/ is in PATH
@@ -207,6 +263,14 @@ func verifyVisibleTool(r *recipe.Recipe, toolName string, toolState *install.Too
 	if !verifyBinaryIntegrity(installDir, versionState) {
 		exitWithCode(ExitVerifyFailed)
 	}
+
+	// Step 5: Functional tests declared by the recipe
+	if len(r.Verify.Tests) > 0 {
+		printInfo("\n  Step 5: Running functional tests...")
+		if !verifyFunctionalTests(r, version, installDir) {
+			exitWithCode(ExitVerifyFailed)
+		}
+	}
 }
 
 var verifyCmd = &cobra.Command{
@@ -219,12 +283,32 @@ For visible tools, verification includes:
   2. Checking that the tool's bin directory is in PATH
   3. Verifying PATH resolution finds the correct binary
   4. Checking binary integrity against stored checksums
+  5. Running the recipe's functional tests, if it declares any
 
-For hidden tools (execution dependencies), only the verification command is run.
+For hidden tools (execution dependencies), only the verification command,
+binary integrity check and functional tests are run.
+
+Functional tests ([[verify.tests]] in the recipe) exercise the tool beyond a
+version check: each test runs a command in a fresh temporary directory with
+optional stdin, and checks the exit code, a stdout regular expression and the
+files the command created.
 
 Binary integrity verification detects post-installation tampering by comparing
 current SHA256 checksums against those stored at installation time. Tools
-installed before this feature will show "Integrity: SKIPPED".`,
+installed before this feature will show "Integrity: SKIPPED".
+
+With --repair, a tool whose binaries fail the integrity check is reinstalled
+from its stored installation plan (see 'tsuku reinstall') before the remaining
+checks run.
+
+With --plan, the verification section is read from an installation plan file
+instead of the recipe (for tools installed with 'tsuku install --plan').
+
+With --reproducible, the tool's stored installation plan is instead executed
+twice, each time into a fresh temporary $TSUKU_HOME, and the two install trees
+are compared by file content, mode and symlink targets (modification times
+and paths under $TSUKU_HOME are ignored). Paths that differ are listed, and the
+result is compared with the tree digest recorded when the tool was installed.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		toolName := args[0]
@@ -251,11 +335,25 @@ installed before this feature will show "Integrity: SKIPPED".`,
 			exitWithCode(ExitGeneral)
 		}
 
-		// Load recipe
-		r, err := loader.Get(toolName)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to load recipe: %v\n", err)
-			exitWithCode(ExitRecipeNotFound)
+		if verifyReproducible {
+			verifyReproducibleBuild(cfg, toolName, &toolState)
+			return
+		}
+
+		// Load recipe, or take the verification section from a plan
+		var r *recipe.Recipe
+		if verifyPlanPath != "" {
+			r, err = recipeFromPlanVerify(verifyPlanPath, toolName)
+			if err != nil {
+				printError(err)
+				exitWithCode(ExitGeneral)
+			}
+		} else {
+			r, err = loader.Get(toolName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to load recipe: %v\n", err)
+				exitWithCode(ExitRecipeNotFound)
+			}
 		}
 
 		// Check if recipe has verification
@@ -281,6 +379,23 @@ installed before this feature will show "Integrity: SKIPPED".`,
 			}
 		}
 
+		// Repair modified binaries before running the checks below
+		if verifyRepair && !verifyBinaryIntegrity(installDir, versionState) {
+			printInfo("  Repairing from stored installation plan...")
+			if err := reinstallFromPlan(cfg, mgr, toolName, toolState.Version); err != nil {
+				printError(err)
+				exitWithCode(ExitVerifyFailed)
+			}
+
+			// Pick up the re-computed checksums
+			if ts, err := mgr.GetState().GetToolState(toolName); err == nil && ts != nil {
+				if vs, ok := ts.Versions[toolState.Version]; ok {
+					versionState = &vs
+				}
+			}
+			printInfo()
+		}
+
 		// Determine verification strategy based on tool visibility
 		if toolState.IsHidden {
 			// Hidden tools: verify with absolute path
@@ -294,3 +409,78 @@ installed before this feature will show "Integrity: SKIPPED".`,
 		printInfof("%s is working correctly\n", toolName)
 	},
 }
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyRepair, "repair", false, "Reinstall from the stored plan if binary integrity verification fails")
+	verifyCmd.Flags().StringVar(&verifyPlanPath, "plan", "", "Use the verification section of a plan file (use '-' for stdin)")
+	verifyCmd.Flags().BoolVar(&verifyReproducible, "reproducible", false, "Build the stored plan twice in isolation and compare the results")
+}
+
+// recipeFromPlanVerify builds a minimal recipe carrying the verification section
+// of an installation plan
+func recipeFromPlanVerify(planPath, toolName string) (*recipe.Recipe, error) {
+	plan, err := loadPlanFromSource(planPath)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Tool != toolName {
+		return nil, fmt.Errorf("plan is for tool '%s', not '%s'", plan.Tool, toolName)
+	}
+
+	r := &recipe.Recipe{
+		Metadata: recipe.MetadataSection{
+			Name: toolName,
+			Type: plan.RecipeType,
+		},
+	}
+	r.Verify = plan.Verify.Section()
+	return r, nil
+}
+
+// verifyReproducibleBuild executes the stored plan of the active version twice
+// and reports whether both builds produced the same tree
+func verifyReproducibleBuild(cfg *config.Config, toolName string, toolState *install.ToolState) {
+	version := toolState.ActiveVersion
+	if version == "" {
+		version = toolState.Version
+	}
+	versionState, ok := toolState.Versions[version]
+	if !ok || versionState.Plan == nil {
+		fmt.Fprintf(os.Stderr, "No stored installation plan for %s@%s (installed before plans were recorded)\n", toolName, version)
+		exitWithCode(ExitGeneral)
+	}
+
+	plan := executor.FromStoragePlan(versionState.Plan)
+	printInfof("Checking reproducibility of %s@%s (%d isolated builds)...\n", toolName, version, executor.ReproducibilityRuns)
+	if !plan.Deterministic {
+		printInfo("Note: the plan is not marked deterministic; ecosystem and source builds may differ legitimately")
+	}
+	printInfo()
+
+	report, err := executor.CheckReproducible(globalCtx, plan, cfg.DownloadCacheDir)
+	if err != nil {
+		printError(err)
+		exitWithCode(ExitVerifyFailed)
+	}
+
+	for i, digest := range report.Digests {
+		printInfof("  Build %d digest: %s\n", i+1, digest)
+	}
+	switch {
+	case versionState.TreeDigest == "":
+		printInfo("  Installed tree: no digest recorded")
+	case versionState.TreeDigest == report.Digest():
+		printInfo("  Installed tree: matches")
+	default:
+		printInfof("  Installed tree: differs (%s)\n", versionState.TreeDigest)
+	}
+
+	if !report.Reproducible() {
+		fmt.Fprintf(os.Stderr, "\n%s@%s is not reproducible: %d path(s) differ between builds\n", toolName, version, len(report.Differences))
+		for _, d := range report.Differences {
+			fmt.Fprintf(os.Stderr, "  %s: %s\n", d.Path, d.Detail)
+		}
+		exitWithCode(ExitVerifyFailed)
+	}
+	printInfof("\n%s@%s is reproducible\n", toolName, version)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecipeFromPlanVerify(t *testing.T) {
+	planJSON := `{
+		"format_version": 3,
+		"tool": "jq",
+		"version": "1.7.1",
+		"platform": {"os": "linux", "arch": "amd64"},
+		"steps": [],
+		"verify": {
+			"command": "jq --version",
+			"pattern": "jq-{version}",
+			"tests": [{"name": "filter", "command": "jq -r .a", "stdin": "{\"a\": 1}", "stdout": "^1$", "exit_code": 0}]
+		}
+	}`
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(planJSON), 0644); err != nil {
+		t.Fatalf("failed to write plan: %v", err)
+	}
+
+	r, err := recipeFromPlanVerify(planPath, "jq")
+	if err != nil {
+		t.Fatalf("recipeFromPlanVerify() error = %v", err)
+	}
+	if r.Verify.Command != "jq --version" || r.Verify.Pattern != "jq-{version}" {
+		t.Errorf("Verify = %+v", r.Verify)
+	}
+	if len(r.Verify.Tests) != 1 {
+		t.Fatalf("got %d functional tests, want 1", len(r.Verify.Tests))
+	}
+	test := r.Verify.Tests[0]
+	if test.Stdin != `{"a": 1}` || test.Stdout != "^1$" || test.ExitCode == nil || *test.ExitCode != 0 {
+		t.Errorf("functional test = %+v", test)
+	}
+
+	if _, err := recipeFromPlanVerify(planPath, "yq"); err == nil {
+		t.Error("expected error for plan of a different tool")
+	}
+}
@@ -59,7 +59,15 @@ var versionsCmd = &cobra.Command{
 		var versions []string
 		var fromCache bool
 
-		if refresh {
+		if config.IsOffline() {
+			// The offline provider reads the cache itself, even when expired;
+			// going through cachedLister would rewrite it as fresh
+			versions, err = lister.ListVersions(ctx)
+			fromCache = true
+			if err == nil && !jsonOutput {
+				fmt.Printf("Using cached versions for %s (%s) [offline]\n", toolName, provider.SourceDescription())
+			}
+		} else if refresh {
 			// Bypass cache with --refresh flag
 			if !jsonOutput {
 				fmt.Printf("Fetching fresh versions for %s (%s)...\n", toolName, provider.SourceDescription())
@@ -11,23 +11,27 @@ import (
 
 // ExecutionContext provides context for action execution
 type ExecutionContext struct {
-	Context          context.Context   // Context for cancellation, timeouts, and deadlines
-	WorkDir          string            // Temporary work directory
-	InstallDir       string            // Installation directory (~/.tsuku/tools/.install/)
-	ToolInstallDir   string            // Tool-specific directory for directory-based installations (~/.tsuku/tools/{name}-{version}/)
-	ToolsDir         string            // Tools directory (~/.tsuku/tools/) for finding other installed tools
-	LibsDir          string            // Libraries directory (~/.tsuku/libs/) for finding installed libraries
-	DownloadCacheDir string            // Download cache directory (~/.tsuku/cache/downloads/)
-	Version          string            // Resolved version (e.g., "1.29.3")
-	VersionTag       string            // Original version tag (e.g., "v1.29.3" or "1.29.3")
-	OS               string            // Target OS (runtime.GOOS)
-	Arch             string            // Target architecture (runtime.GOARCH)
-	Recipe           *recipe.Recipe    // Full recipe (for reference)
-	ExecPaths        []string          // Additional bin paths needed for execution (e.g., nodejs bin for npm tools)
-	Resolver         *version.Resolver // Version resolver (for GitHub API access, asset resolution)
-	Logger           log.Logger        // Logger for structured logging (optional, falls back to log.Default())
-	Dependencies     ResolvedDeps      // Resolved dependencies with their versions
-	Env              []string          // Shared environment variables set by setup_build_env, used by build actions
+	Context           context.Context   // Context for cancellation, timeouts, and deadlines
+	WorkDir           string            // Temporary work directory
+	InstallDir        string            // Installation directory (~/.tsuku/tools/.install/)
+	ToolInstallDir    string            // Tool-specific directory for directory-based installations (~/.tsuku/tools/{name}-{version}/)
+	ToolsDir          string            // Tools directory (~/.tsuku/tools/) for finding other installed tools
+	LibsDir           string            // Libraries directory (~/.tsuku/libs/) for finding installed libraries
+	DownloadCacheDir  string            // Download cache directory (~/.tsuku/cache/downloads/)
+	BuildCacheDir     string            // Compiler cache directory (~/.tsuku/cache/build/), "" disables ccache/sccache
+	BuildCacheMaxSize string            // Compiler cache size limit (e.g. "5G"), "" for the tool default
+	Version           string            // Resolved version (e.g., "1.29.3")
+	VersionTag        string            // Original version tag (e.g., "v1.29.3" or "1.29.3")
+	OS                string            // Target OS (runtime.GOOS)
+	Arch              string            // Target architecture (runtime.GOARCH)
+	Libc              string            // Target C library on Linux ("glibc" or "musl"), "" elsewhere
+	Recipe            *recipe.Recipe    // Full recipe (for reference)
+	ExecPaths         []string          // Additional bin paths needed for execution (e.g., nodejs bin for npm tools)
+	Resolver          *version.Resolver // Version resolver (for GitHub API access, asset resolution)
+	Logger            log.Logger        // Logger for structured logging (optional, falls back to log.Default())
+	Dependencies      ResolvedDeps      // Resolved dependencies with their versions
+	Env               []string          // Shared environment variables set by setup_build_env, used by build actions
+	Confinement       *Confinement      // Landlock confinement for started commands, nil runs them unconfined
 }
 
 // Log returns the logger for this context.
@@ -146,7 +150,12 @@ func init() {
 	Register(&LinkDependenciesAction{})
 	Register(&AptInstallAction{})
 	Register(&YumInstallAction{})
+	Register(&DnfInstallAction{})
+	Register(&ApkInstallAction{})
+	Register(&PacmanInstallAction{})
+	Register(&ZypperInstallAction{})
 	Register(&BrewInstallAction{})
+	Register(&SystemPackageAction{})
 	Register(&RequireSystemAction{})
 
 	// Package manager actions (composite)
@@ -178,4 +187,8 @@ func init() {
 	Register(&DownloadArchiveAction{})
 	Register(&GitHubArchiveAction{})
 	Register(&GitHubFileAction{})
+	Register(&GitLabArchiveAction{})
+	Register(&GitLabFileAction{})
+	Register(&GiteaArchiveAction{})
+	Register(&GiteaFileAction{})
 }
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"path/filepath"
 	"strings"
+
+	"github.com/tsukumogami/tsuku/internal/config"
 )
 
 // ApplyPatchAction applies a patch file using the system patch command.
@@ -82,6 +84,9 @@ func (a *ApplyPatchAction) Execute(ctx *ExecutionContext, params map[string]inte
 		}
 
 		// Download patch
+		if config.IsOffline() {
+			return &OfflineError{Action: "apply_patch", Missing: url}
+		}
 		content, err := downloadPatch(url)
 		if err != nil {
 			return fmt.Errorf("apply_patch: failed to download patch: %w", err)
@@ -0,0 +1,255 @@
+package actions
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Compiler caches wired into source builds when ctx.BuildCacheDir is set:
+// ccache for C/C++ (configure_make, cmake_build, meson_build) and sccache for
+// Rust (cargo_build). Both keep their data under ctx.BuildCacheDir.
+
+// ccacheCompilers are the compiler names ccache masquerades as. Build
+// systems find them first in PATH and ccache runs the next one in PATH.
+var ccacheCompilers = []string{"cc", "gcc", "c++", "g++", "clang", "clang++"}
+
+// buildCacheVars are the environment variables set by applyBuildCache
+var buildCacheVars = []string{
+	"CCACHE_DIR=",
+	"CCACHE_MAXSIZE=",
+	"CCACHE_BASEDIR=",
+	"CCACHE_NOHASHDIR=",
+	"RUSTC_WRAPPER=",
+	"SCCACHE_DIR=",
+	"SCCACHE_CACHE_SIZE=",
+}
+
+// buildCacheAllowed reports whether a step may use a compiler cache. Steps
+// marked deterministic_sensitive must produce output that depends only on
+// their inputs (e.g. reproducibility checks), so they always compile from
+// scratch.
+func buildCacheAllowed(ctx *ExecutionContext, params map[string]interface{}) bool {
+	if ctx.BuildCacheDir == "" {
+		return false
+	}
+	if sensitive, _ := GetBool(params, "deterministic_sensitive"); sensitive {
+		return false
+	}
+	return true
+}
+
+// withBuildCache returns env with the compiler caches enabled when the step
+// allows it, and with any caches inherited from setup_build_env removed when
+// it doesn't.
+func withBuildCache(ctx *ExecutionContext, params map[string]interface{}, env []string) []string {
+	env = stripBuildCache(ctx, env)
+	if !buildCacheAllowed(ctx, params) {
+		return env
+	}
+	return applyBuildCache(ctx, env)
+}
+
+// applyBuildCache adds ccache and sccache to env. Tools that aren't installed
+// are skipped.
+func applyBuildCache(ctx *ExecutionContext, env []string) []string {
+	if ccache := resolveCompilerCache(ctx.ToolsDir, "ccache"); ccache != "" {
+		binDir := ccacheBinDir(ctx)
+		if err := setupCcacheMasquerade(ccache, binDir); err == nil {
+			env = prependPath(env, binDir)
+			env = append(env,
+				"CCACHE_DIR="+filepath.Join(ctx.BuildCacheDir, "ccache"),
+				// Work directories are temporary; hash paths relative to them
+				"CCACHE_BASEDIR="+ctx.WorkDir,
+				"CCACHE_NOHASHDIR=1",
+			)
+			if ctx.BuildCacheMaxSize != "" {
+				env = append(env, "CCACHE_MAXSIZE="+ctx.BuildCacheMaxSize)
+			}
+		}
+	}
+
+	if sccache := resolveCompilerCache(ctx.ToolsDir, "sccache"); sccache != "" {
+		env = append(env,
+			"RUSTC_WRAPPER="+sccache,
+			"SCCACHE_DIR="+filepath.Join(ctx.BuildCacheDir, "sccache"),
+		)
+		if ctx.BuildCacheMaxSize != "" {
+			env = append(env, "SCCACHE_CACHE_SIZE="+ctx.BuildCacheMaxSize)
+		}
+	}
+	return env
+}
+
+// stripBuildCache removes the variables and PATH entry added by
+// applyBuildCache
+func stripBuildCache(ctx *ExecutionContext, env []string) []string {
+	binDir := ccacheBinDir(ctx)
+	out := make([]string, 0, len(env))
+	for _, e := range env {
+		if hasAnyPrefix(e, buildCacheVars) {
+			continue
+		}
+		if path, ok := strings.CutPrefix(e, "PATH="); ok && binDir != "" {
+			var kept []string
+			for _, p := range filepath.SplitList(path) {
+				if p != binDir {
+					kept = append(kept, p)
+				}
+			}
+			e = "PATH=" + strings.Join(kept, string(os.PathListSeparator))
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// ccacheBinDir returns the directory of ccache's compiler symlinks
+func ccacheBinDir(ctx *ExecutionContext) string {
+	if ctx.BuildCacheDir == "" {
+		return ""
+	}
+	return filepath.Join(ctx.BuildCacheDir, "ccache-bin")
+}
+
+// setupCcacheMasquerade creates cc, gcc, c++ etc. symlinks to ccache in dir
+func setupCcacheMasquerade(ccache, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range ccacheCompilers {
+		link := filepath.Join(dir, name)
+		if target, err := os.Readlink(link); err == nil && target == ccache {
+			continue
+		}
+		os.Remove(link)
+		if err := os.Symlink(ccache, link); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveCompilerCache finds a compiler cache binary, preferring the newest
+// version installed by tsuku over one in PATH. Returns "" if not found.
+func resolveCompilerCache(toolsDir, name string) string {
+	if toolsDir != "" {
+		entries, _ := os.ReadDir(toolsDir)
+		var dirs []string
+		for _, entry := range entries {
+			if entry.IsDir() && strings.HasPrefix(entry.Name(), name+"-") {
+				dirs = append(dirs, entry.Name())
+			}
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+		for _, dir := range dirs {
+			for _, candidate := range []string{filepath.Join(toolsDir, dir, "bin", name), filepath.Join(toolsDir, dir, name)} {
+				if info, err := os.Stat(candidate); err == nil && info.Mode()&0111 != 0 {
+					return candidate
+				}
+			}
+		}
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	return ""
+}
+
+// prependPath puts dir at the front of PATH in env
+func prependPath(env []string, dir string) []string {
+	for i, e := range env {
+		if path, ok := strings.CutPrefix(e, "PATH="); ok {
+			env[i] = "PATH=" + dir + string(os.PathListSeparator) + path
+			return env
+		}
+	}
+	return append(env, "PATH="+dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildCacheStats describes one compiler cache under the build cache directory
+type BuildCacheStats struct {
+	Name   string // "ccache" or "sccache"
+	Dir    string
+	Size   int64 // Bytes on disk
+	Hits   int64 // Cache hits, -1 if unknown
+	Misses int64 // Cache misses, -1 if unknown
+}
+
+// GetBuildCacheStats returns disk usage of the compiler caches under dir and,
+// for ccache, its hit and miss counters.
+func GetBuildCacheStats(dir, toolsDir string) []BuildCacheStats {
+	var stats []BuildCacheStats
+	for _, name := range []string{"ccache", "sccache"} {
+		s := BuildCacheStats{Name: name, Dir: filepath.Join(dir, name), Hits: -1, Misses: -1}
+		_ = filepath.Walk(s.Dir, func(_ string, info os.FileInfo, err error) error {
+			if err == nil && info.Mode().IsRegular() {
+				s.Size += info.Size()
+			}
+			return nil
+		})
+		if name == "ccache" && s.Size > 0 {
+			s.Hits, s.Misses = ccacheCounters(resolveCompilerCache(toolsDir, "ccache"), s.Dir)
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// ccacheCounters reads hit and miss counts with "ccache --print-stats"
+// (ccache 4+). Returns -1, -1 when they can't be read.
+func ccacheCounters(ccache, dir string) (int64, int64) {
+	if ccache == "" {
+		return -1, -1
+	}
+	cmd := exec.Command(ccache, "--print-stats")
+	cmd.Env = append(os.Environ(), "CCACHE_DIR="+dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return -1, -1
+	}
+	var hits, misses int64
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		n, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "direct_cache_hit", "preprocessed_cache_hit":
+			hits += n
+		case "cache_miss":
+			misses += n
+		}
+	}
+	return hits, misses
+}
+
+// describeBuildCache returns the names of the compiler caches enabled in env
+func describeBuildCache(env []string) []string {
+	var caches []string
+	for _, e := range env {
+		switch {
+		case strings.HasPrefix(e, "CCACHE_DIR="):
+			caches = append(caches, "ccache")
+		case strings.HasPrefix(e, "RUSTC_WRAPPER="):
+			caches = append(caches, "sccache")
+		}
+	}
+	return caches
+}
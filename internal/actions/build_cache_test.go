@@ -0,0 +1,160 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newFakeCompilerCaches installs stub ccache and sccache binaries the way
+// tsuku lays out tools and returns the tools directory
+func newFakeCompilerCaches(t *testing.T) string {
+	t.Helper()
+	toolsDir := t.TempDir()
+	for _, tool := range []string{"ccache-4.10", "sccache-0.8.2"} {
+		binDir := filepath.Join(toolsDir, tool, "bin")
+		if err := os.MkdirAll(binDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		name := strings.SplitN(tool, "-", 2)[0]
+		if err := os.WriteFile(filepath.Join(binDir, name), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return toolsDir
+}
+
+func lookupEnv(env []string, key string) (string, bool) {
+	for _, e := range env {
+		if v, ok := strings.CutPrefix(e, key+"="); ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func TestWithBuildCache_Enabled(t *testing.T) {
+	t.Parallel()
+	toolsDir := newFakeCompilerCaches(t)
+	cacheDir := t.TempDir()
+	ctx := &ExecutionContext{
+		Context:           context.Background(),
+		WorkDir:           t.TempDir(),
+		ToolsDir:          toolsDir,
+		BuildCacheDir:     cacheDir,
+		BuildCacheMaxSize: "2G",
+	}
+
+	env := withBuildCache(ctx, nil, []string{"PATH=/usr/bin"})
+
+	if got, _ := lookupEnv(env, "CCACHE_DIR"); got != filepath.Join(cacheDir, "ccache") {
+		t.Errorf("CCACHE_DIR = %q", got)
+	}
+	if got, _ := lookupEnv(env, "CCACHE_MAXSIZE"); got != "2G" {
+		t.Errorf("CCACHE_MAXSIZE = %q, want 2G", got)
+	}
+	if got, _ := lookupEnv(env, "RUSTC_WRAPPER"); got != filepath.Join(toolsDir, "sccache-0.8.2", "bin", "sccache") {
+		t.Errorf("RUSTC_WRAPPER = %q", got)
+	}
+	if got, _ := lookupEnv(env, "SCCACHE_DIR"); got != filepath.Join(cacheDir, "sccache") {
+		t.Errorf("SCCACHE_DIR = %q", got)
+	}
+
+	binDir := filepath.Join(cacheDir, "ccache-bin")
+	if got, _ := lookupEnv(env, "PATH"); got != binDir+string(os.PathListSeparator)+"/usr/bin" {
+		t.Errorf("PATH = %q, want ccache-bin first", got)
+	}
+	target, err := os.Readlink(filepath.Join(binDir, "gcc"))
+	if err != nil || target != filepath.Join(toolsDir, "ccache-4.10", "bin", "ccache") {
+		t.Errorf("gcc symlink = %q (%v), want tsuku ccache", target, err)
+	}
+}
+
+func TestWithBuildCache_Disabled(t *testing.T) {
+	t.Parallel()
+	ctx := &ExecutionContext{
+		Context:  context.Background(),
+		ToolsDir: newFakeCompilerCaches(t),
+	}
+
+	env := withBuildCache(ctx, nil, []string{"PATH=/usr/bin"})
+	if _, ok := lookupEnv(env, "CCACHE_DIR"); ok {
+		t.Error("CCACHE_DIR set without a build cache directory")
+	}
+	if _, ok := lookupEnv(env, "RUSTC_WRAPPER"); ok {
+		t.Error("RUSTC_WRAPPER set without a build cache directory")
+	}
+}
+
+func TestWithBuildCache_DeterministicSensitive(t *testing.T) {
+	t.Parallel()
+	ctx := &ExecutionContext{
+		Context:       context.Background(),
+		WorkDir:       t.TempDir(),
+		ToolsDir:      newFakeCompilerCaches(t),
+		BuildCacheDir: t.TempDir(),
+	}
+
+	// Environment inherited from setup_build_env has the caches enabled
+	inherited := withBuildCache(ctx, nil, []string{"PATH=/usr/bin", "CC=gcc"})
+
+	env := withBuildCache(ctx, map[string]interface{}{"deterministic_sensitive": true}, inherited)
+	for _, key := range []string{"CCACHE_DIR", "CCACHE_BASEDIR", "RUSTC_WRAPPER", "SCCACHE_DIR"} {
+		if _, ok := lookupEnv(env, key); ok {
+			t.Errorf("%s still set for deterministic_sensitive step", key)
+		}
+	}
+	if got, _ := lookupEnv(env, "PATH"); got != "/usr/bin" {
+		t.Errorf("PATH = %q, want ccache-bin removed", got)
+	}
+	if got, _ := lookupEnv(env, "CC"); got != "gcc" {
+		t.Errorf("CC = %q, want unrelated variables kept", got)
+	}
+}
+
+func TestResolveCompilerCache_PrefersNewestTsukuVersion(t *testing.T) {
+	t.Parallel()
+	toolsDir := newFakeCompilerCaches(t)
+	newer := filepath.Join(toolsDir, "ccache-4.11", "bin")
+	if err := os.MkdirAll(newer, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(newer, "ccache"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resolveCompilerCache(toolsDir, "ccache"); got != filepath.Join(newer, "ccache") {
+		t.Errorf("resolveCompilerCache() = %q, want %q", got, filepath.Join(newer, "ccache"))
+	}
+}
+
+func TestGetBuildCacheStats(t *testing.T) {
+	t.Parallel()
+	cacheDir := t.TempDir()
+	sccacheDir := filepath.Join(cacheDir, "sccache", "a")
+	if err := os.MkdirAll(sccacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sccacheDir, "entry"), make([]byte, 1024), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := GetBuildCacheStats(cacheDir, "")
+	if len(stats) != 2 {
+		t.Fatalf("GetBuildCacheStats() returned %d caches, want 2", len(stats))
+	}
+	for _, s := range stats {
+		switch s.Name {
+		case "ccache":
+			if s.Size != 0 || s.Hits != -1 {
+				t.Errorf("empty ccache stats = %+v", s)
+			}
+		case "sccache":
+			if s.Size != 1024 {
+				t.Errorf("sccache size = %d, want 1024", s.Size)
+			}
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -39,6 +38,7 @@ func (a *CargoBuildAction) Name() string {
 //   - no_default_features (optional): Disable default features (default: false)
 //   - all_features (optional): Enable all features (default: false)
 //   - rust_version (optional): Required Rust compiler version (e.g., "1.76.0")
+//   - deterministic_sensitive (optional): Never use the sccache for this step (default: false)
 //
 // Deterministic Configuration:
 //   - SOURCE_DATE_EPOCH: Set to Unix epoch (0) for reproducible embedded timestamps
@@ -148,7 +148,7 @@ func (a *CargoBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 	fmt.Printf("   Using cargo: %s\n", cargoPath)
 
 	// Set up deterministic environment with isolated CARGO_HOME
-	env := buildDeterministicCargoEnv(cargoPath, ctx.WorkDir)
+	env := withBuildCache(ctx, params, buildDeterministicCargoEnv(cargoPath, ctx.WorkDir))
 
 	// Validate Rust version if specified
 	if rustVersion != "" {
@@ -186,7 +186,7 @@ func (a *CargoBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 			fetchArgs = append(fetchArgs, "--target", target)
 		}
 
-		fetchCmd := exec.CommandContext(ctx.Context, cargoPath, fetchArgs...)
+		fetchCmd := ctx.Command(cargoPath, fetchArgs...)
 		fetchCmd.Dir = sourceDir
 		fetchCmd.Env = env
 		fetchOutput, err := fetchCmd.CombinedOutput()
@@ -230,7 +230,7 @@ func (a *CargoBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 	}
 
 	// Execute cargo build
-	cmd := exec.CommandContext(ctx.Context, cargoPath, args...)
+	cmd := ctx.Command(cargoPath, args...)
 	cmd.Dir = sourceDir
 	cmd.Env = env
 
@@ -379,7 +379,7 @@ func (a *CargoBuildAction) executeLockDataMode(ctx *ExecutionContext, params map
 	fmt.Printf("   Downloading crate from crates.io...\n")
 	// Use -f to fail on HTTP errors, -L to follow redirects, -S to show errors
 	// Add User-Agent to avoid rate limiting
-	downloadCmd := exec.CommandContext(ctx.Context, "curl", "-fsSL", "-A", "tsuku", "-o", crateTarball, crateURL)
+	downloadCmd := ctx.Command("curl", "-fsSL", "-A", "tsuku", "-o", crateTarball, crateURL)
 	downloadOutput, err := downloadCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to download crate from %s: %w\nOutput: %s", crateURL, err, string(downloadOutput))
@@ -401,7 +401,7 @@ func (a *CargoBuildAction) executeLockDataMode(ctx *ExecutionContext, params map
 	}
 
 	fmt.Printf("   Extracting crate...\n")
-	tarCmd := exec.CommandContext(ctx.Context, "tar", "xzf", crateTarball, "-C", extractDir)
+	tarCmd := ctx.Command("tar", "xzf", crateTarball, "-C", extractDir)
 	tarOutput, err := tarCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to extract crate tarball: %w\nOutput: %s", err, string(tarOutput))
@@ -423,12 +423,12 @@ func (a *CargoBuildAction) executeLockDataMode(ctx *ExecutionContext, params map
 	}
 
 	// Build deterministic environment
-	env := buildDeterministicCargoEnv(cargoPath, tempDir)
+	env := withBuildCache(ctx, params, buildDeterministicCargoEnv(cargoPath, tempDir))
 
 	// Pre-fetch dependencies to populate CARGO_HOME
 	fmt.Printf("   Pre-fetching dependencies...\n")
 	fetchArgs := []string{"fetch", "--locked", "--manifest-path", cargoTomlPath}
-	fetchCmd := exec.CommandContext(ctx.Context, cargoPath, fetchArgs...)
+	fetchCmd := ctx.Command(cargoPath, fetchArgs...)
 	fetchCmd.Dir = crateDir
 	fetchCmd.Env = env
 	fetchOutput, err := fetchCmd.CombinedOutput()
@@ -445,7 +445,7 @@ func (a *CargoBuildAction) executeLockDataMode(ctx *ExecutionContext, params map
 		"--offline",
 		"--manifest-path", cargoTomlPath,
 	}
-	buildCmd := exec.CommandContext(ctx.Context, cargoPath, buildArgs...)
+	buildCmd := ctx.Command(cargoPath, buildArgs...)
 	buildCmd.Dir = crateDir
 	buildCmd.Env = env
 
@@ -616,7 +616,7 @@ func validateRustVersion(ctx *ExecutionContext, cargoPath, requiredVersion strin
 		rustcPath = "rustc"
 	}
 
-	cmd := exec.CommandContext(ctx.Context, rustcPath, "--version")
+	cmd := ctx.Command(rustcPath, "--version")
 	cmd.Env = env
 	output, err := cmd.Output()
 	if err != nil {
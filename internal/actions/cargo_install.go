@@ -114,13 +114,16 @@ func (a *CargoInstallAction) Execute(ctx *ExecutionContext, params map[string]in
 	fmt.Printf("   Installing: cargo install --root=%s %s\n", installDir, crateSpec)
 
 	// Use CommandContext for cancellation support
-	cmd := exec.CommandContext(ctx.Context, cargoPath, "install", "--root", installDir, crateSpec)
+	cmd := ctx.Command(cargoPath, "install", "--root", installDir, crateSpec)
 
 	// Set up environment: add cargo's bin directory to PATH
 	// With the proper install.sh setup, cargo and rustc are both in bin/
 	cargoDir := filepath.Dir(cargoPath)
 	env := os.Environ()
 	env = append(env, fmt.Sprintf("PATH=%s:%s", cargoDir, os.Getenv("PATH")))
+	// Use a CARGO_HOME in the work directory (as cargo_build does) instead of
+	// ~/.cargo, which confined commands cannot write
+	env = append(env, "CARGO_HOME="+filepath.Join(ctx.WorkDir, ".cargo-home"))
 
 	// Set up C compiler for crates with native dependencies
 	// Prefer system compiler (gcc) when available because it has better compatibility
@@ -3,7 +3,6 @@ package actions
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -32,6 +31,7 @@ func (CMakeBuildAction) Dependencies() ActionDeps {
 //   - cmake_args (optional): Arguments to pass to cmake
 //   - executables (required): List of executable names to verify
 //   - build_type (optional): CMAKE_BUILD_TYPE (default: Release)
+//   - deterministic_sensitive (optional): Never use the compiler cache for this step (default: false)
 //
 // The action runs:
 //  1. cmake -S <source_dir> -B <build_dir> -DCMAKE_INSTALL_PREFIX=<install_dir> [cmake_args...]
@@ -109,6 +109,7 @@ func (a *CMakeBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 	} else {
 		env = buildCMakeEnv()
 	}
+	env = withBuildCache(ctx, params, env)
 
 	// Create build directory
 	if err := os.MkdirAll(buildDir, 0755); err != nil {
@@ -129,7 +130,7 @@ func (a *CMakeBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	fmt.Printf("   Running: cmake %s\n", strings.Join(configArgs, " "))
 
-	configCmd := exec.CommandContext(ctx.Context, cmakePath, configArgs...)
+	configCmd := ctx.Command(cmakePath, configArgs...)
 	configCmd.Dir = ctx.WorkDir
 	configCmd.Env = env
 
@@ -143,7 +144,7 @@ func (a *CMakeBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	fmt.Printf("   Running: cmake %s\n", strings.Join(buildArgs, " "))
 
-	buildCmd := exec.CommandContext(ctx.Context, cmakePath, buildArgs...)
+	buildCmd := ctx.Command(cmakePath, buildArgs...)
 	buildCmd.Dir = ctx.WorkDir
 	buildCmd.Env = env
 
@@ -157,7 +158,7 @@ func (a *CMakeBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	fmt.Printf("   Running: cmake %s\n", strings.Join(installArgs, " "))
 
-	installCmd := exec.CommandContext(ctx.Context, cmakePath, installArgs...)
+	installCmd := ctx.Command(cmakePath, installArgs...)
 	installCmd.Dir = ctx.WorkDir
 	installCmd.Env = env
 
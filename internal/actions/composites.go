@@ -145,6 +145,7 @@ func (a *DownloadArchiveAction) Execute(ctx *ExecutionContext, params map[string
 		"version_tag": ctx.VersionTag,
 		"os":          ctx.OS,
 		"arch":        ctx.Arch,
+		"libc":        ctx.Libc,
 	}
 
 	// Apply OS mapping if present
@@ -417,6 +418,7 @@ func (a *GitHubArchiveAction) Execute(ctx *ExecutionContext, params map[string]i
 		"version": ctx.Version,
 		"os":      ctx.OS,
 		"arch":    ctx.Arch,
+		"libc":    ctx.Libc,
 	}
 
 	// Apply OS mapping if present
@@ -605,6 +607,7 @@ func (a *GitHubArchiveAction) resolveAssetName(ctx *EvalContext, params map[stri
 		"version": ctx.Version,
 		"os":      ctx.OS,
 		"arch":    ctx.Arch,
+		"libc":    ctx.Libc,
 	}
 
 	// Apply OS mapping if present
@@ -745,6 +748,7 @@ func (a *GitHubFileAction) Execute(ctx *ExecutionContext, params map[string]inte
 		"version": ctx.Version,
 		"os":      ctx.OS,
 		"arch":    ctx.Arch,
+		"libc":    ctx.Libc,
 	}
 
 	// Apply OS mapping if present
@@ -873,6 +877,7 @@ func (a *GitHubFileAction) Decompose(ctx *EvalContext, params map[string]interfa
 		"version": ctx.Version,
 		"os":      ctx.OS,
 		"arch":    ctx.Arch,
+		"libc":    ctx.Libc,
 	}
 	if osMapping, ok := params["os_mapping"].(map[string]interface{}); ok {
 		if mappedOS, ok := osMapping[ctx.OS].(string); ok {
@@ -3,7 +3,6 @@ package actions
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -46,6 +45,7 @@ func (ConfigureMakeAction) Dependencies() ActionDeps {
 //   - make_targets (optional): Make targets to run (default: ["", "install"])
 //   - executables (required): List of executable names to verify
 //   - prefix (optional): Installation prefix (default: install_dir)
+//   - deterministic_sensitive (optional): Never use the compiler cache for this step (default: false)
 //
 // The action runs:
 //  1. ./configure --prefix=<install_dir> [configure_args...]
@@ -126,13 +126,14 @@ func (a *ConfigureMakeAction) Execute(ctx *ExecutionContext, params map[string]i
 	} else {
 		env = buildAutotoolsEnv(ctx)
 	}
+	env = withBuildCache(ctx, params, env)
 
 	// Step 1: Run ./configure
 	fmt.Printf("   Running: ./configure --prefix=%s\n", prefix)
 	args := []string{"--prefix=" + prefix}
 	args = append(args, configureArgs...)
 
-	configCmd := exec.CommandContext(ctx.Context, configureScript, args...)
+	configCmd := ctx.Command(configureScript, args...)
 	configCmd.Dir = sourceDir
 	configCmd.Env = env
 
@@ -166,7 +167,7 @@ func (a *ConfigureMakeAction) Execute(ctx *ExecutionContext, params map[string]i
 			fmt.Printf("   Running: make\n")
 		}
 
-		makeCmd := exec.CommandContext(ctx.Context, makePath, makeArgs...)
+		makeCmd := ctx.Command(makePath, makeArgs...)
 		makeCmd.Dir = sourceDir
 		makeCmd.Env = env
 
@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/tsukumogami/tsuku/internal/landlock"
+)
+
+// Confinement configures Landlock confinement for the processes an action
+// starts. A nil Confinement runs commands with the user's full access.
+type Confinement struct {
+	// Launcher is the tsuku binary that applies the rules before executing
+	// a command (see landlock.Main)
+	Launcher string
+
+	// Writable and Readable are extra paths granted by the recipe, on top
+	// of the step's directories and the system toolchain paths
+	Writable []string
+	Readable []string
+}
+
+// deviceWritePaths are the devices confined commands may use. The rest of
+// /dev (block devices, terminals of other sessions) stays off limits.
+var deviceWritePaths = []string{
+	"/dev/null", "/dev/zero", "/dev/tty", "/dev/random", "/dev/urandom", "/dev/shm",
+}
+
+// systemReadPaths are the toolchain and system locations confined commands
+// may read and execute. Paths missing on the host are ignored.
+var systemReadPaths = []string{
+	"/usr", "/bin", "/sbin", "/lib", "/lib32", "/lib64", "/libx32",
+	"/etc", "/opt", "/nix", "/proc", "/sys", "/run",
+}
+
+// Command returns an exec.Cmd for name, bound to the context's
+// cancellation. When the context is confined, the command is started
+// through the Landlock launcher: it may write only to the work, install
+// and cache directories, and read the system toolchain and installed tools.
+func (ctx *ExecutionContext) Command(name string, arg ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx.Context, name, arg...)
+	if ctx.Confinement == nil {
+		return cmd
+	}
+	if err := landlock.Wrap(cmd, ctx.Confinement.Launcher, ctx.Ruleset()); err != nil {
+		cmd.Err = err
+	}
+	return cmd
+}
+
+// Ruleset returns the paths a confined command may access
+func (ctx *ExecutionContext) Ruleset() landlock.Ruleset {
+	rs := landlock.Ruleset{
+		ReadWrite: []string{
+			ctx.WorkDir,
+			ctx.InstallDir,
+			ctx.ToolInstallDir,
+			ctx.DownloadCacheDir,
+			ctx.BuildCacheDir,
+			os.TempDir(),
+		},
+		ReadOnly: append([]string{ctx.ToolsDir, ctx.LibsDir}, systemReadPaths...),
+	}
+	rs.ReadWrite = append(rs.ReadWrite, deviceWritePaths...)
+
+	// Language toolchains keep their build caches in the user cache
+	// directory; go_build and go_install share $TSUKU_HOME/.gomodcache
+	if dir, err := os.UserCacheDir(); err == nil {
+		rs.ReadWrite = append(rs.ReadWrite, dir)
+	}
+	if dir, err := goModCacheDir(); err == nil {
+		rs.ReadWrite = append(rs.ReadWrite, dir)
+	}
+
+	rs.ReadOnly = append(rs.ReadOnly, ctx.ExecPaths...)
+	rs.ReadOnly = append(rs.ReadOnly, filepath.SplitList(os.Getenv("PATH"))...)
+
+	if ctx.Confinement != nil {
+		rs.ReadWrite = append(rs.ReadWrite, ctx.Confinement.Writable...)
+		rs.ReadOnly = append(rs.ReadOnly, ctx.Confinement.Readable...)
+	}
+	return rs
+}
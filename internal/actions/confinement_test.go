@@ -0,0 +1,164 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/landlock"
+)
+
+// TestMain lets the test binary act as the Landlock launcher, as the tsuku
+// binary does in production
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == landlock.LauncherArg {
+		landlock.Main(os.Args[2:])
+	}
+	os.Exit(m.Run())
+}
+
+func TestExecutionContext_Command_Unconfined(t *testing.T) {
+	ctx := &ExecutionContext{Context: context.Background()}
+	cmd := ctx.Command("sh", "-c", "true")
+	if slices.Contains(cmd.Args, landlock.LauncherArg) {
+		t.Errorf("unconfined command was wrapped: %q", cmd.Args)
+	}
+}
+
+func TestExecutionContext_Command_Confined(t *testing.T) {
+	ctx := &ExecutionContext{
+		Context:    context.Background(),
+		WorkDir:    "/tmp/work",
+		InstallDir: "/tmp/work/.install",
+		ToolsDir:   "/home/user/.tsuku/tools",
+		Confinement: &Confinement{
+			Launcher: "/usr/local/bin/tsuku",
+			Writable: []string{"/home/user/.cache/tool"},
+			Readable: []string{"/srv/sdk"},
+		},
+	}
+
+	cmd := ctx.Command("sh", "-c", "true")
+	if cmd.Err != nil {
+		t.Fatalf("Command() error = %v", cmd.Err)
+	}
+	if cmd.Path != "/usr/local/bin/tsuku" || cmd.Args[1] != landlock.LauncherArg {
+		t.Fatalf("command not started through the launcher: %s %q", cmd.Path, cmd.Args)
+	}
+
+	var rs landlock.Ruleset
+	if err := json.Unmarshal([]byte(cmd.Args[2]), &rs); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"/tmp/work", "/tmp/work/.install", "/home/user/.cache/tool"} {
+		if !slices.Contains(rs.ReadWrite, dir) {
+			t.Errorf("ReadWrite = %q, missing %s", rs.ReadWrite, dir)
+		}
+	}
+	for _, dir := range []string{"/usr", "/home/user/.tsuku/tools", "/srv/sdk"} {
+		if !slices.Contains(rs.ReadOnly, dir) {
+			t.Errorf("ReadOnly = %q, missing %s", rs.ReadOnly, dir)
+		}
+	}
+	if slices.Contains(rs.ReadWrite, "/home/user/.tsuku/tools") {
+		t.Error("tools directory must not be writable")
+	}
+}
+
+func TestExecutionContext_Ruleset_DevicesAndModuleCache(t *testing.T) {
+	t.Setenv("TSUKU_HOME", "/srv/tsuku-home")
+	ctx := &ExecutionContext{Context: context.Background(), WorkDir: "/tmp/work"}
+
+	rs := ctx.Ruleset()
+	if slices.Contains(rs.ReadWrite, "/dev") {
+		t.Error("all of /dev must not be writable")
+	}
+	for _, dev := range []string{"/dev/null", "/dev/urandom", "/dev/shm"} {
+		if !slices.Contains(rs.ReadWrite, dev) {
+			t.Errorf("ReadWrite = %q, missing %s", rs.ReadWrite, dev)
+		}
+	}
+	if !slices.Contains(rs.ReadWrite, "/srv/tsuku-home/.gomodcache") {
+		t.Errorf("ReadWrite = %q, want the module cache under $TSUKU_HOME", rs.ReadWrite)
+	}
+}
+
+func TestEcosystemInstall_Confined(t *testing.T) {
+	if runtime.GOOS != "linux" || !landlock.Supported() {
+		t.Skip("Landlock is not supported on this system")
+	}
+
+	// Keep the home directory outside every writable path, including the
+	// temp directory, so a cache left at its default location is denied
+	base := t.TempDir()
+	t.Setenv("TMPDIR", filepath.Join(base, "tmp"))
+	t.Setenv("HOME", filepath.Join(base, "home"))
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("TSUKU_HOME", filepath.Join(base, "tsuku"))
+	for _, dir := range []string{"tmp", "home", "tools"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		action   Action
+		tool     string // Fake package manager, given by the path parameter
+		cacheEnv string // Cache the real tool uses, and its default location
+		params   map[string]interface{}
+	}{
+		{&NpmInstallAction{}, "npm", "${npm_config_cache:-$HOME/.npm}",
+			map[string]interface{}{"package": "prettier"}},
+		{&CargoInstallAction{}, "cargo", "${CARGO_HOME:-$HOME/.cargo}/registry",
+			map[string]interface{}{"crate": "ripgrep"}},
+		{&GemInstallAction{}, "gem", "${GEM_SPEC_CACHE:-$HOME/.local/share/gem/specs}",
+			map[string]interface{}{"gem": "rake"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action.Name(), func(t *testing.T) {
+			workDir := filepath.Join(base, "work-"+tt.tool)
+			installDir := filepath.Join(workDir, ".install")
+			if err := os.MkdirAll(installDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			toolPath := filepath.Join(base, "tools", tt.tool)
+			script := fmt.Sprintf(`#!/bin/sh
+set -e
+mkdir -p "%[1]s"
+touch "%[1]s/index"
+mkdir -p "%[2]s/bin"
+printf '#!/bin/sh\n' > "%[2]s/bin/tool"
+chmod +x "%[2]s/bin/tool"
+`, tt.cacheEnv, installDir)
+			if err := os.WriteFile(toolPath, []byte(script), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := &ExecutionContext{
+				Context:     context.Background(),
+				WorkDir:     workDir,
+				InstallDir:  installDir,
+				ToolsDir:    filepath.Join(base, "tools"),
+				Version:     "1.0.0",
+				Confinement: &Confinement{Launcher: os.Args[0]},
+			}
+			params := map[string]interface{}{
+				tt.tool + "_path": toolPath,
+				"executables":     []interface{}{"tool"},
+			}
+			for k, v := range tt.params {
+				params[k] = v
+			}
+
+			if err := tt.action.Execute(ctx, params); err != nil {
+				t.Fatalf("confined %s failed: %v", tt.action.Name(), err)
+			}
+		})
+	}
+}
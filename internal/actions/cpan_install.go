@@ -3,7 +3,6 @@ package actions
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -225,13 +224,16 @@ func (a *CpanInstallAction) Execute(ctx *ExecutionContext, params map[string]int
 	}
 
 	// Build command
-	cmd := exec.CommandContext(ctx.Context, cpanmPath, args...)
+	cmd := ctx.Command(cpanmPath, args...)
 
 	// Get perl directory for wrapper scripts and PATH
 	perlDir := filepath.Dir(perlPath)
 
 	// Set up deterministic environment
 	cleanEnv := buildDeterministicPerlEnv(perlPath, offline)
+	// Keep cpanm's build directory in the work directory instead of ~/.cpanm,
+	// which confined commands cannot write
+	cleanEnv = append(cleanEnv, "PERL_CPANM_HOME="+filepath.Join(ctx.WorkDir, ".cpanm"))
 
 	cmd.Env = cleanEnv
 
@@ -440,7 +442,7 @@ func buildDeterministicPerlEnv(perlPath string, offline bool) []string {
 // validatePerlVersion verifies the installed Perl version matches the required version.
 // Version format: major.minor.patch (e.g., "5.38.0") or just major.minor (e.g., "5.38")
 func validatePerlVersion(ctx *ExecutionContext, perlPath, requiredVersion string) error {
-	cmd := exec.CommandContext(ctx.Context, perlPath, "-v")
+	cmd := ctx.Command(perlPath, "-v")
 	output, err := cmd.Output()
 	if err != nil {
 		return fmt.Errorf("failed to get Perl version: %w", err)
@@ -63,6 +63,7 @@ type EvalContext struct {
 	VersionTag    string            // Original version tag (e.g., "v1.29.3")
 	OS            string            // Target OS (runtime.GOOS)
 	Arch          string            // Target architecture (runtime.GOARCH)
+	Libc          string            // Target C library on Linux ("glibc" or "musl"), "" elsewhere
 	Recipe        *recipe.Recipe    // Full recipe (for reference)
 	Resolver      *version.Resolver // For API calls (asset resolution, etc.)
 	Downloader    Downloader        // For downloading files to compute checksums
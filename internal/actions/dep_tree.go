@@ -0,0 +1,95 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// Dependency types reported in a DependencyNode
+const (
+	DepTypeInstall = "install"
+	DepTypeRuntime = "runtime"
+	DepTypeBoth    = "install+runtime"
+)
+
+// DependencyNode is a recipe in a dependency tree together with the
+// dependencies its recipe declares for the target platform.
+type DependencyNode struct {
+	Name         string            `json:"name"`
+	Type         string            `json:"type,omitempty"`    // How the parent uses it: install, runtime or install+runtime
+	Version      string            `json:"version,omitempty"` // Version constraint declared by the parent
+	Library      bool              `json:"library,omitempty"`
+	NotFound     bool              `json:"not_found,omitempty"` // No recipe exists (e.g., provided by the system)
+	Dependencies []*DependencyNode `json:"dependencies,omitempty"`
+}
+
+// BuildDependencyTree expands a recipe's dependencies for targetOS into a tree.
+// Unlike ResolveTransitive, which flattens the graph, every dependency keeps
+// its own subtree so callers can show why each recipe is pulled in.
+//
+// Dependencies without a recipe are included as leaves marked NotFound.
+// Returns ErrCyclicDependency if a cycle is found and ErrMaxDepthExceeded if
+// the tree is deeper than MaxTransitiveDepth.
+func BuildDependencyTree(ctx context.Context, loader RecipeLoader, r *recipe.Recipe, targetOS string) (*DependencyNode, error) {
+	root := &DependencyNode{Name: r.Metadata.Name, Library: r.IsLibrary()}
+	if err := expandDependencyNode(ctx, loader, root, r, targetOS, []string{r.Metadata.Name}); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// expandDependencyNode adds the dependencies of r as children of node. The
+// path slice holds the names from the root to node for cycle detection.
+func expandDependencyNode(ctx context.Context, loader RecipeLoader, node *DependencyNode, r *recipe.Recipe, targetOS string, path []string) error {
+	if len(path) > MaxTransitiveDepth {
+		return fmt.Errorf("%w: exceeded depth %d at path %s",
+			ErrMaxDepthExceeded, MaxTransitiveDepth, strings.Join(path, " -> "))
+	}
+
+	deps := ResolveDependenciesForPlatform(r, targetOS)
+	children := make(map[string]*DependencyNode)
+	for name, version := range deps.InstallTime {
+		children[name] = &DependencyNode{Name: name, Type: DepTypeInstall, Version: version}
+	}
+	for name, version := range deps.Runtime {
+		if child, ok := children[name]; ok {
+			child.Type = DepTypeBoth
+			continue
+		}
+		children[name] = &DependencyNode{Name: name, Type: DepTypeRuntime, Version: version}
+	}
+
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, ancestor := range path {
+			if ancestor == name {
+				return fmt.Errorf("%w: %s", ErrCyclicDependency, strings.Join(append(path, name), " -> "))
+			}
+		}
+
+		child := children[name]
+		node.Dependencies = append(node.Dependencies, child)
+
+		depRecipe, err := loader.GetWithContext(ctx, name)
+		if err != nil {
+			child.NotFound = true
+			continue
+		}
+		child.Library = depRecipe.IsLibrary()
+
+		childPath := append(append([]string{}, path...), name)
+		if err := expandDependencyNode(ctx, loader, child, depRecipe, targetOS, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
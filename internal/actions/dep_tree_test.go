@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// flattenTree renders a dependency tree as "name:type" entries in depth-first order
+func flattenTree(node *DependencyNode, depth int, out *[]string) {
+	entry := strings.Repeat(" ", depth) + node.Name
+	if node.Type != "" {
+		entry += ":" + node.Type
+	}
+	if node.NotFound {
+		entry += ":missing"
+	}
+	*out = append(*out, entry)
+	for _, child := range node.Dependencies {
+		flattenTree(child, depth+1, out)
+	}
+}
+
+func TestBuildDependencyTree(t *testing.T) {
+	t.Parallel()
+	loader := newMockLoader()
+	loader.addRecipe("ruby", &recipe.Recipe{Metadata: recipe.MetadataSection{
+		Dependencies:        []string{"libyaml", "openssl"},
+		RuntimeDependencies: []string{"openssl"},
+	}})
+	loader.addRecipe("libyaml", &recipe.Recipe{Metadata: recipe.MetadataSection{Type: recipe.RecipeTypeLibrary}})
+	loader.addRecipe("openssl", &recipe.Recipe{Metadata: recipe.MetadataSection{Dependencies: []string{"perl"}}})
+	loader.addRecipe("jekyll", &recipe.Recipe{Metadata: recipe.MetadataSection{
+		Dependencies: []string{"ruby@3.4"},
+	}})
+
+	root, _ := loader.GetWithContext(context.Background(), "jekyll")
+	tree, err := BuildDependencyTree(context.Background(), loader, root, "linux")
+	if err != nil {
+		t.Fatalf("BuildDependencyTree() error = %v", err)
+	}
+
+	var got []string
+	flattenTree(tree, 0, &got)
+	want := []string{
+		"jekyll",
+		" ruby:install",
+		"  libyaml:install",
+		"  openssl:install+runtime",
+		"   perl:install:missing",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("tree =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if ruby := tree.Dependencies[0]; ruby.Version != "3.4" {
+		t.Errorf("ruby version = %q, want 3.4", ruby.Version)
+	}
+	if libyaml := tree.Dependencies[0].Dependencies[0]; !libyaml.Library {
+		t.Error("libyaml should be marked as a library")
+	}
+}
+
+func TestBuildDependencyTree_Cycle(t *testing.T) {
+	t.Parallel()
+	loader := newMockLoader()
+	loader.addRecipe("a", &recipe.Recipe{Metadata: recipe.MetadataSection{Dependencies: []string{"b"}}})
+	loader.addRecipe("b", &recipe.Recipe{Metadata: recipe.MetadataSection{RuntimeDependencies: []string{"a"}}})
+
+	root, _ := loader.GetWithContext(context.Background(), "a")
+	_, err := BuildDependencyTree(context.Background(), loader, root, "linux")
+	if !errors.Is(err, ErrCyclicDependency) {
+		t.Fatalf("BuildDependencyTree() error = %v, want ErrCyclicDependency", err)
+	}
+	if !strings.Contains(err.Error(), "a -> b -> a") {
+		t.Errorf("error should include the cycle path, got %v", err)
+	}
+}
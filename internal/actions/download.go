@@ -91,6 +91,7 @@ func (a *DownloadAction) Decompose(ctx *EvalContext, params map[string]interface
 		"version_tag": ctx.VersionTag,
 		"os":          ctx.OS,
 		"arch":        ctx.Arch,
+		"libc":        ctx.Libc,
 	}
 	if len(osMapping) > 0 {
 		vars["os"] = ApplyMapping(vars["os"], osMapping)
@@ -238,8 +239,10 @@ func (a *DownloadAction) Execute(ctx *ExecutionContext, params map[string]interf
 			logger.Debug("cache hit", "dest", dest)
 			fmt.Printf("   Using cached: %s\n", dest)
 			// For cached files, verify checksum via URL if available
+			// The checksum was verified when the file was cached; offline
+			// mode can't fetch checksum_url again
 			checksumURL, hasChecksumURL := GetString(params, "checksum_url")
-			if hasChecksumURL {
+			if hasChecksumURL && !config.IsOffline() {
 				if err := a.verifyChecksumFromURL(ctx.Context, ctx, checksumURL, destPath, checksumAlgo, vars); err != nil {
 					// Cache may be stale, invalidate and re-download
 					logger.Debug("cache checksum mismatch, will re-download")
@@ -259,6 +262,10 @@ func (a *DownloadAction) Execute(ctx *ExecutionContext, params map[string]interf
 		}
 	}
 
+	if config.IsOffline() {
+		return &OfflineError{Action: "download", Missing: url}
+	}
+
 	fmt.Printf("   Downloading: %s\n", url)
 	fmt.Printf("   Destination: %s\n", dest)
 
@@ -143,7 +143,7 @@ func (c *DownloadCache) Save(url, sourcePath, checksum string) error {
 	}
 
 	// Copy file to cache (atomic: write to temp, then rename)
-	tempPath := filePath + ".tmp"
+	tempPath := cacheTempPath(filePath)
 	if err := copyFile(sourcePath, tempPath); err != nil {
 		return fmt.Errorf("failed to copy to cache: %w", err)
 	}
@@ -258,7 +258,7 @@ func (c *DownloadCache) writeMeta(metaPath string, meta *downloadCacheEntry) err
 		return err
 	}
 
-	tempPath := metaPath + ".tmp"
+	tempPath := cacheTempPath(metaPath)
 	if err := os.WriteFile(tempPath, data, 0644); err != nil {
 		return err
 	}
@@ -271,6 +271,13 @@ func (c *DownloadCache) writeMeta(metaPath string, meta *downloadCacheEntry) err
 	return nil
 }
 
+// cacheTempPath returns a temporary path next to path that is unique to this
+// process, so concurrent tsuku processes caching the same URL don't write to
+// the same file
+func cacheTempPath(path string) string {
+	return fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+}
+
 // copyFile copies a file from src to dst
 func copyFile(src, dst string) error {
 	srcFile, err := os.Open(src)
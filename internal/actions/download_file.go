@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/tsukumogami/tsuku/internal/config"
 	"github.com/tsukumogami/tsuku/internal/log"
 	"github.com/tsukumogami/tsuku/internal/progress"
 )
@@ -93,6 +94,10 @@ func (a *DownloadFileAction) Execute(ctx *ExecutionContext, params map[string]in
 		}
 	}
 
+	if config.IsOffline() {
+		return &OfflineError{Action: "download_file", Missing: url}
+	}
+
 	fmt.Printf("   Downloading: %s\n", url)
 	fmt.Printf("   Destination: %s\n", dest)
 
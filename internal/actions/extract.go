@@ -54,6 +54,18 @@ func validateSymlinkTarget(linkTarget, linkLocation, destPath string) error {
 	return nil
 }
 
+// IsPathWithinDirectory reports whether targetPath is basePath or lies below it.
+// Exported for other packages that unpack archives, such as the binary cache.
+func IsPathWithinDirectory(targetPath, basePath string) bool {
+	return isPathWithinDirectory(targetPath, basePath)
+}
+
+// ValidateSymlinkTarget checks that a symlink at linkLocation pointing to
+// linkTarget stays within destPath. Absolute targets are rejected.
+func ValidateSymlinkTarget(linkTarget, linkLocation, destPath string) error {
+	return validateSymlinkTarget(linkTarget, linkLocation, destPath)
+}
+
 // ExtractAction implements archive extraction
 type ExtractAction struct{ BaseAction }
 
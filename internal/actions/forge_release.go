@@ -0,0 +1,475 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+// Ensure forge release actions implement Decomposable
+var (
+	_ Decomposable = (*GitLabArchiveAction)(nil)
+	_ Decomposable = (*GitLabFileAction)(nil)
+	_ Decomposable = (*GiteaArchiveAction)(nil)
+	_ Decomposable = (*GiteaFileAction)(nil)
+)
+
+// GitLabArchiveAction downloads, extracts, and installs binaries from a GitLab release asset.
+// Works with gitlab.com and self-hosted instances (via base_url).
+type GitLabArchiveAction struct{ BaseAction }
+
+// IsDeterministic returns true because gitlab_archive decomposes to only deterministic primitives.
+func (GitLabArchiveAction) IsDeterministic() bool { return true }
+
+func (a *GitLabArchiveAction) Name() string { return "gitlab_archive" }
+
+// Preflight validates parameters without side effects.
+func (a *GitLabArchiveAction) Preflight(params map[string]interface{}) *PreflightResult {
+	return forgeArchivePreflight(a.Name(), version.ForgeGitLab, params)
+}
+
+func (a *GitLabArchiveAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	return forgeArchiveExecute(ctx, version.ForgeGitLab, params)
+}
+
+// Decompose resolves the GitLab release asset and returns primitive steps.
+func (a *GitLabArchiveAction) Decompose(ctx *EvalContext, params map[string]interface{}) ([]Step, error) {
+	return forgeArchiveDecompose(ctx, version.ForgeGitLab, params)
+}
+
+// GitLabFileAction downloads pre-compiled binary files from GitLab releases.
+type GitLabFileAction struct{ BaseAction }
+
+// IsDeterministic returns true because gitlab_file decomposes to only deterministic primitives.
+func (GitLabFileAction) IsDeterministic() bool { return true }
+
+func (a *GitLabFileAction) Name() string { return "gitlab_file" }
+
+// Preflight validates parameters without side effects.
+func (a *GitLabFileAction) Preflight(params map[string]interface{}) *PreflightResult {
+	return forgeFilePreflight(a.Name(), version.ForgeGitLab, params)
+}
+
+func (a *GitLabFileAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	return forgeFileExecute(ctx, version.ForgeGitLab, params)
+}
+
+// Decompose returns the primitive steps for gitlab_file action.
+func (a *GitLabFileAction) Decompose(ctx *EvalContext, params map[string]interface{}) ([]Step, error) {
+	return forgeFileDecompose(ctx, version.ForgeGitLab, params)
+}
+
+// GiteaArchiveAction downloads, extracts, and installs binaries from a Gitea or
+// Forgejo release asset. Requires base_url (e.g., "https://codeberg.org").
+type GiteaArchiveAction struct{ BaseAction }
+
+// IsDeterministic returns true because gitea_archive decomposes to only deterministic primitives.
+func (GiteaArchiveAction) IsDeterministic() bool { return true }
+
+func (a *GiteaArchiveAction) Name() string { return "gitea_archive" }
+
+// Preflight validates parameters without side effects.
+func (a *GiteaArchiveAction) Preflight(params map[string]interface{}) *PreflightResult {
+	return forgeArchivePreflight(a.Name(), version.ForgeGitea, params)
+}
+
+func (a *GiteaArchiveAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	return forgeArchiveExecute(ctx, version.ForgeGitea, params)
+}
+
+// Decompose resolves the Gitea release asset and returns primitive steps.
+func (a *GiteaArchiveAction) Decompose(ctx *EvalContext, params map[string]interface{}) ([]Step, error) {
+	return forgeArchiveDecompose(ctx, version.ForgeGitea, params)
+}
+
+// GiteaFileAction downloads pre-compiled binary files from Gitea or Forgejo releases.
+type GiteaFileAction struct{ BaseAction }
+
+// IsDeterministic returns true because gitea_file decomposes to only deterministic primitives.
+func (GiteaFileAction) IsDeterministic() bool { return true }
+
+func (a *GiteaFileAction) Name() string { return "gitea_file" }
+
+// Preflight validates parameters without side effects.
+func (a *GiteaFileAction) Preflight(params map[string]interface{}) *PreflightResult {
+	return forgeFilePreflight(a.Name(), version.ForgeGitea, params)
+}
+
+func (a *GiteaFileAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	return forgeFileExecute(ctx, version.ForgeGitea, params)
+}
+
+// Decompose returns the primitive steps for gitea_file action.
+func (a *GiteaFileAction) Decompose(ctx *EvalContext, params map[string]interface{}) ([]Step, error) {
+	return forgeFileDecompose(ctx, version.ForgeGitea, params)
+}
+
+// forgeCommonPreflight validates the repo, base_url, asset_pattern and mapping parameters
+// shared by all forge release actions. Returns the asset pattern for further checks.
+func forgeCommonPreflight(result *PreflightResult, action, forge string, params map[string]interface{}) string {
+	repo, hasRepo := GetString(params, "repo")
+	if !hasRepo {
+		result.AddError(fmt.Sprintf("%s action requires 'repo' parameter", action))
+	} else if forge == version.ForgeGitea {
+		if strings.Count(repo, "/") != 1 {
+			result.AddError("repo should be in 'owner/repository' format (e.g., 'forgejo/forgejo')")
+		}
+	} else if !strings.Contains(repo, "/") {
+		result.AddError("repo should be in 'group/project' format (e.g., 'gitlab-org/cli')")
+	}
+
+	baseURL, hasBaseURL := GetString(params, "base_url")
+	if !hasBaseURL && forge == version.ForgeGitea {
+		result.AddError(fmt.Sprintf("%s action requires 'base_url' parameter (e.g., 'https://codeberg.org')", action))
+	}
+	if hasBaseURL && !strings.HasPrefix(baseURL, "https://") {
+		result.AddError("base_url must use https://")
+	}
+
+	assetPattern, ok := GetString(params, "asset_pattern")
+	if !ok {
+		result.AddError(fmt.Sprintf("%s action requires 'asset_pattern' parameter", action))
+	}
+
+	// WARNING: Unused os_mapping
+	if _, hasOSMapping := GetMapStringString(params, "os_mapping"); hasOSMapping {
+		if !containsPlaceholder(assetPattern, "os") {
+			result.AddWarning("os_mapping provided but asset_pattern does not contain {os} placeholder; mapping will have no effect")
+		}
+	}
+
+	// WARNING: Unused arch_mapping
+	if _, hasArchMapping := GetMapStringString(params, "arch_mapping"); hasArchMapping {
+		if !containsPlaceholder(assetPattern, "arch") {
+			result.AddWarning("arch_mapping provided but asset_pattern does not contain {arch} placeholder; mapping will have no effect")
+		}
+	}
+
+	return assetPattern
+}
+
+func forgeArchivePreflight(action, forge string, params map[string]interface{}) *PreflightResult {
+	result := &PreflightResult{}
+	assetPattern := forgeCommonPreflight(result, action, forge, params)
+
+	// WARNING: Redundant archive_format when it can be inferred from asset_pattern
+	if archiveFormat, hasFormat := GetString(params, "archive_format"); hasFormat {
+		detectedFormat := DetectArchiveFormat(assetPattern)
+		if detectedFormat != "" && detectedFormat == archiveFormat {
+			result.AddWarning("archive_format can be inferred from asset_pattern; consider removing redundant parameter")
+		}
+	}
+
+	return result
+}
+
+func forgeFilePreflight(action, forge string, params map[string]interface{}) *PreflightResult {
+	result := &PreflightResult{}
+	assetPattern := forgeCommonPreflight(result, action, forge, params)
+
+	// WARNING: Archive extension in asset_pattern
+	archiveExts := []string{".tar.gz", ".tgz", ".tar.xz", ".tar.bz2", ".zip", ".tar"}
+	lowerPattern := strings.ToLower(assetPattern)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(lowerPattern, ext) {
+			result.AddWarning(fmt.Sprintf("asset_pattern ends with archive extension; consider using '%s_archive' action instead", forge))
+			break
+		}
+	}
+
+	return result
+}
+
+// forgeAssetVars builds the template variables for asset pattern expansion,
+// applying os_mapping and arch_mapping when present.
+func forgeAssetVars(params map[string]interface{}, ver, goos, goarch, libc string) map[string]string {
+	vars := map[string]string{
+		"version": ver,
+		"os":      goos,
+		"arch":    goarch,
+		"libc":    libc,
+	}
+	if osMapping, ok := params["os_mapping"].(map[string]interface{}); ok {
+		if mappedOS, ok := osMapping[goos].(string); ok {
+			vars["os"] = mappedOS
+		}
+	}
+	if archMapping, ok := params["arch_mapping"].(map[string]interface{}); ok {
+		if mappedArch, ok := archMapping[goarch].(string); ok {
+			vars["arch"] = mappedArch
+		}
+	}
+	return vars
+}
+
+// resolveForgeAsset expands the asset pattern and looks the asset up in the release
+// identified by versionTag. Unlike GitHub, forge download URLs cannot be constructed
+// from the asset name alone, so the release API is always consulted.
+func resolveForgeAsset(ctx context.Context, resolver *version.Resolver, forge string, params map[string]interface{}, vars map[string]string, versionTag string) (*version.ForgeAsset, error) {
+	repo, ok := GetString(params, "repo")
+	if !ok {
+		return nil, fmt.Errorf("repo is required")
+	}
+	assetPattern, ok := GetString(params, "asset_pattern")
+	if !ok {
+		return nil, fmt.Errorf("asset_pattern is required")
+	}
+	baseURL, _ := GetString(params, "base_url")
+	if forge == version.ForgeGitea && baseURL == "" {
+		return nil, fmt.Errorf("base_url is required")
+	}
+	if baseURL != "" && !strings.HasPrefix(baseURL, "https://") {
+		return nil, fmt.Errorf("base_url must use https://")
+	}
+	if resolver == nil {
+		return nil, fmt.Errorf("resolver not available in context (required for %s release assets)", forge)
+	}
+
+	apiCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	release, err := resolver.GetForgeRelease(apiCtx, forge, baseURL, repo, versionTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release assets: %w", err)
+	}
+
+	asset, err := version.MatchForgeAsset(release, ExpandVars(assetPattern, vars))
+	if err != nil {
+		return nil, fmt.Errorf("asset pattern matching failed: %w", err)
+	}
+	return asset, nil
+}
+
+// forgeArchiveParams holds the validated parameters of a *_archive forge action
+type forgeArchiveParams struct {
+	archiveFormat string
+	stripDirs     int
+	binaries      interface{}
+	installMode   string
+}
+
+func parseForgeArchiveParams(params map[string]interface{}) (*forgeArchiveParams, error) {
+	assetPattern, ok := GetString(params, "asset_pattern")
+	if !ok {
+		return nil, fmt.Errorf("asset_pattern is required")
+	}
+
+	archiveFormat, _ := GetString(params, "archive_format")
+	if archiveFormat == "" {
+		archiveFormat = DetectArchiveFormat(assetPattern)
+		if archiveFormat == "" {
+			return nil, fmt.Errorf("could not detect archive format from asset_pattern; please specify 'archive_format'")
+		}
+	}
+
+	binariesRaw, ok := params["binaries"]
+	if !ok {
+		return nil, fmt.Errorf("binaries is required")
+	}
+
+	stripDirs, _ := GetInt(params, "strip_dirs")
+
+	installMode, _ := GetString(params, "install_mode")
+	if installMode == "" {
+		installMode = "binaries"
+	}
+	installMode = strings.ToLower(installMode)
+	if installMode != "binaries" && installMode != "directory" && installMode != "directory_wrapped" {
+		return nil, fmt.Errorf("invalid install_mode '%s': must be 'binaries', 'directory', or 'directory_wrapped'", installMode)
+	}
+
+	return &forgeArchiveParams{
+		archiveFormat: archiveFormat,
+		stripDirs:     stripDirs,
+		binaries:      binariesRaw,
+		installMode:   installMode,
+	}, nil
+}
+
+func forgeArchiveDecompose(ctx *EvalContext, forge string, params map[string]interface{}) ([]Step, error) {
+	p, err := parseForgeArchiveParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := forgeAssetVars(params, ctx.Version, ctx.OS, ctx.Arch, ctx.Libc)
+	asset, err := resolveForgeAsset(ctx.Context, ctx.Resolver, forge, params, vars, ctx.VersionTag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Delegate to download action for checksum computation
+	// URL is already fully resolved, so no mappings needed
+	downloadStep, err := decomposeDownload(ctx, asset.URL, asset.Name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Step{
+		downloadStep,
+		{
+			Action: "extract",
+			Params: map[string]interface{}{
+				"archive":    asset.Name,
+				"format":     p.archiveFormat,
+				"strip_dirs": p.stripDirs,
+			},
+		},
+		{
+			Action: "chmod",
+			Params: map[string]interface{}{
+				"files": extractSourceFiles(p.binaries),
+			},
+		},
+		{
+			Action: "install_binaries",
+			Params: map[string]interface{}{
+				"binaries":     p.binaries,
+				"install_mode": p.installMode,
+			},
+		},
+	}, nil
+}
+
+func forgeArchiveExecute(ctx *ExecutionContext, forge string, params map[string]interface{}) error {
+	p, err := parseForgeArchiveParams(params)
+	if err != nil {
+		return err
+	}
+
+	// Enforce verification for directory-based installs
+	// Libraries are exempt since they cannot be run directly to verify
+	verifyCmd := strings.TrimSpace(ctx.Recipe.Verify.Command)
+	isLibrary := ctx.Recipe.Metadata.Type == "library"
+	if (p.installMode == "directory" || p.installMode == "directory_wrapped") && verifyCmd == "" && !isLibrary {
+		return fmt.Errorf("recipes with install_mode='%s' must include a [verify] section with a command to ensure the installation works correctly", p.installMode)
+	}
+
+	vars := forgeAssetVars(params, ctx.Version, ctx.OS, ctx.Arch, ctx.Libc)
+	asset, err := resolveForgeAsset(ctx.Context, ctx.Resolver, forge, params, vars, ctx.VersionTag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   → Resolved release asset: %s\n", asset.Name)
+
+	downloadAction := &DownloadAction{}
+	if err := downloadAction.Execute(ctx, map[string]interface{}{"url": asset.URL, "dest": asset.Name}); err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	extractAction := &ExtractAction{}
+	extractParams := map[string]interface{}{
+		"archive":    asset.Name,
+		"format":     p.archiveFormat,
+		"strip_dirs": p.stripDirs,
+	}
+	if err := extractAction.Execute(ctx, extractParams); err != nil {
+		return fmt.Errorf("extract failed: %w", err)
+	}
+
+	chmodAction := &ChmodAction{}
+	if err := chmodAction.Execute(ctx, map[string]interface{}{"files": extractSourceFiles(p.binaries)}); err != nil {
+		return fmt.Errorf("chmod failed: %w", err)
+	}
+
+	installAction := &InstallBinariesAction{}
+	installParams := map[string]interface{}{
+		"binaries":     p.binaries,
+		"install_mode": p.installMode,
+	}
+	if err := installAction.Execute(ctx, installParams); err != nil {
+		return fmt.Errorf("install failed: %w", err)
+	}
+
+	return nil
+}
+
+// parseForgeFileBinaries supports both 'binary' (single name) and 'binaries' ([{src, dest}])
+// and returns the binaries value plus the name the asset is downloaded as.
+func parseForgeFileBinaries(params map[string]interface{}) (interface{}, string, error) {
+	if binariesParam, ok := params["binaries"]; ok {
+		if arr, ok := binariesParam.([]interface{}); ok && len(arr) > 0 {
+			if m, ok := arr[0].(map[string]interface{}); ok {
+				if src, ok := m["src"].(string); ok && src != "" {
+					return binariesParam, src, nil
+				}
+			}
+		}
+		return nil, "", fmt.Errorf("binaries[0].src is required for download")
+	}
+	if binary, ok := GetString(params, "binary"); ok {
+		return []interface{}{binary}, binary, nil
+	}
+	return nil, "", fmt.Errorf("either 'binary' or 'binaries' is required")
+}
+
+func forgeFileDecompose(ctx *EvalContext, forge string, params map[string]interface{}) ([]Step, error) {
+	binaries, downloadName, err := parseForgeFileBinaries(params)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := forgeAssetVars(params, ctx.Version, ctx.OS, ctx.Arch, ctx.Libc)
+	asset, err := resolveForgeAsset(ctx.Context, ctx.Resolver, forge, params, vars, ctx.VersionTag)
+	if err != nil {
+		return nil, err
+	}
+	expandedDownloadName := ExpandVars(downloadName, vars)
+
+	// Delegate to download action for checksum computation
+	downloadStep, err := decomposeDownload(ctx, asset.URL, expandedDownloadName, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Step{
+		downloadStep,
+		{
+			Action: "chmod",
+			Params: map[string]interface{}{
+				"files": []string{expandedDownloadName},
+			},
+		},
+		{
+			Action: "install_binaries",
+			Params: map[string]interface{}{
+				"binaries": binaries,
+			},
+		},
+	}, nil
+}
+
+func forgeFileExecute(ctx *ExecutionContext, forge string, params map[string]interface{}) error {
+	binaries, downloadName, err := parseForgeFileBinaries(params)
+	if err != nil {
+		return err
+	}
+
+	vars := forgeAssetVars(params, ctx.Version, ctx.OS, ctx.Arch, ctx.Libc)
+	asset, err := resolveForgeAsset(ctx.Context, ctx.Resolver, forge, params, vars, ctx.VersionTag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("   → Resolved release asset: %s\n", asset.Name)
+	expandedDownloadName := ExpandVars(downloadName, vars)
+
+	downloadAction := &DownloadAction{}
+	if err := downloadAction.Execute(ctx, map[string]interface{}{"url": asset.URL, "dest": expandedDownloadName}); err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	chmodAction := &ChmodAction{}
+	if err := chmodAction.Execute(ctx, map[string]interface{}{"files": []string{expandedDownloadName}}); err != nil {
+		return fmt.Errorf("chmod failed: %w", err)
+	}
+
+	installAction := &InstallBinariesAction{}
+	if err := installAction.Execute(ctx, map[string]interface{}{"binaries": binaries}); err != nil {
+		return fmt.Errorf("install failed: %w", err)
+	}
+
+	return nil
+}
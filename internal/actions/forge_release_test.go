@@ -0,0 +1,184 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+// newGitLabReleaseServer serves a single GitLab release for group/tool at tag v1.2.0
+func newGitLabReleaseServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Ftool/releases/v1.2.0" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"tag_name": "v1.2.0", "assets": {"links": [
+			{"name": "tool-1.2.0-linux-x86_64.tar.gz", "direct_asset_url": "https://gitlab.com/group/tool/-/releases/v1.2.0/downloads/tool-1.2.0-linux-x86_64.tar.gz"},
+			{"name": "tool-linux-amd64", "direct_asset_url": "https://gitlab.com/group/tool/-/releases/v1.2.0/downloads/tool-linux-amd64"}
+		]}}`))
+	}))
+}
+
+func TestForgeReleaseActions_Names(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		action Action
+		want   string
+	}{
+		{&GitLabArchiveAction{}, "gitlab_archive"},
+		{&GitLabFileAction{}, "gitlab_file"},
+		{&GiteaArchiveAction{}, "gitea_archive"},
+		{&GiteaFileAction{}, "gitea_file"},
+	}
+	for _, tt := range tests {
+		if got := tt.action.Name(); got != tt.want {
+			t.Errorf("Name() = %q, want %q", got, tt.want)
+		}
+		if Get(tt.want) == nil {
+			t.Errorf("action %q is not registered", tt.want)
+		}
+	}
+}
+
+func TestGitLabArchiveAction_Decompose(t *testing.T) {
+	t.Parallel()
+	server := newGitLabReleaseServer(t)
+	defer server.Close()
+
+	ctx := &EvalContext{
+		Context:    context.Background(),
+		Version:    "1.2.0",
+		VersionTag: "v1.2.0",
+		OS:         "linux",
+		Arch:       "amd64",
+		Resolver:   version.New(version.WithGitLabURL(server.URL)),
+	}
+	params := map[string]interface{}{
+		"repo":          "group/tool",
+		"asset_pattern": "tool-{version}-{os}-{arch}.tar.gz",
+		"arch_mapping":  map[string]interface{}{"amd64": "x86_64"},
+		"binaries":      []interface{}{"tool"},
+	}
+
+	steps, err := (&GitLabArchiveAction{}).Decompose(ctx, params)
+	if err != nil {
+		t.Fatalf("Decompose() error = %v", err)
+	}
+
+	wantActions := []string{"download_file", "extract", "chmod", "install_binaries"}
+	if len(steps) != len(wantActions) {
+		t.Fatalf("Decompose() returned %d steps, want %d", len(steps), len(wantActions))
+	}
+	for i, want := range wantActions {
+		if steps[i].Action != want {
+			t.Errorf("steps[%d].Action = %q, want %q", i, steps[i].Action, want)
+		}
+	}
+
+	wantURL := "https://gitlab.com/group/tool/-/releases/v1.2.0/downloads/tool-1.2.0-linux-x86_64.tar.gz"
+	if url, _ := steps[0].Params["url"].(string); url != wantURL {
+		t.Errorf("download url = %q, want %q", url, wantURL)
+	}
+	if format, _ := steps[1].Params["format"].(string); format != "tar.gz" {
+		t.Errorf("extract format = %q, want tar.gz", format)
+	}
+}
+
+func TestGitLabFileAction_Decompose_Wildcard(t *testing.T) {
+	t.Parallel()
+	server := newGitLabReleaseServer(t)
+	defer server.Close()
+
+	ctx := &EvalContext{
+		Context:    context.Background(),
+		Version:    "1.2.0",
+		VersionTag: "v1.2.0",
+		OS:         "linux",
+		Arch:       "amd64",
+		Resolver:   version.New(version.WithGitLabURL(server.URL)),
+	}
+	params := map[string]interface{}{
+		"repo":          "group/tool",
+		"asset_pattern": "tool-{os}-{arch}*",
+		"binary":        "tool",
+	}
+
+	steps, err := (&GitLabFileAction{}).Decompose(ctx, params)
+	if err != nil {
+		t.Fatalf("Decompose() error = %v", err)
+	}
+	if len(steps) != 3 {
+		t.Fatalf("Decompose() returned %d steps, want 3", len(steps))
+	}
+	if url, _ := steps[0].Params["url"].(string); !strings.HasSuffix(url, "/tool-linux-amd64") {
+		t.Errorf("download url = %q, want asset tool-linux-amd64", url)
+	}
+	if dest, _ := steps[0].Params["dest"].(string); dest != "tool" {
+		t.Errorf("download dest = %q, want tool", dest)
+	}
+}
+
+func TestGiteaArchiveAction_Decompose_BaseURLValidation(t *testing.T) {
+	t.Parallel()
+	ctx := &EvalContext{
+		Context:    context.Background(),
+		Version:    "1.0.0",
+		VersionTag: "v1.0.0",
+		OS:         "linux",
+		Arch:       "amd64",
+		Resolver:   version.New(),
+	}
+
+	tests := []struct {
+		name    string
+		baseURL interface{}
+		wantErr string
+	}{
+		{"missing base_url", nil, "base_url is required"},
+		{"plain http", "http://codeberg.org", "https"},
+	}
+	for _, tt := range tests {
+		params := map[string]interface{}{
+			"repo":          "owner/tool",
+			"asset_pattern": "tool.tar.gz",
+			"binaries":      []interface{}{"tool"},
+		}
+		if tt.baseURL != nil {
+			params["base_url"] = tt.baseURL
+		}
+		_, err := (&GiteaArchiveAction{}).Decompose(ctx, params)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Decompose() error = %v, want containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestForgeReleaseActions_Preflight(t *testing.T) {
+	t.Parallel()
+
+	result := (&GiteaFileAction{}).Preflight(map[string]interface{}{
+		"repo":          "owner/sub/tool",
+		"asset_pattern": "tool.tar.gz",
+	})
+	if len(result.Errors) != 2 {
+		t.Errorf("expected errors for repo format and missing base_url, got %v", result.Errors)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "gitea_archive") {
+		t.Errorf("expected archive extension warning, got %v", result.Warnings)
+	}
+
+	result = (&GitLabArchiveAction{}).Preflight(map[string]interface{}{
+		"repo":          "group/subgroup/tool",
+		"asset_pattern": "tool-{os}.tar.gz",
+	})
+	if len(result.Errors) != 0 {
+		t.Errorf("nested GitLab groups should be valid, got %v", result.Errors)
+	}
+}
@@ -160,7 +160,7 @@ func (a *GemExecAction) Execute(ctx *ExecutionContext, params map[string]interfa
 		args = append(args, "--standalone") // Self-contained installation
 		if outputDir != "" {
 			// Use bundle config instead of --path flag (compatible with bundler 3.x+)
-			configCmd := exec.CommandContext(ctx.Context, bundlerPath, "config", "set", "--local", "path", outputDir)
+			configCmd := ctx.Command(bundlerPath, "config", "set", "--local", "path", outputDir)
 			configCmd.Dir = sourceDir
 			configCmd.Env = env
 			if output, err := configCmd.CombinedOutput(); err != nil {
@@ -170,7 +170,7 @@ func (a *GemExecAction) Execute(ctx *ExecutionContext, params map[string]interfa
 	}
 
 	// Create and execute command
-	cmd := exec.CommandContext(ctx.Context, bundlerPath, args...)
+	cmd := ctx.Command(bundlerPath, args...)
 	cmd.Dir = sourceDir
 	cmd.Env = env
 
@@ -417,7 +417,7 @@ func (a *GemExecAction) executeLockDataMode(ctx *ExecutionContext, params map[st
 
 	// Configure path using bundle config (compatible with bundler 3.x+)
 	// The --path flag was removed in bundler 3.0+
-	configCmd := exec.CommandContext(ctx.Context, bundlerPath, "config", "set", "--local", "path", installDir)
+	configCmd := ctx.Command(bundlerPath, "config", "set", "--local", "path", installDir)
 	configCmd.Dir = installDir
 	configCmd.Env = env
 	if output, err := configCmd.CombinedOutput(); err != nil {
@@ -428,7 +428,7 @@ func (a *GemExecAction) executeLockDataMode(ctx *ExecutionContext, params map[st
 	args := []string{"install"}
 
 	// Create and execute command
-	cmd := exec.CommandContext(ctx.Context, bundlerPath, args...)
+	cmd := ctx.Command(bundlerPath, args...)
 	cmd.Dir = installDir
 	cmd.Env = env
 
@@ -135,7 +135,7 @@ func (a *GemInstallAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	// Build command: gem install <gem> --version <version> --no-document --install-dir <dir>
 	// Use CommandContext for cancellation support
-	cmd := exec.CommandContext(ctx.Context, gemPath, "install", gemName,
+	cmd := ctx.Command(gemPath, "install", gemName,
 		"--version", ctx.Version,
 		"--no-document",             // Skip documentation
 		"--install-dir", installDir, // Install to our directory
@@ -147,6 +147,9 @@ func (a *GemInstallAction) Execute(ctx *ExecutionContext, params map[string]inte
 	env := os.Environ()
 	env = append(env, fmt.Sprintf("GEM_HOME=%s", installDir))
 	env = append(env, fmt.Sprintf("GEM_PATH=%s", installDir))
+	// Keep the gem spec cache in the work directory instead of the home
+	// directory, which confined commands cannot write
+	env = append(env, fmt.Sprintf("GEM_SPEC_CACHE=%s", filepath.Join(ctx.WorkDir, ".gem-spec-cache")))
 
 	// Build PATH incrementally - start with gem's directory
 	gemDir := filepath.Dir(gemPath)
@@ -3,7 +3,6 @@ package actions
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -136,10 +135,9 @@ func (a *GoBuildAction) Execute(ctx *ExecutionContext, params map[string]interfa
 	fmt.Printf("   CGO enabled: %v\n", cgoEnabled)
 	fmt.Printf("   Build flags: %v\n", buildFlags)
 
-	// Get home directory for paths
-	homeDir, err := os.UserHomeDir()
+	modCache, err := goModCacheDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
 	installDir := ctx.InstallDir
@@ -171,13 +169,12 @@ func (a *GoBuildAction) Execute(ctx *ExecutionContext, params map[string]interfa
 
 	// Set up isolated environment
 	goDir := filepath.Dir(goPath)
-	modCache := filepath.Join(homeDir, ".tsuku", ".gomodcache")
 
 	// Build environment with GOPROXY enabled for module downloads and installation
 	// Checksums are verified via go mod verify for deterministic builds
 	downloadEnv := buildGoEnv(goDir, binDir, modCache, cgoEnabled, false)
 
-	downloadCmd := exec.CommandContext(ctx.Context, goPath, "mod", "download", "-x")
+	downloadCmd := ctx.Command(goPath, "mod", "download", "-x")
 	downloadCmd.Dir = tempDir
 	downloadCmd.Env = downloadEnv
 
@@ -187,7 +184,7 @@ func (a *GoBuildAction) Execute(ctx *ExecutionContext, params map[string]interfa
 	}
 
 	// Verify checksums match the captured go.sum
-	verifyCmd := exec.CommandContext(ctx.Context, goPath, "mod", "verify")
+	verifyCmd := ctx.Command(goPath, "mod", "verify")
 	verifyCmd.Dir = tempDir
 	verifyCmd.Env = downloadEnv
 
@@ -212,7 +209,7 @@ func (a *GoBuildAction) Execute(ctx *ExecutionContext, params map[string]interfa
 
 	// Use online mode for install since go install module@version requires network
 	// even when modules are cached. Checksums are already verified by go mod verify.
-	installCmd := exec.CommandContext(ctx.Context, goPath, installArgs...)
+	installCmd := ctx.Command(goPath, installArgs...)
 	installCmd.Dir = tempDir
 	installCmd.Env = downloadEnv
 
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/tsukumogami/tsuku/internal/config"
 	versionpkg "github.com/tsukumogami/tsuku/internal/version"
 )
 
@@ -108,10 +109,9 @@ func (a *GoInstallAction) Execute(ctx *ExecutionContext, params map[string]inter
 	fmt.Printf("   Executables: %v\n", executables)
 	fmt.Printf("   Using go: %s\n", goPath)
 
-	// Get home directory for GOMODCACHE
-	homeDir, err := os.UserHomeDir()
+	modCache, err := goModCacheDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
 
 	installDir := ctx.InstallDir
@@ -134,7 +134,7 @@ func (a *GoInstallAction) Execute(ctx *ExecutionContext, params map[string]inter
 	}
 
 	// Use CommandContext for cancellation support
-	cmd := exec.CommandContext(ctx.Context, goPath, "install", target)
+	cmd := ctx.Command(goPath, "install", target)
 
 	// SECURITY: Set up isolated environment with explicit secure defaults
 	// These settings prevent contamination from user environment and ensure
@@ -166,7 +166,7 @@ func (a *GoInstallAction) Execute(ctx *ExecutionContext, params map[string]inter
 	// Set isolation environment variables
 	filteredEnv = append(filteredEnv,
 		"GOBIN="+binDir,
-		"GOMODCACHE="+filepath.Join(homeDir, ".tsuku", ".gomodcache"),
+		"GOMODCACHE="+modCache,
 		"CGO_ENABLED=0",
 		"GOPROXY=https://proxy.golang.org,direct",
 		"GOSUMDB=sum.golang.org",
@@ -331,10 +331,9 @@ func (a *GoInstallAction) Decompose(ctx *EvalContext, params map[string]interfac
 	}
 	defer os.RemoveAll(tempDir)
 
-	// Get home directory for GOMODCACHE
-	homeDir, err := os.UserHomeDir()
+	modCache, err := goModCacheDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
 
 	// Create minimal go.mod
@@ -346,7 +345,6 @@ func (a *GoInstallAction) Decompose(ctx *EvalContext, params map[string]interfac
 
 	// Set up environment for go get
 	goDir := filepath.Dir(goPath)
-	modCache := filepath.Join(homeDir, ".tsuku", ".gomodcache")
 
 	env := os.Environ()
 	filteredEnv := make([]string, 0, len(env))
@@ -426,3 +424,13 @@ func (a *GoInstallAction) Decompose(ctx *EvalContext, params map[string]interfac
 		},
 	}, nil
 }
+
+// goModCacheDir returns the module cache shared by go_build and go_install
+// ($TSUKU_HOME/.gomodcache)
+func goModCacheDir() (string, error) {
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate Go module cache: %w", err)
+	}
+	return cfg.GoModCacheDir, nil
+}
@@ -13,6 +13,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/tsukumogami/tsuku/internal/config"
 )
 
 // ghcrHTTPClient returns an HTTP client with appropriate timeouts for GHCR requests.
@@ -381,6 +383,11 @@ func (a *HomebrewAction) Decompose(ctx *EvalContext, params map[string]interface
 		return nil, fmt.Errorf("unsupported platform: %w", err)
 	}
 
+	// The bottle digest comes from the GHCR manifest, which is never cached
+	if config.IsOffline() {
+		return nil, &OfflineError{Action: "homebrew", Missing: "the bottle manifest of " + formula}
+	}
+
 	// Get anonymous GHCR token
 	token, err := a.getGHCRToken(formula)
 	if err != nil {
@@ -70,7 +70,7 @@ func (a *InstallGemDirectAction) Execute(ctx *ExecutionContext, params map[strin
 	}
 
 	// Run gem install
-	cmd := exec.CommandContext(ctx.Context, gemPath, "install", gemName, "--version", version, "--install-dir", gemHome)
+	cmd := ctx.Command(gemPath, "install", gemName, "--version", version, "--install-dir", gemHome)
 	cmd.Env = append(os.Environ(),
 		"GEM_HOME="+gemHome,
 		"GEM_PATH="+gemHome,
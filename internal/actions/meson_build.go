@@ -37,6 +37,7 @@ func (a *MesonBuildAction) Name() string {
 //   - executables (required): List of executable names to verify
 //   - buildtype (optional): Build type (default: release)
 //   - wrap_mode (optional): Dependency wrapping behavior (default: nofallback)
+//   - deterministic_sensitive (optional): Never use the compiler cache for this step (default: false)
 //
 // The action runs:
 //  1. meson setup <build_dir> <source_dir> --prefix=<install_dir> [meson_args...]
@@ -119,7 +120,7 @@ func (a *MesonBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 	}
 
 	// Build environment
-	env := buildMesonEnv()
+	env := withBuildCache(ctx, params, buildMesonEnv())
 
 	// Find meson - check ExecPaths first (for installed dependencies), then fall back to PATH
 	mesonPath := ""
@@ -147,7 +148,7 @@ func (a *MesonBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	fmt.Printf("   Running: meson %s\n", strings.Join(setupArgs, " "))
 
-	setupCmd := exec.CommandContext(ctx.Context, mesonPath, setupArgs...)
+	setupCmd := ctx.Command(mesonPath, setupArgs...)
 	setupCmd.Dir = ctx.WorkDir
 	setupCmd.Env = env
 
@@ -161,7 +162,7 @@ func (a *MesonBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	fmt.Printf("   Running: meson %s\n", strings.Join(compileArgs, " "))
 
-	compileCmd := exec.CommandContext(ctx.Context, mesonPath, compileArgs...)
+	compileCmd := ctx.Command(mesonPath, compileArgs...)
 	compileCmd.Dir = ctx.WorkDir
 	compileCmd.Env = env
 
@@ -175,7 +176,7 @@ func (a *MesonBuildAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	fmt.Printf("   Running: meson %s\n", strings.Join(installArgs, " "))
 
-	installCmd := exec.CommandContext(ctx.Context, mesonPath, installArgs...)
+	installCmd := ctx.Command(mesonPath, installArgs...)
 	installCmd.Dir = ctx.WorkDir
 	installCmd.Env = env
 
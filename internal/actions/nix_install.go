@@ -124,7 +124,9 @@ func (a *NixInstallAction) Execute(ctx *ExecutionContext, params map[string]inte
 	// Using --profile to install to a specific profile location
 	fmt.Printf("   Installing: nix profile install nixpkgs#%s\n", packageName)
 
-	// Use CommandContext for cancellation support
+	// Not ctx.Command: nix actions are exempt from confinement. nix-portable
+	// sets up its own namespace sandbox, which Landlock rules would break,
+	// and writes the Nix store under NP_LOCATION outside the step's directories.
 	cmd := exec.CommandContext(ctx.Context, nixPortablePath, "nix", "profile", "install",
 		"--profile", profilePath,
 		fmt.Sprintf("nixpkgs#%s", packageName))
@@ -169,6 +169,7 @@ func (a *NixRealizeAction) Execute(ctx *ExecutionContext, params map[string]inte
 		fmt.Printf("   Realizing from derivation: %s\n", derivationPath)
 		args = []string{"nix-store", "--realize", derivationPath}
 
+		// Unconfined like nix_install: nix-portable sandboxes itself
 		cmd := exec.CommandContext(ctx.Context, nixPortablePath, args...)
 		cmd.Env = append(os.Environ(),
 			fmt.Sprintf("NP_LOCATION=%s", npLocation),
@@ -208,7 +209,7 @@ func (a *NixRealizeAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 		fmt.Printf("   Installing: nix profile install %s\n", args[len(args)-1])
 
-		// Execute with isolation
+		// Execute with isolation (unconfined, see above)
 		cmd := exec.CommandContext(ctx.Context, nixPortablePath, args...)
 		cmd.Env = append(os.Environ(),
 			fmt.Sprintf("NP_LOCATION=%s", npLocation),
@@ -165,7 +165,7 @@ func (a *NpmExecAction) Execute(ctx *ExecutionContext, params map[string]interfa
 			ciArgs = append(ciArgs, "--ignore-scripts")
 		}
 
-		ciCmd := exec.CommandContext(ctx.Context, npmPath, ciArgs...)
+		ciCmd := ctx.Command(npmPath, ciArgs...)
 		ciCmd.Dir = sourceDir
 		ciCmd.Env = env
 
@@ -181,7 +181,7 @@ func (a *NpmExecAction) Execute(ctx *ExecutionContext, params map[string]interfa
 			installArgs = append(installArgs, "--ignore-scripts")
 		}
 
-		installCmd := exec.CommandContext(ctx.Context, npmPath, installArgs...)
+		installCmd := ctx.Command(npmPath, installArgs...)
 		installCmd.Dir = sourceDir
 		installCmd.Env = env
 
@@ -196,7 +196,7 @@ func (a *NpmExecAction) Execute(ctx *ExecutionContext, params map[string]interfa
 
 	// Parse command - it may be "build" or "run build"
 	cmdArgs := strings.Fields(command)
-	execCmd := exec.CommandContext(ctx.Context, npmPath, cmdArgs...)
+	execCmd := ctx.Command(npmPath, cmdArgs...)
 	execCmd.Dir = sourceDir
 	execCmd.Env = env
 
@@ -516,7 +516,7 @@ func (a *NpmExecAction) executePackageInstall(ctx *ExecutionContext, params map[
 		ciArgs = append(ciArgs, "--ignore-scripts")
 	}
 
-	ciCmd := exec.CommandContext(ctx.Context, npmPath, ciArgs...)
+	ciCmd := ctx.Command(npmPath, ciArgs...)
 	ciCmd.Dir = ctx.InstallDir
 	ciCmd.Env = env
 
@@ -84,7 +84,7 @@ func (a *NpmInstallAction) Execute(ctx *ExecutionContext, params map[string]inte
 	fmt.Printf("   Installing: npm install -g --prefix=%s %s\n", installDir, packageSpec)
 
 	// Use CommandContext for cancellation support
-	cmd := exec.CommandContext(ctx.Context, npmPath, "install", "-g", fmt.Sprintf("--prefix=%s", installDir), packageSpec)
+	cmd := ctx.Command(npmPath, "install", "-g", fmt.Sprintf("--prefix=%s", installDir), packageSpec)
 
 	// Set up environment: add npm's bin directory to PATH so npm can find node
 	// npm is a Node.js script that needs node in PATH
@@ -92,6 +92,9 @@ func (a *NpmInstallAction) Execute(ctx *ExecutionContext, params map[string]inte
 	env := os.Environ()
 	// Prepend npm's bin dir to PATH
 	env = append(env, fmt.Sprintf("PATH=%s:%s", npmDir, os.Getenv("PATH")))
+	// Keep the npm cache in the work directory instead of ~/.npm, which
+	// confined commands cannot write
+	env = append(env, fmt.Sprintf("npm_config_cache=%s", filepath.Join(ctx.WorkDir, ".npm-cache")))
 	cmd.Env = env
 
 	output, err := cmd.CombinedOutput()
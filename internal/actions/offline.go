@@ -0,0 +1,92 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OfflineError indicates a step needs something that is not in the local
+// caches while offline mode (--offline / TSUKU_OFFLINE) forbids fetching it.
+type OfflineError struct {
+	Action  string // Action that needs the network
+	Missing string // What is missing from the cache (a URL, a package, ...)
+}
+
+func (e *OfflineError) Error() string {
+	return fmt.Sprintf("offline mode: %s needs %s, which is not cached", e.Action, e.Missing)
+}
+
+// Suggestion returns an actionable hint for the user
+func (e *OfflineError) Suggestion() string {
+	return "Run the same command once while online to populate the cache, or drop --offline"
+}
+
+// OfflineDownloader serves downloads from the download cache only.
+// It implements Downloader for plan generation in offline mode.
+type OfflineDownloader struct {
+	cache *DownloadCache // nil when no download cache is configured
+}
+
+// NewOfflineDownloader creates a Downloader backed by cache
+func NewOfflineDownloader(cache *DownloadCache) *OfflineDownloader {
+	return &OfflineDownloader{cache: cache}
+}
+
+// Download copies the cached file for url to a temporary directory.
+// Returns an OfflineError when url is not cached.
+func (d *OfflineDownloader) Download(ctx context.Context, url string) (*DownloadResult, error) {
+	if d.cache == nil {
+		return nil, &OfflineError{Action: "download", Missing: url}
+	}
+
+	filePath, metaPath := d.cache.cachePaths(url)
+	meta, err := d.cache.readMeta(metaPath)
+	if err != nil {
+		return nil, &OfflineError{Action: "download", Missing: url}
+	}
+	info, err := os.Stat(filePath)
+	if err != nil || info.Size() != meta.Size {
+		return nil, &OfflineError{Action: "download", Missing: url}
+	}
+
+	downloadDir, err := os.MkdirTemp("", "tsuku-offline-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	filename := filepath.Base(url)
+	if idx := strings.Index(filename, "?"); idx != -1 {
+		filename = filename[:idx]
+	}
+	if filename == "" || filename == "." {
+		filename = "download"
+	}
+	destPath := filepath.Join(downloadDir, filename)
+
+	if err := copyFile(filePath, destPath); err != nil {
+		os.RemoveAll(downloadDir)
+		return nil, fmt.Errorf("failed to copy cached file: %w", err)
+	}
+
+	// The cache records the hash computed when the file was saved; recompute
+	// it so a tampered cache entry can't slip a wrong checksum into the plan
+	checksum, err := computeSHA256(destPath)
+	if err != nil {
+		os.RemoveAll(downloadDir)
+		return nil, fmt.Errorf("failed to compute checksum: %w", err)
+	}
+	if meta.ActualHash != "" && meta.ActualHash != checksum {
+		os.RemoveAll(downloadDir)
+		d.cache.invalidate(url)
+		return nil, &OfflineError{Action: "download", Missing: url}
+	}
+
+	return &DownloadResult{
+		AssetPath: destPath,
+		Checksum:  checksum,
+		Size:      info.Size(),
+	}, nil
+}
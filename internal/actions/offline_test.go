@@ -0,0 +1,123 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOfflineDownloader_CacheHit(t *testing.T) {
+	t.Parallel()
+	cache := NewDownloadCache(createSecureCacheDir(t))
+
+	sourcePath := filepath.Join(t.TempDir(), "tool.tar.gz")
+	if err := os.WriteFile(sourcePath, []byte("archive content"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	url := "https://example.com/tool.tar.gz"
+	if err := cache.Save(url, sourcePath, ""); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	want, err := computeSHA256(sourcePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewOfflineDownloader(cache).Download(context.Background(), url)
+	if err != nil {
+		t.Fatalf("Download() error = %v", err)
+	}
+	defer func() { _ = result.Cleanup() }()
+
+	if result.Checksum != want {
+		t.Errorf("Checksum = %s, want %s", result.Checksum, want)
+	}
+	if result.Size != int64(len("archive content")) {
+		t.Errorf("Size = %d, want %d", result.Size, len("archive content"))
+	}
+	if filepath.Base(result.AssetPath) != "tool.tar.gz" {
+		t.Errorf("AssetPath = %s, want a file named tool.tar.gz", result.AssetPath)
+	}
+}
+
+func TestOfflineDownloader_CacheMiss(t *testing.T) {
+	t.Parallel()
+	url := "https://example.com/missing.tar.gz"
+
+	for name, cache := range map[string]*DownloadCache{
+		"empty cache": NewDownloadCache(createSecureCacheDir(t)),
+		"no cache":    nil,
+	} {
+		_, err := NewOfflineDownloader(cache).Download(context.Background(), url)
+		var offlineErr *OfflineError
+		if !errors.As(err, &offlineErr) {
+			t.Fatalf("%s: Download() error = %v, want OfflineError", name, err)
+		}
+		if offlineErr.Missing != url {
+			t.Errorf("%s: Missing = %q, want %q", name, offlineErr.Missing, url)
+		}
+	}
+}
+
+func TestOfflineDownloader_TamperedEntry(t *testing.T) {
+	t.Parallel()
+	cacheDir := createSecureCacheDir(t)
+	cache := NewDownloadCache(cacheDir)
+
+	sourcePath := filepath.Join(t.TempDir(), "tool.tar.gz")
+	if err := os.WriteFile(sourcePath, []byte("original"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	url := "https://example.com/tool.tar.gz"
+	if err := cache.Save(url, sourcePath, ""); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Same size, different content
+	filePath, _ := cache.cachePaths(url)
+	if err := os.WriteFile(filePath, []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewOfflineDownloader(cache).Download(context.Background(), url)
+	var offlineErr *OfflineError
+	if !errors.As(err, &offlineErr) {
+		t.Fatalf("Download() error = %v, want OfflineError", err)
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Error("tampered cache entry should be removed")
+	}
+}
+
+func TestDownloadFileAction_Execute_OfflineCacheMiss(t *testing.T) {
+	t.Setenv("TSUKU_OFFLINE", "1")
+	tmpDir := t.TempDir()
+	ctx := &ExecutionContext{
+		Context:          context.Background(),
+		WorkDir:          tmpDir,
+		InstallDir:       tmpDir,
+		DownloadCacheDir: createSecureCacheDir(t),
+		Version:          "1.0.0",
+	}
+
+	url := "https://example.com/tool-1.0.0.tar.gz"
+	action := &DownloadFileAction{}
+	err := action.Execute(ctx, map[string]interface{}{
+		"url":      url,
+		"checksum": "0000000000000000000000000000000000000000000000000000000000000000",
+	})
+
+	var offlineErr *OfflineError
+	if !errors.As(err, &offlineErr) {
+		t.Fatalf("Execute() error = %v, want OfflineError", err)
+	}
+	if !strings.Contains(err.Error(), url) {
+		t.Errorf("error should name the missing URL, got %q", err.Error())
+	}
+	if offlineErr.Suggestion() == "" {
+		t.Error("OfflineError should carry a suggestion")
+	}
+}
@@ -127,7 +127,7 @@ func (a *PipExecAction) Execute(ctx *ExecutionContext, params map[string]interfa
 		return fmt.Errorf("failed to create venvs directory: %w", err)
 	}
 
-	venvCmd := exec.CommandContext(ctx.Context, pythonPath, "-m", "venv", venvDir)
+	venvCmd := ctx.Command(pythonPath, "-m", "venv", venvDir)
 	if output, err := venvCmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to create venv: %w\nOutput: %s", err, string(output))
 	}
@@ -155,7 +155,7 @@ func (a *PipExecAction) Execute(ctx *ExecutionContext, params map[string]interfa
 		"-r", reqFile,
 	}
 
-	pipCmd := exec.CommandContext(ctx.Context, pipBin, pipArgs...)
+	pipCmd := ctx.Command(pipBin, pipArgs...)
 	pipCmd.Dir = venvDir
 
 	// Set up environment - filter out PIP_USER which conflicts with venv installs
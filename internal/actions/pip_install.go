@@ -121,7 +121,7 @@ func (a *PipInstallAction) Execute(ctx *ExecutionContext, params map[string]inte
 
 	fmt.Printf("   Installing packages: pip %s\n", strings.Join(args, " "))
 
-	cmd := exec.CommandContext(ctx.Context, pipPath, args...)
+	cmd := ctx.Command(pipPath, args...)
 	cmd.Dir = ctx.WorkDir
 
 	// Set up deterministic environment
@@ -128,7 +128,7 @@ func (a *PipxInstallAction) Execute(ctx *ExecutionContext, params map[string]int
 		args = append(args, "--python", pythonPath)
 	}
 
-	cmd := exec.CommandContext(ctx.Context, pipxPath, args...)
+	cmd := ctx.Command(pipxPath, args...)
 
 	// Set environment for isolated installation
 	env := os.Environ()
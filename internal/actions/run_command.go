@@ -2,7 +2,6 @@ package actions
 
 import (
 	"fmt"
-	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -101,7 +100,7 @@ func (a *RunCommandAction) Execute(ctx *ExecutionContext, params map[string]inte
 	}
 
 	// Execute command with context for cancellation support
-	cmd := exec.CommandContext(ctx.Context, "sh", "-c", command)
+	cmd := ctx.Command("sh", "-c", command)
 	cmd.Dir = workingDir
 
 	// Capture output
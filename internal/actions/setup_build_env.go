@@ -24,12 +24,14 @@ func (a *SetupBuildEnvAction) Name() string {
 // PKG_CONFIG_PATH, CPPFLAGS, LDFLAGS, CC, CXX, and other variables needed for
 // building with tsuku-provided dependencies.
 //
-// No parameters required - uses ctx.Dependencies automatically.
+// No parameters required - uses ctx.Dependencies automatically. When a build
+// cache is configured, ccache and sccache are enabled unless the step sets
+// deterministic_sensitive.
 func (a *SetupBuildEnvAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
 	fmt.Printf("   Configuring build environment from %d dependencies\n", len(ctx.Dependencies.InstallTime))
 
 	// Build environment from dependencies and set it on the context
-	ctx.Env = buildAutotoolsEnv(ctx)
+	ctx.Env = withBuildCache(ctx, params, buildAutotoolsEnv(ctx))
 
 	// Extract and display the configured environment variables
 	var pkgConfigPath, cppFlags, ldFlags, cc, cxx string
@@ -66,6 +68,9 @@ func (a *SetupBuildEnvAction) Execute(ctx *ExecutionContext, params map[string]i
 	if cxx != "" {
 		fmt.Printf("   CXX: %s\n", cxx)
 	}
+	if caches := describeBuildCache(ctx.Env); len(caches) > 0 {
+		fmt.Printf("   Compiler cache: %s\n", strings.Join(caches, ", "))
+	}
 
 	if len(ctx.Dependencies.InstallTime) == 0 {
 		fmt.Printf("   (No dependencies to configure)\n")
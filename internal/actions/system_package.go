@@ -0,0 +1,362 @@
+package actions
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/tsukumogami/tsuku/internal/platform"
+)
+
+// systemPackageManager describes how to query and install packages with a
+// system package manager.
+type systemPackageManager struct {
+	Name   string // Key used in system_package "packages" maps (e.g., "apt")
+	Action string // Corresponding primitive action (e.g., "apt_install")
+	Binary string // Binary that must be present for the manager to be usable
+	Root   bool   // Whether installing requires root
+
+	// checkArgs returns the command that exits 0 when pkg is installed
+	checkArgs func(pkg string) []string
+	// checkOutput, if set, must equal the trimmed output of the check command
+	checkOutput string
+	// installArgs returns the command that installs pkgs
+	installArgs func(pkgs []string) []string
+}
+
+func rpmCheck(pkg string) []string { return []string{"rpm", "-q", pkg} }
+
+// systemPackageManagers lists the supported managers by name
+var systemPackageManagers = map[string]*systemPackageManager{
+	"apt": {
+		Name: "apt", Action: "apt_install", Binary: "apt-get", Root: true,
+		checkArgs:   func(pkg string) []string { return []string{"dpkg-query", "-W", "-f=${db:Status-Status}", pkg} },
+		checkOutput: "installed",
+		installArgs: func(pkgs []string) []string { return append([]string{"apt-get", "install", "-y"}, pkgs...) },
+	},
+	"dnf": {
+		Name: "dnf", Action: "dnf_install", Binary: "dnf", Root: true,
+		checkArgs:   rpmCheck,
+		installArgs: func(pkgs []string) []string { return append([]string{"dnf", "install", "-y"}, pkgs...) },
+	},
+	"yum": {
+		Name: "yum", Action: "yum_install", Binary: "yum", Root: true,
+		checkArgs:   rpmCheck,
+		installArgs: func(pkgs []string) []string { return append([]string{"yum", "install", "-y"}, pkgs...) },
+	},
+	"apk": {
+		Name: "apk", Action: "apk_install", Binary: "apk", Root: true,
+		checkArgs:   func(pkg string) []string { return []string{"apk", "info", "-e", pkg} },
+		installArgs: func(pkgs []string) []string { return append([]string{"apk", "add"}, pkgs...) },
+	},
+	"pacman": {
+		Name: "pacman", Action: "pacman_install", Binary: "pacman", Root: true,
+		checkArgs: func(pkg string) []string { return []string{"pacman", "-Q", pkg} },
+		installArgs: func(pkgs []string) []string {
+			return append([]string{"pacman", "-S", "--noconfirm", "--needed"}, pkgs...)
+		},
+	},
+	"zypper": {
+		Name: "zypper", Action: "zypper_install", Binary: "zypper", Root: true,
+		checkArgs: rpmCheck,
+		installArgs: func(pkgs []string) []string {
+			return append([]string{"zypper", "--non-interactive", "install"}, pkgs...)
+		},
+	},
+	"brew": {
+		Name: "brew", Action: "brew_install", Binary: "brew", Root: false,
+		checkArgs:   func(pkg string) []string { return []string{"brew", "list", "--versions", pkg} },
+		installArgs: func(pkgs []string) []string { return append([]string{"brew", "install"}, pkgs...) },
+	},
+}
+
+// distroPackageManagers maps os-release IDs to the manager of that distro family
+var distroPackageManagers = map[string]string{
+	"debian":              "apt",
+	"ubuntu":              "apt",
+	"fedora":              "dnf",
+	"rhel":                "dnf",
+	"centos":              "dnf",
+	"rocky":               "dnf",
+	"almalinux":           "dnf",
+	"amzn":                "dnf",
+	"alpine":              "apk",
+	"arch":                "pacman",
+	"manjaro":             "pacman",
+	"endeavouros":         "pacman",
+	"opensuse":            "zypper",
+	"opensuse-leap":       "zypper",
+	"opensuse-tumbleweed": "zypper",
+	"suse":                "zypper",
+	"sles":                "zypper",
+}
+
+// fallbackManagerOrder is the order in which managers are probed on Linux
+// systems whose os-release doesn't identify a known distro family
+var fallbackManagerOrder = []string{"apt", "dnf", "yum", "apk", "pacman", "zypper"}
+
+// detectPackageManager selects the package manager for a system from its OS and
+// os-release information. lookPath reports whether a binary is available.
+func detectPackageManager(goos string, distro platform.Distro, lookPath func(string) bool) (*systemPackageManager, error) {
+	if goos == "darwin" {
+		return systemPackageManagers["brew"], nil
+	}
+	if goos != "linux" {
+		return nil, fmt.Errorf("no supported system package manager for %s", goos)
+	}
+
+	for _, id := range distro.Like() {
+		name, ok := distroPackageManagers[id]
+		if !ok {
+			continue
+		}
+		// Older RHEL-family releases only ship yum
+		if name == "dnf" && !lookPath("dnf") && lookPath("yum") {
+			name = "yum"
+		}
+		return systemPackageManagers[name], nil
+	}
+
+	for _, name := range fallbackManagerOrder {
+		if pm := systemPackageManagers[name]; lookPath(pm.Binary) {
+			return pm, nil
+		}
+	}
+	return nil, fmt.Errorf("could not detect the system package manager (distro %q)", distro.ID)
+}
+
+// resolveSystemPackages maps a system_package step to package names for a
+// manager. Lookup order: the distro ID (e.g., "ubuntu"), the manager name
+// (e.g., "apt"), then the logical package name itself.
+func resolveSystemPackages(params map[string]interface{}, pm *systemPackageManager, distroID string) ([]string, error) {
+	mapping, _ := params["packages"].(map[string]interface{})
+	for _, key := range []string{distroID, pm.Name} {
+		if key == "" {
+			continue
+		}
+		value, ok := mapping[key]
+		if !ok {
+			continue
+		}
+		pkgs, err := systemPackageList(value)
+		if err != nil {
+			return nil, fmt.Errorf("packages.%s: %w", key, err)
+		}
+		return pkgs, nil
+	}
+
+	name, _ := GetString(params, "name")
+	if name == "" {
+		return nil, fmt.Errorf("system_package action requires 'name' parameter")
+	}
+	if err := validatePackageName(name); err != nil {
+		return nil, err
+	}
+	return []string{name}, nil
+}
+
+// systemPackageList parses a package mapping value: a name or a list of names
+func systemPackageList(value interface{}) ([]string, error) {
+	var pkgs []string
+	switch v := value.(type) {
+	case string:
+		pkgs = []string{v}
+	case []string:
+		pkgs = v
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("package names must be strings")
+			}
+			pkgs = append(pkgs, s)
+		}
+	default:
+		return nil, fmt.Errorf("expected a package name or list of names")
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no package names given")
+	}
+	for _, pkg := range pkgs {
+		if err := validatePackageName(pkg); err != nil {
+			return nil, err
+		}
+	}
+	return pkgs, nil
+}
+
+// validatePackageName rejects names that could be interpreted as options or
+// contain characters no package manager uses.
+func validatePackageName(name string) error {
+	if name == "" {
+		return fmt.Errorf("package name cannot be empty")
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("package name cannot start with '-': %s", name)
+	}
+	for _, c := range name {
+		if !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+			strings.ContainsRune("-_.+@:/", c)) {
+			return fmt.Errorf("package name contains invalid character '%c': %s", c, name)
+		}
+	}
+	return nil
+}
+
+// isInstalled reports whether pkg is installed, using commands that don't need root
+func (pm *systemPackageManager) isInstalled(pkg string) bool {
+	args := pm.checkArgs(pkg)
+	output, err := exec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return false
+	}
+	return pm.checkOutput == "" || strings.TrimSpace(string(output)) == pm.checkOutput
+}
+
+// installCommand returns the command that installs pkgs, prefixed with sudo
+// when the manager needs root and the current user isn't root.
+func (pm *systemPackageManager) installCommand(pkgs []string, root bool) []string {
+	args := pm.installArgs(pkgs)
+	if pm.Root && !root {
+		return append([]string{"sudo"}, args...)
+	}
+	return args
+}
+
+// SystemPackageAction installs a system package through the host's package
+// manager, mapping a logical name to the package names of each distro family.
+type SystemPackageAction struct{ BaseAction }
+
+// RequiresNetwork returns true because missing packages are fetched from repositories.
+func (SystemPackageAction) RequiresNetwork() bool { return true }
+
+// Name returns the action name
+func (a *SystemPackageAction) Name() string {
+	return "system_package"
+}
+
+// Preflight validates parameters without side effects.
+func (a *SystemPackageAction) Preflight(params map[string]interface{}) *PreflightResult {
+	result := &PreflightResult{}
+	name, ok := GetString(params, "name")
+	if !ok || name == "" {
+		result.AddError("system_package action requires 'name' parameter")
+	} else if err := validatePackageName(name); err != nil {
+		result.AddError(err.Error())
+	}
+
+	raw, hasPackages := params["packages"]
+	if !hasPackages {
+		result.AddWarning("consider adding 'packages' with per-distro package names (apt, dnf, apk, pacman, zypper, brew)")
+		return result
+	}
+	mapping, ok := raw.(map[string]interface{})
+	if !ok {
+		result.AddError("'packages' must be a table mapping package managers or distro IDs to package names")
+		return result
+	}
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := systemPackageList(mapping[key]); err != nil {
+			result.AddError(fmt.Sprintf("packages.%s: %v", key, err))
+		}
+	}
+	return result
+}
+
+// Execute ensures a system package is installed.
+//
+// Parameters:
+//   - name (required): Logical package name, used when no mapping applies
+//   - packages (optional): Map of package manager (apt, dnf, yum, apk, pacman,
+//     zypper, brew) or distro ID (e.g., ubuntu) to a package name or list of names
+//   - install_guide (optional): Platform-specific instructions shown when no
+//     package manager can be detected
+//
+// Packages that are already installed are detected without root. Missing
+// packages are installed directly when running as root (or for Homebrew), or
+// with sudo when it's available without a password. Otherwise the action fails
+// with the exact command to run.
+func (a *SystemPackageAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	name, ok := GetString(params, "name")
+	if !ok || name == "" {
+		return fmt.Errorf("system_package action requires 'name' parameter")
+	}
+
+	distro := platform.HostDistro()
+	pm, err := detectPackageManager(runtime.GOOS, distro, func(bin string) bool {
+		_, err := exec.LookPath(bin)
+		return err == nil
+	})
+	if err != nil {
+		installGuide, _ := GetMapStringString(params, "install_guide")
+		return &SystemDepMissingError{
+			Command:      name,
+			InstallGuide: getPlatformGuide(installGuide, runtime.GOOS, runtime.GOARCH),
+		}
+	}
+
+	pkgs, err := resolveSystemPackages(params, pm, distro.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("   Checking system packages via %s: %s\n", pm.Name, strings.Join(pkgs, " "))
+	var missing []string
+	for _, pkg := range pkgs {
+		if !pm.isInstalled(pkg) {
+			missing = append(missing, pkg)
+		}
+	}
+	if len(missing) == 0 {
+		fmt.Printf("   System packages already installed: %s\n", strings.Join(pkgs, " "))
+		return nil
+	}
+
+	root := os.Geteuid() == 0
+	command := pm.installCommand(missing, root)
+	if pm.Root && !root && !sudoAvailable() {
+		return &SystemPackageMissingError{
+			Manager:  pm.Name,
+			Packages: missing,
+			Command:  strings.Join(command, " "),
+		}
+	}
+
+	fmt.Printf("   Running: %s\n", strings.Join(command, " "))
+	cmd := exec.CommandContext(ctx.Context, command[0], command[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to install %s via %s: %w", strings.Join(missing, " "), pm.Name, err)
+	}
+	return nil
+}
+
+// sudoAvailable reports whether sudo can be used without a password prompt
+func sudoAvailable() bool {
+	if _, err := exec.LookPath("sudo"); err != nil {
+		return false
+	}
+	return exec.Command("sudo", "-n", "true").Run() == nil
+}
+
+// SystemPackageMissingError indicates system packages are missing and could not
+// be installed without user intervention.
+type SystemPackageMissingError struct {
+	Manager  string
+	Packages []string
+	Command  string
+}
+
+func (e *SystemPackageMissingError) Error() string {
+	return fmt.Sprintf("required system packages not installed: %s\n\nInstall them with %s:\n  %s",
+		strings.Join(e.Packages, ", "), e.Manager, e.Command)
+}
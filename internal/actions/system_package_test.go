@@ -0,0 +1,170 @@
+package actions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/platform"
+)
+
+func lookPathFor(bins ...string) func(string) bool {
+	return func(bin string) bool {
+		for _, b := range bins {
+			if b == bin {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestDetectPackageManager(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		goos   string
+		distro platform.Distro
+		bins   []string
+		want   string
+	}{
+		{"ubuntu", "linux", platform.Distro{ID: "ubuntu", IDLike: "debian"}, nil, "apt"},
+		{"debian derivative", "linux", platform.Distro{ID: "pop", IDLike: "ubuntu debian"}, nil, "apt"},
+		{"fedora", "linux", platform.Distro{ID: "fedora"}, []string{"dnf"}, "dnf"},
+		{"centos 7 without dnf", "linux", platform.Distro{ID: "centos", IDLike: "rhel fedora"}, []string{"yum"}, "yum"},
+		{"alpine", "linux", platform.Distro{ID: "alpine"}, nil, "apk"},
+		{"arch derivative", "linux", platform.Distro{ID: "garuda", IDLike: "arch"}, nil, "pacman"},
+		{"opensuse", "linux", platform.Distro{ID: "opensuse-tumbleweed", IDLike: "opensuse suse"}, nil, "zypper"},
+		{"unknown distro probes binaries", "linux", platform.Distro{ID: "custom"}, []string{"pacman"}, "pacman"},
+		{"darwin", "darwin", platform.Distro{}, nil, "brew"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pm, err := detectPackageManager(tt.goos, tt.distro, lookPathFor(tt.bins...))
+			if err != nil {
+				t.Fatalf("detectPackageManager() error = %v", err)
+			}
+			if pm.Name != tt.want {
+				t.Errorf("detectPackageManager() = %q, want %q", pm.Name, tt.want)
+			}
+		})
+	}
+
+	if _, err := detectPackageManager("linux", platform.Distro{ID: "custom"}, lookPathFor()); err == nil {
+		t.Error("detectPackageManager() should fail when no manager is available")
+	}
+	if _, err := detectPackageManager("windows", platform.Distro{}, lookPathFor()); err == nil {
+		t.Error("detectPackageManager() should fail on unsupported OS")
+	}
+}
+
+func TestResolveSystemPackages(t *testing.T) {
+	t.Parallel()
+	params := map[string]interface{}{
+		"name": "openssl",
+		"packages": map[string]interface{}{
+			"apt":    []interface{}{"libssl-dev", "pkg-config"},
+			"dnf":    "openssl-devel",
+			"ubuntu": "libssl3-dev",
+		},
+	}
+
+	tests := []struct {
+		manager  string
+		distroID string
+		want     []string
+	}{
+		{"apt", "ubuntu", []string{"libssl3-dev"}},
+		{"apt", "debian", []string{"libssl-dev", "pkg-config"}},
+		{"dnf", "fedora", []string{"openssl-devel"}},
+		{"apk", "alpine", []string{"openssl"}},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveSystemPackages(params, systemPackageManagers[tt.manager], tt.distroID)
+		if err != nil {
+			t.Errorf("resolveSystemPackages(%s, %s) error = %v", tt.manager, tt.distroID, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("resolveSystemPackages(%s, %s) = %v, want %v", tt.manager, tt.distroID, got, tt.want)
+		}
+	}
+
+	bad := map[string]interface{}{
+		"name":     "openssl",
+		"packages": map[string]interface{}{"apt": "--allow-unauthenticated"},
+	}
+	if _, err := resolveSystemPackages(bad, systemPackageManagers["apt"], "debian"); err == nil {
+		t.Error("resolveSystemPackages() should reject option-like package names")
+	}
+}
+
+func TestSystemPackageManager_InstallCommand(t *testing.T) {
+	t.Parallel()
+	pkgs := []string{"libssl-dev"}
+
+	if got := strings.Join(systemPackageManagers["apt"].installCommand(pkgs, false), " "); got != "sudo apt-get install -y libssl-dev" {
+		t.Errorf("apt installCommand() = %q", got)
+	}
+	if got := strings.Join(systemPackageManagers["apk"].installCommand(pkgs, true), " "); got != "apk add libssl-dev" {
+		t.Errorf("apk installCommand() as root = %q", got)
+	}
+	if got := strings.Join(systemPackageManagers["brew"].installCommand([]string{"openssl@3"}, false), " "); got != "brew install openssl@3" {
+		t.Errorf("brew installCommand() = %q", got)
+	}
+}
+
+func TestSystemPackageAction_Preflight(t *testing.T) {
+	t.Parallel()
+	action := &SystemPackageAction{}
+
+	result := action.Preflight(map[string]interface{}{
+		"name":     "openssl",
+		"packages": map[string]interface{}{"apt": "libssl-dev", "pacman": []interface{}{"openssl"}},
+	})
+	if result.HasErrors() || result.HasWarnings() {
+		t.Errorf("Preflight() = %+v, want no errors or warnings", result)
+	}
+
+	if result := action.Preflight(map[string]interface{}{}); !result.HasErrors() {
+		t.Error("Preflight() should fail without 'name'")
+	}
+	if result := action.Preflight(map[string]interface{}{"name": "openssl"}); !result.HasWarnings() {
+		t.Error("Preflight() should warn without 'packages'")
+	}
+	result = action.Preflight(map[string]interface{}{
+		"name":     "openssl",
+		"packages": map[string]interface{}{"apt": 42},
+	})
+	if !result.HasErrors() {
+		t.Error("Preflight() should fail for non-string package names")
+	}
+}
+
+func TestSystemPackageAction_Execute_MissingName(t *testing.T) {
+	t.Parallel()
+	action := &SystemPackageAction{}
+	if !action.RequiresNetwork() {
+		t.Error("RequiresNetwork() = false, want true")
+	}
+	if err := action.Execute(&ExecutionContext{}, map[string]interface{}{}); err == nil {
+		t.Error("Execute() should fail when 'name' parameter is missing")
+	}
+}
+
+func TestSystemPackageMissingError_Error(t *testing.T) {
+	t.Parallel()
+	err := &SystemPackageMissingError{
+		Manager:  "apt",
+		Packages: []string{"libssl-dev", "pkg-config"},
+		Command:  "sudo apt-get install -y libssl-dev pkg-config",
+	}
+	msg := err.Error()
+	for _, want := range []string{"libssl-dev, pkg-config", "sudo apt-get install -y libssl-dev pkg-config"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Error() = %q, want to contain %q", msg, want)
+		}
+	}
+}
@@ -56,6 +56,110 @@ func (a *YumInstallAction) Execute(ctx *ExecutionContext, params map[string]inte
 	return nil
 }
 
+// DnfInstallAction implements dnf package installation (stub for validation)
+type DnfInstallAction struct{ BaseAction }
+
+// RequiresNetwork returns true because dnf_install fetches packages from repositories.
+func (DnfInstallAction) RequiresNetwork() bool { return true }
+
+// Name returns the action name
+func (a *DnfInstallAction) Name() string {
+	return "dnf_install"
+}
+
+// Execute is a stub that logs what would be installed
+//
+// Parameters:
+//   - packages (required): List of packages to install
+func (a *DnfInstallAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	packages, ok := GetStringSlice(params, "packages")
+	if !ok {
+		return fmt.Errorf("dnf_install action requires 'packages' parameter")
+	}
+
+	fmt.Printf("   Would install via dnf: %v\n", packages)
+	fmt.Printf("   (Skipped - requires sudo and system modification)\n")
+	return nil
+}
+
+// ApkInstallAction implements Alpine apk package installation (stub for validation)
+type ApkInstallAction struct{ BaseAction }
+
+// RequiresNetwork returns true because apk_install fetches packages from repositories.
+func (ApkInstallAction) RequiresNetwork() bool { return true }
+
+// Name returns the action name
+func (a *ApkInstallAction) Name() string {
+	return "apk_install"
+}
+
+// Execute is a stub that logs what would be installed
+//
+// Parameters:
+//   - packages (required): List of packages to install
+func (a *ApkInstallAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	packages, ok := GetStringSlice(params, "packages")
+	if !ok {
+		return fmt.Errorf("apk_install action requires 'packages' parameter")
+	}
+
+	fmt.Printf("   Would install via apk: %v\n", packages)
+	fmt.Printf("   (Skipped - requires sudo and system modification)\n")
+	return nil
+}
+
+// PacmanInstallAction implements pacman package installation (stub for validation)
+type PacmanInstallAction struct{ BaseAction }
+
+// RequiresNetwork returns true because pacman_install fetches packages from repositories.
+func (PacmanInstallAction) RequiresNetwork() bool { return true }
+
+// Name returns the action name
+func (a *PacmanInstallAction) Name() string {
+	return "pacman_install"
+}
+
+// Execute is a stub that logs what would be installed
+//
+// Parameters:
+//   - packages (required): List of packages to install
+func (a *PacmanInstallAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	packages, ok := GetStringSlice(params, "packages")
+	if !ok {
+		return fmt.Errorf("pacman_install action requires 'packages' parameter")
+	}
+
+	fmt.Printf("   Would install via pacman: %v\n", packages)
+	fmt.Printf("   (Skipped - requires sudo and system modification)\n")
+	return nil
+}
+
+// ZypperInstallAction implements zypper package installation (stub for validation)
+type ZypperInstallAction struct{ BaseAction }
+
+// RequiresNetwork returns true because zypper_install fetches packages from repositories.
+func (ZypperInstallAction) RequiresNetwork() bool { return true }
+
+// Name returns the action name
+func (a *ZypperInstallAction) Name() string {
+	return "zypper_install"
+}
+
+// Execute is a stub that logs what would be installed
+//
+// Parameters:
+//   - packages (required): List of packages to install
+func (a *ZypperInstallAction) Execute(ctx *ExecutionContext, params map[string]interface{}) error {
+	packages, ok := GetStringSlice(params, "packages")
+	if !ok {
+		return fmt.Errorf("zypper_install action requires 'packages' parameter")
+	}
+
+	fmt.Printf("   Would install via zypper: %v\n", packages)
+	fmt.Printf("   (Skipped - requires sudo and system modification)\n")
+	return nil
+}
+
 // BrewInstallAction implements Homebrew package installation (stub for validation)
 type BrewInstallAction struct{ BaseAction }
 
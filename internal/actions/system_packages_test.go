@@ -133,3 +133,38 @@ func TestBrewInstallAction_Execute_MissingPackages(t *testing.T) {
 		t.Error("Execute() should fail when 'packages' parameter is missing")
 	}
 }
+
+func TestDistroInstallActions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		action Action
+		name   string
+	}{
+		{&DnfInstallAction{}, "dnf_install"},
+		{&ApkInstallAction{}, "apk_install"},
+		{&PacmanInstallAction{}, "pacman_install"},
+		{&ZypperInstallAction{}, "zypper_install"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.action.Name() != tt.name {
+				t.Errorf("Name() = %q, want %q", tt.action.Name(), tt.name)
+			}
+			if !tt.action.(NetworkValidator).RequiresNetwork() {
+				t.Error("RequiresNetwork() = false, want true")
+			}
+
+			tmpDir := t.TempDir()
+			ctx := &ExecutionContext{WorkDir: tmpDir, InstallDir: tmpDir, Version: "1.0.0"}
+			if err := tt.action.Execute(ctx, map[string]interface{}{
+				"packages": []interface{}{"openssl"},
+			}); err != nil {
+				t.Errorf("Execute() error = %v", err)
+			}
+			if err := tt.action.Execute(ctx, map[string]interface{}{}); err == nil {
+				t.Error("Execute() should fail when 'packages' parameter is missing")
+			}
+		})
+	}
+}
@@ -12,10 +12,12 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+
+	"github.com/tsukumogami/tsuku/internal/platform"
 )
 
 // ExpandVars replaces variables in a string with their values
-// Supported variables: {version}, {os}, {arch}, {install_dir}, {work_dir}, {libs_dir}
+// Supported variables: {version}, {os}, {arch}, {libc}, {install_dir}, {work_dir}, {libs_dir}
 func ExpandVars(s string, vars map[string]string) string {
 	result := s
 	for k, v := range vars {
@@ -30,6 +32,7 @@ func GetStandardVars(version, installDir, workDir, libsDir string) map[string]st
 		"version":     version,
 		"os":          MapOS(runtime.GOOS),
 		"arch":        MapArch(runtime.GOARCH),
+		"libc":        platform.Libc(),
 		"install_dir": installDir,
 		"work_dir":    workDir,
 		"libs_dir":    libsDir,
@@ -0,0 +1,315 @@
+// Package audit checks the packages of installed tools against an OSV
+// vulnerability database. Packages are identified by the package URLs that
+// the sbom package derives from installation plans: the package of a tool
+// installed through npm, PyPI, crates.io, RubyGems or Go modules, and every
+// package pinned by its captured lockfile.
+package audit
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/tsukumogami/tsuku/internal/sbom"
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+// Finding is a vulnerable package version used by an installed tool
+type Finding struct {
+	Tool        string   `json:"tool"`
+	ToolVersion string   `json:"tool_version"`
+	Package     string   `json:"package"` // Package URL
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Ecosystem   string   `json:"ecosystem"`
+	ID          string   `json:"id"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Fixed       []string `json:"fixed,omitempty"` // Versions that fix the advisory, if any
+}
+
+// Report is the result of an audit
+type Report struct {
+	Tools    int       `json:"tools"`    // Tool versions with at least one auditable package
+	Packages int       `json:"packages"` // Package versions checked
+	Findings []Finding `json:"findings"`
+}
+
+// Vulnerable reports whether any advisory matched
+func (r *Report) Vulnerable() bool {
+	return len(r.Findings) > 0
+}
+
+// VulnerableTools returns the names of tools with findings, sorted
+func (r *Report) VulnerableTools() []string {
+	seen := make(map[string]bool)
+	var tools []string
+	for _, f := range r.Findings {
+		if !seen[f.Tool] {
+			seen[f.Tool] = true
+			tools = append(tools, f.Tool)
+		}
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// RequiredEcosystems returns the OSV ecosystems of the packages in doc,
+// sorted
+func RequiredEcosystems(doc *sbom.Document) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, c := range doc.Components {
+		purls := []string{c.PURL}
+		for _, p := range c.Packages {
+			purls = append(purls, p.PURL)
+		}
+		for _, p := range purls {
+			typ, _, _, _ := parsePURL(p)
+			if ecosystem := ecosystems[typ]; ecosystem != "" && !seen[ecosystem] {
+				seen[ecosystem] = true
+				out = append(out, ecosystem)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Audit checks every tool in doc, and the packages bundled with it, against
+// the database
+func Audit(doc *sbom.Document, db *Database) (*Report, error) {
+	report := &Report{Findings: []Finding{}}
+	for _, c := range doc.Components {
+		if c.Type != sbom.TypeApplication {
+			continue
+		}
+		purls := make([]string, 0, len(c.Packages)+1)
+		if c.PURL != "" {
+			purls = append(purls, c.PURL)
+		}
+		for _, p := range c.Packages {
+			purls = append(purls, p.PURL)
+		}
+
+		audited := false
+		for _, p := range purls {
+			typ, name, ver, ok := parsePURL(p)
+			ecosystem := ecosystems[typ]
+			if !ok || ecosystem == "" || ver == "" {
+				continue
+			}
+			audited = true
+			report.Packages++
+
+			advisories, err := db.Advisories(ecosystem, name)
+			if err != nil {
+				return nil, err
+			}
+			for _, adv := range advisories {
+				fixed, affected := matches(adv, ecosystem, name, ver)
+				if !affected {
+					continue
+				}
+				report.Findings = append(report.Findings, Finding{
+					Tool:        c.Name,
+					ToolVersion: c.Version,
+					Package:     p,
+					Name:        name,
+					Version:     ver,
+					Ecosystem:   ecosystem,
+					ID:          adv.ID,
+					Aliases:     adv.Aliases,
+					Summary:     adv.Summary,
+					Fixed:       fixed,
+				})
+			}
+		}
+		if audited {
+			report.Tools++
+		}
+	}
+
+	sort.Slice(report.Findings, func(i, j int) bool {
+		a, b := report.Findings[i], report.Findings[j]
+		if a.Tool != b.Tool {
+			return a.Tool < b.Tool
+		}
+		if a.ToolVersion != b.ToolVersion {
+			return a.ToolVersion < b.ToolVersion
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.ID < b.ID
+	})
+	return report, nil
+}
+
+// matches reports whether an advisory affects a package version, and the
+// versions that fix it
+func matches(adv *Advisory, ecosystem, name, ver string) ([]string, bool) {
+	key := packageKey(ecosystem, name)
+	var fixed []string
+	affected := false
+	for _, a := range adv.Affected {
+		if a.Package.Ecosystem != ecosystem || packageKey(ecosystem, a.Package.Name) != key {
+			continue
+		}
+		if affectsVersion(a, ecosystem, ver) {
+			affected = true
+			for _, r := range a.Ranges {
+				for _, e := range r.Events {
+					if e.Fixed != "" {
+						fixed = append(fixed, e.Fixed)
+					}
+				}
+			}
+		}
+	}
+	return fixed, affected
+}
+
+// affectsVersion evaluates the explicit versions and the SEMVER and
+// ECOSYSTEM ranges of an affected entry. GIT ranges refer to commits, which
+// installed packages don't record.
+func affectsVersion(a Affected, ecosystem, ver string) bool {
+	v := normalizeVersion(ecosystem, ver)
+	for _, listed := range a.Versions {
+		if normalizeVersion(ecosystem, listed) == v {
+			return true
+		}
+	}
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+		if inRange(r.Events, ecosystem, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// inRange applies the OSV range evaluation: the version is affected when
+// the closest introduced event at or below it is not followed by a fixed
+// event at or below it, or a last_affected event below it.
+func inRange(events []Event, ecosystem, v string) bool {
+	type point struct {
+		version string
+		kind    string
+	}
+	var points []point
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			points = append(points, point{e.Introduced, "introduced"})
+		case e.Fixed != "":
+			points = append(points, point{e.Fixed, "fixed"})
+		case e.LastAffected != "":
+			points = append(points, point{e.LastAffected, "last_affected"})
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return compareVersions(ecosystem, points[i].version, points[j].version) < 0
+	})
+
+	affected := false
+	for _, p := range points {
+		c := compareVersions(ecosystem, p.version, v)
+		switch p.kind {
+		case "introduced":
+			if c <= 0 {
+				affected = true
+			}
+		case "fixed":
+			if c <= 0 {
+				affected = false
+			}
+		case "last_affected":
+			if c < 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// compareVersions orders two versions of a package. "0" is the start of
+// every range. Semantic versions are compared with prerelease precedence;
+// other schemes fall back to comparing the numeric parts.
+func compareVersions(ecosystem, a, b string) int {
+	a, b = normalizeVersion(ecosystem, a), normalizeVersion(ecosystem, b)
+	if a == b {
+		return 0
+	}
+	if a == "0" {
+		return -1
+	}
+	if b == "0" {
+		return 1
+	}
+	va, errA := semver.StrictNewVersion(a)
+	vb, errB := semver.StrictNewVersion(b)
+	if errA == nil && errB == nil {
+		return va.Compare(vb)
+	}
+	return version.CompareVersions(a, b)
+}
+
+// normalizeVersion removes the "v" prefix of Go module versions, which OSV
+// omits
+func normalizeVersion(ecosystem, v string) string {
+	if ecosystem == "Go" {
+		return strings.TrimPrefix(v, "v")
+	}
+	return v
+}
+
+// packageKey returns the name a package is indexed under. PyPI names are
+// case-insensitive and treat "-", "_" and "." alike.
+func packageKey(ecosystem, name string) string {
+	if ecosystem == "PyPI" {
+		return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	}
+	return name
+}
+
+// parsePURL splits a package URL into its type, name (with namespace) and
+// version. Qualifiers and subpaths are ignored.
+func parsePURL(p string) (typ, name, ver string, ok bool) {
+	rest, found := strings.CutPrefix(p, "pkg:")
+	if !found {
+		return "", "", "", false
+	}
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	typ, rest, found = strings.Cut(rest, "/")
+	if !found || rest == "" {
+		return "", "", "", false
+	}
+	if i := strings.LastIndex(rest, "@"); i >= 0 {
+		var err error
+		if ver, err = url.PathUnescape(rest[i+1:]); err != nil {
+			return "", "", "", false
+		}
+		rest = rest[:i]
+	}
+	name, ok = unescapeName(rest)
+	return typ, name, ver, ok
+}
+
+// unescapeName decodes each path segment of a purl name
+func unescapeName(escaped string) (string, bool) {
+	segments := strings.Split(escaped, "/")
+	for i, s := range segments {
+		u, err := url.PathUnescape(s)
+		if err != nil {
+			return "", false
+		}
+		segments[i] = u
+	}
+	return strings.Join(segments, "/"), true
+}
@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/sbom"
+)
+
+func writeAdvisory(t *testing.T, dir, ecosystem, id, content string) {
+	t.Helper()
+	ecoDir := filepath.Join(dir, ecosystem)
+	if err := os.MkdirAll(ecoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ecoDir, id+".json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testDatabase(t *testing.T) *Database {
+	t.Helper()
+	dir := t.TempDir()
+	writeAdvisory(t, dir, "crates.io", "RUSTSEC-2024-0001", `{
+		"id": "RUSTSEC-2024-0001",
+		"summary": "memchr out-of-bounds read",
+		"aliases": ["CVE-2024-0001"],
+		"affected": [{
+			"package": {"ecosystem": "crates.io", "name": "memchr"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "2.0.0"}, {"fixed": "2.7.2"}]}]
+		}]
+	}`)
+	writeAdvisory(t, dir, "PyPI", "PYSEC-2024-1", `{
+		"id": "PYSEC-2024-1",
+		"affected": [{
+			"package": {"ecosystem": "PyPI", "name": "Typing_Extensions"},
+			"versions": ["4.11.0"]
+		}]
+	}`)
+	writeAdvisory(t, dir, "npm", "GHSA-old", `{
+		"id": "GHSA-old",
+		"affected": [{
+			"package": {"ecosystem": "npm", "name": "prettier"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "2.0.0"}]}]
+		}]
+	}`)
+	writeAdvisory(t, dir, "npm", "GHSA-withdrawn", `{
+		"id": "GHSA-withdrawn",
+		"withdrawn": "2024-01-01T00:00:00Z",
+		"affected": [{"package": {"ecosystem": "npm", "name": "prettier"}, "versions": ["3.0.0"]}]
+	}`)
+
+	db, err := OpenDatabase(dir)
+	if err != nil {
+		t.Fatalf("OpenDatabase() error = %v", err)
+	}
+	return db
+}
+
+func testDocument() *sbom.Document {
+	return &sbom.Document{Components: []*sbom.Component{
+		{
+			Type: sbom.TypeApplication, Name: "ripgrep", Version: "14.1.0", PURL: "pkg:cargo/ripgrep@14.1.0",
+			Packages: []*sbom.Component{{PURL: "pkg:cargo/memchr@2.7.1"}, {PURL: "pkg:cargo/regex@1.10.0"}},
+		},
+		{
+			Type: sbom.TypeApplication, Name: "ruff", Version: "0.4.1", PURL: "pkg:pypi/ruff@0.4.1",
+			Packages: []*sbom.Component{{PURL: "pkg:pypi/typing-extensions@4.11.0"}},
+		},
+		{Type: sbom.TypeApplication, Name: "prettier", Version: "3.0.0", PURL: "pkg:npm/prettier@3.0.0"},
+		{Type: sbom.TypeApplication, Name: "jq", Version: "1.7.1"},
+		{Type: sbom.TypeLibrary, Name: "libyaml", Version: "0.2.5"},
+	}}
+}
+
+func TestAudit(t *testing.T) {
+	report, err := Audit(testDocument(), testDatabase(t))
+	if err != nil {
+		t.Fatalf("Audit() error = %v", err)
+	}
+
+	if report.Tools != 3 || report.Packages != 6 {
+		t.Errorf("audited %d tools and %d packages, want 3 and 6", report.Tools, report.Packages)
+	}
+	if len(report.Findings) != 2 {
+		t.Fatalf("got %d findings, want 2: %+v", len(report.Findings), report.Findings)
+	}
+
+	rg := report.Findings[0]
+	if rg.Tool != "ripgrep" || rg.ID != "RUSTSEC-2024-0001" || rg.Name != "memchr" || rg.Ecosystem != "crates.io" {
+		t.Errorf("finding = %+v", rg)
+	}
+	if len(rg.Fixed) != 1 || rg.Fixed[0] != "2.7.2" {
+		t.Errorf("Fixed = %v, want [2.7.2]", rg.Fixed)
+	}
+
+	if report.Findings[1].Tool != "ruff" || report.Findings[1].ID != "PYSEC-2024-1" {
+		t.Errorf("finding = %+v", report.Findings[1])
+	}
+
+	if got := report.VulnerableTools(); len(got) != 2 || got[0] != "ripgrep" || got[1] != "ruff" {
+		t.Errorf("VulnerableTools() = %v", got)
+	}
+}
+
+func TestRequiredEcosystems(t *testing.T) {
+	got := RequiredEcosystems(testDocument())
+	if len(got) != 3 || got[0] != "PyPI" || got[1] != "crates.io" || got[2] != "npm" {
+		t.Errorf("RequiredEcosystems() = %v, want [PyPI crates.io npm]", got)
+	}
+}
+
+func TestInRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		ecosystem string
+		events    []Event
+		version   string
+		want      bool
+	}{
+		{"below fix", "npm", []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "1.1.9", true},
+		{"at fix", "npm", []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}, "1.2.0", false},
+		{"before introduced", "npm", []Event{{Introduced: "1.0.0"}, {Fixed: "1.2.0"}}, "0.9.0", false},
+		{"prerelease of fix", "npm", []Event{{Introduced: "0"}, {Fixed: "2.0.0"}}, "2.0.0-rc.1", true},
+		{"last affected", "npm", []Event{{Introduced: "1.0.0"}, {LastAffected: "1.5.0"}}, "1.5.0", true},
+		{"after last affected", "npm", []Event{{Introduced: "1.0.0"}, {LastAffected: "1.5.0"}}, "1.5.1", false},
+		{"second range", "npm", []Event{{Introduced: "1.0.0"}, {Fixed: "1.1.0"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}}, "2.0.5", true},
+		{"between ranges", "npm", []Event{{Introduced: "1.0.0"}, {Fixed: "1.1.0"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}}, "1.5.0", false},
+		{"go v prefix", "Go", []Event{{Introduced: "0"}, {Fixed: "1.4.0"}}, "v1.3.0", true},
+		{"non-semver", "PyPI", []Event{{Introduced: "0"}, {Fixed: "2.31.0"}}, "2.30", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inRange(tt.events, tt.ecosystem, normalizeVersion(tt.ecosystem, tt.version)); got != tt.want {
+				t.Errorf("inRange(%s) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePURL(t *testing.T) {
+	tests := []struct {
+		purl, typ, name, version string
+		ok                       bool
+	}{
+		{"pkg:npm/%40babel/core@7.24.0", "npm", "@babel/core", "7.24.0", true},
+		{"pkg:golang/github.com/junegunn/fzf@v0.44.1", "golang", "github.com/junegunn/fzf", "v0.44.1", true},
+		{"pkg:pypi/ruff", "pypi", "ruff", "", true},
+		{"pkg:cargo/memchr@2.7.1?arch=x86_64", "cargo", "memchr", "2.7.1", true},
+		{"npm/prettier@3.0.0", "", "", "", false},
+	}
+	for _, tt := range tests {
+		typ, name, version, ok := parsePURL(tt.purl)
+		if typ != tt.typ || name != tt.name || version != tt.version || ok != tt.ok {
+			t.Errorf("parsePURL(%q) = %q, %q, %q, %v", tt.purl, typ, name, version, ok)
+		}
+	}
+}
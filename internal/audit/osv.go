@@ -0,0 +1,252 @@
+package audit
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/httputil"
+)
+
+// DefaultOSVURL is the bucket serving the OSV database as one all.zip
+// archive per ecosystem
+const DefaultOSVURL = "https://osv-vulnerabilities.storage.googleapis.com"
+
+// ecosystems maps package URL types to OSV ecosystem names. CPAN has no OSV
+// ecosystem, so Perl distributions are not audited.
+var ecosystems = map[string]string{
+	"npm":    "npm",
+	"pypi":   "PyPI",
+	"cargo":  "crates.io",
+	"gem":    "RubyGems",
+	"golang": "Go",
+}
+
+// Advisory is a vulnerability entry in the OSV format
+// (https://ossf.github.io/osv-schema/). Only the fields needed to match
+// installed packages are decoded.
+type Advisory struct {
+	ID        string     `json:"id"`
+	Summary   string     `json:"summary"`
+	Aliases   []string   `json:"aliases"`
+	Withdrawn string     `json:"withdrawn"`
+	Affected  []Affected `json:"affected"`
+}
+
+// Affected describes the versions of one package an advisory applies to
+type Affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges   []Range  `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+// Range is a list of events that introduce and fix a vulnerability
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event is one entry of a range; exactly one field is set
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// Database is an OSV database on disk. Each ecosystem is a directory named
+// after it holding either the all.zip archive published by OSV or the
+// advisories as individual JSON files, as in a local mirror.
+type Database struct {
+	dir   string
+	index map[string]map[string][]*Advisory // ecosystem -> package name -> advisories
+}
+
+// OpenDatabase returns the database stored in dir. Ecosystems are loaded
+// when first queried.
+func OpenDatabase(dir string) (*Database, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("vulnerability database not found: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("vulnerability database %s is not a directory", dir)
+	}
+	return &Database{dir: dir, index: make(map[string]map[string][]*Advisory)}, nil
+}
+
+// Dir returns the database directory
+func (db *Database) Dir() string {
+	return db.dir
+}
+
+// Advisories returns the advisories affecting any version of a package
+func (db *Database) Advisories(ecosystem, name string) ([]*Advisory, error) {
+	byName, ok := db.index[ecosystem]
+	if !ok {
+		var err error
+		if byName, err = db.load(ecosystem); err != nil {
+			return nil, err
+		}
+		db.index[ecosystem] = byName
+	}
+	return byName[packageKey(ecosystem, name)], nil
+}
+
+// load reads every advisory of an ecosystem. A missing ecosystem directory
+// is an empty ecosystem.
+func (db *Database) load(ecosystem string) (map[string][]*Advisory, error) {
+	byName := make(map[string][]*Advisory)
+	add := func(r io.Reader, source string) error {
+		var adv Advisory
+		if err := json.NewDecoder(r).Decode(&adv); err != nil {
+			return fmt.Errorf("failed to parse advisory %s: %w", source, err)
+		}
+		if adv.Withdrawn != "" {
+			return nil
+		}
+		seen := make(map[string]bool)
+		for _, a := range adv.Affected {
+			key := packageKey(ecosystem, a.Package.Name)
+			if a.Package.Ecosystem != ecosystem || seen[key] {
+				continue
+			}
+			seen[key] = true
+			byName[key] = append(byName[key], &adv)
+		}
+		return nil
+	}
+
+	dir := filepath.Join(db.dir, ecosystem)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return byName, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		switch {
+		case e.IsDir():
+			continue
+		case strings.HasSuffix(e.Name(), ".zip"):
+			if err := loadZip(path, add); err != nil {
+				return nil, err
+			}
+		case strings.HasSuffix(e.Name(), ".json"):
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			err = add(f, path)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return byName, nil
+}
+
+// loadZip passes every JSON file of an archive to add
+func loadZip(path string, add func(io.Reader, string) error) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s in %s: %w", f.Name, path, err)
+		}
+		err = add(rc, f.Name)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fetch downloads the all.zip archive of each ecosystem from baseURL into
+// dir. Archives are replaced atomically, so an interrupted download keeps
+// the previous copy usable.
+func Fetch(baseURL, dir string, ecosystems []string) error {
+	client := httputil.NewSecureClient(httputil.ClientOptions{
+		Timeout: 10 * time.Minute,
+	})
+	for _, ecosystem := range ecosystems {
+		if err := fetchEcosystem(client, baseURL, dir, ecosystem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fetchEcosystem(client *http.Client, baseURL, dir, ecosystem string) error {
+	url := strings.TrimSuffix(baseURL, "/") + "/" + ecosystem + "/all.zip"
+	resp, err := client.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download %s advisories: %w", ecosystem, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s advisories: %s returned %s", ecosystem, url, resp.Status)
+	}
+
+	ecoDir := filepath.Join(dir, ecosystem)
+	if err := os.MkdirAll(ecoDir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(ecoDir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	_, err = io.Copy(tmp, resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// Reject truncated or non-zip responses before replacing the archive
+		var zr *zip.ReadCloser
+		if zr, err = zip.OpenReader(tmpPath); err == nil {
+			zr.Close()
+		}
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, filepath.Join(ecoDir, "all.zip"))
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to download %s advisories: %w", ecosystem, err)
+	}
+	return nil
+}
+
+// MissingArchives returns the ecosystems that have no downloaded archive
+// in dir
+func MissingArchives(dir string, ecosystems []string) []string {
+	var missing []string
+	for _, ecosystem := range ecosystems {
+		if _, err := os.Stat(filepath.Join(dir, ecosystem, "all.zip")); err != nil {
+			missing = append(missing, ecosystem)
+		}
+	}
+	return missing
+}
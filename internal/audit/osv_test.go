@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetch(t *testing.T) {
+	archive := zipArchive(t, map[string]string{
+		"GO-2024-0001.json": `{"id": "GO-2024-0001", "affected": [{"package": {"ecosystem": "Go", "name": "github.com/a/b"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.1.0"}]}]}]}`,
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Go/all.zip" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	if err := Fetch(server.URL, dir, []string{"Go"}); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if missing := MissingArchives(dir, []string{"Go", "npm"}); len(missing) != 1 || missing[0] != "npm" {
+		t.Errorf("MissingArchives() = %v, want [npm]", missing)
+	}
+
+	db, err := OpenDatabase(dir)
+	if err != nil {
+		t.Fatalf("OpenDatabase() error = %v", err)
+	}
+	advisories, err := db.Advisories("Go", "github.com/a/b")
+	if err != nil {
+		t.Fatalf("Advisories() error = %v", err)
+	}
+	if len(advisories) != 1 || advisories[0].ID != "GO-2024-0001" {
+		t.Errorf("Advisories() = %+v", advisories)
+	}
+
+	// A failed download keeps the previous archive
+	if err := Fetch(server.URL, dir, []string{"npm"}); err == nil {
+		t.Error("expected error for a missing archive")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Go", "all.zip")); err != nil {
+		t.Errorf("archive missing after failed fetch: %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "npm"))
+	if len(entries) != 0 {
+		t.Errorf("failed fetch left %d files behind", len(entries))
+	}
+}
+
+func TestOpenDatabase_Missing(t *testing.T) {
+	if _, err := OpenDatabase(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for a missing database")
+	}
+}
+
+func TestAdvisories_InvalidJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeAdvisory(t, dir, "npm", "bad", "{")
+	db, _ := OpenDatabase(dir)
+	if _, err := db.Advisories("npm", "prettier"); err == nil {
+		t.Error("expected error for an invalid advisory")
+	}
+}
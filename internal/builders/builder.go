@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 
+	"github.com/tsukumogami/tsuku/internal/llm"
 	"github.com/tsukumogami/tsuku/internal/recipe"
 	"github.com/tsukumogami/tsuku/internal/sandbox"
 )
@@ -38,6 +39,25 @@ type LLMConfig interface {
 	LLMHourlyRateLimit() int
 }
 
+// llmFactoryOptions derives LLM factory options from the session's user config.
+// Provider order and local provider settings are applied when the config
+// exposes them (userconfig.Config does), so that a restricted provider list
+// is honored even when the factory is created lazily by a builder.
+func llmFactoryOptions(opts *SessionOptions) []llm.FactoryOption {
+	if opts == nil || opts.LLMConfig == nil {
+		return nil
+	}
+
+	var factoryOpts []llm.FactoryOption
+	if cfg, ok := opts.LLMConfig.(interface{ LLMProviders() []string }); ok {
+		factoryOpts = append(factoryOpts, llm.WithProviderOrder(cfg.LLMProviders()))
+	}
+	if cfg, ok := opts.LLMConfig.(llm.LocalLLMConfig); ok {
+		factoryOpts = append(factoryOpts, llm.WithLocalProvider(cfg.LLMLocalBaseURL(), cfg.LLMLocalModel()))
+	}
+	return factoryOpts
+}
+
 // ConfirmableError is an error that can be bypassed with user confirmation.
 // The CLI checks for this interface to prompt the user before proceeding.
 type ConfirmableError interface {
@@ -0,0 +1,422 @@
+package builders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+// forgePlatformAliases lists the spellings release assets commonly use for each
+// Go OS/arch value. Longer aliases come first so "x86_64" wins over "x64".
+var (
+	forgeOSAliases = map[string][]string{
+		"linux":  {"linux"},
+		"darwin": {"darwin", "macos", "apple", "osx"},
+	}
+	forgeArchAliases = map[string][]string{
+		"amd64": {"x86_64", "amd64", "x64"},
+		"arm64": {"aarch64", "arm64"},
+	}
+)
+
+// forgeIgnoredSuffixes identifies release assets that are never installable binaries
+var forgeIgnoredSuffixes = []string{
+	".sha256", ".sha256sum", ".sha512", ".md5", ".sig", ".asc", ".pem", ".sbom",
+	".json", ".txt", ".deb", ".rpm", ".apk", ".msi", ".exe", ".dmg", ".pkg",
+}
+
+// ForgeReleaseBuilder generates recipes from GitLab or Gitea/Forgejo release assets.
+// Unlike GitHubReleaseBuilder it does not use an LLM: assets are matched to
+// platforms by well-known OS and architecture names in their file names.
+type ForgeReleaseBuilder struct {
+	resolver *version.Resolver
+	forge    string
+}
+
+// NewGitLabReleaseBuilder creates a builder for projects released on GitLab.
+// If resolver is nil, a default resolver is created.
+func NewGitLabReleaseBuilder(resolver *version.Resolver) *ForgeReleaseBuilder {
+	if resolver == nil {
+		resolver = version.New()
+	}
+	return &ForgeReleaseBuilder{resolver: resolver, forge: version.ForgeGitLab}
+}
+
+// NewGiteaReleaseBuilder creates a builder for projects released on Gitea or Forgejo.
+// If resolver is nil, a default resolver is created.
+func NewGiteaReleaseBuilder(resolver *version.Resolver) *ForgeReleaseBuilder {
+	if resolver == nil {
+		resolver = version.New()
+	}
+	return &ForgeReleaseBuilder{resolver: resolver, forge: version.ForgeGitea}
+}
+
+// Name returns the builder identifier
+func (b *ForgeReleaseBuilder) Name() string {
+	return b.forge
+}
+
+// RequiresLLM returns false as assets are matched deterministically.
+func (b *ForgeReleaseBuilder) RequiresLLM() bool {
+	return false
+}
+
+// CanBuild checks that the project exists and has at least one release
+func (b *ForgeReleaseBuilder) CanBuild(ctx context.Context, req BuildRequest) (bool, error) {
+	baseURL, project, err := parseForgeSource(b.forge, req.SourceArg)
+	if err != nil {
+		return false, nil
+	}
+
+	releases, err := b.resolver.ListForgeReleases(ctx, b.forge, baseURL, project)
+	if err != nil {
+		var resolverErr *version.ResolverError
+		if errors.As(err, &resolverErr) && resolverErr.Type == version.ErrTypeNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(releases) > 0, nil
+}
+
+// NewSession creates a new build session for the given request.
+func (b *ForgeReleaseBuilder) NewSession(ctx context.Context, req BuildRequest, opts *SessionOptions) (BuildSession, error) {
+	return NewDeterministicSession(b.Build, req), nil
+}
+
+// Build generates a recipe from the assets of the latest stable release
+func (b *ForgeReleaseBuilder) Build(ctx context.Context, req BuildRequest) (*BuildResult, error) {
+	baseURL, project, err := parseForgeSource(b.forge, req.SourceArg)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider *version.ForgeProvider
+	if b.forge == version.ForgeGitLab {
+		provider = version.NewGitLabProvider(b.resolver, baseURL, project, "")
+	} else {
+		provider = version.NewGiteaProvider(b.resolver, baseURL, project, "")
+	}
+
+	latest, err := provider.ResolveLatest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve latest release: %w", err)
+	}
+
+	release, err := b.resolver.GetForgeRelease(ctx, b.forge, baseURL, project, latest.Tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release %s: %w", latest.Tag, err)
+	}
+
+	pattern, err := deriveForgeAssetPattern(release.AssetNames(), latest.Version)
+	if err != nil {
+		return nil, fmt.Errorf("release %s: %w", latest.Tag, err)
+	}
+
+	homepage := forgeInstanceURL(b.forge, baseURL) + "/" + project
+	result := &BuildResult{
+		Source:   fmt.Sprintf("%s:%s", b.forge, project),
+		Warnings: pattern.warnings,
+	}
+
+	params := map[string]interface{}{
+		"repo":          project,
+		"asset_pattern": pattern.assetPattern,
+	}
+	if baseURL != "" {
+		params["base_url"] = baseURL
+	}
+	if len(pattern.osMapping) > 0 {
+		params["os_mapping"] = pattern.osMapping
+	}
+	if len(pattern.archMapping) > 0 {
+		params["arch_mapping"] = pattern.archMapping
+	}
+
+	action := b.forge + "_file"
+	if format := actions.DetectArchiveFormat(pattern.assetPattern); format != "" {
+		action = b.forge + "_archive"
+		params["binaries"] = []string{req.Package}
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("Assumed the archive contains %q at its top level; adjust binaries or strip_dirs if installation fails", req.Package))
+	} else {
+		params["binary"] = req.Package
+	}
+
+	source := "gitlab_releases"
+	if b.forge == version.ForgeGitea {
+		source = "gitea_releases"
+	}
+
+	result.Recipe = &recipe.Recipe{
+		Metadata: recipe.MetadataSection{
+			Name:                 req.Package,
+			Homepage:             homepage,
+			SupportedOS:          pattern.supportedOS,
+			SupportedArch:        pattern.supportedArch,
+			UnsupportedPlatforms: pattern.unsupported,
+		},
+		Version: recipe.VersionSection{
+			Source:  source,
+			Repo:    project,
+			BaseURL: baseURL,
+		},
+		Steps: []recipe.Step{{
+			Action: action,
+			Params: params,
+		}},
+		Verify: recipe.VerifySection{
+			Command: fmt.Sprintf("%s --version", req.Package),
+		},
+	}
+
+	return result, nil
+}
+
+// forgeInstanceURL returns baseURL, or the gitlab.com URL for GitLab when baseURL is empty
+func forgeInstanceURL(forge, baseURL string) string {
+	if baseURL == "" && forge == version.ForgeGitLab {
+		return version.DefaultGitLabURL
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// parseForgeSource parses the --from argument for a forge builder.
+// Accepted forms:
+//   - GitLab: "group/project", "group/sub/project", or "https://host/group/project"
+//   - Gitea/Forgejo: "https://host/owner/repo" or "host/owner/repo"
+//
+// Returns an empty baseURL for gitlab.com.
+func parseForgeSource(forge, arg string) (baseURL, project string, err error) {
+	arg = strings.TrimSuffix(strings.TrimSpace(arg), "/")
+	arg = strings.TrimSuffix(arg, ".git")
+	if arg == "" {
+		return "", "", fmt.Errorf("%s source requires a project (e.g., %s)", forge, forgeSourceExample(forge))
+	}
+
+	if strings.Contains(arg, "://") {
+		u, parseErr := url.Parse(arg)
+		if parseErr != nil || u.Host == "" {
+			return "", "", fmt.Errorf("invalid %s URL: %s", forge, arg)
+		}
+		if u.Scheme != "https" {
+			return "", "", fmt.Errorf("invalid %s URL %s: must use https://", forge, arg)
+		}
+		baseURL = "https://" + u.Host
+		project = strings.Trim(u.Path, "/")
+	} else if forge == version.ForgeGitea {
+		// Gitea has no default instance, so the first segment is the host
+		parts := strings.SplitN(arg, "/", 2)
+		if len(parts) != 2 || !strings.Contains(parts[0], ".") {
+			return "", "", fmt.Errorf("gitea source requires the instance host (e.g., %s)", forgeSourceExample(forge))
+		}
+		baseURL = "https://" + parts[0]
+		project = parts[1]
+	} else {
+		project = arg
+	}
+
+	if forge == version.ForgeGitLab && baseURL == version.DefaultGitLabURL {
+		baseURL = ""
+	}
+
+	parts := strings.Split(project, "/")
+	if len(parts) < 2 || (forge == version.ForgeGitea && len(parts) != 2) {
+		return "", "", fmt.Errorf("invalid %s project %q (e.g., %s)", forge, project, forgeSourceExample(forge))
+	}
+	for _, p := range parts {
+		if p == "" || p == "." || p == ".." {
+			return "", "", fmt.Errorf("invalid %s project %q", forge, project)
+		}
+	}
+
+	return baseURL, project, nil
+}
+
+func forgeSourceExample(forge string) string {
+	if forge == version.ForgeGitea {
+		return "gitea:codeberg.org/owner/repo"
+	}
+	return "gitlab:group/project"
+}
+
+// forgePlatformAsset is a release asset matched to a Go OS/arch pair.
+// osAlias and archAlias are the exact spellings used in the asset name.
+type forgePlatformAsset struct {
+	goos, goarch       string
+	name               string
+	osAlias, archAlias string
+	osIdx, archIdx     int
+}
+
+// forgeAssetPattern is the result of matching release assets to platforms
+type forgeAssetPattern struct {
+	assetPattern  string
+	osMapping     map[string]string
+	archMapping   map[string]string
+	supportedOS   []string
+	supportedArch []string
+	unsupported   []string
+	warnings      []string
+}
+
+// findAlias returns the first alias that appears in name as a whole word
+// (bounded by non-alphanumeric characters) and its byte offset.
+func findAlias(name string, aliases []string) (string, int) {
+	lower := strings.ToLower(name)
+	for _, alias := range aliases {
+		start := 0
+		for {
+			idx := strings.Index(lower[start:], alias)
+			if idx == -1 {
+				break
+			}
+			idx += start
+			end := idx + len(alias)
+			if (idx == 0 || !isAlphaNum(lower[idx-1])) && (end == len(lower) || !isAlphaNum(lower[end])) {
+				return name[idx:end], idx
+			}
+			start = idx + 1
+		}
+	}
+	return "", -1
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// matchForgeAssets assigns at most one asset to each supported platform.
+// Assets built against musl are only used when no other asset matches.
+func matchForgeAssets(assets []string) []forgePlatformAsset {
+	var matched []forgePlatformAsset
+	for _, goos := range []string{"linux", "darwin"} {
+		for _, goarch := range []string{"amd64", "arm64"} {
+			var best *forgePlatformAsset
+			for _, name := range assets {
+				if isIgnoredForgeAsset(name) {
+					continue
+				}
+				osAlias, osIdx := findAlias(name, forgeOSAliases[goos])
+				archAlias, archIdx := findAlias(name, forgeArchAliases[goarch])
+				if osAlias == "" || archAlias == "" {
+					continue
+				}
+				candidate := &forgePlatformAsset{
+					goos: goos, goarch: goarch, name: name,
+					osAlias: osAlias, archAlias: archAlias,
+					osIdx: osIdx, archIdx: archIdx,
+				}
+				if best == nil || (strings.Contains(strings.ToLower(best.name), "musl") && !strings.Contains(strings.ToLower(name), "musl")) {
+					best = candidate
+				}
+			}
+			if best != nil {
+				matched = append(matched, *best)
+			}
+		}
+	}
+	return matched
+}
+
+func isIgnoredForgeAsset(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range forgeIgnoredSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// deriveForgeAssetPattern turns the release's asset names into an asset_pattern with
+// {version}, {os} and {arch} placeholders plus the mappings needed to expand it.
+// Platforms whose asset does not follow the common pattern are left unsupported.
+func deriveForgeAssetPattern(assets []string, ver string) (*forgeAssetPattern, error) {
+	matched := matchForgeAssets(assets)
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("no linux or darwin assets for amd64/arm64 found (assets: %s)", strings.Join(assets, ", "))
+	}
+
+	// Use the first match (linux/amd64 when available) as the template
+	tmpl := matched[0]
+	pattern := replaceSpan(tmpl.name, tmpl.osIdx, len(tmpl.osAlias), "{os}", tmpl.archIdx, len(tmpl.archAlias), "{arch}")
+	if ver != "" && strings.Contains(ver, ".") {
+		pattern = strings.ReplaceAll(pattern, ver, "{version}")
+	}
+
+	result := &forgeAssetPattern{
+		assetPattern: pattern,
+		osMapping:    make(map[string]string),
+		archMapping:  make(map[string]string),
+	}
+
+	osSet := make(map[string]bool)
+	archSet := make(map[string]bool)
+	covered := make(map[string]bool)
+	for _, m := range matched {
+		// Mappings must be consistent across platforms for a single pattern to work
+		if existing, ok := result.osMapping[m.goos]; ok && existing != m.osAlias {
+			continue
+		}
+		if existing, ok := result.archMapping[m.goarch]; ok && existing != m.archAlias {
+			continue
+		}
+		expanded := actions.ExpandVars(pattern, map[string]string{"version": ver, "os": m.osAlias, "arch": m.archAlias})
+		if expanded != m.name {
+			result.warnings = append(result.warnings,
+				fmt.Sprintf("Asset %s does not follow the pattern %s; %s/%s is not supported", m.name, pattern, m.goos, m.goarch))
+			continue
+		}
+		result.osMapping[m.goos] = m.osAlias
+		result.archMapping[m.goarch] = m.archAlias
+		osSet[m.goos] = true
+		archSet[m.goarch] = true
+		covered[m.goos+"/"+m.goarch] = true
+	}
+
+	for goos := range osSet {
+		result.supportedOS = append(result.supportedOS, goos)
+	}
+	for goarch := range archSet {
+		result.supportedArch = append(result.supportedArch, goarch)
+	}
+	sort.Strings(result.supportedOS)
+	sort.Strings(result.supportedArch)
+	for _, goos := range result.supportedOS {
+		for _, goarch := range result.supportedArch {
+			if !covered[goos+"/"+goarch] {
+				result.unsupported = append(result.unsupported, goos+"/"+goarch)
+			}
+		}
+	}
+
+	// Identity mappings are noise in the generated recipe
+	for k, v := range result.osMapping {
+		if k == v {
+			delete(result.osMapping, k)
+		}
+	}
+	for k, v := range result.archMapping {
+		if k == v {
+			delete(result.archMapping, k)
+		}
+	}
+
+	return result, nil
+}
+
+// replaceSpan replaces two non-overlapping byte ranges of s
+func replaceSpan(s string, idx1, len1 int, repl1 string, idx2, len2 int, repl2 string) string {
+	if idx1 > idx2 {
+		idx1, len1, repl1, idx2, len2, repl2 = idx2, len2, repl2, idx1, len1, repl1
+	}
+	return s[:idx1] + repl1 + s[idx1+len1:idx2] + repl2 + s[idx2+len2:]
+}
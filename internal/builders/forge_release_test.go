@@ -0,0 +1,172 @@
+package builders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+func TestParseForgeSource(t *testing.T) {
+	tests := []struct {
+		name        string
+		forge       string
+		arg         string
+		wantBaseURL string
+		wantProject string
+		wantErr     bool
+	}{
+		{"gitlab short", version.ForgeGitLab, "gitlab-org/cli", "", "gitlab-org/cli", false},
+		{"gitlab nested", version.ForgeGitLab, "group/sub/tool", "", "group/sub/tool", false},
+		{"gitlab.com URL", version.ForgeGitLab, "https://gitlab.com/gitlab-org/cli.git", "", "gitlab-org/cli", false},
+		{"gitlab self-hosted", version.ForgeGitLab, "https://gitlab.gnome.org/GNOME/tool/", "https://gitlab.gnome.org", "GNOME/tool", false},
+		{"gitlab http rejected", version.ForgeGitLab, "http://gitlab.example.com/a/b", "", "", true},
+		{"gitlab missing project", version.ForgeGitLab, "tool", "", "", true},
+		{"gitea URL", version.ForgeGitea, "https://codeberg.org/forgejo/runner", "https://codeberg.org", "forgejo/runner", false},
+		{"gitea host form", version.ForgeGitea, "codeberg.org/forgejo/runner", "https://codeberg.org", "forgejo/runner", false},
+		{"gitea without host", version.ForgeGitea, "forgejo/runner", "", "", true},
+		{"gitea nested rejected", version.ForgeGitea, "codeberg.org/a/b/c", "", "", true},
+		{"empty", version.ForgeGitea, "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseURL, project, err := parseForgeSource(tt.forge, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseForgeSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if baseURL != tt.wantBaseURL || project != tt.wantProject {
+				t.Errorf("parseForgeSource() = (%q, %q), want (%q, %q)", baseURL, project, tt.wantBaseURL, tt.wantProject)
+			}
+		})
+	}
+}
+
+func TestDeriveForgeAssetPattern(t *testing.T) {
+	assets := []string{
+		"tool_1.4.0_Linux_x86_64.tar.gz",
+		"tool_1.4.0_Linux_arm64.tar.gz",
+		"tool_1.4.0_macOS_x86_64.tar.gz",
+		"tool_1.4.0_macOS_arm64.tar.gz",
+		"tool_1.4.0_Windows_x86_64.zip",
+		"checksums.txt",
+	}
+
+	p, err := deriveForgeAssetPattern(assets, "1.4.0")
+	if err != nil {
+		t.Fatalf("deriveForgeAssetPattern failed: %v", err)
+	}
+
+	if p.assetPattern != "tool_{version}_{os}_{arch}.tar.gz" {
+		t.Errorf("assetPattern = %q", p.assetPattern)
+	}
+	wantOS := map[string]string{"linux": "Linux", "darwin": "macOS"}
+	if !reflect.DeepEqual(p.osMapping, wantOS) {
+		t.Errorf("osMapping = %v, want %v", p.osMapping, wantOS)
+	}
+	wantArch := map[string]string{"amd64": "x86_64"}
+	if !reflect.DeepEqual(p.archMapping, wantArch) {
+		t.Errorf("archMapping = %v, want %v (identity mappings dropped)", p.archMapping, wantArch)
+	}
+	if len(p.unsupported) != 0 || len(p.warnings) != 0 {
+		t.Errorf("unexpected unsupported=%v warnings=%v", p.unsupported, p.warnings)
+	}
+}
+
+func TestDeriveForgeAssetPattern_PartialCoverage(t *testing.T) {
+	assets := []string{
+		"tool-linux-amd64",
+		"tool-linux-arm64",
+		"tool-darwin-arm64",
+		"tool-linux-amd64.sha256",
+	}
+
+	p, err := deriveForgeAssetPattern(assets, "0.3.1")
+	if err != nil {
+		t.Fatalf("deriveForgeAssetPattern failed: %v", err)
+	}
+	if p.assetPattern != "tool-{os}-{arch}" {
+		t.Errorf("assetPattern = %q", p.assetPattern)
+	}
+	if !reflect.DeepEqual(p.unsupported, []string{"darwin/amd64"}) {
+		t.Errorf("unsupported = %v, want [darwin/amd64]", p.unsupported)
+	}
+}
+
+func TestDeriveForgeAssetPattern_NoPlatformAssets(t *testing.T) {
+	if _, err := deriveForgeAssetPattern([]string{"source.tar.gz", "tool.exe"}, "1.0.0"); err == nil {
+		t.Error("expected error when no platform assets match")
+	}
+}
+
+func TestForgeReleaseBuilder_Build(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Ftool/releases":
+			_, _ = w.Write([]byte(`[{"tag_name": "v2.1.0"}, {"tag_name": "v2.0.0"}]`))
+		case "/api/v4/projects/group%2Ftool/releases/v2.1.0":
+			_, _ = w.Write([]byte(`{"tag_name": "v2.1.0", "assets": {"links": [
+				{"name": "tool-v2.1.0-linux-amd64.tar.gz", "direct_asset_url": "https://example.com/1"},
+				{"name": "tool-v2.1.0-darwin-arm64.tar.gz", "direct_asset_url": "https://example.com/2"}
+			]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	b := NewGitLabReleaseBuilder(version.New(version.WithGitLabURL(server.URL)))
+	if b.Name() != "gitlab" || b.RequiresLLM() {
+		t.Fatalf("unexpected builder identity: %s (llm=%v)", b.Name(), b.RequiresLLM())
+	}
+
+	req := BuildRequest{Package: "tool", SourceArg: "group/tool"}
+	ok, err := b.CanBuild(context.Background(), req)
+	if err != nil || !ok {
+		t.Fatalf("CanBuild() = %v, %v; want true", ok, err)
+	}
+
+	result, err := b.Build(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	r := result.Recipe
+	if r.Version.Source != "gitlab_releases" || r.Version.Repo != "group/tool" {
+		t.Errorf("unexpected version section: %+v", r.Version)
+	}
+	if len(r.Steps) != 1 || r.Steps[0].Action != "gitlab_archive" {
+		t.Fatalf("unexpected steps: %+v", r.Steps)
+	}
+	if got := r.Steps[0].Params["asset_pattern"]; got != "tool-v{version}-{os}-{arch}.tar.gz" {
+		t.Errorf("asset_pattern = %v", got)
+	}
+	if _, hasBaseURL := r.Steps[0].Params["base_url"]; hasBaseURL {
+		t.Error("base_url should be omitted for gitlab.com projects")
+	}
+	if r.Metadata.Homepage != "https://gitlab.com/group/tool" {
+		t.Errorf("homepage = %q", r.Metadata.Homepage)
+	}
+	if !strings.Contains(strings.Join(r.Metadata.UnsupportedPlatforms, ","), "linux/arm64") {
+		t.Errorf("expected linux/arm64 to be unsupported, got %v", r.Metadata.UnsupportedPlatforms)
+	}
+}
+
+func TestForgeReleaseBuilder_CanBuild_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	b := NewGitLabReleaseBuilder(version.New(version.WithGitLabURL(server.URL)))
+	ok, err := b.CanBuild(context.Background(), BuildRequest{Package: "tool", SourceArg: "group/missing"})
+	if err != nil || ok {
+		t.Errorf("CanBuild() = %v, %v; want false, nil", ok, err)
+	}
+}
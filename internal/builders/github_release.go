@@ -214,7 +214,7 @@ func (b *GitHubReleaseBuilder) NewSession(ctx context.Context, req BuildRequest,
 	// Get or create LLM factory
 	factory := b.factory
 	if factory == nil {
-		factory, err = llm.NewFactory(ctx)
+		factory, err = llm.NewFactory(ctx, llmFactoryOptions(opts)...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create LLM factory: %w", err)
 		}
@@ -561,11 +561,12 @@ func (b *GitHubReleaseBuilder) executeToolCall(ctx context.Context, genCtx *gene
 			return "", nil, fmt.Errorf("invalid extract_pattern input: %w", err)
 		}
 		pattern := &llm.AssetPattern{
-			Mappings:       input.Mappings,
-			Executable:     input.Executable,
-			VerifyCommand:  input.VerifyCommand,
-			StripPrefix:    input.StripPrefix,
-			InstallSubpath: input.InstallSubpath,
+			Mappings:        input.Mappings,
+			Executable:      input.Executable,
+			VerifyCommand:   input.VerifyCommand,
+			StripPrefix:     input.StripPrefix,
+			InstallSubpath:  input.InstallSubpath,
+			FunctionalTests: input.FunctionalTests,
 		}
 		return "", pattern, nil
 
@@ -605,11 +606,34 @@ func (b *GitHubReleaseBuilder) buildRepairMessageFromSandbox(result *sandbox.San
 		sb.WriteString(fmt.Sprintf("\nSandbox error: %v\n", result.Error))
 	}
 
+	if strings.Contains(output, "functional test") {
+		sb.WriteString("\nA functional test failed after a successful install. Fix the test's command or expected output, or drop the test if the tool cannot be exercised offline.\n")
+	}
+
 	sb.WriteString("\nPlease analyze what went wrong and call extract_pattern again with a corrected recipe.")
 
 	return sb.String()
 }
 
+// functionalTestsFromPattern converts LLM-proposed functional tests to recipe tests,
+// dropping entries without a command.
+func functionalTestsFromPattern(inputs []llm.FunctionalTestInput) []recipe.FunctionalTest {
+	var tests []recipe.FunctionalTest
+	for _, in := range inputs {
+		if strings.TrimSpace(in.Command) == "" {
+			continue
+		}
+		tests = append(tests, recipe.FunctionalTest{
+			Name:     in.Name,
+			Command:  in.Command,
+			Stdin:    in.Stdin,
+			Stdout:   in.Stdout,
+			ExitCode: in.ExitCode,
+		})
+	}
+	return tests
+}
+
 // parseRepo parses "owner/repo" into separate components.
 func parseRepo(sourceArg string) (owner, repo string, err error) {
 	if sourceArg == "" {
@@ -919,6 +943,7 @@ func generateRecipe(packageName, repoPath string, meta *repoMeta, pattern *llm.A
 		Verify: recipe.VerifySection{
 			Command: pattern.VerifyCommand,
 			Pattern: "{version}",
+			Tests:   functionalTestsFromPattern(pattern.FunctionalTests),
 		},
 	}
 
@@ -1020,6 +1045,8 @@ When analyzing assets:
 - Identify the archive format from the file extension: tar.gz, tar.xz, zip, tbz (bzip2 tar), tgz, or binary (no extension)
 - Determine the executable name inside the archive
 - Consider common verification commands (tool --version, tool version)
+- If the README shows a simple offline usage example, add it as a functional test
+  (command, stdin, expected stdout regex) so the recipe is validated beyond --version
 
 Once you understand the pattern, call extract_pattern with the mappings.
 Focus on linux (amd64, arm64) and darwin (amd64, arm64) platforms.`
@@ -1133,6 +1160,7 @@ func buildToolDefs() []llm.ToolDef {
 						"type":        "string",
 						"description": "Subdirectory in archive where binary is located (optional)",
 					},
+					"functional_tests": llm.FunctionalTestsSchema(),
 				},
 				"required": []string{"mappings", "executable", "verify_command"},
 			},
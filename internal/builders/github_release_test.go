@@ -405,6 +405,39 @@ func TestGenerateRecipe_Binary(t *testing.T) {
 	}
 }
 
+func TestGenerateRecipe_FunctionalTests(t *testing.T) {
+	meta := &repoMeta{Description: "JSON processor"}
+	exitCode := 5
+
+	pattern := &llm.AssetPattern{
+		Mappings: []llm.PlatformMapping{
+			{Asset: "jq-linux-amd64", OS: "linux", Arch: "amd64", Format: "binary"},
+		},
+		Executable:    "jq",
+		VerifyCommand: "jq --version",
+		FunctionalTests: []llm.FunctionalTestInput{
+			{Name: "filter", Command: "jq -r .name", Stdin: `{"name": "tsuku"}`, Stdout: "^tsuku$"},
+			{Command: "  "},
+			{Command: "jq .", Stdin: "not json", ExitCode: &exitCode},
+		},
+	}
+
+	r, err := generateRecipe("jq", "jqlang/jq", meta, pattern)
+	if err != nil {
+		t.Fatalf("generateRecipe error: %v", err)
+	}
+
+	if len(r.Verify.Tests) != 2 {
+		t.Fatalf("expected 2 functional tests (empty command dropped), got %d", len(r.Verify.Tests))
+	}
+	if r.Verify.Tests[0].Stdout != "^tsuku$" || r.Verify.Tests[0].Stdin != `{"name": "tsuku"}` {
+		t.Errorf("first test = %+v", r.Verify.Tests[0])
+	}
+	if r.Verify.Tests[1].ExitCode == nil || *r.Verify.Tests[1].ExitCode != 5 {
+		t.Errorf("second test exit code = %v, want 5", r.Verify.Tests[1].ExitCode)
+	}
+}
+
 func TestGenerateRecipe_EmptyMappings(t *testing.T) {
 	meta := &repoMeta{
 		Description: "Test",
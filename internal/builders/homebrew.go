@@ -105,6 +105,7 @@ type HomebrewSession struct {
 	// LLM state (provider may be nil for bottle mode until needed)
 	provider     llm.Provider
 	factory      *llm.Factory // For deferred LLM initialization in bottle mode
+	factoryOpts  []llm.FactoryOption
 	messages     []llm.Message
 	systemPrompt string
 	tools        []llm.ToolDef
@@ -397,6 +398,7 @@ func (b *HomebrewBuilder) NewSession(ctx context.Context, req BuildRequest, opts
 		req:          req,
 		provider:     nil, // Initialized lazily if deterministic generation fails
 		factory:      factory,
+		factoryOpts:  llmFactoryOptions(opts),
 		messages:     messages,
 		systemPrompt: systemPrompt,
 		tools:        tools,
@@ -465,7 +467,7 @@ func (s *HomebrewSession) ensureLLMProvider(ctx context.Context) error {
 	factory := s.factory
 	if factory == nil {
 		var err error
-		factory, err = llm.NewFactory(ctx)
+		factory, err = llm.NewFactory(ctx, s.factoryOpts...)
 		if err != nil {
 			return fmt.Errorf("failed to create LLM factory: %w", err)
 		}
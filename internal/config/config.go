@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 )
 
@@ -17,11 +18,20 @@ const (
 	// EnvVersionCacheTTL is the environment variable to configure version cache TTL
 	EnvVersionCacheTTL = "TSUKU_VERSION_CACHE_TTL"
 
+	// EnvLockTimeout is the environment variable to configure how long to wait for per-tool install locks
+	EnvLockTimeout = "TSUKU_LOCK_TIMEOUT"
+
+	// EnvOffline is the environment variable that disables all network access
+	EnvOffline = "TSUKU_OFFLINE"
+
 	// DefaultAPITimeout is the default timeout for API requests (30 seconds)
 	DefaultAPITimeout = 30 * time.Second
 
 	// DefaultVersionCacheTTL is the default TTL for cached version lists (1 hour)
 	DefaultVersionCacheTTL = 1 * time.Hour
+
+	// DefaultLockTimeout is the default time to wait for another process's install lock (10 minutes)
+	DefaultLockTimeout = 10 * time.Minute
 )
 
 // GetAPITimeout returns the configured API timeout from TSUKU_API_TIMEOUT environment variable.
@@ -88,6 +98,51 @@ func GetVersionCacheTTL() time.Duration {
 	return duration
 }
 
+// GetLockTimeout returns the configured install lock timeout from TSUKU_LOCK_TIMEOUT.
+// If not set or invalid, returns DefaultLockTimeout (10 minutes).
+// Accepts duration strings like "30s", "5m", "1h".
+func GetLockTimeout() time.Duration {
+	envValue := os.Getenv(EnvLockTimeout)
+	if envValue == "" {
+		return DefaultLockTimeout
+	}
+
+	duration, err := time.ParseDuration(envValue)
+	if err != nil {
+		// Invalid duration format, use default
+		fmt.Fprintf(os.Stderr, "Warning: invalid %s value %q, using default %v\n",
+			EnvLockTimeout, envValue, DefaultLockTimeout)
+		return DefaultLockTimeout
+	}
+
+	// Validate reasonable range (1 second to 24 hours)
+	if duration < 1*time.Second {
+		fmt.Fprintf(os.Stderr, "Warning: %s too low (%v), using minimum 1s\n",
+			EnvLockTimeout, duration)
+		return 1 * time.Second
+	}
+	if duration > 24*time.Hour {
+		fmt.Fprintf(os.Stderr, "Warning: %s too high (%v), using maximum 24h\n",
+			EnvLockTimeout, duration)
+		return 24 * time.Hour
+	}
+
+	return duration
+}
+
+// IsOffline reports whether offline mode is enabled through TSUKU_OFFLINE
+// (set by the --offline flag). Any value parsed as true by strconv.ParseBool
+// enables it; an invalid value is treated as enabled, since the user
+// clearly asked for something.
+func IsOffline() bool {
+	envValue := os.Getenv(EnvOffline)
+	if envValue == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(envValue)
+	return err != nil || enabled
+}
+
 // Config holds tsuku configuration
 type Config struct {
 	HomeDir          string // $TSUKU_HOME
@@ -99,7 +154,15 @@ type Config struct {
 	CacheDir         string // $TSUKU_HOME/cache
 	VersionCacheDir  string // $TSUKU_HOME/cache/versions
 	DownloadCacheDir string // $TSUKU_HOME/cache/downloads
+	ArtifactCacheDir string // $TSUKU_HOME/cache/artifacts (prebuilt install trees)
+	BuildCacheDir    string // $TSUKU_HOME/cache/build (ccache/sccache data)
+	OSVCacheDir      string // $TSUKU_HOME/cache/osv (vulnerability database)
+	GoModCacheDir    string // $TSUKU_HOME/.gomodcache (shared by go_build and go_install)
+	TelemetrySpool   string // $TSUKU_HOME/cache/telemetry.jsonl (events queued while offline)
+	LocksDir         string // $TSUKU_HOME/locks (per-tool install locks)
+	StoreDir         string // $TSUKU_HOME/store (content-addressed file store)
 	ConfigFile       string // $TSUKU_HOME/config.toml
+	PolicyFile       string // $TSUKU_HOME/policy.toml (install policy)
 }
 
 // DefaultConfig returns the default configuration
@@ -124,7 +187,15 @@ func DefaultConfig() (*Config, error) {
 		CacheDir:         filepath.Join(tsukuHome, "cache"),
 		VersionCacheDir:  filepath.Join(tsukuHome, "cache", "versions"),
 		DownloadCacheDir: filepath.Join(tsukuHome, "cache", "downloads"),
+		ArtifactCacheDir: filepath.Join(tsukuHome, "cache", "artifacts"),
+		BuildCacheDir:    filepath.Join(tsukuHome, "cache", "build"),
+		OSVCacheDir:      filepath.Join(tsukuHome, "cache", "osv"),
+		GoModCacheDir:    filepath.Join(tsukuHome, ".gomodcache"),
+		TelemetrySpool:   filepath.Join(tsukuHome, "cache", "telemetry.jsonl"),
+		LocksDir:         filepath.Join(tsukuHome, "locks"),
+		StoreDir:         filepath.Join(tsukuHome, "store"),
 		ConfigFile:       filepath.Join(tsukuHome, "config.toml"),
+		PolicyFile:       filepath.Join(tsukuHome, "policy.toml"),
 	}, nil
 }
 
@@ -140,6 +211,7 @@ func (c *Config) EnsureDirectories() error {
 		c.CacheDir,
 		c.VersionCacheDir,
 		c.DownloadCacheDir,
+		c.LocksDir,
 	}
 
 	for _, dir := range dirs {
@@ -52,6 +52,7 @@ func TestEnsureDirectories(t *testing.T) {
 		CacheDir:         filepath.Join(tmpDir, "tsuku", "cache"),
 		VersionCacheDir:  filepath.Join(tmpDir, "tsuku", "cache", "versions"),
 		DownloadCacheDir: filepath.Join(tmpDir, "tsuku", "cache", "downloads"),
+		LocksDir:         filepath.Join(tmpDir, "tsuku", "locks"),
 	}
 
 	err := cfg.EnsureDirectories()
@@ -60,7 +61,7 @@ func TestEnsureDirectories(t *testing.T) {
 	}
 
 	// Verify all directories exist
-	dirs := []string{cfg.HomeDir, cfg.ToolsDir, cfg.CurrentDir, cfg.RecipesDir, cfg.RegistryDir, cfg.LibsDir, cfg.CacheDir, cfg.VersionCacheDir, cfg.DownloadCacheDir}
+	dirs := []string{cfg.HomeDir, cfg.ToolsDir, cfg.CurrentDir, cfg.RecipesDir, cfg.RegistryDir, cfg.LibsDir, cfg.CacheDir, cfg.VersionCacheDir, cfg.DownloadCacheDir, cfg.LocksDir}
 	for _, dir := range dirs {
 		info, err := os.Stat(dir)
 		if err != nil {
@@ -320,3 +321,45 @@ func TestGetVersionCacheTTL_TooHigh(t *testing.T) {
 		t.Errorf("GetVersionCacheTTL() = %v, want 168h (maximum)", ttl)
 	}
 }
+
+func TestGetLockTimeout(t *testing.T) {
+	original := os.Getenv(EnvLockTimeout)
+	defer os.Setenv(EnvLockTimeout, original)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", DefaultLockTimeout},
+		{"30m", 30 * time.Minute},
+		{"invalid", DefaultLockTimeout},
+		{"100ms", 1 * time.Second},
+		{"48h", 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		os.Setenv(EnvLockTimeout, tt.value)
+		if got := GetLockTimeout(); got != tt.want {
+			t.Errorf("GetLockTimeout() with %q = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestIsOffline(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"", false},
+		{"1", true},
+		{"true", true},
+		{"0", false},
+		{"false", false},
+		{"yes", true},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvOffline, tt.value)
+		if got := IsOffline(); got != tt.want {
+			t.Errorf("IsOffline() with %q = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
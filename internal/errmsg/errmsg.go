@@ -8,7 +8,7 @@ import (
 )
 
 // Suggester is an interface for errors that can provide actionable suggestions.
-// Both version.ResolverError and registry.RegistryError implement this interface.
+// version.ResolverError, registry.RegistryError and policy.Error implement this interface.
 type Suggester interface {
 	error
 	Suggestion() string
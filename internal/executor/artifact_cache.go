@@ -0,0 +1,613 @@
+package executor
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/httputil"
+)
+
+// ArtifactFormatVersion is the version of the binary cache archive layout.
+// Archives with another version are treated as cache misses.
+const ArtifactFormatVersion = 1
+
+// Placeholders written into cached install trees in place of paths that
+// differ between machines. They are rewritten on restore, the way
+// homebrew_relocate rewrites @@HOMEBREW_PREFIX@@.
+const (
+	placeholderInstallDir = "@@TSUKU_INSTALL_DIR@@"
+	placeholderHome       = "@@TSUKU_HOME@@"
+)
+
+// Layout of a cached archive: the install tree under tree/, followed by
+// manifest.json
+const (
+	artifactTreeDir      = "tree/"
+	artifactManifestName = "manifest.json"
+)
+
+// maxArtifactDigestSize limits the size of a downloaded digest file
+const maxArtifactDigestSize = 4096
+
+// buildActions are the plan actions whose output is worth caching. Plans
+// without any of them are cheap to replay and are never cached.
+var buildActions = map[string]bool{
+	"configure_make": true,
+	"cmake_build":    true,
+	"meson_build":    true,
+	"cargo_build":    true,
+	"go_build":       true,
+}
+
+// IsBuildPlan reports whether a plan compiles from source or through an
+// ecosystem toolchain, so its install tree can be served from a binary cache.
+func IsBuildPlan(plan *InstallationPlan) bool {
+	for _, step := range plan.Steps {
+		if buildActions[step.Action] {
+			return true
+		}
+	}
+	return false
+}
+
+// ArtifactKey returns the binary cache key of a plan: a SHA-256 over the
+// tool, version, platform, every step's action, parameters and checksum, and
+// the versions of the dependencies the build ran against.
+func ArtifactKey(plan *InstallationPlan) string {
+	type keyStep struct {
+		Action   string                 `json:"action"`
+		Params   map[string]interface{} `json:"params"`
+		Checksum string                 `json:"checksum,omitempty"`
+	}
+	input := struct {
+		Format       int       `json:"format"`
+		Tool         string    `json:"tool"`
+		Version      string    `json:"version"`
+		Platform     string    `json:"platform"`
+		Steps        []keyStep `json:"steps"`
+		Dependencies []string  `json:"dependencies"`
+	}{
+		Format:       ArtifactFormatVersion,
+		Tool:         plan.Tool,
+		Version:      plan.Version,
+		Platform:     plan.Platform.String(),
+		Dependencies: flattenDependencies(plan.Dependencies),
+	}
+	for _, step := range plan.Steps {
+		input.Steps = append(input.Steps, keyStep{Action: step.Action, Params: step.Params, Checksum: step.Checksum})
+	}
+
+	// json.Marshal sorts map keys, so the encoding is stable
+	data, _ := json.Marshal(input)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// flattenDependencies lists dependencies depth-first as name@version
+func flattenDependencies(deps []DependencyPlan) []string {
+	var out []string
+	for _, dep := range deps {
+		out = append(out, flattenDependencies(dep.Dependencies)...)
+		out = append(out, dep.Tool+"@"+dep.Version)
+	}
+	return out
+}
+
+// artifactManifest describes a cached install tree
+type artifactManifest struct {
+	FormatVersion int       `json:"format_version"`
+	Key           string    `json:"key"`
+	Tool          string    `json:"tool"`
+	Version       string    `json:"version"`
+	Platform      string    `json:"platform"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// HomeBinaries lists binary files that embed the $TSUKU_HOME of the
+	// machine that built them. Binaries can't be rewritten like text files,
+	// so the archive is only usable under the same $TSUKU_HOME.
+	Home         string   `json:"home"`
+	HomeBinaries []string `json:"home_binaries,omitempty"`
+}
+
+// ArtifactCache stores relocatable install trees of build plans, keyed by
+// ArtifactKey, in a local directory and optionally reads them from an HTTPS
+// cache with the same layout (<url>/<key>.tar.gz). Every archive has a
+// <key>.tar.gz.sha256 file next to it, and remote archives are only used
+// when their content matches that digest.
+type ArtifactCache struct {
+	dir       string
+	remoteURL string
+	client    *http.Client
+}
+
+// NewArtifactCache creates a binary cache in dir. remoteURL may be empty.
+func NewArtifactCache(dir, remoteURL string) *ArtifactCache {
+	return &ArtifactCache{
+		dir:       dir,
+		remoteURL: strings.TrimSuffix(remoteURL, "/"),
+		client:    newArtifactHTTPClient(),
+	}
+}
+
+// archivePath returns the local path of a cached archive
+func (c *ArtifactCache) archivePath(key string) string {
+	return filepath.Join(c.dir, key+".tar.gz")
+}
+
+// digestPath returns the local path of a cached archive's digest file
+func (c *ArtifactCache) digestPath(key string) string {
+	return c.archivePath(key) + ".sha256"
+}
+
+// Has reports whether the local cache holds an archive for key
+func (c *ArtifactCache) Has(key string) bool {
+	_, err := os.Stat(c.archivePath(key))
+	return err == nil
+}
+
+// Save packages installDir into the cache under key. Occurrences of
+// installDir and home in text files and symlink targets are replaced with
+// placeholders so the tree can be restored elsewhere.
+func (c *ArtifactCache) Save(key string, plan *InstallationPlan, installDir, home string) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create binary cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	manifest := artifactManifest{
+		FormatVersion: ArtifactFormatVersion,
+		Key:           key,
+		Tool:          plan.Tool,
+		Version:       plan.Version,
+		Platform:      plan.Platform.String(),
+		CreatedAt:     time.Now().UTC(),
+		Home:          home,
+	}
+	r := newRelocator(map[string]string{installDir: placeholderInstallDir, home: placeholderHome})
+
+	hasher := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(tmp, hasher))
+	tw := tar.NewWriter(gz)
+	err = filepath.Walk(installDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(installDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		name := artifactTreeDir + filepath.ToSlash(rel)
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeSymlink,
+				Name:     name,
+				Linkname: string(r.apply([]byte(target))),
+				Mode:     int64(info.Mode().Perm()),
+			})
+		case info.IsDir():
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     name + "/",
+				Mode:     int64(info.Mode().Perm()),
+			})
+		case info.Mode().IsRegular():
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if isBinaryContent(content) {
+				if bytes.Contains(content, []byte(home)) {
+					manifest.HomeBinaries = append(manifest.HomeBinaries, filepath.ToSlash(rel))
+				}
+			} else {
+				content = r.apply(content)
+			}
+			return writeTarFile(tw, name, content, int64(info.Mode().Perm()))
+		default:
+			return nil // Skip sockets, devices and other special files
+		}
+	})
+
+	// The manifest goes last, once the binaries embedding $TSUKU_HOME are known
+	if err == nil {
+		data, _ := json.MarshalIndent(manifest, "", "  ")
+		err = writeTarFile(tw, artifactManifestName, data, 0644)
+	}
+	if err == nil {
+		err = tw.Close()
+	}
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to package install tree: %w", err)
+	}
+
+	// sha256sum format, so a published cache directory can be checked with it
+	digest := fmt.Sprintf("%s  %s.tar.gz\n", hex.EncodeToString(hasher.Sum(nil)), key)
+	if err := os.WriteFile(c.digestPath(key), []byte(digest), 0644); err != nil {
+		return fmt.Errorf("failed to store archive digest: %w", err)
+	}
+	if err := os.Rename(tmpPath, c.archivePath(key)); err != nil {
+		return fmt.Errorf("failed to store archive: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces installDir with the tree cached under key, rewriting
+// placeholders for installDir and home. It reports false
+// without error when there is no usable archive. The remote cache is only
+// consulted when the local cache misses, and never in offline mode.
+func (c *ArtifactCache) Restore(key, installDir, home string) (bool, error) {
+	path := c.archivePath(key)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if c.remoteURL == "" || config.IsOffline() {
+			return false, nil
+		}
+		found, err := c.fetch(key)
+		if err != nil || !found {
+			return false, err
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	staging := installDir + ".restore"
+	os.RemoveAll(staging)
+	ok, err := unpackArtifact(f, key, staging, home, installDir)
+	if err != nil || !ok {
+		os.RemoveAll(staging)
+		return false, err
+	}
+
+	if err := os.RemoveAll(installDir); err != nil {
+		os.RemoveAll(staging)
+		return false, err
+	}
+	if err := os.Rename(staging, installDir); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// unpackArtifact extracts an archive's tree into dst. It returns false if
+// the manifest doesn't match key or the archive can't be relocated to home;
+// dst is then left for the caller to remove.
+func unpackArtifact(r io.Reader, key, dst, home, installDir string) (bool, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return false, fmt.Errorf("invalid archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	rel := newRelocator(map[string]string{placeholderInstallDir: installDir, placeholderHome: home})
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return false, err
+	}
+
+	var manifest *artifactManifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, fmt.Errorf("invalid archive: %w", err)
+		}
+
+		if hdr.Name == artifactManifestName {
+			manifest = &artifactManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return false, fmt.Errorf("invalid archive manifest: %w", err)
+			}
+			continue
+		}
+		name, ok := strings.CutPrefix(hdr.Name, artifactTreeDir)
+		if !ok || name == "" {
+			continue
+		}
+		target := filepath.Join(dst, filepath.FromSlash(name))
+		if !strings.HasPrefix(target, dst+string(os.PathSeparator)) {
+			return false, fmt.Errorf("invalid archive: %s escapes the install directory", hdr.Name)
+		}
+		// An earlier entry may have planted a symlink; never write through one
+		if err := checkNoSymlinkInPath(dst, target); err != nil {
+			return false, fmt.Errorf("invalid archive: %w", err)
+		}
+
+		mode := os.FileMode(hdr.Mode).Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode|0700); err != nil {
+				return false, err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return false, err
+			}
+			linkTarget := string(rel.apply([]byte(hdr.Linkname)))
+			if err := validateArtifactSymlink(linkTarget, target, dst, installDir, home); err != nil {
+				return false, fmt.Errorf("invalid archive: %w", err)
+			}
+			if err := os.Symlink(linkTarget, target); err != nil {
+				return false, err
+			}
+		case tar.TypeReg:
+			content, err := io.ReadAll(tr)
+			if err != nil {
+				return false, fmt.Errorf("invalid archive: %w", err)
+			}
+			if !isBinaryContent(content) {
+				content = rel.apply(content)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return false, err
+			}
+			if err := os.WriteFile(target, content, mode); err != nil {
+				return false, err
+			}
+			if err := os.Chmod(target, mode); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if manifest == nil {
+		return false, fmt.Errorf("invalid archive: missing manifest")
+	}
+	if manifest.FormatVersion != ArtifactFormatVersion || manifest.Key != key {
+		return false, nil
+	}
+	if len(manifest.HomeBinaries) > 0 && manifest.Home != home {
+		// Binaries point into another $TSUKU_HOME; rebuild instead
+		return false, nil
+	}
+	return true, nil
+}
+
+// validateArtifactSymlink checks a restored symlink's target. Relative
+// targets must stay inside the unpacked tree. Absolute targets come from the
+// placeholders, so they may only point into the install directory or
+// $TSUKU_HOME.
+func validateArtifactSymlink(linkTarget, linkLocation, dst, installDir, home string) error {
+	if !filepath.IsAbs(linkTarget) {
+		return actions.ValidateSymlinkTarget(linkTarget, linkLocation, dst)
+	}
+	for _, dir := range []string{installDir, home} {
+		if dir != "" && actions.IsPathWithinDirectory(linkTarget, dir) {
+			return nil
+		}
+	}
+	return fmt.Errorf("symlink target outside the install directory and $TSUKU_HOME: %s -> %s", linkLocation, linkTarget)
+}
+
+// checkNoSymlinkInPath fails if target, or any directory between dst and
+// target, already exists as a symlink
+func checkNoSymlinkInPath(dst, target string) error {
+	rel, err := filepath.Rel(dst, target)
+	if err != nil {
+		return err
+	}
+	path := dst
+	for _, part := range strings.Split(rel, string(os.PathSeparator)) {
+		path = filepath.Join(path, part)
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s passes through symlink %s", target, path)
+		}
+	}
+	return nil
+}
+
+// writeTarFile writes a regular file entry
+func writeTarFile(tw *tar.Writer, name string, content []byte, mode int64) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(content)),
+		Mode:     mode,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// fetch downloads an archive from the remote cache into the local cache.
+// The archive's published digest is fetched first and the download is
+// discarded unless it matches. Archives without a digest are cache misses.
+func (c *ArtifactCache) fetch(key string) (bool, error) {
+	if !strings.HasPrefix(c.remoteURL, "https://") {
+		return false, fmt.Errorf("binary cache URL must use https: %s", c.remoteURL)
+	}
+
+	digestBody, found, err := c.get(key + ".tar.gz.sha256")
+	if err != nil || !found {
+		return false, err
+	}
+	data, err := io.ReadAll(io.LimitReader(digestBody, maxArtifactDigestSize))
+	digestBody.Close()
+	if err != nil {
+		return false, fmt.Errorf("failed to download from binary cache: %w", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return false, fmt.Errorf("binary cache returned an invalid digest for %s", key)
+	}
+	want := strings.ToLower(fields[0])
+
+	body, found, err := c.get(key + ".tar.gz")
+	if err != nil || !found {
+		return false, err
+	}
+	defer body.Close()
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return false, err
+	}
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return false, err
+	}
+	tmpPath := tmp.Name()
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hasher), body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		if got := hex.EncodeToString(hasher.Sum(nil)); got != want {
+			err = fmt.Errorf("archive digest %s does not match published digest %s", got, want)
+		}
+	}
+	if err == nil {
+		err = os.WriteFile(c.digestPath(key), data, 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, c.archivePath(key))
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return false, fmt.Errorf("failed to download from binary cache: %w", err)
+	}
+	return true, nil
+}
+
+// get requests a file from the remote cache. It reports false without error
+// when the file doesn't exist.
+func (c *ArtifactCache) get(name string) (io.ReadCloser, bool, error) {
+	resp, err := c.client.Get(c.remoteURL + "/" + name)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query binary cache: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, false, fmt.Errorf("binary cache returned %s", resp.Status)
+	}
+	return resp.Body, true, nil
+}
+
+// ArtifactCacheInfo summarizes the local binary cache
+type ArtifactCacheInfo struct {
+	EntryCount int
+	TotalSize  int64
+}
+
+// Info returns the number and total size of locally cached archives.
+func (c *ArtifactCache) Info() (*ArtifactCacheInfo, error) {
+	info := &ArtifactCacheInfo{}
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return info, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".tar.gz") || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if fi, err := entry.Info(); err == nil {
+			info.EntryCount++
+			info.TotalSize += fi.Size()
+		}
+	}
+	return info, nil
+}
+
+// Clear removes all locally cached archives.
+func (c *ArtifactCache) Clear() error {
+	if err := os.RemoveAll(c.dir); err != nil {
+		return fmt.Errorf("failed to clear binary cache: %w", err)
+	}
+	return nil
+}
+
+// relocator replaces a set of strings, longest first so that a path is
+// never partially replaced by one of its prefixes
+type relocator struct {
+	from [][]byte
+	to   [][]byte
+}
+
+func newRelocator(replacements map[string]string) *relocator {
+	r := &relocator{}
+	for from, to := range replacements {
+		if from == "" {
+			continue
+		}
+		i := 0
+		for i < len(r.from) && len(r.from[i]) >= len(from) {
+			i++
+		}
+		r.from = append(r.from[:i], append([][]byte{[]byte(from)}, r.from[i:]...)...)
+		r.to = append(r.to[:i], append([][]byte{[]byte(to)}, r.to[i:]...)...)
+	}
+	return r
+}
+
+func (r *relocator) apply(content []byte) []byte {
+	for i, from := range r.from {
+		content = bytes.ReplaceAll(content, from, r.to[i])
+	}
+	return content
+}
+
+// isBinaryContent detects binary files by a NUL byte in the first 8KB
+func isBinaryContent(content []byte) bool {
+	if len(content) > 8192 {
+		content = content[:8192]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// newArtifactHTTPClient returns the client used for the remote cache, with a
+// timeout long enough for large install trees
+func newArtifactHTTPClient() *http.Client {
+	return httputil.NewSecureClient(httputil.ClientOptions{
+		Timeout: 10 * time.Minute,
+	})
+}
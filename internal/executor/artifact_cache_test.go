@@ -0,0 +1,349 @@
+package executor
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func buildPlan() *InstallationPlan {
+	return &InstallationPlan{
+		FormatVersion: PlanFormatVersion,
+		Tool:          "jq",
+		Version:       "1.7.1",
+		Platform:      Platform{OS: "linux", Arch: "amd64", Libc: "glibc"},
+		Dependencies:  []DependencyPlan{{Tool: "oniguruma", Version: "6.9.9"}},
+		Steps: []ResolvedStep{
+			{Action: "download_file", Params: map[string]interface{}{"url": "https://example.com/jq.tar.gz"}, Checksum: "abc"},
+			{Action: "extract", Params: map[string]interface{}{"archive": "jq.tar.gz"}},
+			{Action: "configure_make", Params: map[string]interface{}{"source_dir": "jq-1.7.1"}},
+		},
+	}
+}
+
+func TestIsBuildPlan(t *testing.T) {
+	if !IsBuildPlan(buildPlan()) {
+		t.Error("IsBuildPlan() = false for a configure_make plan")
+	}
+	plan := &InstallationPlan{Steps: []ResolvedStep{{Action: "download_file"}, {Action: "install_binaries"}}}
+	if IsBuildPlan(plan) {
+		t.Error("IsBuildPlan() = true for a download-only plan")
+	}
+}
+
+func TestArtifactKey(t *testing.T) {
+	key := ArtifactKey(buildPlan())
+	if key != ArtifactKey(buildPlan()) {
+		t.Error("ArtifactKey() is not stable")
+	}
+
+	changes := map[string]func(*InstallationPlan){
+		"checksum":   func(p *InstallationPlan) { p.Steps[0].Checksum = "def" },
+		"params":     func(p *InstallationPlan) { p.Steps[2].Params["configure_args"] = []string{"--with-oniguruma"} },
+		"platform":   func(p *InstallationPlan) { p.Platform.Libc = "musl" },
+		"dependency": func(p *InstallationPlan) { p.Dependencies[0].Version = "6.9.10" },
+	}
+	for name, change := range changes {
+		plan := buildPlan()
+		change(plan)
+		if ArtifactKey(plan) == key {
+			t.Errorf("ArtifactKey() unchanged after changing %s", name)
+		}
+	}
+
+	// Generation time and recipe provenance don't affect the build
+	plan := buildPlan()
+	plan.RecipeSource = "/tmp/jq.toml"
+	if ArtifactKey(plan) != key {
+		t.Error("ArtifactKey() changed with the recipe source")
+	}
+}
+
+// writeTree creates files under dir; values starting with "-> " are symlinks
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if target, ok := strings.CutPrefix(content, "-> "); ok {
+			if err := os.Symlink(target, path); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestArtifactCache_SaveRestoreRelocates(t *testing.T) {
+	root := t.TempDir()
+	cache := NewArtifactCache(filepath.Join(root, "cache"), "")
+	plan := buildPlan()
+	key := ArtifactKey(plan)
+
+	buildHome := filepath.Join(root, "home-a")
+	buildInstall := filepath.Join(root, "work-a", ".install")
+	writeTree(t, buildInstall, map[string]string{
+		"bin/jq":              "\x7fELF\x00binary",
+		"lib/pkgconfig/jq.pc": "prefix=" + buildInstall + "\nlibdir=" + buildHome + "/libs/oniguruma-6.9.9/lib\n",
+		"lib/libonig.so":      "-> " + buildHome + "/libs/oniguruma-6.9.9/lib/libonig.so",
+	})
+	if err := cache.Save(key, plan, buildInstall, buildHome); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	home := filepath.Join(root, "home-b")
+	installDir := filepath.Join(root, "work-b", ".install")
+	if err := os.MkdirAll(installDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := cache.Restore(key, installDir, home)
+	if err != nil || !restored {
+		t.Fatalf("Restore() = %v, %v; want hit", restored, err)
+	}
+
+	pc, _ := os.ReadFile(filepath.Join(installDir, "lib", "pkgconfig", "jq.pc"))
+	want := "prefix=" + installDir + "\nlibdir=" + home + "/libs/oniguruma-6.9.9/lib\n"
+	if string(pc) != want {
+		t.Errorf("jq.pc = %q, want %q", pc, want)
+	}
+	target, _ := os.Readlink(filepath.Join(installDir, "lib", "libonig.so"))
+	if want := home + "/libs/oniguruma-6.9.9/lib/libonig.so"; target != want {
+		t.Errorf("symlink -> %q, want %q", target, want)
+	}
+	bin, _ := os.ReadFile(filepath.Join(installDir, "bin", "jq"))
+	if string(bin) != "\x7fELF\x00binary" {
+		t.Errorf("binary changed on restore: %q", bin)
+	}
+	if info, err := os.Stat(filepath.Join(installDir, "bin", "jq")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("binary mode not preserved: %v, %v", info, err)
+	}
+
+	if restored, _ := cache.Restore(ArtifactKey(&InstallationPlan{Tool: "other"}), installDir, home); restored {
+		t.Error("Restore() hit for an unknown key")
+	}
+}
+
+func TestArtifactCache_BinaryWithHomeNotRelocated(t *testing.T) {
+	root := t.TempDir()
+	cache := NewArtifactCache(filepath.Join(root, "cache"), "")
+	plan := buildPlan()
+	key := ArtifactKey(plan)
+
+	buildHome := filepath.Join(root, "home-a")
+	buildInstall := filepath.Join(root, "work-a", ".install")
+	writeTree(t, buildInstall, map[string]string{
+		"bin/jq": "\x7fELF\x00rpath=" + buildHome + "/libs/oniguruma-6.9.9/lib",
+	})
+	if err := cache.Save(key, plan, buildInstall, buildHome); err != nil {
+		t.Fatal(err)
+	}
+
+	installDir := filepath.Join(root, "work-b", ".install")
+	if restored, err := cache.Restore(key, installDir, filepath.Join(root, "home-b")); restored || err != nil {
+		t.Errorf("Restore() under another home = %v, %v; want miss", restored, err)
+	}
+	if restored, err := cache.Restore(key, installDir, buildHome); !restored || err != nil {
+		t.Errorf("Restore() under the same home = %v, %v; want hit", restored, err)
+	}
+}
+
+func TestArtifactCache_RemoteFetch(t *testing.T) {
+	root := t.TempDir()
+	plan := buildPlan()
+	key := ArtifactKey(plan)
+
+	// Populate a cache directory and serve it over HTTP
+	served := NewArtifactCache(filepath.Join(root, "served"), "")
+	buildInstall := filepath.Join(root, "work-a", ".install")
+	writeTree(t, buildInstall, map[string]string{"bin/jq": "#!/bin/sh\n"})
+	if err := served.Save(key, plan, buildInstall, filepath.Join(root, "home")); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewTLSServer(http.FileServer(http.Dir(filepath.Join(root, "served"))))
+	defer srv.Close()
+
+	cache := NewArtifactCache(filepath.Join(root, "local"), srv.URL+"/")
+	cache.client = srv.Client()
+
+	installDir := filepath.Join(root, "work-b", ".install")
+	restored, err := cache.Restore(key, installDir, filepath.Join(root, "home"))
+	if err != nil || !restored {
+		t.Fatalf("Restore() = %v, %v; want remote hit", restored, err)
+	}
+	if _, err := os.Stat(cache.archivePath(key)); err != nil {
+		t.Errorf("remote archive not kept in the local cache: %v", err)
+	}
+
+	info, err := cache.Info()
+	if err != nil || info.EntryCount != 1 {
+		t.Errorf("Info() = %+v, %v; want 1 entry", info, err)
+	}
+
+	if restored, err := cache.Restore(strings.Repeat("0", 64), installDir, filepath.Join(root, "home")); restored || err != nil {
+		t.Errorf("Restore() of a key the remote doesn't have = %v, %v; want miss", restored, err)
+	}
+}
+
+// writeRawArtifact stores a hand-built archive with the given tree entries
+// and a manifest for key
+func writeRawArtifact(t *testing.T, cache *ArtifactCache, key string, entries []*tar.Header) {
+	t.Helper()
+	if err := os.MkdirAll(cache.dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(cache.archivePath(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range entries {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			if _, err := tw.Write([]byte(strings.Repeat("x", int(hdr.Size)))); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	manifest := `{"format_version": 1, "key": "` + key + `"}`
+	if err := writeTarFile(tw, artifactManifestName, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArtifactCache_RestoreRejectsUnsafeSymlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries func(outside string) []*tar.Header
+	}{
+		{
+			name: "absolute target outside",
+			entries: func(outside string) []*tar.Header {
+				return []*tar.Header{{Typeflag: tar.TypeSymlink, Name: "tree/etc", Linkname: outside}}
+			},
+		},
+		{
+			name: "relative target escaping the tree",
+			entries: func(outside string) []*tar.Header {
+				return []*tar.Header{{Typeflag: tar.TypeSymlink, Name: "tree/lib/up", Linkname: "../../../../outside"}}
+			},
+		},
+		{
+			name: "write through a symlink inside the tree",
+			entries: func(outside string) []*tar.Header {
+				return []*tar.Header{
+					{Typeflag: tar.TypeDir, Name: "tree/share/", Mode: 0755},
+					{Typeflag: tar.TypeSymlink, Name: "tree/doc", Linkname: "share"},
+					{Typeflag: tar.TypeReg, Name: "tree/doc/x", Size: 1, Mode: 0644},
+				}
+			},
+		},
+		{
+			name: "overwrite a symlink with a file",
+			entries: func(outside string) []*tar.Header {
+				return []*tar.Header{
+					{Typeflag: tar.TypeSymlink, Name: "tree/a", Linkname: "b"},
+					{Typeflag: tar.TypeReg, Name: "tree/a", Size: 1, Mode: 0644},
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			outside := filepath.Join(root, "outside")
+			if err := os.MkdirAll(outside, 0755); err != nil {
+				t.Fatal(err)
+			}
+			cache := NewArtifactCache(filepath.Join(root, "cache"), "")
+			key := ArtifactKey(buildPlan())
+			writeRawArtifact(t, cache, key, tt.entries(outside))
+
+			installDir := filepath.Join(root, "work", ".install")
+			restored, err := cache.Restore(key, installDir, filepath.Join(root, "home"))
+			if restored || err == nil {
+				t.Errorf("Restore() = %v, %v; want an invalid archive error", restored, err)
+			}
+			if _, err := os.Stat(installDir + ".restore"); !os.IsNotExist(err) {
+				t.Error("staging directory left behind")
+			}
+			if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+				t.Errorf("archive wrote outside the tree: %v", entries)
+			}
+		})
+	}
+}
+
+func TestArtifactCache_RemoteFetchVerifiesDigest(t *testing.T) {
+	root := t.TempDir()
+	plan := buildPlan()
+	key := ArtifactKey(plan)
+
+	servedDir := filepath.Join(root, "served")
+	served := NewArtifactCache(servedDir, "")
+	buildInstall := filepath.Join(root, "work-a", ".install")
+	writeTree(t, buildInstall, map[string]string{"bin/jq": "#!/bin/sh\n"})
+	if err := served.Save(key, plan, buildInstall, filepath.Join(root, "home")); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewTLSServer(http.FileServer(http.Dir(servedDir)))
+	defer srv.Close()
+	installDir := filepath.Join(root, "work-b", ".install")
+
+	// Tampered archive
+	digest, err := os.ReadFile(served.digestPath(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(served.digestPath(key), []byte(strings.Repeat("0", 64)+"  "+key+".tar.gz\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cache := NewArtifactCache(filepath.Join(root, "local"), srv.URL)
+	cache.client = srv.Client()
+	if restored, err := cache.Restore(key, installDir, filepath.Join(root, "home")); restored || err == nil {
+		t.Errorf("Restore() with a mismatched digest = %v, %v; want error", restored, err)
+	}
+	if cache.Has(key) {
+		t.Error("archive with a mismatched digest kept in the local cache")
+	}
+
+	// Unpublished digest
+	if err := os.Remove(served.digestPath(key)); err != nil {
+		t.Fatal(err)
+	}
+	if restored, err := cache.Restore(key, installDir, filepath.Join(root, "home")); restored || err != nil {
+		t.Errorf("Restore() without a digest = %v, %v; want miss", restored, err)
+	}
+
+	// Plain HTTP is refused
+	if err := os.WriteFile(served.digestPath(key), digest, 0644); err != nil {
+		t.Fatal(err)
+	}
+	plain := httptest.NewServer(http.FileServer(http.Dir(servedDir)))
+	defer plain.Close()
+	insecure := NewArtifactCache(filepath.Join(root, "local-http"), plain.URL)
+	insecure.client = plain.Client()
+	if restored, err := insecure.Restore(key, installDir, filepath.Join(root, "home")); restored || err == nil {
+		t.Errorf("Restore() over plain HTTP = %v, %v; want error", restored, err)
+	}
+}
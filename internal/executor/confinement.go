@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/landlock"
+)
+
+// SetConfinement confines the commands started by actions with Landlock.
+// launcher is the tsuku binary that applies the rules; "" disables
+// confinement.
+func (e *Executor) SetConfinement(launcher string) {
+	e.confinementLauncher = launcher
+}
+
+// confinement returns the confinement for a tool's steps, applying the
+// exceptions recorded in its plan. It reports the outcome so the install
+// output shows how each tool's commands ran. Confinement is skipped with a
+// warning when the kernel doesn't support Landlock.
+func (e *Executor) confinement(pc *PlanConfinement) *actions.Confinement {
+	if e.confinementLauncher == "" {
+		return nil
+	}
+	if pc != nil && pc.Unconfined {
+		fmt.Printf("   Confinement: disabled by recipe (%s)\n", pc.Reason)
+		return nil
+	}
+	if !landlock.Supported() {
+		if !e.confinementWarned {
+			fmt.Println("   Warning: Landlock is not available on this system, commands run unconfined")
+			e.confinementWarned = true
+		}
+		return nil
+	}
+
+	c := &actions.Confinement{Launcher: e.confinementLauncher}
+	if pc == nil {
+		fmt.Println("   Confinement: Landlock")
+		return c
+	}
+	c.Writable = expandConfinementPaths(pc.Writable)
+	c.Readable = expandConfinementPaths(pc.Readable)
+	fmt.Printf("   Confinement: Landlock with recipe exceptions (%s)\n", pc.Reason)
+	for _, p := range c.Writable {
+		fmt.Printf("      writable: %s\n", p)
+	}
+	for _, p := range c.Readable {
+		fmt.Printf("      readable: %s\n", p)
+	}
+	return c
+}
+
+// expandConfinementPaths expands environment variables and a leading ~ in
+// the paths of a recipe's confinement exceptions
+func expandConfinementPaths(paths []string) []string {
+	home, _ := os.UserHomeDir()
+	var expanded []string
+	for _, p := range paths {
+		p = os.ExpandEnv(p)
+		if home != "" && (p == "~" || strings.HasPrefix(p, "~/")) {
+			p = filepath.Join(home, p[1:])
+		}
+		expanded = append(expanded, p)
+	}
+	return expanded
+}
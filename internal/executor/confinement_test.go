@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/landlock"
+)
+
+func TestExecutor_Confinement(t *testing.T) {
+	e := &Executor{}
+	if c := e.confinement(nil); c != nil {
+		t.Errorf("confinement() = %+v, want nil when not enabled", c)
+	}
+
+	e.SetConfinement("/usr/local/bin/tsuku")
+	if c := e.confinement(&PlanConfinement{Unconfined: true, Reason: "needs docker"}); c != nil {
+		t.Errorf("confinement() = %+v, want nil for unconfined recipe", c)
+	}
+
+	c := e.confinement(&PlanConfinement{Writable: []string{"/srv/cache"}, Reason: "cache"})
+	if !landlock.Supported() {
+		if c != nil {
+			t.Errorf("confinement() = %+v, want nil without Landlock support", c)
+		}
+		return
+	}
+	if c == nil || c.Launcher != "/usr/local/bin/tsuku" || len(c.Writable) != 1 || c.Writable[0] != "/srv/cache" {
+		t.Errorf("confinement() = %+v, want launcher and recipe exceptions", c)
+	}
+}
+
+func TestExpandConfinementPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TOOL_SDK", "/opt/sdk")
+
+	got := expandConfinementPaths([]string{"~/.cache/tool", "$TOOL_SDK/bin", "/abs", "~"})
+	want := []string{filepath.Join(home, ".cache/tool"), "/opt/sdk/bin", "/abs", home}
+	if len(got) != len(want) {
+		t.Fatalf("expandConfinementPaths() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expandConfinementPaths()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
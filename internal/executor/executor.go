@@ -13,6 +13,7 @@ import (
 
 	"github.com/tsukumogami/tsuku/internal/actions"
 	"github.com/tsukumogami/tsuku/internal/log"
+	"github.com/tsukumogami/tsuku/internal/platform"
 	"github.com/tsukumogami/tsuku/internal/recipe"
 	"github.com/tsukumogami/tsuku/internal/version"
 )
@@ -22,13 +23,20 @@ type Executor struct {
 	workDir          string
 	installDir       string
 	downloadCacheDir string // Download cache directory
+	versionCacheDir  string // Version cache recording resolved versions ("" disables it)
 	recipe           *recipe.Recipe
 	ctx              *actions.ExecutionContext
-	version          string   // Resolved version
-	reqVersion       string   // Requested version (optional)
-	execPaths        []string // Additional bin paths for execution (e.g., nodejs for npm tools)
-	toolsDir         string   // Tools directory (~/.tsuku/tools/) for finding other installed tools
-	libsDir          string   // Libraries directory (~/.tsuku/libs/) for finding installed libraries
+	version          string         // Resolved version
+	reqVersion       string         // Requested version (optional)
+	execPaths        []string       // Additional bin paths for execution (e.g., nodejs for npm tools)
+	toolsDir         string         // Tools directory (~/.tsuku/tools/) for finding other installed tools
+	libsDir          string         // Libraries directory (~/.tsuku/libs/) for finding installed libraries
+	artifactCache    *ArtifactCache // Binary cache for build plans (nil disables it)
+	buildCacheDir    string         // ccache/sccache directory ("" disables compiler caching)
+	buildCacheSize   string         // Compiler cache size limit
+
+	confinementLauncher string // tsuku binary applying Landlock rules ("" disables confinement)
+	confinementWarned   bool   // Whether the missing Landlock support was reported
 }
 
 // New creates a new executor
@@ -62,11 +70,37 @@ func NewWithVersion(r *recipe.Recipe, version string) (*Executor, error) {
 	return exec, nil
 }
 
+// SetArtifactCache enables the binary cache for plans that build from source
+func (e *Executor) SetArtifactCache(cache *ArtifactCache) {
+	e.artifactCache = cache
+}
+
+// SetBuildCache enables ccache/sccache for source builds, storing compiler
+// output in dir. maxSize limits each cache ("" for the tool default).
+func (e *Executor) SetBuildCache(dir, maxSize string) {
+	e.buildCacheDir = dir
+	e.buildCacheSize = maxSize
+}
+
 // SetDownloadCacheDir sets the download cache directory
 func (e *Executor) SetDownloadCacheDir(dir string) {
 	e.downloadCacheDir = dir
 }
 
+// SetVersionCacheDir records the versions resolved online in the version
+// cache, so offline mode can resolve them later
+func (e *Executor) SetVersionCacheDir(dir string) {
+	e.versionCacheDir = dir
+}
+
+// newResolver creates a version resolver using the executor's version cache
+func (e *Executor) newResolver() *version.Resolver {
+	if e.versionCacheDir == "" {
+		return version.New()
+	}
+	return version.New(version.WithVersionCache(e.versionCacheDir))
+}
+
 // resolveVersionWith attempts to resolve the latest version for the recipe using the given resolver
 func (e *Executor) resolveVersionWith(ctx context.Context, resolver *version.Resolver) (*version.VersionInfo, error) {
 	// Use unified provider factory
@@ -91,7 +125,7 @@ func (e *Executor) resolveVersionWith(ctx context.Context, resolver *version.Res
 // If constraint is specified, attempts to resolve that specific version.
 // Returns the resolved version string (e.g., "14.1.0").
 func (e *Executor) ResolveVersion(ctx context.Context, constraint string) (string, error) {
-	resolver := version.New()
+	resolver := e.newResolver()
 	factory := version.NewProviderFactory()
 	provider, err := factory.ProviderFromRecipe(resolver, e.recipe)
 	if err != nil {
@@ -131,6 +165,18 @@ func (e *Executor) shouldExecute(when map[string]string) bool {
 		}
 	}
 
+	// Check libc and distro conditions against the host
+	if libcCondition, ok := when["libc"]; ok {
+		if libcCondition != platform.Libc() {
+			return false
+		}
+	}
+	if distroCondition, ok := when["distro"]; ok {
+		if distroCondition != platform.HostDistro().ID {
+			return false
+		}
+	}
+
 	// Check package_manager condition (stub - always true for validation)
 	if _, ok := when["package_manager"]; ok {
 		// In real implementation, would detect system package manager
@@ -140,6 +186,15 @@ func (e *Executor) shouldExecute(when map[string]string) bool {
 	return true
 }
 
+// planLibc returns the libc a plan was generated for. Plans from before libc was
+// recorded were always generated for the host.
+func planLibc(p Platform) string {
+	if p.Libc == "" && p.OS == runtime.GOOS {
+		return platform.Libc()
+	}
+	return p.Libc
+}
+
 // Cleanup removes temporary directories
 func (e *Executor) Cleanup() {
 	if e.workDir != "" {
@@ -184,7 +239,7 @@ func expandVars(s string, vars map[string]string) string {
 // DryRun shows what would be done without actually executing
 func (e *Executor) DryRun(ctx context.Context) error {
 	// Create version resolver
-	resolver := version.New()
+	resolver := e.newResolver()
 
 	// Resolve version from recipe steps
 	versionInfo, err := e.resolveVersionWith(ctx, resolver)
@@ -290,6 +345,11 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *InstallationPlan) erro
 		return fmt.Errorf("plan validation failed: %w", err)
 	}
 
+	// In offline mode, fail before touching anything if a step needs the network
+	if err := e.checkOffline(plan); err != nil {
+		return err
+	}
+
 	fmt.Printf("Executing plan: %s@%s\n", plan.Tool, plan.Version)
 	fmt.Printf("   Work directory: %s\n", e.workDir)
 
@@ -319,10 +379,7 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *InstallationPlan) erro
 					Name: plan.Tool,
 					Type: plan.RecipeType,
 				},
-				Verify: recipe.VerifySection{
-					Command: plan.Verify.Command,
-					Pattern: plan.Verify.Pattern,
-				},
+				Verify: plan.Verify.Section(),
 			}
 		}
 	}
@@ -332,21 +389,25 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *InstallationPlan) erro
 
 	// Create execution context from plan
 	execCtx := &actions.ExecutionContext{
-		Context:          ctx,
-		WorkDir:          e.workDir,
-		InstallDir:       e.installDir,
-		ToolInstallDir:   "",
-		ToolsDir:         e.toolsDir,
-		LibsDir:          e.libsDir,
-		DownloadCacheDir: e.downloadCacheDir,
-		Version:          plan.Version,
-		VersionTag:       plan.Version, // Plan doesn't track tag separately
-		OS:               plan.Platform.OS,
-		Arch:             plan.Platform.Arch,
-		Recipe:           recipeForContext,
-		ExecPaths:        e.execPaths,
-		Logger:           log.Default(),
-		Dependencies:     resolvedDeps,
+		Context:           ctx,
+		WorkDir:           e.workDir,
+		InstallDir:        e.installDir,
+		ToolInstallDir:    "",
+		ToolsDir:          e.toolsDir,
+		LibsDir:           e.libsDir,
+		DownloadCacheDir:  e.downloadCacheDir,
+		BuildCacheDir:     e.buildCacheDir,
+		BuildCacheMaxSize: e.buildCacheSize,
+		Version:           plan.Version,
+		VersionTag:        plan.Version, // Plan doesn't track tag separately
+		OS:                plan.Platform.OS,
+		Arch:              plan.Platform.Arch,
+		Libc:              planLibc(plan.Platform),
+		Recipe:            recipeForContext,
+		ExecPaths:         e.execPaths,
+		Logger:            log.Default(),
+		Dependencies:      resolvedDeps,
+		Confinement:       e.confinement(plan.Confinement),
 	}
 	e.ctx = execCtx
 
@@ -363,6 +424,19 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *InstallationPlan) erro
 		}
 	}
 
+	// A cached build of the same steps replaces running them
+	cacheKey := e.artifactCacheKey(plan)
+	if cacheKey != "" {
+		restored, err := e.artifactCache.Restore(cacheKey, e.installDir, filepath.Dir(e.toolsDir))
+		if err != nil {
+			fmt.Printf("   Warning: binary cache lookup failed: %v\n", err)
+		}
+		if restored {
+			fmt.Printf("Restored build from binary cache (%s)\n", cacheKey[:12])
+			return nil
+		}
+	}
+
 	// Execute each step (including flattened dependency steps)
 	for i, step := range allSteps {
 		// Check for context cancellation
@@ -412,9 +486,24 @@ func (e *Executor) ExecutePlan(ctx context.Context, plan *InstallationPlan) erro
 		fmt.Println()
 	}
 
+	if cacheKey != "" {
+		if err := e.artifactCache.Save(cacheKey, plan, e.installDir, filepath.Dir(e.toolsDir)); err != nil {
+			fmt.Printf("   Warning: failed to store build in binary cache: %v\n", err)
+		}
+	}
+
 	return nil
 }
 
+// artifactCacheKey returns the binary cache key for a plan, or "" when the
+// cache is disabled or the plan doesn't build anything
+func (e *Executor) artifactCacheKey(plan *InstallationPlan) string {
+	if e.artifactCache == nil || e.toolsDir == "" || !IsBuildPlan(plan) {
+		return ""
+	}
+	return ArtifactKey(plan)
+}
+
 // executeDownloadWithVerification downloads a file and verifies its checksum against the plan.
 func (e *Executor) executeDownloadWithVerification(
 	ctx context.Context,
@@ -565,21 +654,25 @@ func (e *Executor) installSingleDependency(ctx context.Context, dep *DependencyP
 
 	// Create execution context for this dependency
 	execCtx := &actions.ExecutionContext{
-		Context:          ctx,
-		WorkDir:          depWorkDir,
-		InstallDir:       depInstallDir,
-		ToolInstallDir:   "",
-		ToolsDir:         e.toolsDir,
-		LibsDir:          e.libsDir,
-		DownloadCacheDir: e.downloadCacheDir,
-		Version:          dep.Version,
-		VersionTag:       dep.Version,
-		OS:               platform.OS,
-		Arch:             platform.Arch,
-		Recipe:           depRecipe,
-		ExecPaths:        e.execPaths,
-		Logger:           log.Default(),
-		Dependencies:     depResolvedDeps,
+		Context:           ctx,
+		WorkDir:           depWorkDir,
+		InstallDir:        depInstallDir,
+		ToolInstallDir:    "",
+		ToolsDir:          e.toolsDir,
+		LibsDir:           e.libsDir,
+		DownloadCacheDir:  e.downloadCacheDir,
+		BuildCacheDir:     e.buildCacheDir,
+		BuildCacheMaxSize: e.buildCacheSize,
+		Version:           dep.Version,
+		VersionTag:        dep.Version,
+		OS:                platform.OS,
+		Arch:              platform.Arch,
+		Libc:              planLibc(platform),
+		Recipe:            depRecipe,
+		ExecPaths:         e.execPaths,
+		Logger:            log.Default(),
+		Dependencies:      depResolvedDeps,
+		Confinement:       e.confinement(dep.Confinement),
 	}
 
 	// Validate all steps before execution (fail fast)
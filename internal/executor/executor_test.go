@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -1343,3 +1344,45 @@ func TestExecuteDownloadWithVerification_ChecksumMismatch(t *testing.T) {
 		t.Errorf("resolveDownloadDest() = %q, want %q", destPath, testFilePath)
 	}
 }
+
+func TestExecutePlan_ArtifactCacheHitSkipsBuild(t *testing.T) {
+	exec, err := New(&recipe.Recipe{Metadata: recipe.MetadataSection{Name: "test-tool"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer exec.Cleanup()
+
+	home := t.TempDir()
+	exec.SetToolsDir(filepath.Join(home, "tools"))
+	cache := NewArtifactCache(filepath.Join(home, "cache", "artifacts"), "")
+	exec.SetArtifactCache(cache)
+
+	// The source directory doesn't exist, so running the build would fail
+	plan := &InstallationPlan{
+		FormatVersion: PlanFormatVersion,
+		Tool:          "test-tool",
+		Version:       "1.0.0",
+		Platform:      Platform{OS: runtime.GOOS, Arch: runtime.GOARCH},
+		Steps: []ResolvedStep{
+			{Action: "configure_make", Params: map[string]interface{}{"source_dir": "missing", "executables": []interface{}{"test-tool"}}},
+		},
+	}
+
+	built := filepath.Join(t.TempDir(), ".install")
+	if err := os.MkdirAll(filepath.Join(built, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(built, "bin", "test-tool"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(ArtifactKey(plan), plan, built, home); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := exec.ExecutePlan(context.Background(), plan); err != nil {
+		t.Fatalf("ExecutePlan() error = %v, want build served from cache", err)
+	}
+	if _, err := os.Stat(filepath.Join(exec.WorkDir(), ".install", "bin", "test-tool")); err != nil {
+		t.Errorf("cached install tree not restored: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"fmt"
+
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/config"
+)
+
+// checkOffline fails when offline mode is on and the plan contains a step
+// that needs the network (actions.NetworkValidator). The main tool's steps
+// are allowed when its build can be restored from the local binary cache.
+// Downloads are not checked here: they are served from the download cache
+// and report the missing URL themselves.
+func (e *Executor) checkOffline(plan *InstallationPlan) error {
+	if !config.IsOffline() {
+		return nil
+	}
+
+	if err := checkOfflineDependencies(plan.Dependencies); err != nil {
+		return err
+	}
+
+	if key := e.artifactCacheKey(plan); key != "" && e.artifactCache.Has(key) {
+		return nil
+	}
+	return checkOfflineSteps(plan.Tool, plan.Version, plan.Steps)
+}
+
+func checkOfflineDependencies(deps []DependencyPlan) error {
+	for _, dep := range deps {
+		if err := checkOfflineDependencies(dep.Dependencies); err != nil {
+			return err
+		}
+		if err := checkOfflineSteps(dep.Tool, dep.Version, dep.Steps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkOfflineSteps returns an OfflineError naming the first step that
+// requires network access
+func checkOfflineSteps(tool, version string, steps []ResolvedStep) error {
+	for i, step := range steps {
+		nv, ok := actions.Get(step.Action).(actions.NetworkValidator)
+		if !ok || !nv.RequiresNetwork() {
+			continue
+		}
+		return &actions.OfflineError{
+			Action:  fmt.Sprintf("%s (step %d of %s@%s)", step.Action, i+1, tool, version),
+			Missing: "network access or a cached build of " + tool,
+		}
+	}
+	return nil
+}
+
+// checkOfflineDecompose returns an OfflineError when offline mode is on and
+// decomposing the recipe step at index would reach the network. Composite
+// package manager actions (cargo_install, npm_install, go_install, ...) fetch
+// package metadata to pin their dependencies while the plan is generated, so
+// they fail here, before any command starts. A plan cached by an earlier
+// online install skips plan generation altogether.
+func checkOfflineDecompose(tool, version string, index int, action string) error {
+	if !config.IsOffline() || !actions.IsDecomposable(action) {
+		return nil
+	}
+	nv, ok := actions.Get(action).(actions.NetworkValidator)
+	if !ok || !nv.RequiresNetwork() {
+		return nil
+	}
+	return &actions.OfflineError{
+		Action:  fmt.Sprintf("%s (step %d of %s@%s)", action, index+1, tool, version),
+		Missing: "package metadata from the network or a cached plan of " + tool,
+	}
+}
@@ -0,0 +1,199 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+func cargoPlan() *InstallationPlan {
+	return &InstallationPlan{
+		FormatVersion: PlanFormatVersion,
+		Tool:          "ripgrep",
+		Version:       "14.1.0",
+		Platform:      Platform{OS: "linux", Arch: "amd64"},
+		Steps: []ResolvedStep{
+			{Action: "download_file", Params: map[string]interface{}{"url": "https://example.com/rg.tar.gz"}, Checksum: "abc"},
+			{Action: "extract", Params: map[string]interface{}{"archive": "rg.tar.gz"}},
+			{Action: "cargo_build", Params: map[string]interface{}{"source_dir": "ripgrep-14.1.0"}},
+		},
+	}
+}
+
+func newOfflineTestExecutor(t *testing.T) *Executor {
+	t.Helper()
+	exec, err := New(&recipe.Recipe{Metadata: recipe.MetadataSection{Name: "ripgrep"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(exec.Cleanup)
+	return exec
+}
+
+func TestCheckOffline_Disabled(t *testing.T) {
+	t.Setenv("TSUKU_OFFLINE", "")
+	exec := newOfflineTestExecutor(t)
+
+	if err := exec.checkOffline(cargoPlan()); err != nil {
+		t.Errorf("checkOffline() error = %v, want nil when online", err)
+	}
+}
+
+func TestCheckOffline_NetworkStep(t *testing.T) {
+	t.Setenv("TSUKU_OFFLINE", "1")
+	exec := newOfflineTestExecutor(t)
+
+	err := exec.checkOffline(cargoPlan())
+	var offlineErr *actions.OfflineError
+	if !errors.As(err, &offlineErr) {
+		t.Fatalf("checkOffline() error = %v, want OfflineError", err)
+	}
+	for _, want := range []string{"cargo_build", "step 3", "ripgrep@14.1.0"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err.Error(), want)
+		}
+	}
+}
+
+func TestCheckOffline_DependencyNetworkStep(t *testing.T) {
+	t.Setenv("TSUKU_OFFLINE", "1")
+	exec := newOfflineTestExecutor(t)
+
+	plan := buildPlan()
+	plan.Dependencies = []DependencyPlan{{
+		Tool:    "oniguruma",
+		Version: "6.9.9",
+		Dependencies: []DependencyPlan{{
+			Tool:    "pcre-tool",
+			Version: "1.0.0",
+			Steps:   []ResolvedStep{{Action: "npm_install", Params: map[string]interface{}{"package": "x"}}},
+		}},
+	}}
+
+	err := exec.checkOffline(plan)
+	if err == nil || !strings.Contains(err.Error(), "pcre-tool@1.0.0") {
+		t.Errorf("checkOffline() error = %v, want it to name the nested dependency", err)
+	}
+}
+
+func TestCheckOffline_CachedBuild(t *testing.T) {
+	t.Setenv("TSUKU_OFFLINE", "1")
+	exec := newOfflineTestExecutor(t)
+
+	home := t.TempDir()
+	exec.SetToolsDir(filepath.Join(home, "tools"))
+	cache := NewArtifactCache(filepath.Join(home, "cache", "artifacts"), "")
+	exec.SetArtifactCache(cache)
+
+	plan := cargoPlan()
+	built := filepath.Join(t.TempDir(), ".install")
+	if err := os.MkdirAll(filepath.Join(built, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(built, "bin", "rg"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(ArtifactKey(plan), plan, built, home); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := exec.checkOffline(plan); err != nil {
+		t.Errorf("checkOffline() error = %v, want nil when the build is cached", err)
+	}
+}
+
+func TestGeneratePlan_OfflineCargoInstall(t *testing.T) {
+	t.Setenv("TSUKU_HOME", t.TempDir())
+	t.Setenv("TSUKU_OFFLINE", "1")
+
+	// Stand-ins for the commands cargo_install decomposition runs; each
+	// records that it was started
+	bin := t.TempDir()
+	started := filepath.Join(bin, "started")
+	for _, tool := range []string{"cargo", "curl", "rustc", "tar"} {
+		script := "#!/bin/sh\necho " + tool + " >> " + started + "\n"
+		if err := os.WriteFile(filepath.Join(bin, tool), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+
+	r := &recipe.Recipe{
+		Metadata: recipe.MetadataSection{Name: "ripgrep"},
+		Steps: []recipe.Step{{
+			Action: "cargo_install",
+			Params: map[string]interface{}{"crate": "ripgrep", "executables": []interface{}{"rg"}},
+		}},
+	}
+	primeOfflineVersions(t, r, "14.1.0")
+
+	exec, err := New(r)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(exec.Cleanup)
+
+	_, err = exec.GeneratePlan(context.Background(), PlanConfig{OS: "linux", Arch: "amd64"})
+	var offlineErr *actions.OfflineError
+	if !errors.As(err, &offlineErr) {
+		t.Fatalf("GeneratePlan() error = %v, want OfflineError", err)
+	}
+	for _, want := range []string{"cargo_install", "step 1", "ripgrep@14.1.0"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %q", err.Error(), want)
+		}
+	}
+	if data, err := os.ReadFile(started); err == nil {
+		t.Errorf("offline plan generation started commands:\n%s", data)
+	}
+}
+
+// primeOfflineVersions caches versions for the recipe's version source, as
+// an earlier online run would have
+func primeOfflineVersions(t *testing.T, r *recipe.Recipe, versions ...string) {
+	t.Helper()
+	cfg, err := config.DefaultConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := version.NewProviderFactory().ProviderFromRecipe(version.New(), r)
+	if err != nil {
+		t.Fatalf("ProviderFromRecipe() error = %v", err)
+	}
+	lister := &staticVersionLister{source: provider.SourceDescription(), versions: versions}
+	cached := version.NewCachedVersionLister(lister, cfg.VersionCacheDir, time.Hour)
+	if _, err := cached.ListVersions(context.Background()); err != nil {
+		t.Fatalf("failed to prime version cache: %v", err)
+	}
+}
+
+// staticVersionLister lists a fixed set of versions for a source
+type staticVersionLister struct {
+	source   string
+	versions []string
+}
+
+func (l *staticVersionLister) ListVersions(ctx context.Context) ([]string, error) {
+	return l.versions, nil
+}
+
+func (l *staticVersionLister) ResolveLatest(ctx context.Context) (*version.VersionInfo, error) {
+	return &version.VersionInfo{Version: l.versions[0], Tag: l.versions[0]}, nil
+}
+
+func (l *staticVersionLister) ResolveVersion(ctx context.Context, v string) (*version.VersionInfo, error) {
+	return &version.VersionInfo{Version: v, Tag: v}, nil
+}
+
+func (l *staticVersionLister) SourceDescription() string {
+	return l.source
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/platform"
+	"github.com/tsukumogami/tsuku/internal/recipe"
 )
 
 // PlanFormatVersion is the current version of the installation plan format.
@@ -56,6 +58,10 @@ type InstallationPlan struct {
 
 	// Metadata from the recipe (needed for install_binaries directory mode checks)
 	RecipeType string `json:"recipe_type,omitempty"` // "tool" or "library"
+
+	// Confinement records the recipe's exceptions to Landlock confinement,
+	// so they can be audited before the plan is executed
+	Confinement *PlanConfinement `json:"confinement,omitempty"`
 }
 
 // DependencyPlan represents a nested installation plan for a dependency.
@@ -76,18 +82,88 @@ type DependencyPlan struct {
 	Verify *PlanVerify `json:"verify,omitempty"`
 	// Recipe type
 	RecipeType string `json:"recipe_type,omitempty"`
+	// Confinement exceptions for this dependency
+	Confinement *PlanConfinement `json:"confinement,omitempty"`
 }
 
 // PlanVerify captures verification information from the recipe.
 type PlanVerify struct {
-	Command string `json:"command,omitempty"`
-	Pattern string `json:"pattern,omitempty"`
+	Command       string                  `json:"command,omitempty"`
+	Pattern       string                  `json:"pattern,omitempty"`
+	PatternRegex  string                  `json:"pattern_regex,omitempty"`
+	VersionFormat string                  `json:"version_format,omitempty"`
+	ExitCode      *int                    `json:"exit_code,omitempty"`
+	Tests         []recipe.FunctionalTest `json:"tests,omitempty"` // Functional test cases
+}
+
+// PlanConfinement captures the confinement section of a recipe. Paths are
+// kept as written in the recipe and expanded when the plan is executed.
+type PlanConfinement struct {
+	Unconfined bool     `json:"unconfined,omitempty"`
+	Writable   []string `json:"writable,omitempty"`
+	Readable   []string `json:"readable,omitempty"`
+	Reason     string   `json:"reason"`
+}
+
+// NewPlanConfinement captures the confinement section of a recipe for a plan
+func NewPlanConfinement(c *recipe.ConfinementSection) *PlanConfinement {
+	if c == nil {
+		return nil
+	}
+	return &PlanConfinement{
+		Unconfined: c.Unconfined,
+		Writable:   c.Writable,
+		Readable:   c.Readable,
+		Reason:     c.Reason,
+	}
+}
+
+// NewPlanVerify captures the verify section of a recipe for a plan
+func NewPlanVerify(v recipe.VerifySection) *PlanVerify {
+	if v.Command == "" {
+		return nil
+	}
+	return &PlanVerify{
+		Command:       v.Command,
+		Pattern:       v.Pattern,
+		PatternRegex:  v.PatternRegex,
+		VersionFormat: v.VersionFormat,
+		ExitCode:      v.ExitCode,
+		Tests:         v.Tests,
+	}
+}
+
+// Section converts the plan's verification back into a recipe verify section
+func (v *PlanVerify) Section() recipe.VerifySection {
+	if v == nil {
+		return recipe.VerifySection{}
+	}
+	return recipe.VerifySection{
+		Command:       v.Command,
+		Pattern:       v.Pattern,
+		PatternRegex:  v.PatternRegex,
+		VersionFormat: v.VersionFormat,
+		ExitCode:      v.ExitCode,
+		Tests:         v.Tests,
+	}
 }
 
-// Platform identifies the target operating system and architecture.
+// Platform identifies the target operating system and architecture, and on
+// Linux the C library and (for plans generated for the host) the distribution.
 type Platform struct {
-	OS   string `json:"os"`   // e.g., "linux", "darwin", "windows"
-	Arch string `json:"arch"` // e.g., "amd64", "arm64"
+	OS     string `json:"os"`               // e.g., "linux", "darwin", "windows"
+	Arch   string `json:"arch"`             // e.g., "amd64", "arm64"
+	Libc   string `json:"libc,omitempty"`   // e.g., "glibc", "musl" (Linux only)
+	Distro string `json:"distro,omitempty"` // os-release ID, e.g., "ubuntu", "alpine"
+}
+
+// String returns the platform tuple used in cache keys: "os-arch", with
+// "-libc" appended when the libc is known (e.g., "linux-amd64-musl").
+func (p Platform) String() string {
+	if p.Libc == "" {
+		return fmt.Sprintf("%s-%s", p.OS, p.Arch)
+	}
+	return fmt.Sprintf("%s-%s-%s", p.OS, p.Arch, p.Libc)
 }
 
 // ResolvedStep represents a single installation step with all templates
@@ -145,9 +221,14 @@ var ActionEvaluability = map[string]bool{
 	"npm_exec": true,
 
 	// System package actions - not evaluable (external package managers)
-	"apt_install":  false,
-	"yum_install":  false,
-	"brew_install": false,
+	"apt_install":    false,
+	"yum_install":    false,
+	"dnf_install":    false,
+	"apk_install":    false,
+	"pacman_install": false,
+	"zypper_install": false,
+	"brew_install":   false,
+	"system_package": false,
 
 	// Ecosystem package managers - not evaluable (external dependency resolution)
 	"npm_install":   false,
@@ -210,7 +291,7 @@ func (e *PlanValidationError) Error() string {
 // or a PlanValidationError containing all validation failures.
 //
 // Validation rules:
-//   - Platform must match the current OS and architecture
+//   - Platform must match the current OS and architecture (and libc, if recorded)
 //   - Decomposable (composite) actions must be decomposed at eval time
 //   - Unknown actions are rejected
 //   - The composite "download" action is rejected (use download_file primitive)
@@ -241,6 +322,13 @@ func ValidatePlan(plan *InstallationPlan) error {
 			Message: fmt.Sprintf("plan is for %s-%s, but this system is %s-%s",
 				plan.Platform.OS, plan.Platform.Arch, runtime.GOOS, runtime.GOARCH),
 		})
+	} else if plan.Platform.Libc != "" && plan.Platform.Libc != platform.Libc() {
+		errors = append(errors, ValidationError{
+			Step:   -1,
+			Action: "",
+			Message: fmt.Sprintf("plan is for %s, but this system uses %s",
+				plan.Platform.Libc, platform.Libc()),
+		})
 	}
 
 	// Validate each step
@@ -12,17 +12,23 @@ import (
 type PlanCacheKey struct {
 	Tool       string `json:"tool"`
 	Version    string `json:"version"`     // RESOLVED version (e.g., "14.1.0")
-	Platform   string `json:"platform"`    // e.g., "linux-amd64"
+	Platform   string `json:"platform"`    // e.g., "linux-amd64-glibc", "darwin-arm64"
 	RecipeHash string `json:"recipe_hash"` // SHA256 of recipe TOML content
 }
 
 // CacheKeyFor generates a cache key from version resolution output.
 // This should be called AFTER version resolution completes.
 func CacheKeyFor(tool, resolvedVersion, os, arch, recipeHash string) PlanCacheKey {
+	return CacheKeyForPlatform(tool, resolvedVersion, Platform{OS: os, Arch: arch}, recipeHash)
+}
+
+// CacheKeyForPlatform generates a cache key for a full platform tuple, so that
+// plans generated for different C libraries are not reused for each other.
+func CacheKeyForPlatform(tool, resolvedVersion string, platform Platform, recipeHash string) PlanCacheKey {
 	return PlanCacheKey{
 		Tool:       tool,
 		Version:    resolvedVersion,
-		Platform:   fmt.Sprintf("%s-%s", os, arch),
+		Platform:   platform.String(),
 		RecipeHash: recipeHash,
 	}
 }
@@ -30,7 +36,7 @@ func CacheKeyFor(tool, resolvedVersion, os, arch, recipeHash string) PlanCacheKe
 // ValidateCachedPlan checks if a cached plan is still valid for the given cache key.
 // Validation checks:
 //   - Format version matches current PlanFormatVersion
-//   - Platform (OS-Arch) matches the key
+//   - Platform (OS-Arch, and libc when the key has one) matches the key
 //   - Recipe hash matches (recipe hasn't changed)
 //
 // Returns nil if valid, or an error describing why the plan is invalid.
@@ -41,16 +47,21 @@ func ValidateCachedPlan(plan *InstallationPlan, key PlanCacheKey) error {
 			plan.FormatVersion, PlanFormatVersion)
 	}
 
-	// Parse platform from key (format: "os-arch")
-	keyOS, keyArch, found := strings.Cut(key.Platform, "-")
-	if !found {
+	// Parse platform from key (format: "os-arch" or "os-arch-libc")
+	parts := strings.SplitN(key.Platform, "-", 3)
+	if len(parts) < 2 {
 		return fmt.Errorf("invalid platform format in cache key: %q (expected \"os-arch\")", key.Platform)
 	}
+	keyPlatform := Platform{OS: parts[0], Arch: parts[1]}
+	if len(parts) == 3 {
+		keyPlatform.Libc = parts[2]
+	}
 
-	// Check platform
-	if plan.Platform.OS != keyOS || plan.Platform.Arch != keyArch {
-		return fmt.Errorf("plan platform %s-%s does not match %s",
-			plan.Platform.OS, plan.Platform.Arch, key.Platform)
+	// Check platform. Plans cached before libc was recorded were generated for
+	// this host, so a missing libc is accepted.
+	if plan.Platform.OS != keyPlatform.OS || plan.Platform.Arch != keyPlatform.Arch ||
+		(plan.Platform.Libc != "" && plan.Platform.Libc != keyPlatform.Libc) {
+		return fmt.Errorf("plan platform %s does not match %s", plan.Platform, key.Platform)
 	}
 
 	// Check recipe hash
@@ -148,6 +148,34 @@ func TestValidateCachedPlan(t *testing.T) {
 			key:     validKey,
 			wantErr: "recipe has changed since plan was generated",
 		},
+		{
+			name: "libc mismatch",
+			plan: &InstallationPlan{
+				FormatVersion: PlanFormatVersion,
+				Tool:          "ripgrep",
+				Version:       "14.1.0",
+				Platform:      Platform{OS: "linux", Arch: "amd64", Libc: "glibc"},
+				RecipeHash:    "abc123def456",
+			},
+			key: PlanCacheKey{
+				Tool:       "ripgrep",
+				Version:    "14.1.0",
+				Platform:   "linux-amd64-musl",
+				RecipeHash: "abc123def456",
+			},
+			wantErr: "plan platform linux-amd64-glibc does not match linux-amd64-musl",
+		},
+		{
+			name: "plan without libc matches key with libc",
+			plan: validPlan,
+			key: PlanCacheKey{
+				Tool:       "ripgrep",
+				Version:    "14.1.0",
+				Platform:   "linux-amd64-musl",
+				RecipeHash: "abc123def456",
+			},
+			wantErr: "",
+		},
 		{
 			name: "invalid platform format in key",
 			plan: validPlan,
@@ -248,3 +276,15 @@ func TestPlanCacheKey_ZeroValue(t *testing.T) {
 		t.Error("PlanCacheKey zero value should have empty strings")
 	}
 }
+
+func TestCacheKeyForPlatform(t *testing.T) {
+	key := CacheKeyForPlatform("ripgrep", "14.1.0", Platform{OS: "linux", Arch: "arm64", Libc: "musl"}, "abc")
+	if key.Platform != "linux-arm64-musl" {
+		t.Errorf("Platform = %q, want linux-arm64-musl", key.Platform)
+	}
+
+	key = CacheKeyForPlatform("ripgrep", "14.1.0", Platform{OS: "darwin", Arch: "arm64"}, "abc")
+	if key.Platform != "darwin-arm64" {
+		t.Errorf("Platform = %q, want darwin-arm64", key.Platform)
+	}
+}
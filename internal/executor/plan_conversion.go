@@ -28,14 +28,17 @@ func ToStoragePlan(plan *InstallationPlan) *install.Plan {
 		Tool:          plan.Tool,
 		Version:       plan.Version,
 		Platform: install.PlanPlatform{
-			OS:   plan.Platform.OS,
-			Arch: plan.Platform.Arch,
+			OS:     plan.Platform.OS,
+			Arch:   plan.Platform.Arch,
+			Libc:   plan.Platform.Libc,
+			Distro: plan.Platform.Distro,
 		},
 		GeneratedAt:   plan.GeneratedAt,
 		RecipeHash:    plan.RecipeHash,
 		RecipeSource:  plan.RecipeSource,
 		Deterministic: plan.Deterministic,
 		Steps:         steps,
+		Confinement:   (*install.PlanConfinement)(plan.Confinement),
 	}
 }
 
@@ -65,13 +68,16 @@ func FromStoragePlan(plan *install.Plan) *InstallationPlan {
 		Tool:          plan.Tool,
 		Version:       plan.Version,
 		Platform: Platform{
-			OS:   plan.Platform.OS,
-			Arch: plan.Platform.Arch,
+			OS:     plan.Platform.OS,
+			Arch:   plan.Platform.Arch,
+			Libc:   plan.Platform.Libc,
+			Distro: plan.Platform.Distro,
 		},
 		GeneratedAt:   plan.GeneratedAt,
 		RecipeHash:    plan.RecipeHash,
 		RecipeSource:  plan.RecipeSource,
 		Deterministic: plan.Deterministic,
 		Steps:         steps,
+		Confinement:   (*PlanConfinement)(plan.Confinement),
 	}
 }
@@ -371,3 +371,28 @@ func TestRoundTripConversion(t *testing.T) {
 		}
 	})
 }
+
+func TestStoragePlan_RoundTripConfinement(t *testing.T) {
+	plan := &InstallationPlan{
+		Tool:    "tool",
+		Version: "1.0.0",
+		Confinement: &PlanConfinement{
+			Writable: []string{"~/.cache/tool"},
+			Reason:   "build cache",
+		},
+	}
+
+	stored := ToStoragePlan(plan)
+	if stored.Confinement == nil || stored.Confinement.Reason != "build cache" {
+		t.Fatalf("ToStoragePlan dropped confinement: %+v", stored.Confinement)
+	}
+
+	restored := FromStoragePlan(stored)
+	if !reflect.DeepEqual(restored.Confinement, plan.Confinement) {
+		t.Errorf("Confinement = %+v, want %+v", restored.Confinement, plan.Confinement)
+	}
+
+	if ToStoragePlan(&InstallationPlan{Tool: "tool"}).Confinement != nil {
+		t.Error("expected nil confinement for plans without exceptions")
+	}
+}
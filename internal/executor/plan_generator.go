@@ -4,12 +4,15 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/config"
+	"github.com/tsukumogami/tsuku/internal/platform"
 	"github.com/tsukumogami/tsuku/internal/recipe"
 	"github.com/tsukumogami/tsuku/internal/version"
 )
@@ -20,6 +23,9 @@ type PlanConfig struct {
 	OS string
 	// Arch overrides the target architecture (default: runtime.GOARCH)
 	Arch string
+	// Libc overrides the target C library for Linux targets (default: the host's
+	// libc when targeting the host OS, otherwise glibc; ignored for other OSes)
+	Libc string
 	// RecipeSource indicates where the recipe came from ("registry" or file path)
 	RecipeSource string
 	// OnWarning is called when a non-evaluable step is encountered
@@ -58,16 +64,22 @@ func (e *Executor) GeneratePlan(ctx context.Context, cfg PlanConfig) (*Installat
 	if targetArch == "" {
 		targetArch = runtime.GOARCH
 	}
+	target := targetPlatform(targetOS, targetArch, cfg.Libc)
 	recipeSource := cfg.RecipeSource
 	if recipeSource == "" {
 		recipeSource = "unknown"
 	}
 
 	// Create version resolver
-	resolver := version.New()
+	resolver := e.newResolver()
 
 	// Resolve version from recipe
 	versionInfo, err := e.resolveVersionWith(ctx, resolver)
+	var resolverErr *version.ResolverError
+	if errors.As(err, &resolverErr) && resolverErr.Type == version.ErrTypeOffline {
+		// A version missing from the offline cache is not a "dev" recipe
+		return nil, err
+	}
 	if err != nil {
 		// Fall back to "dev" version for recipes without proper version sources
 		// This matches the behavior in Execute() for backward compatibility
@@ -92,6 +104,10 @@ func (e *Executor) GeneratePlan(ctx context.Context, cfg PlanConfig) (*Installat
 	// Get downloader for checksum computation
 	// Callers must provide a Downloader; if nil, no checksums will be computed
 	downloader := cfg.Downloader
+	if config.IsOffline() {
+		// Offline plans are computed from the download cache alone
+		downloader = actions.NewOfflineDownloader(cfg.DownloadCache)
+	}
 
 	// Build variable map for template expansion
 	vars := map[string]string{
@@ -99,6 +115,8 @@ func (e *Executor) GeneratePlan(ctx context.Context, cfg PlanConfig) (*Installat
 		"version_tag": versionInfo.Tag,
 		"os":          targetOS,
 		"arch":        targetArch,
+		"libc":        target.Libc,
+		"distro":      target.Distro,
 	}
 
 	// Create EvalContext for decomposition
@@ -108,6 +126,7 @@ func (e *Executor) GeneratePlan(ctx context.Context, cfg PlanConfig) (*Installat
 		VersionTag:    versionInfo.Tag,
 		OS:            targetOS,
 		Arch:          targetArch,
+		Libc:          target.Libc,
 		Recipe:        e.recipe,
 		Resolver:      resolver,
 		Downloader:    downloader,
@@ -116,12 +135,17 @@ func (e *Executor) GeneratePlan(ctx context.Context, cfg PlanConfig) (*Installat
 
 	// Process each step
 	var steps []ResolvedStep
-	for _, step := range e.recipe.Steps {
+	for i, step := range e.recipe.Steps {
 		// Check conditional execution against target platform
-		if !shouldExecuteForPlatform(step.When, targetOS, targetArch) {
+		if !matchesPlatform(step.When, target) {
 			continue
 		}
 
+		// Offline, fail before a decomposition reaches the network
+		if err := checkOfflineDecompose(e.recipe.Metadata.Name, versionInfo.Version, i, step.Action); err != nil {
+			return nil, err
+		}
+
 		// Resolve the step (handles decomposition of composites)
 		resolvedSteps, err := e.resolveStep(ctx, step, vars, downloader, cfg, evalCtx)
 		if err != nil {
@@ -155,22 +179,13 @@ func (e *Executor) GeneratePlan(ctx context.Context, cfg PlanConfig) (*Installat
 	planDeterministic := computeDeterministic(steps, dependencies)
 
 	// Capture verify section from recipe for plan execution
-	var verify *PlanVerify
-	if e.recipe.Verify.Command != "" {
-		verify = &PlanVerify{
-			Command: e.recipe.Verify.Command,
-			Pattern: e.recipe.Verify.Pattern,
-		}
-	}
+	verify := NewPlanVerify(e.recipe.Verify)
 
 	return &InstallationPlan{
 		FormatVersion: PlanFormatVersion,
 		Tool:          e.recipe.Metadata.Name,
 		Version:       versionInfo.Version,
-		Platform: Platform{
-			OS:   targetOS,
-			Arch: targetArch,
-		},
+		Platform:      target,
 		GeneratedAt:   time.Now().UTC(),
 		RecipeHash:    recipeHash,
 		RecipeSource:  recipeSource,
@@ -179,6 +194,7 @@ func (e *Executor) GeneratePlan(ctx context.Context, cfg PlanConfig) (*Installat
 		Steps:         steps,
 		Verify:        verify,
 		RecipeType:    string(e.recipe.Metadata.Type),
+		Confinement:   NewPlanConfinement(e.recipe.Confinement),
 	}, nil
 }
 
@@ -207,22 +223,60 @@ func computeRecipeHash(r interface{ ToTOML() ([]byte, error) }) (string, error)
 	return hex.EncodeToString(hash[:]), nil
 }
 
+// targetPlatform builds the platform a plan is generated for. The libc defaults to
+// the host's when targeting the host OS; the distro is only recorded when the
+// target is the host itself, since it can't be known for other systems.
+func targetPlatform(targetOS, targetArch, libc string) Platform {
+	p := Platform{OS: targetOS, Arch: targetArch}
+	if targetOS != "linux" {
+		return p
+	}
+	p.Libc = libc
+	if p.Libc == "" {
+		p.Libc = platform.DefaultLibc(targetOS)
+	}
+	if targetOS == runtime.GOOS && targetArch == runtime.GOARCH && p.Libc == platform.Libc() {
+		p.Distro = platform.HostDistro().ID
+	}
+	return p
+}
+
 // shouldExecuteForPlatform checks if a step should execute for the given platform.
 func shouldExecuteForPlatform(when map[string]string, targetOS, targetArch string) bool {
+	return matchesPlatform(when, Platform{OS: targetOS, Arch: targetArch})
+}
+
+// matchesPlatform checks a step's when clause against the full target platform,
+// including libc and distro conditions.
+func matchesPlatform(when map[string]string, target Platform) bool {
 	if len(when) == 0 {
 		return true
 	}
 
 	// Check OS condition
 	if osCondition, ok := when["os"]; ok {
-		if osCondition != targetOS {
+		if osCondition != target.OS {
 			return false
 		}
 	}
 
 	// Check arch condition
 	if archCondition, ok := when["arch"]; ok {
-		if archCondition != targetArch {
+		if archCondition != target.Arch {
+			return false
+		}
+	}
+
+	// Check libc condition (never matches non-Linux targets, which have no libc)
+	if libcCondition, ok := when["libc"]; ok {
+		if libcCondition != target.Libc {
+			return false
+		}
+	}
+
+	// Check distro condition (os-release ID)
+	if distroCondition, ok := when["distro"]; ok {
+		if distroCondition != target.Distro {
 			return false
 		}
 	}
@@ -653,6 +707,7 @@ func generateSingleDependencyPlan(
 	depCfg := PlanConfig{
 		OS:                 cfg.OS,
 		Arch:               cfg.Arch,
+		Libc:               cfg.Libc,
 		RecipeSource:       "dependency",
 		OnWarning:          cfg.OnWarning,
 		Downloader:         cfg.Downloader,
@@ -668,13 +723,7 @@ func generateSingleDependencyPlan(
 	}
 
 	// Build verify info if present
-	var verify *PlanVerify
-	if depRecipe.Verify.Command != "" {
-		verify = &PlanVerify{
-			Command: depRecipe.Verify.Command,
-			Pattern: depRecipe.Verify.Pattern,
-		}
-	}
+	verify := NewPlanVerify(depRecipe.Verify)
 
 	return &DependencyPlan{
 		Tool:         depName,
@@ -684,6 +733,7 @@ func generateSingleDependencyPlan(
 		Steps:        plan.Steps,
 		Verify:       verify,
 		RecipeType:   plan.RecipeType,
+		Confinement:  plan.Confinement,
 	}, nil
 }
 
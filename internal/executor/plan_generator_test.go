@@ -1881,3 +1881,98 @@ func TestGeneratePlan_WithPatches(t *testing.T) {
 		t.Error("expected apply_patch step in plan")
 	}
 }
+
+func TestMatchesPlatform_Libc(t *testing.T) {
+	muslLinux := Platform{OS: "linux", Arch: "amd64", Libc: "musl", Distro: "alpine"}
+	darwin := Platform{OS: "darwin", Arch: "arm64"}
+
+	tests := []struct {
+		name   string
+		when   map[string]string
+		target Platform
+		want   bool
+	}{
+		{"matching libc", map[string]string{"libc": "musl"}, muslLinux, true},
+		{"non-matching libc", map[string]string{"libc": "glibc"}, muslLinux, false},
+		{"libc never matches darwin", map[string]string{"libc": "musl"}, darwin, false},
+		{"os and libc", map[string]string{"os": "linux", "libc": "musl"}, muslLinux, true},
+		{"matching distro", map[string]string{"distro": "alpine"}, muslLinux, true},
+		{"non-matching distro", map[string]string{"distro": "ubuntu"}, muslLinux, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesPlatform(tt.when, tt.target); got != tt.want {
+				t.Errorf("matchesPlatform() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratePlan_Libc(t *testing.T) {
+	r := &recipe.Recipe{
+		Metadata: recipe.MetadataSection{
+			Name: "test-tool",
+		},
+		Steps: []recipe.Step{
+			{
+				Action: "chmod",
+				Params: map[string]interface{}{"files": []interface{}{"tool-{libc}"}},
+			},
+			{
+				Action: "chmod",
+				Params: map[string]interface{}{"files": []interface{}{"musl-only"}},
+				When:   map[string]string{"libc": "musl"},
+			},
+		},
+	}
+
+	exec, err := New(r)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer exec.Cleanup()
+
+	plan, err := exec.GeneratePlan(context.Background(), PlanConfig{
+		OS:           "linux",
+		Arch:         "amd64",
+		Libc:         "musl",
+		RecipeSource: "test",
+	})
+	if err != nil {
+		t.Fatalf("GeneratePlan() error: %v", err)
+	}
+
+	if plan.Platform.Libc != "musl" {
+		t.Errorf("Platform.Libc = %q, want musl", plan.Platform.Libc)
+	}
+	if len(plan.Steps) != 2 {
+		t.Fatalf("len(Steps) = %d, want 2", len(plan.Steps))
+	}
+	files, _ := plan.Steps[0].Params["files"].([]interface{})
+	if len(files) != 1 || files[0] != "tool-musl" {
+		t.Errorf("files = %v, want [tool-musl]", plan.Steps[0].Params["files"])
+	}
+
+	exec2, err := New(r)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	defer exec2.Cleanup()
+
+	plan2, err := exec2.GeneratePlan(context.Background(), PlanConfig{
+		OS:           "darwin",
+		Arch:         "arm64",
+		Libc:         "musl", // ignored for non-Linux targets
+		RecipeSource: "test",
+	})
+	if err != nil {
+		t.Fatalf("GeneratePlan() for darwin error: %v", err)
+	}
+	if plan2.Platform.Libc != "" {
+		t.Errorf("darwin Platform.Libc = %q, want empty", plan2.Platform.Libc)
+	}
+	if len(plan2.Steps) != 1 {
+		t.Errorf("darwin len(Steps) = %d, want 1", len(plan2.Steps))
+	}
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tsukumogami/tsuku/internal/install"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// ReproducibilityRuns is the number of times CheckReproducible executes a plan
+const ReproducibilityRuns = 2
+
+// ReproducibilityReport is the result of executing a plan several times and
+// comparing the install trees it produced.
+type ReproducibilityReport struct {
+	Tool        string
+	Version     string
+	Digests     []string                    // Normalized tree digest of each run
+	Differences []ReproducibilityDifference // Paths whose contents differ between runs
+}
+
+// ReproducibilityDifference describes a path that differed between two runs
+type ReproducibilityDifference struct {
+	Path   string
+	Detail string // How the entry changed from the first run to the second
+}
+
+// Reproducible reports whether every run produced the same tree.
+func (r *ReproducibilityReport) Reproducible() bool {
+	return len(r.Differences) == 0
+}
+
+// Digest returns the tree digest of the first run.
+func (r *ReproducibilityReport) Digest() string {
+	if len(r.Digests) == 0 {
+		return ""
+	}
+	return r.Digests[0]
+}
+
+// CheckReproducible executes plan ReproducibilityRuns times, each into its own
+// temporary $TSUKU_HOME, and compares the resulting install trees by their
+// normalized manifests (see install.ComputeTreeManifest). Downloads are served
+// from downloadCacheDir when set, since they are verified against the plan
+// checksums anyway; the binary and compiler caches are never used, as they
+// would hide differences between builds.
+func CheckReproducible(ctx context.Context, plan *InstallationPlan, downloadCacheDir string) (*ReproducibilityReport, error) {
+	report := &ReproducibilityReport{Tool: plan.Tool, Version: plan.Version}
+
+	var manifests []install.TreeManifest
+	for run := 1; run <= ReproducibilityRuns; run++ {
+		fmt.Printf("Build %d/%d of %s@%s\n", run, ReproducibilityRuns, plan.Tool, plan.Version)
+		manifest, err := executeIsolated(ctx, plan, downloadCacheDir)
+		if err != nil {
+			return nil, fmt.Errorf("build %d failed: %w", run, err)
+		}
+		manifests = append(manifests, manifest)
+		report.Digests = append(report.Digests, manifest.Digest())
+	}
+
+	for _, path := range install.DiffTreeManifests(manifests[0], manifests[1]) {
+		report.Differences = append(report.Differences, ReproducibilityDifference{
+			Path:   path,
+			Detail: install.DescribeTreeDifference(manifests[0], manifests[1], path),
+		})
+	}
+	return report, nil
+}
+
+// executeIsolated runs plan with a fresh temporary $TSUKU_HOME and returns
+// the manifest of the install tree it produced
+func executeIsolated(ctx context.Context, plan *InstallationPlan, downloadCacheDir string) (install.TreeManifest, error) {
+	home, err := os.MkdirTemp("", "tsuku-reproducible-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary home: %w", err)
+	}
+	defer os.RemoveAll(home)
+
+	exec, err := New(&recipe.Recipe{
+		Metadata: recipe.MetadataSection{
+			Name: plan.Tool,
+			Type: plan.RecipeType,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor: %w", err)
+	}
+	defer exec.Cleanup()
+
+	exec.SetToolsDir(filepath.Join(home, "tools"))
+	exec.SetLibsDir(filepath.Join(home, "libs"))
+	if downloadCacheDir != "" {
+		exec.SetDownloadCacheDir(downloadCacheDir)
+	}
+
+	if err := exec.ExecutePlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	return install.ComputeBuildManifest(exec.WorkDir(), home)
+}
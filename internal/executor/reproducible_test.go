@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/install"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+func runCommandPlan(command string) *InstallationPlan {
+	return &InstallationPlan{
+		FormatVersion: PlanFormatVersion,
+		Tool:          "test-tool",
+		Version:       "1.0.0",
+		Platform:      Platform{OS: runtime.GOOS, Arch: runtime.GOARCH},
+		Steps: []ResolvedStep{
+			{Action: "run_command", Params: map[string]interface{}{"command": command}},
+		},
+	}
+}
+
+func TestCheckReproducible_IdenticalBuilds(t *testing.T) {
+	// The install directory differs between runs but is normalized away
+	plan := runCommandPlan("mkdir -p {install_dir}/bin && echo 'prefix={install_dir}' > {install_dir}/bin/test-tool && chmod +x {install_dir}/bin/test-tool")
+
+	report, err := CheckReproducible(context.Background(), plan, "")
+	if err != nil {
+		t.Fatalf("CheckReproducible() error = %v", err)
+	}
+	if !report.Reproducible() {
+		t.Errorf("Reproducible() = false, differences: %v", report.Differences)
+	}
+	if len(report.Digests) != ReproducibilityRuns || report.Digests[0] != report.Digests[1] {
+		t.Errorf("Digests = %v, want %d identical digests", report.Digests, ReproducibilityRuns)
+	}
+}
+
+func TestCheckReproducible_ReportsDifferingPaths(t *testing.T) {
+	plan := runCommandPlan("mkdir -p {install_dir}/share && echo stable > {install_dir}/share/stable && date +%s%N > {install_dir}/share/stamp")
+
+	report, err := CheckReproducible(context.Background(), plan, "")
+	if err != nil {
+		t.Fatalf("CheckReproducible() error = %v", err)
+	}
+	if report.Reproducible() {
+		t.Fatal("Reproducible() = true for a build that embeds a timestamp")
+	}
+	if len(report.Differences) != 1 || report.Differences[0].Path != "share/stamp" {
+		t.Errorf("Differences = %v, want only share/stamp", report.Differences)
+	}
+}
+
+func TestCheckReproducible_MatchesInstalledDigest(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+
+	// The build embeds its temporary install prefix
+	plan := runCommandPlan("mkdir -p {install_dir}/bin && echo 'prefix={install_dir}' > {install_dir}/bin/test-tool && chmod +x {install_dir}/bin/test-tool")
+
+	exec, err := New(&recipe.Recipe{Metadata: recipe.MetadataSection{Name: plan.Tool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exec.Cleanup()
+	exec.SetToolsDir(cfg.ToolsDir)
+	exec.SetLibsDir(cfg.LibsDir)
+	if err := exec.ExecutePlan(context.Background(), plan); err != nil {
+		t.Fatalf("ExecutePlan() error = %v", err)
+	}
+
+	mgr := install.New(cfg)
+	opts := install.DefaultInstallOptions()
+	opts.Binaries = []string{"bin/test-tool"}
+	opts.Plan = ToStoragePlan(plan)
+	if err := mgr.InstallWithOptions(plan.Tool, plan.Version, exec.WorkDir(), opts); err != nil {
+		t.Fatalf("InstallWithOptions() error = %v", err)
+	}
+	ts, err := mgr.GetState().GetToolState(plan.Tool)
+	if err != nil || ts == nil {
+		t.Fatalf("GetToolState() = %v, %v", ts, err)
+	}
+	recorded := ts.Versions[plan.Version].TreeDigest
+	if recorded == "" {
+		t.Fatal("no tree digest recorded")
+	}
+
+	report, err := CheckReproducible(context.Background(), plan, "")
+	if err != nil {
+		t.Fatalf("CheckReproducible() error = %v", err)
+	}
+	if report.Digest() != recorded {
+		t.Errorf("reproducible digest %s differs from the installed digest %s (work dir %s)",
+			report.Digest(), recorded, filepath.Base(exec.WorkDir()))
+	}
+}
@@ -1,12 +1,20 @@
 package httputil
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/tsukumogami/tsuku/internal/config"
 )
 
+// ErrOffline is returned for connections attempted while offline mode
+// (TSUKU_OFFLINE) is enabled.
+var ErrOffline = errors.New("network access is disabled in offline mode")
+
 // ClientOptions configures the secure HTTP client.
 type ClientOptions struct {
 	// Timeout is the overall request timeout. Default: 30s.
@@ -89,10 +97,10 @@ func NewSecureClient(opts ClientOptions) *http.Client {
 		Timeout: opts.Timeout,
 		Transport: &http.Transport{
 			DisableCompression: disableCompression,
-			DialContext: (&net.Dialer{
+			DialContext: offlineDialer((&net.Dialer{
 				Timeout:   opts.DialTimeout,
 				KeepAlive: 30 * time.Second,
-			}).DialContext,
+			}).DialContext),
 			TLSHandshakeTimeout:   opts.TLSHandshakeTimeout,
 			ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
 			ExpectContinueTimeout: 1 * time.Second,
@@ -103,6 +111,17 @@ func NewSecureClient(opts ClientOptions) *http.Client {
 	}
 }
 
+// offlineDialer refuses connections while offline mode is enabled, so
+// callers that missed an offline check fail fast instead of timing out.
+func offlineDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if config.IsOffline() {
+			return nil, ErrOffline
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
 // makeRedirectChecker creates a redirect validation function.
 func makeRedirectChecker(maxRedirects int) func(req *http.Request, via []*http.Request) error {
 	return func(req *http.Request, via []*http.Request) error {
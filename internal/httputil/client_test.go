@@ -1,6 +1,7 @@
 package httputil
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -168,3 +169,24 @@ func TestDefaultOptions(t *testing.T) {
 		t.Error("Expected default EnableCompression false")
 	}
 }
+
+func TestNewSecureClient_Offline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("TSUKU_OFFLINE", "1")
+	client := NewSecureClient(ClientOptions{})
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Get() error = %v, want ErrOffline", err)
+	}
+
+	t.Setenv("TSUKU_OFFLINE", "")
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() online error = %v", err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,191 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// binaryPath returns the path, relative to the tool directory, of the binary
+// that tools/current/<link> runs. Tools without declared binaries fall back to
+// the legacy layout: the tool directory root for symlinks, bin/ for wrappers.
+func binaryPath(toolName string, binaries []string, link string, wrapper bool) string {
+	for _, b := range binaries {
+		if filepath.Base(b) == link {
+			return b
+		}
+	}
+	if wrapper {
+		return filepath.Join("bin", toolName)
+	}
+	return toolName
+}
+
+// linkBinaries records the tool as a provider of its binaries and creates the
+// tools/current entries it owns: wrapper scripts when it has runtime
+// dependencies, symlinks otherwise. Binaries owned by another tool are left
+// alone and returned, mapped to their owner.
+func (m *Manager) linkBinaries(name, version string, binaries []string, runtimeDeps map[string]string) (map[string]string, error) {
+	conflicts, err := m.state.RegisterBinaries(name, linkNames(name, binaries))
+	if err != nil {
+		return nil, fmt.Errorf("failed to record binary owners: %w", err)
+	}
+
+	for _, link := range linkNames(name, binaries) {
+		if _, taken := conflicts[link]; taken {
+			continue
+		}
+		if err := m.createLink(name, version, binaries, link, runtimeDeps); err != nil {
+			return nil, err
+		}
+	}
+	return conflicts, nil
+}
+
+// createLink writes tools/current/<link> for one binary of a tool version
+func (m *Manager) createLink(name, version string, binaries []string, link string, runtimeDeps map[string]string) error {
+	if len(runtimeDeps) > 0 {
+		if err := m.createBinaryWrapper(name, version, binaryPath(name, binaries, link, true), runtimeDeps); err != nil {
+			return fmt.Errorf("failed to create wrapper for %s: %w", link, err)
+		}
+		return nil
+	}
+	if err := m.createBinarySymlink(name, version, binaryPath(name, binaries, link, false)); err != nil {
+		return fmt.Errorf("failed to create symlink for %s: %w", link, err)
+	}
+	return nil
+}
+
+// linkProvider points tools/current/<link> at the active version of tool,
+// recreating the wrapper or symlink its install would have written.
+func (m *Manager) linkProvider(tool, link string) error {
+	state, err := m.state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	ts, ok := state.Installed[tool]
+	if !ok || ts.ActiveVersion == "" {
+		return fmt.Errorf("tool %q is not installed", tool)
+	}
+
+	runtimeDeps := make(map[string]string)
+	for _, dep := range ts.RuntimeDependencies {
+		if depState, ok := state.Installed[dep]; ok && depState.ActiveVersion != "" {
+			runtimeDeps[dep] = depState.ActiveVersion
+		}
+	}
+
+	return m.createLink(tool, ts.ActiveVersion, ts.Versions[ts.ActiveVersion].Binaries, link, runtimeDeps)
+}
+
+// releaseBinaries gives the tools/current entries owned by a tool that is
+// being removed back to the previous provider, or deletes them when no other
+// tool provides the binary. Entries owned by other tools are untouched.
+func (m *Manager) releaseBinaries(name string, toolState *ToolState) error {
+	state, err := m.state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	// Binaries without recorded owners predate ownership tracking; remove
+	// their entries as before
+	binaries := append([]string{}, toolState.Binaries...)
+	for _, vs := range toolState.Versions {
+		binaries = append(binaries, vs.Binaries...)
+	}
+	for _, link := range linkNames(name, binaries) {
+		if _, tracked := state.Alternatives[link]; !tracked {
+			_ = os.Remove(m.config.CurrentSymlink(link)) // Ignore errors - symlink may not exist
+		}
+	}
+
+	released, err := m.state.UnregisterBinaries(name)
+	if err != nil {
+		return fmt.Errorf("failed to update binary owners: %w", err)
+	}
+	return m.restoreReleased(released)
+}
+
+// releaseVersionBinaries releases the binaries that only the removed version
+// of a tool shipped, after that version was deleted from state. Binaries the
+// remaining versions still provide stay registered to the tool.
+func (m *Manager) releaseVersionBinaries(name string, removed VersionState, remaining map[string]VersionState) error {
+	kept := make(map[string]bool)
+	for _, vs := range remaining {
+		for _, link := range linkNames(name, vs.Binaries) {
+			kept[link] = true
+		}
+	}
+	var links []string
+	for _, link := range linkNames(name, removed.Binaries) {
+		if !kept[link] {
+			links = append(links, link)
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	state, err := m.state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	for _, link := range links {
+		if _, tracked := state.Alternatives[link]; !tracked {
+			_ = os.Remove(m.config.CurrentSymlink(link)) // Ignore errors - symlink may not exist
+		}
+	}
+
+	released, err := m.state.UnregisterBinaryNames(name, links)
+	if err != nil {
+		return fmt.Errorf("failed to update binary owners: %w", err)
+	}
+	return m.restoreReleased(released)
+}
+
+// restoreReleased hands each released tools/current entry to the provider
+// that took it over, or deletes it when no other tool provides the binary
+func (m *Manager) restoreReleased(released map[string]string) error {
+	links := make([]string, 0, len(released))
+	for link := range released {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+
+	for _, link := range links {
+		owner := released[link]
+		if owner == "" {
+			_ = os.Remove(m.config.CurrentSymlink(link))
+			continue
+		}
+		if err := m.linkProvider(owner, link); err != nil {
+			return fmt.Errorf("failed to restore %s from %s: %w", link, owner, err)
+		}
+		fmt.Printf("🔗 Restored %s from %s\n", link, owner)
+	}
+	return nil
+}
+
+// SetAlternative makes tool the provider of tools/current/<binary>.
+func (m *Manager) SetAlternative(binary, tool string) error {
+	if err := m.state.SetAlternative(binary, tool); err != nil {
+		return err
+	}
+	return m.linkProvider(tool, binary)
+}
+
+// printBinaryConflicts reports binaries that stayed with another tool
+func printBinaryConflicts(name string, conflicts map[string]string) {
+	links := make([]string, 0, len(conflicts))
+	for link := range conflicts {
+		links = append(links, link)
+	}
+	sort.Strings(links)
+
+	for _, link := range links {
+		owner := conflicts[link]
+		fmt.Printf("⚠️  %s is also provided by %s, which keeps %s. Run 'tsuku alternatives set %s %s' to switch.\n",
+			link, owner, link, link, name)
+	}
+}
@@ -0,0 +1,226 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+// installFakeTool installs a tool whose work dir contains the given binaries
+func installFakeTool(t *testing.T, mgr *Manager, name, version string, binaries ...string) {
+	t.Helper()
+	workDir, workCleanup := testutil.TempDir(t)
+	defer workCleanup()
+
+	for _, b := range binaries {
+		path := filepath.Join(workDir, ".install", b)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("#!/bin/sh\necho "+name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := DefaultInstallOptions()
+	opts.Binaries = binaries
+	if err := mgr.InstallWithOptions(name, version, workDir, opts); err != nil {
+		t.Fatalf("InstallWithOptions(%s) error = %v", name, err)
+	}
+}
+
+func TestInstallWithOptions_BinaryConflictKeepsOwner(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	installFakeTool(t, mgr, "golang", "1.22.0", "bin/go", "bin/gofmt")
+	installFakeTool(t, mgr, "go", "1.23.1", "bin/go")
+
+	target, _ := os.Readlink(cfg.CurrentSymlink("go"))
+	if want := filepath.Join(cfg.ToolDir("golang", "1.22.0"), "bin", "go"); target != want {
+		t.Errorf("current/go -> %s, want %s (first provider keeps it)", target, want)
+	}
+
+	state, err := mgr.GetState().Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	alt := state.Alternatives["go"]
+	if alt.Active != "golang" || len(alt.Providers) != 2 {
+		t.Errorf("go alternatives = %+v", alt)
+	}
+}
+
+func TestManager_SetAlternative(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	installFakeTool(t, mgr, "golang", "1.22.0", "bin/go")
+	installFakeTool(t, mgr, "go", "1.23.1", "bin/go")
+
+	if err := mgr.SetAlternative("go", "go"); err != nil {
+		t.Fatalf("SetAlternative() error = %v", err)
+	}
+	target, _ := os.Readlink(cfg.CurrentSymlink("go"))
+	if want := filepath.Join(cfg.ToolDir("go", "1.23.1"), "bin", "go"); target != want {
+		t.Errorf("current/go -> %s, want %s", target, want)
+	}
+
+	if err := mgr.SetAlternative("go", "zig"); err == nil {
+		t.Error("SetAlternative() with a tool that doesn't provide the binary should fail")
+	}
+	if err := mgr.SetAlternative("gcc", "go"); err == nil {
+		t.Error("SetAlternative() for an unknown binary should fail")
+	}
+}
+
+func TestRemoveAllVersions_RestoresPreviousProvider(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	installFakeTool(t, mgr, "golang", "1.22.0", "bin/go", "bin/gofmt")
+	installFakeTool(t, mgr, "go", "1.23.1", "bin/go")
+	if err := mgr.SetAlternative("go", "go"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removing the owner hands the link back to the other provider
+	if err := mgr.RemoveAllVersions("go"); err != nil {
+		t.Fatalf("RemoveAllVersions(go) error = %v", err)
+	}
+	target, err := os.Readlink(cfg.CurrentSymlink("go"))
+	if err != nil {
+		t.Fatalf("current/go was removed instead of restored: %v", err)
+	}
+	if want := filepath.Join(cfg.ToolDir("golang", "1.22.0"), "bin", "go"); target != want {
+		t.Errorf("current/go -> %s, want %s", target, want)
+	}
+
+	// Removing the last provider removes the link
+	if err := mgr.RemoveAllVersions("golang"); err != nil {
+		t.Fatalf("RemoveAllVersions(golang) error = %v", err)
+	}
+	for _, link := range []string{"go", "gofmt"} {
+		if _, err := os.Lstat(cfg.CurrentSymlink(link)); !os.IsNotExist(err) {
+			t.Errorf("current/%s still exists", link)
+		}
+	}
+	state, _ := mgr.GetState().Load()
+	if len(state.Alternatives) != 0 {
+		t.Errorf("Alternatives = %+v, want empty", state.Alternatives)
+	}
+}
+
+func TestRemoveAllVersions_KeepsOtherOwnersLink(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	installFakeTool(t, mgr, "golang", "1.22.0", "bin/go")
+	installFakeTool(t, mgr, "go", "1.23.1", "bin/go")
+
+	// go never owned current/go, so removing it leaves golang's link alone
+	if err := mgr.RemoveAllVersions("go"); err != nil {
+		t.Fatalf("RemoveAllVersions() error = %v", err)
+	}
+	target, err := os.Readlink(cfg.CurrentSymlink("go"))
+	if err != nil {
+		t.Fatalf("current/go removed: %v", err)
+	}
+	if want := filepath.Join(cfg.ToolDir("golang", "1.22.0"), "bin", "go"); target != want {
+		t.Errorf("current/go -> %s, want %s", target, want)
+	}
+}
+
+func TestRemoveVersion_ReleasesOnlyRemovedVersionBinaries(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	installFakeTool(t, mgr, "golang", "1.21.0", "bin/go", "bin/godoc")
+	installFakeTool(t, mgr, "golang", "1.22.0", "bin/go")
+
+	if err := mgr.RemoveVersion("golang", "1.21.0"); err != nil {
+		t.Fatalf("RemoveVersion() error = %v", err)
+	}
+
+	state, err := mgr.GetState().Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alt := state.Alternatives["go"]; alt.Active != "golang" {
+		t.Errorf("go alternatives = %+v, want golang to keep it", alt)
+	}
+	if _, err := os.Lstat(cfg.CurrentSymlink("go")); err != nil {
+		t.Errorf("current/go removed: %v", err)
+	}
+	if alt, ok := state.Alternatives["godoc"]; ok {
+		t.Errorf("godoc alternatives = %+v, want it released", alt)
+	}
+	if _, err := os.Lstat(cfg.CurrentSymlink("godoc")); !os.IsNotExist(err) {
+		t.Error("current/godoc still exists")
+	}
+}
+
+func TestRemove_KeepsRemainingVersionBinaries(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	installFakeTool(t, mgr, "golang", "1.21.0", "bin/go")
+	installFakeTool(t, mgr, "golang", "1.22.0", "bin/go")
+
+	if err := mgr.Remove("golang"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	ts, err := mgr.GetState().GetToolState("golang")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts == nil || len(ts.Versions) != 1 {
+		t.Fatalf("golang state = %+v, want one remaining version", ts)
+	}
+	state, _ := mgr.GetState().Load()
+	if alt := state.Alternatives["go"]; alt.Active != "golang" {
+		t.Errorf("go alternatives = %+v, want golang to keep it", alt)
+	}
+	target, err := os.Readlink(cfg.CurrentSymlink("go"))
+	if err != nil {
+		t.Fatalf("current/go removed: %v", err)
+	}
+	if want := filepath.Join(cfg.ToolDir("golang", ts.ActiveVersion), "bin", "go"); target != want {
+		t.Errorf("current/go -> %s, want %s", target, want)
+	}
+}
+
+func TestStateManager_UnregisterBinaries_SkipsHiddenProviders(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	sm := NewStateManager(cfg)
+
+	for _, tool := range []string{"a", "b", "c"} {
+		if err := sm.UpdateTool(tool, func(ts *ToolState) { ts.ActiveVersion = "1.0"; ts.IsHidden = tool == "b" }); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sm.RegisterBinaries(tool, []string{"tool"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sm.SetAlternative("tool", "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	released, err := sm.UnregisterBinaries("c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if released["tool"] != "a" {
+		t.Errorf("released = %v, want tool taken over by a", released)
+	}
+}
@@ -0,0 +1,230 @@
+package install
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LibraryVersion identifies an installed library version
+type LibraryVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// RemovalPlan lists the tools and library versions a cascading removal or
+// autoremove will delete.
+type RemovalPlan struct {
+	Tools     []string         `json:"tools"`
+	Libraries []LibraryVersion `json:"libraries"`
+}
+
+// IsEmpty reports whether the plan removes nothing
+func (p *RemovalPlan) IsEmpty() bool {
+	return len(p.Tools) == 0 && len(p.Libraries) == 0
+}
+
+// PlanRemoval computes what to delete when the named tools are removed: the
+// tools themselves plus every tool and library version that is no longer
+// reachable from an explicitly installed tool. With no names it returns the
+// current orphans, which is what autoremove deletes.
+//
+// A tool is kept if an explicit tool depends on it, directly or transitively,
+// through its install or runtime dependencies or RequiredBy. A library version
+// is kept if a kept tool is listed in its UsedBy, or if none of its UsedBy
+// entries names an installed tool and a kept tool depends on the library by
+// name (usage that predates UsedBy tracking).
+func PlanRemoval(state *State, names []string) (*RemovalPlan, error) {
+	removing := make(map[string]bool)
+	for _, name := range names {
+		if _, ok := state.Installed[name]; !ok {
+			return nil, fmt.Errorf("tool %q is not installed", name)
+		}
+		removing[name] = true
+	}
+
+	graph := BuildGraph(state)
+	deps := make(map[string][]string)
+	for _, e := range graph.Edges {
+		deps[e.From] = append(deps[e.From], e.To)
+	}
+
+	kept := make(map[string]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		if kept[id] || removing[id] {
+			return
+		}
+		kept[id] = true
+		for _, dep := range deps[id] {
+			visit(dep)
+		}
+	}
+	for name, ts := range state.Installed {
+		if ts.IsExplicit {
+			visit(name)
+		}
+	}
+
+	plan := &RemovalPlan{Tools: []string{}, Libraries: []LibraryVersion{}}
+	for name := range state.Installed {
+		if !kept[name] {
+			plan.Tools = append(plan.Tools, name)
+		}
+	}
+	sort.Strings(plan.Tools)
+
+	for libName, versions := range state.Libs {
+		for libVersion, ls := range versions {
+			if kept[LibraryNodeID(libName, libVersion)] {
+				continue
+			}
+			if !hasInstalledUser(state, ls) && kept[libName] {
+				continue
+			}
+			plan.Libraries = append(plan.Libraries, LibraryVersion{Name: libName, Version: libVersion})
+		}
+	}
+	sort.Slice(plan.Libraries, func(i, j int) bool {
+		if plan.Libraries[i].Name != plan.Libraries[j].Name {
+			return plan.Libraries[i].Name < plan.Libraries[j].Name
+		}
+		return plan.Libraries[i].Version < plan.Libraries[j].Version
+	})
+
+	return plan, nil
+}
+
+// hasInstalledUser reports whether any UsedBy entry of a library version names
+// an installed tool
+func hasInstalledUser(state *State, ls LibraryVersionState) bool {
+	for _, user := range ls.UsedBy {
+		if toolForNameVersion(state, user) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanRemoval computes a RemovalPlan against the current state. See PlanRemoval.
+func (m *Manager) PlanRemoval(names ...string) (*RemovalPlan, error) {
+	state, err := m.state.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load state: %w", err)
+	}
+	return PlanRemoval(state, names)
+}
+
+// RemovalSize returns the disk space, in bytes, that applying plan reclaims
+func (m *Manager) RemovalSize(plan *RemovalPlan) int64 {
+	state, err := m.state.Load()
+	if err != nil {
+		return 0
+	}
+
+	var total int64
+	for _, name := range plan.Tools {
+		ts := state.Installed[name]
+		versions := make(map[string]bool)
+		for v := range ts.Versions {
+			versions[v] = true
+		}
+		if ts.Version != "" {
+			versions[ts.Version] = true
+		}
+		for v := range versions {
+			total += dirSize(m.config.ToolDir(name, v))
+		}
+	}
+	for _, lib := range plan.Libraries {
+		total += dirSize(m.config.LibDir(lib.Name, lib.Version))
+	}
+	return total
+}
+
+// ApplyRemoval removes the tools and library versions in plan, then drops
+// references to removed tools from the RequiredBy lists of remaining tools and
+// the UsedBy lists of remaining library versions.
+func (m *Manager) ApplyRemoval(plan *RemovalPlan) error {
+	state, err := m.state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+
+	// Remember the "<tool>-<version>" names used in library UsedBy lists
+	removedUsers := make(map[string]bool)
+	for _, name := range plan.Tools {
+		ts := state.Installed[name]
+		for v := range ts.Versions {
+			removedUsers[name+"-"+v] = true
+		}
+		if ts.Version != "" {
+			removedUsers[name+"-"+ts.Version] = true
+		}
+
+		if err := m.RemoveAllVersions(name); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+	}
+
+	for _, lib := range plan.Libraries {
+		if err := os.RemoveAll(m.config.LibDir(lib.Name, lib.Version)); err != nil {
+			return fmt.Errorf("failed to remove library %s-%s: %w", lib.Name, lib.Version, err)
+		}
+		if err := m.state.RemoveLibraryVersion(lib.Name, lib.Version); err != nil {
+			return fmt.Errorf("failed to update library state: %w", err)
+		}
+	}
+
+	removedTools := make(map[string]bool)
+	for _, name := range plan.Tools {
+		removedTools[name] = true
+	}
+
+	// Reload: removal above rewrote state
+	state, err = m.state.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	for name, ts := range state.Installed {
+		for _, parent := range ts.RequiredBy {
+			if removedTools[parent] {
+				if err := m.state.RemoveRequiredBy(name, parent); err != nil {
+					return fmt.Errorf("failed to update dependency state for %s: %w", name, err)
+				}
+			}
+		}
+	}
+	for libName, versions := range state.Libs {
+		for libVersion, ls := range versions {
+			for _, user := range ls.UsedBy {
+				if removedUsers[user] {
+					if err := m.state.RemoveLibraryUsedBy(libName, libVersion, user); err != nil {
+						return fmt.Errorf("failed to update library state for %s: %w", libName, err)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// dirSize returns the total size of regular files under dir
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
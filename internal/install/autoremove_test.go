@@ -0,0 +1,168 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+func autoremoveState() *State {
+	return &State{
+		Installed: map[string]ToolState{
+			"serverless": {
+				ActiveVersion:       "3.0.0",
+				Versions:            map[string]VersionState{"3.0.0": {}},
+				IsExplicit:          true,
+				InstallDependencies: []string{"nodejs"},
+				RuntimeDependencies: []string{"nodejs"},
+			},
+			"nodejs": {
+				ActiveVersion:         "20.0.0",
+				Versions:              map[string]VersionState{"20.0.0": {}},
+				IsHidden:              true,
+				IsExecutionDependency: true,
+				RequiredBy:            []string{"serverless", "prettier"},
+			},
+			"prettier": {
+				ActiveVersion:       "3.1.0",
+				Versions:            map[string]VersionState{"3.1.0": {}},
+				IsExplicit:          true,
+				InstallDependencies: []string{"nodejs"},
+			},
+			"ruby": {
+				ActiveVersion: "3.4.0",
+				Versions:      map[string]VersionState{"3.4.0": {}},
+				RequiredBy:    []string{"jekyll"},
+			},
+		},
+		Libs: map[string]map[string]LibraryVersionState{
+			"libyaml": {
+				"0.2.5": {UsedBy: []string{"ruby-3.4.0"}},
+			},
+			"openssl": {
+				"3.0.0": {UsedBy: []string{}},
+				"3.1.0": {UsedBy: []string{"prettier-3.1.0"}},
+			},
+		},
+	}
+}
+
+func TestPlanRemoval_Orphans(t *testing.T) {
+	plan, err := PlanRemoval(autoremoveState(), nil)
+	if err != nil {
+		t.Fatalf("PlanRemoval() error = %v", err)
+	}
+	// ruby's only dependent (jekyll) is gone, so ruby and the library it uses are orphans
+	if want := []string{"ruby"}; !reflect.DeepEqual(plan.Tools, want) {
+		t.Errorf("Tools = %v, want %v", plan.Tools, want)
+	}
+	want := []LibraryVersion{{"libyaml", "0.2.5"}, {"openssl", "3.0.0"}}
+	if !reflect.DeepEqual(plan.Libraries, want) {
+		t.Errorf("Libraries = %v, want %v", plan.Libraries, want)
+	}
+}
+
+func TestPlanRemoval_Cascade(t *testing.T) {
+	state := autoremoveState()
+
+	plan, err := PlanRemoval(state, []string{"serverless"})
+	if err != nil {
+		t.Fatalf("PlanRemoval() error = %v", err)
+	}
+	// nodejs is still needed by prettier
+	if want := []string{"ruby", "serverless"}; !reflect.DeepEqual(plan.Tools, want) {
+		t.Errorf("Tools = %v, want %v", plan.Tools, want)
+	}
+
+	plan, err = PlanRemoval(state, []string{"serverless", "prettier"})
+	if err != nil {
+		t.Fatalf("PlanRemoval() error = %v", err)
+	}
+	if want := []string{"nodejs", "prettier", "ruby", "serverless"}; !reflect.DeepEqual(plan.Tools, want) {
+		t.Errorf("Tools = %v, want %v", plan.Tools, want)
+	}
+	if len(plan.Libraries) != 3 {
+		t.Errorf("Libraries = %v, want all 3 library versions", plan.Libraries)
+	}
+
+	if _, err := PlanRemoval(state, []string{"missing"}); err == nil {
+		t.Error("PlanRemoval() should fail for a tool that isn't installed")
+	}
+}
+
+func TestPlanRemoval_UntrackedLibraryUse(t *testing.T) {
+	state := &State{
+		Installed: map[string]ToolState{
+			"ruby": {ActiveVersion: "3.4.0", IsExplicit: true, InstallDependencies: []string{"libyaml"}},
+		},
+		Libs: map[string]map[string]LibraryVersionState{
+			"libyaml": {"0.2.5": {UsedBy: []string{}}},
+		},
+	}
+	plan, err := PlanRemoval(state, nil)
+	if err != nil {
+		t.Fatalf("PlanRemoval() error = %v", err)
+	}
+	if !plan.IsEmpty() {
+		t.Errorf("PlanRemoval() = %+v, want library kept through ruby's dependency", plan)
+	}
+}
+
+func TestApplyRemoval(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	if err := mgr.state.Save(autoremoveState()); err != nil {
+		t.Fatalf("failed to save state: %v", err)
+	}
+	for _, dir := range []string{
+		cfg.ToolDir("serverless", "3.0.0"),
+		cfg.ToolDir("ruby", "3.4.0"),
+		cfg.LibDir("libyaml", "0.2.5"),
+		cfg.LibDir("openssl", "3.0.0"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "file"), make([]byte, 100), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan, err := mgr.PlanRemoval("serverless")
+	if err != nil {
+		t.Fatalf("PlanRemoval() error = %v", err)
+	}
+	if size := mgr.RemovalSize(plan); size != 400 {
+		t.Errorf("RemovalSize() = %d, want 400", size)
+	}
+	if err := mgr.ApplyRemoval(plan); err != nil {
+		t.Fatalf("ApplyRemoval() error = %v", err)
+	}
+
+	state, err := mgr.state.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"serverless", "ruby"} {
+		if _, ok := state.Installed[name]; ok {
+			t.Errorf("%s should be removed from state", name)
+		}
+	}
+	if got := state.Installed["nodejs"].RequiredBy; !reflect.DeepEqual(got, []string{"prettier"}) {
+		t.Errorf("nodejs RequiredBy = %v, want [prettier]", got)
+	}
+	if _, ok := state.Libs["libyaml"]; ok {
+		t.Error("libyaml should be removed from state")
+	}
+	if _, ok := state.Libs["openssl"]["3.1.0"]; !ok {
+		t.Error("openssl 3.1.0 is used by prettier and should be kept")
+	}
+	if _, err := os.Stat(cfg.LibDir("libyaml", "0.2.5")); !os.IsNotExist(err) {
+		t.Error("libyaml directory should be removed")
+	}
+}
@@ -38,6 +38,20 @@ func (fl *FileLock) LockExclusive() error {
 	return fl.lockExclusive()
 }
 
+// TryLockExclusive attempts to acquire an exclusive lock without blocking.
+// It returns false if another process holds the lock.
+func (fl *FileLock) TryLockExclusive() (bool, error) {
+	if err := fl.openFile(); err != nil {
+		return false, err
+	}
+	ok, err := fl.tryLockExclusive()
+	if err != nil || !ok {
+		fl.file.Close()
+		fl.file = nil
+	}
+	return ok, err
+}
+
 // Unlock releases the lock and closes the file.
 func (fl *FileLock) Unlock() error {
 	if fl.file == nil {
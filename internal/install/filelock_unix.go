@@ -3,6 +3,7 @@
 package install
 
 import (
+	"errors"
 	"fmt"
 	"syscall"
 )
@@ -23,6 +24,18 @@ func (fl *FileLock) lockExclusive() error {
 	return nil
 }
 
+// tryLockExclusive acquires an exclusive lock using flock(2) with LOCK_NB.
+func (fl *FileLock) tryLockExclusive() (bool, error) {
+	err := syscall.Flock(int(fl.file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire exclusive lock: %w", err)
+	}
+	return true, nil
+}
+
 // unlock releases the flock.
 func (fl *FileLock) unlock() error {
 	if err := syscall.Flock(int(fl.file.Fd()), syscall.LOCK_UN); err != nil {
@@ -3,6 +3,7 @@
 package install
 
 import (
+	"errors"
 	"fmt"
 
 	"golang.org/x/sys/windows"
@@ -11,6 +12,9 @@ import (
 const (
 	// lockfileExclusiveLock is the flag for exclusive lock
 	lockfileExclusiveLock = 0x00000002
+
+	// lockfileFailImmediately is the flag to return instead of waiting for the lock
+	lockfileFailImmediately = 0x00000001
 )
 
 // lockShared acquires a shared (read) lock using LockFileEx.
@@ -47,6 +51,26 @@ func (fl *FileLock) lockExclusive() error {
 	return nil
 }
 
+// tryLockExclusive acquires an exclusive lock using LockFileEx without waiting.
+func (fl *FileLock) tryLockExclusive() (bool, error) {
+	var overlapped windows.Overlapped
+	err := windows.LockFileEx(
+		windows.Handle(fl.file.Fd()),
+		lockfileExclusiveLock|lockfileFailImmediately,
+		0,
+		1,
+		0,
+		&overlapped,
+	)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire exclusive lock: %w", err)
+	}
+	return true, nil
+}
+
 // unlock releases the lock using UnlockFileEx.
 func (fl *FileLock) unlock() error {
 	var overlapped windows.Overlapped
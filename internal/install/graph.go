@@ -0,0 +1,248 @@
+package install
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Graph node kinds
+const (
+	NodeTool    = "tool"
+	NodeLibrary = "library"
+)
+
+// Graph edge types
+const (
+	EdgeInstall    = "install"    // Needed to install the dependent tool
+	EdgeRuntime    = "runtime"    // Needed when the dependent tool runs
+	EdgeDependency = "dependency" // Recorded only through RequiredBy
+	EdgeLibrary    = "library"    // Library version used by the tool
+)
+
+// GraphNode is an installed tool or library version in the installation graph.
+type GraphNode struct {
+	ID        string `json:"id"` // Tool name, or "name@version" for libraries
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Version   string `json:"version,omitempty"`
+	Explicit  bool   `json:"explicit,omitempty"`
+	Hidden    bool   `json:"hidden,omitempty"`
+	Installed bool   `json:"installed"`
+}
+
+// GraphEdge records that From depends on To.
+type GraphEdge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Types []string `json:"types"`
+}
+
+// Graph is the dependency graph of everything recorded in state.json.
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// LibraryNodeID returns the graph node ID of a library version
+func LibraryNodeID(name, version string) string {
+	return name + "@" + version
+}
+
+// BuildGraph builds the installation graph from state. Tool edges come from
+// each tool's install and runtime dependencies and from RequiredBy, library
+// edges from each library version's UsedBy entries. Dependencies that are not
+// installed appear as nodes with Installed set to false.
+func BuildGraph(state *State) *Graph {
+	nodes := make(map[string]*GraphNode)
+	edges := make(map[[2]string]map[string]bool)
+
+	toolNode := func(name string) *GraphNode {
+		if n, ok := nodes[name]; ok {
+			return n
+		}
+		n := &GraphNode{ID: name, Name: name, Kind: NodeTool}
+		nodes[name] = n
+		return n
+	}
+	addEdge := func(from, to, edgeType string) {
+		key := [2]string{from, to}
+		if edges[key] == nil {
+			edges[key] = make(map[string]bool)
+		}
+		edges[key][edgeType] = true
+	}
+
+	for name, ts := range state.Installed {
+		n := toolNode(name)
+		n.Version = ts.ActiveVersion
+		if n.Version == "" {
+			n.Version = ts.Version
+		}
+		n.Explicit = ts.IsExplicit
+		n.Hidden = ts.IsHidden
+		n.Installed = true
+
+		for _, dep := range ts.InstallDependencies {
+			toolNode(dep)
+			addEdge(name, dep, EdgeInstall)
+		}
+		for _, dep := range ts.RuntimeDependencies {
+			toolNode(dep)
+			addEdge(name, dep, EdgeRuntime)
+		}
+	}
+
+	// RequiredBy may record edges missing from the dependency lists (e.g.,
+	// state written before they were tracked)
+	for name, ts := range state.Installed {
+		for _, parent := range ts.RequiredBy {
+			if _, ok := edges[[2]string{parent, name}]; ok {
+				continue
+			}
+			toolNode(parent)
+			addEdge(parent, name, EdgeDependency)
+		}
+	}
+
+	for libName, versions := range state.Libs {
+		for libVersion, ls := range versions {
+			id := LibraryNodeID(libName, libVersion)
+			nodes[id] = &GraphNode{ID: id, Name: libName, Kind: NodeLibrary, Version: libVersion, Installed: true}
+			for _, user := range ls.UsedBy {
+				if tool := toolForNameVersion(state, user); tool != "" {
+					addEdge(tool, id, EdgeLibrary)
+				}
+			}
+		}
+	}
+
+	g := &Graph{Nodes: make([]GraphNode, 0, len(nodes)), Edges: make([]GraphEdge, 0, len(edges))}
+	for _, n := range nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+
+	for key, types := range edges {
+		e := GraphEdge{From: key[0], To: key[1]}
+		for t := range types {
+			e.Types = append(e.Types, t)
+		}
+		sort.Strings(e.Types)
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+	return g
+}
+
+// toolForNameVersion maps a library UsedBy entry ("<tool>-<version>") to the
+// installed tool it names. Tool names may contain dashes, so the entry is
+// matched against the versions recorded for each installed tool.
+func toolForNameVersion(state *State, nameVersion string) string {
+	for name, ts := range state.Installed {
+		rest, ok := strings.CutPrefix(nameVersion, name+"-")
+		if !ok {
+			continue
+		}
+		if _, ok := ts.Versions[rest]; ok || rest == ts.ActiveVersion || rest == ts.Version {
+			return name
+		}
+	}
+	return ""
+}
+
+// Why returns every dependency path from an explicitly installed tool to the
+// node(s) called name, each ordered from the explicit tool to the target. A
+// library name matches all of its installed versions. An explicitly installed
+// target yields a single-element path. Paths are sorted for stable output.
+func (g *Graph) Why(name string) [][]string {
+	dependents := make(map[string][]string)
+	for _, e := range g.Edges {
+		dependents[e.To] = append(dependents[e.To], e.From)
+	}
+	byID := make(map[string]GraphNode, len(g.Nodes))
+	for _, n := range g.Nodes {
+		byID[n.ID] = n
+	}
+
+	var paths [][]string
+	var walk func(id string, path []string)
+	walk = func(id string, path []string) {
+		for _, seen := range path {
+			if seen == id {
+				return
+			}
+		}
+		path = append([]string{id}, path...)
+		if byID[id].Explicit {
+			paths = append(paths, path)
+		}
+		for _, parent := range dependents[id] {
+			walk(parent, path)
+		}
+	}
+
+	for _, n := range g.Nodes {
+		if n.Name == name {
+			walk(n.ID, nil)
+		}
+	}
+
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.Join(paths[i], " ") < strings.Join(paths[j], " ")
+	})
+	return paths
+}
+
+// Node returns the node with the given ID
+func (g *Graph) Node(id string) (GraphNode, bool) {
+	for _, n := range g.Nodes {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return GraphNode{}, false
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Explicit tools are drawn
+// bold, libraries as boxes, uninstalled dependencies dashed and runtime-only
+// edges dashed.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph tsuku {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		label := n.Name
+		if n.Version != "" {
+			label += "\n" + n.Version
+		}
+		var attrs []string
+		attrs = append(attrs, fmt.Sprintf("label=%q", label))
+		if n.Kind == NodeLibrary {
+			attrs = append(attrs, "shape=box")
+		}
+		switch {
+		case !n.Installed:
+			attrs = append(attrs, "style=dashed")
+		case n.Explicit:
+			attrs = append(attrs, "style=bold")
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", n.ID, strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		attrs := fmt.Sprintf("label=%q", strings.Join(e.Types, ","))
+		if len(e.Types) == 1 && e.Types[0] == EdgeRuntime {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "  %q -> %q [%s];\n", e.From, e.To, attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
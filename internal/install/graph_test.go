@@ -0,0 +1,121 @@
+package install
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testGraphState() *State {
+	return &State{
+		Installed: map[string]ToolState{
+			"ruby": {
+				ActiveVersion:       "3.4.0",
+				Versions:            map[string]VersionState{"3.4.0": {}},
+				IsExplicit:          true,
+				InstallDependencies: []string{"zig"},
+				RuntimeDependencies: []string{"openssl-tool"},
+			},
+			"jekyll": {
+				ActiveVersion:       "4.3.0",
+				IsExplicit:          true,
+				InstallDependencies: []string{"ruby"},
+				RuntimeDependencies: []string{"ruby"},
+			},
+			"zig":          {ActiveVersion: "0.13.0", IsHidden: true, RequiredBy: []string{"ruby"}},
+			"openssl-tool": {ActiveVersion: "3.0.0", RequiredBy: []string{"ruby", "legacy"}},
+			"orphan":       {ActiveVersion: "1.0.0"},
+		},
+		Libs: map[string]map[string]LibraryVersionState{
+			"libyaml": {"0.2.5": {UsedBy: []string{"ruby-3.4.0"}}},
+		},
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	g := BuildGraph(testGraphState())
+
+	var edges []string
+	for _, e := range g.Edges {
+		edges = append(edges, e.From+">"+e.To+":"+strings.Join(e.Types, ","))
+	}
+	want := []string{
+		"jekyll>ruby:install,runtime",
+		"legacy>openssl-tool:dependency",
+		"ruby>libyaml@0.2.5:library",
+		"ruby>openssl-tool:runtime",
+		"ruby>zig:install",
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("edges = %v, want %v", edges, want)
+	}
+
+	lib, ok := g.Node("libyaml@0.2.5")
+	if !ok || lib.Kind != NodeLibrary || lib.Version != "0.2.5" {
+		t.Errorf("library node = %+v, %v", lib, ok)
+	}
+	legacy, ok := g.Node("legacy")
+	if !ok || legacy.Installed {
+		t.Errorf("uninstalled dependent = %+v, %v", legacy, ok)
+	}
+	if zig, _ := g.Node("zig"); !zig.Hidden || zig.Version != "0.13.0" {
+		t.Errorf("zig node = %+v", zig)
+	}
+}
+
+func TestGraph_Why(t *testing.T) {
+	g := BuildGraph(testGraphState())
+
+	tests := []struct {
+		name string
+		want [][]string
+	}{
+		{"libyaml", [][]string{
+			{"jekyll", "ruby", "libyaml@0.2.5"},
+			{"ruby", "libyaml@0.2.5"},
+		}},
+		{"ruby", [][]string{{"jekyll", "ruby"}, {"ruby"}}},
+		{"openssl-tool", [][]string{
+			{"jekyll", "ruby", "openssl-tool"},
+			{"ruby", "openssl-tool"},
+		}},
+		{"orphan", nil},
+	}
+	for _, tt := range tests {
+		if got := g.Why(tt.name); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Why(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGraph_Why_Cycle(t *testing.T) {
+	g := BuildGraph(&State{Installed: map[string]ToolState{
+		"a": {IsExplicit: true, InstallDependencies: []string{"b"}},
+		"b": {InstallDependencies: []string{"c"}},
+		"c": {InstallDependencies: []string{"b"}},
+	}})
+	want := [][]string{{"a", "b", "c"}}
+	if got := g.Why("c"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Why(c) = %v, want %v", got, want)
+	}
+}
+
+func TestGraph_WriteDOT(t *testing.T) {
+	var b strings.Builder
+	if err := BuildGraph(testGraphState()).WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"digraph tsuku {",
+		`"libyaml@0.2.5" [label="libyaml\n0.2.5", shape=box];`,
+		`"ruby" [label="ruby\n3.4.0", style=bold];`,
+		`"legacy" [label="legacy", style=dashed];`,
+		`"ruby" -> "openssl-tool" [label="runtime", style=dashed];`,
+		`"ruby" -> "zig" [label="install"];`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteDOT() missing %q in:\n%s", want, out)
+		}
+	}
+}
@@ -34,9 +34,11 @@ func ExposeHidden(mgr *Manager, toolName string) error {
 	}
 
 	// Create symlinks for all binaries this tool provides
-	if err := mgr.createSymlinksForBinaries(toolName, toolState.Version, toolState.Binaries); err != nil {
+	conflicts, err := mgr.linkBinaries(toolName, toolState.Version, toolState.Binaries, nil)
+	if err != nil {
 		return fmt.Errorf("failed to create symlinks: %w", err)
 	}
+	printBinaryConflicts(toolName, conflicts)
 
 	// Update state to mark as no longer hidden and explicitly requested
 	return sm.UpdateTool(toolName, func(ts *ToolState) {
@@ -17,6 +17,7 @@ import (
 type Manager struct {
 	config *config.Config
 	state  *StateManager
+	store  *Store
 }
 
 // New creates a new install manager
@@ -24,6 +25,7 @@ func New(cfg *config.Config) *Manager {
 	return &Manager{
 		config: cfg,
 		state:  NewStateManager(cfg),
+		store:  NewStore(cfg),
 	}
 }
 
@@ -35,6 +37,7 @@ type InstallOptions struct {
 	RuntimeDependencies map[string]string // Runtime deps: name -> version (for wrapper scripts)
 	RequestedVersion    string            // What user originally requested ("17", "@lts", "")
 	Plan                *Plan             // Installation plan to store (if generated)
+	UseStore            bool              // Hardlink files from the content-addressed store instead of copying
 }
 
 // DefaultInstallOptions returns the default installation options
@@ -80,7 +83,7 @@ func (m *Manager) InstallWithOptions(name, version, workDir string, opts Install
 	// Copy the entire .install directory to preserve full structure (bin/, lib/, share/, etc.)
 	srcInstallDir := filepath.Join(workDir, ".install")
 
-	if err := copyDir(srcInstallDir, stagingDir); err != nil {
+	if err := m.populate(srcInstallDir, stagingDir, opts.UseStore); err != nil {
 		// Clean up staging directory on copy failure
 		os.RemoveAll(stagingDir)
 		return fmt.Errorf("failed to copy installation: %w", err)
@@ -108,39 +111,44 @@ func (m *Manager) InstallWithOptions(name, version, workDir string, opts Install
 	// Create symlink or wrapper in current/ (unless hidden)
 	// If symlink creation fails, we need to rollback by removing the tool directory
 	if opts.CreateSymlinks {
-		var symlinkErr error
-		if len(opts.RuntimeDependencies) > 0 {
-			// Tool has runtime deps - create wrapper scripts
-			symlinkErr = m.createWrappersForBinaries(name, version, opts.Binaries, opts.RuntimeDependencies)
-			if symlinkErr == nil {
-				fmt.Printf("📍 Installed to: %s\n", toolDir)
-				if len(opts.Binaries) > 0 {
-					fmt.Printf("🔗 Wrapped %d binaries: %v\n", len(opts.Binaries), opts.Binaries)
-				} else {
-					fmt.Printf("🔗 Wrapped: %s\n", m.config.CurrentSymlink(name))
-				}
-			}
-		} else {
-			// No runtime deps - use symlinks (faster)
-			symlinkErr = m.createSymlinksForBinaries(name, version, opts.Binaries)
-			if symlinkErr == nil {
-				fmt.Printf("📍 Installed to: %s\n", toolDir)
-				if len(opts.Binaries) > 0 {
-					fmt.Printf("🔗 Symlinked %d binaries: %v\n", len(opts.Binaries), opts.Binaries)
-				} else {
-					fmt.Printf("🔗 Symlinked: %s -> %s\n", m.config.CurrentSymlink(name), filepath.Join(toolDir, "bin", name))
-				}
-			}
-		}
+		existing, _ := m.state.GetToolState(name)
+		conflicts, symlinkErr := m.linkBinaries(name, version, opts.Binaries, opts.RuntimeDependencies)
 
 		// If symlink/wrapper creation failed, rollback the installation
 		if symlinkErr != nil {
 			os.RemoveAll(toolDir)
+			if existing == nil {
+				_, _ = m.state.UnregisterBinaries(name)
+			}
 			if len(opts.RuntimeDependencies) > 0 {
 				return fmt.Errorf("failed to create wrappers: %w", symlinkErr)
 			}
 			return fmt.Errorf("failed to create symlinks: %w", symlinkErr)
 		}
+
+		fmt.Printf("📍 Installed to: %s\n", toolDir)
+		verb := "Symlinked"
+		if len(opts.RuntimeDependencies) > 0 {
+			verb = "Wrapped"
+		}
+		if len(opts.Binaries) > 0 {
+			var linked []string
+			for _, b := range opts.Binaries {
+				if _, taken := conflicts[filepath.Base(b)]; !taken {
+					linked = append(linked, b)
+				}
+			}
+			if len(linked) > 0 {
+				fmt.Printf("🔗 %s %d binaries: %v\n", verb, len(linked), linked)
+			}
+		} else if _, taken := conflicts[name]; !taken {
+			if len(opts.RuntimeDependencies) > 0 {
+				fmt.Printf("🔗 Wrapped: %s\n", m.config.CurrentSymlink(name))
+			} else {
+				fmt.Printf("🔗 Symlinked: %s -> %s\n", m.config.CurrentSymlink(name), filepath.Join(toolDir, "bin", name))
+			}
+		}
+		printBinaryConflicts(name, conflicts)
 	} else {
 		fmt.Printf("📍 Installed to: %s (hidden)\n", toolDir)
 	}
@@ -156,6 +164,13 @@ func (m *Manager) InstallWithOptions(name, version, workDir string, opts Install
 		}
 	}
 
+	// Record a digest of the built tree so builds of the same plan on
+	// other machines can be compared against it
+	var treeDigest string
+	if opts.Plan != nil {
+		treeDigest = m.computeTreeDigest(workDir)
+	}
+
 	// Update state with multi-version support
 	// Note: IsExplicit and RequiredBy are handled by the caller (main.go)
 	err := m.state.UpdateTool(name, func(ts *ToolState) {
@@ -171,6 +186,8 @@ func (m *Manager) InstallWithOptions(name, version, workDir string, opts Install
 			BinaryChecksums: binaryChecksums,
 			InstalledAt:     time.Now(),
 			Plan:            opts.Plan,
+			Store:           opts.UseStore,
+			TreeDigest:      treeDigest,
 		}
 
 		// Set as active version
@@ -258,7 +275,7 @@ func (m *Manager) Activate(name, version string) error {
 		binaries = []string{name}
 	}
 
-	if err := m.createSymlinksForBinaries(name, version, binaries); err != nil {
+	if _, err := m.linkBinaries(name, version, binaries, nil); err != nil {
 		return fmt.Errorf("failed to update symlinks: %w", err)
 	}
 
@@ -451,6 +468,15 @@ func generateWrapperScript(targetPath string, pathAdditions []string) string {
 	return sb.String()
 }
 
+// populate fills a staging directory from an install directory, through the
+// content-addressed store when useStore is set
+func (m *Manager) populate(src, dst string, useStore bool) error {
+	if useStore {
+		return m.store.Materialize(src, dst)
+	}
+	return copyDir(src, dst)
+}
+
 // copyDir recursively copies a directory
 func copyDir(src, dst string) error {
 	// Get source directory info
@@ -680,7 +706,11 @@ func fixPythonShebang(filePath, toolDir, packageName string) error {
 	newShebang := "#!" + venvPythonPath
 	newContent := newShebang + "\n" + rest
 
-	// Write back
+	// Write back. Replace the file rather than writing through it, since it
+	// may be a hardlink to a shared store object.
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
 	if err := os.WriteFile(filePath, []byte(newContent), 0755); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
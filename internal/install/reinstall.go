@@ -0,0 +1,110 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Reinstall replaces the files of an installed tool version with freshly built
+// output from workDir, keeping the version's recorded state (requested version,
+// binaries, plan). Binary checksums are re-computed from the new files.
+//
+// The replacement is atomic: the new files are staged next to the tool directory,
+// the existing directory is moved aside, and the staged directory is renamed into
+// place. If the rename fails, the original directory is restored.
+func (m *Manager) Reinstall(name, version, workDir string) error {
+	// Validate version string to prevent path traversal attacks
+	if err := ValidateVersionString(version); err != nil {
+		return fmt.Errorf("invalid version: %w", err)
+	}
+
+	toolState, err := m.state.GetToolState(name)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if toolState == nil {
+		return fmt.Errorf("tool %q is not installed", name)
+	}
+	versionState, exists := toolState.Versions[version]
+	if !exists {
+		return m.versionNotInstalledError(name, version, toolState)
+	}
+
+	toolDir := m.config.ToolDir(name, version)
+	stagingDir := m.stagingDir(name, version)
+	backupDir := m.backupDir(name, version)
+
+	// Clean up leftovers from a previous failed reinstall
+	if err := os.RemoveAll(stagingDir); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clean up stale staging directory: %w", err)
+	}
+	if err := os.RemoveAll(backupDir); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to clean up stale backup directory: %w", err)
+	}
+
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	if err := m.populate(filepath.Join(workDir, ".install"), stagingDir, versionState.Store); err != nil {
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("failed to copy installation: %w", err)
+	}
+	_ = fixPipxShebangs(stagingDir, m.config.ToolsDir) // Ignore errors - not all tools use pipx
+
+	// Move the existing directory aside so it can be restored if the swap fails
+	hadExisting := false
+	if _, err := os.Lstat(toolDir); err == nil {
+		if err := os.Rename(toolDir, backupDir); err != nil {
+			os.RemoveAll(stagingDir)
+			return fmt.Errorf("failed to move existing installation aside: %w", err)
+		}
+		hadExisting = true
+	}
+
+	if err := os.Rename(stagingDir, toolDir); err != nil {
+		os.RemoveAll(stagingDir)
+		if hadExisting {
+			_ = os.Rename(backupDir, toolDir)
+		}
+		return fmt.Errorf("failed to finalize reinstallation: %w", err)
+	}
+	os.RemoveAll(backupDir)
+
+	binaries := versionState.Binaries
+	if len(binaries) == 0 {
+		binaries = toolState.Binaries
+	}
+	var binaryChecksums map[string]string
+	if len(binaries) > 0 {
+		binaryChecksums, err = ComputeBinaryChecksums(toolDir, binaries)
+		if err != nil {
+			// Log warning but don't fail - checksums are for verification, not blocking
+			fmt.Printf("⚠️  Could not compute binary checksums: %v\n", err)
+		}
+	}
+
+	treeDigest := m.computeTreeDigest(workDir)
+
+	err = m.state.UpdateTool(name, func(ts *ToolState) {
+		vs, ok := ts.Versions[version]
+		if !ok {
+			return
+		}
+		vs.BinaryChecksums = binaryChecksums
+		vs.TreeDigest = treeDigest
+		ts.Versions[version] = vs
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update state: %w", err)
+	}
+
+	return nil
+}
+
+// backupDir returns the path an existing tool directory is moved to while it is
+// being replaced. Like the staging directory, it shares the tool directory's parent
+// so renames stay on one filesystem.
+func (m *Manager) backupDir(name, version string) string {
+	return filepath.Join(m.config.ToolsDir, fmt.Sprintf(".%s-%s.old", name, version))
+}
@@ -0,0 +1,147 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+// writeWorkDir creates a work directory whose .install/bin contains a single binary
+func writeWorkDir(t *testing.T, binary, content string) string {
+	t.Helper()
+	workDir := t.TempDir()
+	binDir := filepath.Join(workDir, ".install", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatalf("failed to create install bin dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(binDir, binary), []byte(content), 0755); err != nil {
+		t.Fatalf("failed to create binary: %v", err)
+	}
+	return workDir
+}
+
+func TestReinstall_RestoresModifiedBinary(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+
+	mgr := New(cfg)
+	plan := &Plan{Tool: "mytool", Version: "1.0.0"}
+	opts := InstallOptions{
+		CreateSymlinks:   true,
+		Binaries:         []string{"bin/mytool"},
+		RequestedVersion: "1",
+		Plan:             plan,
+	}
+	original := "#!/bin/sh\necho original\n"
+	if err := mgr.InstallWithOptions("mytool", "1.0.0", writeWorkDir(t, "mytool", original), opts); err != nil {
+		t.Fatalf("InstallWithOptions() error = %v", err)
+	}
+
+	// Tamper with the installed binary and leave a stray file behind
+	toolDir := cfg.ToolDir("mytool", "1.0.0")
+	binaryPath := filepath.Join(toolDir, "bin", "mytool")
+	if err := os.WriteFile(binaryPath, []byte("#!/bin/sh\necho tampered\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(toolDir, "stray"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := mgr.Reinstall("mytool", "1.0.0", writeWorkDir(t, "mytool", original)); err != nil {
+		t.Fatalf("Reinstall() error = %v", err)
+	}
+
+	data, err := os.ReadFile(binaryPath)
+	if err != nil {
+		t.Fatalf("failed to read binary: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("binary content = %q, want original", data)
+	}
+	if _, err := os.Stat(filepath.Join(toolDir, "stray")); !os.IsNotExist(err) {
+		t.Error("stray file should be gone after reinstall")
+	}
+	for _, dir := range []string{mgr.stagingDir("mytool", "1.0.0"), mgr.backupDir("mytool", "1.0.0")} {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s should be cleaned up", dir)
+		}
+	}
+
+	ts, err := mgr.GetState().GetToolState("mytool")
+	if err != nil || ts == nil {
+		t.Fatalf("GetToolState() = %v, %v", ts, err)
+	}
+	vs := ts.Versions["1.0.0"]
+	if vs.Requested != "1" || vs.Plan == nil || vs.Plan.Tool != "mytool" {
+		t.Errorf("version state not preserved: %+v", vs)
+	}
+	mismatches, err := VerifyBinaryChecksums(toolDir, vs.BinaryChecksums)
+	if err != nil || len(mismatches) != 0 {
+		t.Errorf("checksums should match after reinstall: %v, %v", mismatches, err)
+	}
+}
+
+func TestReinstall_UpdatesChecksums(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+
+	mgr := New(cfg)
+	opts := InstallOptions{CreateSymlinks: true, Binaries: []string{"bin/mytool"}}
+	if err := mgr.InstallWithOptions("mytool", "1.0.0", writeWorkDir(t, "mytool", "v1"), opts); err != nil {
+		t.Fatalf("InstallWithOptions() error = %v", err)
+	}
+	before, _ := mgr.GetState().GetToolState("mytool")
+
+	if err := mgr.Reinstall("mytool", "1.0.0", writeWorkDir(t, "mytool", "v1-rebuilt")); err != nil {
+		t.Fatalf("Reinstall() error = %v", err)
+	}
+	after, _ := mgr.GetState().GetToolState("mytool")
+
+	if before.Versions["1.0.0"].BinaryChecksums["bin/mytool"] == after.Versions["1.0.0"].BinaryChecksums["bin/mytool"] {
+		t.Error("expected binary checksum to be re-computed")
+	}
+}
+
+func TestReinstall_NotInstalled(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+
+	mgr := New(cfg)
+	if err := mgr.Reinstall("missing", "1.0.0", t.TempDir()); err == nil {
+		t.Error("expected error for tool that is not installed")
+	}
+
+	opts := InstallOptions{CreateSymlinks: true, Binaries: []string{"bin/mytool"}}
+	if err := mgr.InstallWithOptions("mytool", "1.0.0", writeWorkDir(t, "mytool", "v1"), opts); err != nil {
+		t.Fatalf("InstallWithOptions() error = %v", err)
+	}
+	if err := mgr.Reinstall("mytool", "2.0.0", writeWorkDir(t, "mytool", "v2")); err == nil {
+		t.Error("expected error for version that is not installed")
+	}
+	if err := mgr.Reinstall("mytool", "../1.0.0", writeWorkDir(t, "mytool", "v2")); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
+
+func TestReinstall_FailedCopyKeepsExisting(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+
+	mgr := New(cfg)
+	opts := InstallOptions{CreateSymlinks: true, Binaries: []string{"bin/mytool"}}
+	if err := mgr.InstallWithOptions("mytool", "1.0.0", writeWorkDir(t, "mytool", "v1"), opts); err != nil {
+		t.Fatalf("InstallWithOptions() error = %v", err)
+	}
+
+	// A work directory without .install output cannot be staged
+	if err := mgr.Reinstall("mytool", "1.0.0", t.TempDir()); err == nil {
+		t.Fatal("expected error for empty work directory")
+	}
+
+	data, err := os.ReadFile(filepath.Join(cfg.ToolDir("mytool", "1.0.0"), "bin", "mytool"))
+	if err != nil || string(data) != "v1" {
+		t.Errorf("existing installation should be untouched, got %q, %v", data, err)
+	}
+}
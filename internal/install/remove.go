@@ -3,7 +3,6 @@ package install
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"sort"
 	"time"
 )
@@ -29,13 +28,28 @@ func (m *Manager) Remove(name string) error {
 		return fmt.Errorf("tool %s is not installed", name)
 	}
 
-	// 2. Remove tool directory
+	// 2. Versions tracked in state go through RemoveVersion, which keeps the
+	// binaries the remaining versions still provide
+	toolState, err := m.state.GetToolState(name)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %w", err)
+	}
+	if toolState != nil {
+		if _, ok := toolState.Versions[version]; ok {
+			return m.RemoveVersion(name, version)
+		}
+	}
+
+	// 3. Remove tool directory
 	toolDir := m.config.ToolDir(name, version)
 	if err := os.RemoveAll(toolDir); err != nil {
 		return fmt.Errorf("failed to remove tool directory: %w", err)
 	}
 
-	// 3. Remove symlink if it points to this tool
+	// 4. Remove or hand back the tool's entries in current/
+	if toolState != nil {
+		return m.releaseBinaries(name, toolState)
+	}
 	symlinkPath := m.config.CurrentSymlink(name)
 	if _, err := os.Lstat(symlinkPath); err == nil {
 		if err := os.Remove(symlinkPath); err != nil {
@@ -65,7 +79,8 @@ func (m *Manager) RemoveVersion(name, version string) error {
 	}
 
 	// Check if version exists
-	if _, exists := toolState.Versions[version]; !exists {
+	versionState, exists := toolState.Versions[version]
+	if !exists {
 		return m.versionNotInstalledError(name, version, toolState)
 	}
 
@@ -74,6 +89,9 @@ func (m *Manager) RemoveVersion(name, version string) error {
 	if err := os.RemoveAll(toolDir); err != nil {
 		return fmt.Errorf("failed to remove tool directory: %w", err)
 	}
+	if versionState.Store {
+		m.collectStoreGarbage()
+	}
 
 	// Check if this was the last version
 	if len(toolState.Versions) == 1 {
@@ -103,6 +121,17 @@ func (m *Manager) RemoveVersion(name, version string) error {
 		return fmt.Errorf("failed to update state: %w", err)
 	}
 
+	// Release the binaries no remaining version provides
+	remaining := make(map[string]VersionState, len(toolState.Versions)-1)
+	for v, vs := range toolState.Versions {
+		if v != version {
+			remaining[v] = vs
+		}
+	}
+	if err := m.releaseVersionBinaries(name, versionState, remaining); err != nil {
+		return err
+	}
+
 	// If active version was removed, update symlinks to point to new active version
 	if wasActive && newActiveVersion != "" {
 		// Reload state to get binaries for new active version
@@ -114,7 +143,7 @@ func (m *Manager) RemoveVersion(name, version string) error {
 		if len(binaries) == 0 {
 			binaries = []string{name}
 		}
-		if err := m.createSymlinksForBinaries(name, newActiveVersion, binaries); err != nil {
+		if _, err := m.linkBinaries(name, newActiveVersion, binaries, nil); err != nil {
 			return fmt.Errorf("failed to update symlinks: %w", err)
 		}
 	}
@@ -134,11 +163,16 @@ func (m *Manager) RemoveAllVersions(name string) error {
 	}
 
 	// Remove all version directories
-	for version := range toolState.Versions {
+	usedStore := false
+	for version, vs := range toolState.Versions {
 		toolDir := m.config.ToolDir(name, version)
 		if err := os.RemoveAll(toolDir); err != nil {
 			return fmt.Errorf("failed to remove version %s: %w", version, err)
 		}
+		usedStore = usedStore || vs.Store
+	}
+	if usedStore {
+		m.collectStoreGarbage()
 	}
 
 	// Remove symlinks and state
@@ -147,30 +181,9 @@ func (m *Manager) RemoveAllVersions(name string) error {
 
 // removeToolEntirely removes all symlinks and state for a tool.
 func (m *Manager) removeToolEntirely(name string, toolState *ToolState) error {
-	// Collect all binaries to remove symlinks
-	binaries := make(map[string]bool)
-
-	// From legacy field
-	for _, b := range toolState.Binaries {
-		binaries[filepath.Base(b)] = true
-	}
-
-	// From each version
-	for _, vs := range toolState.Versions {
-		for _, b := range vs.Binaries {
-			binaries[filepath.Base(b)] = true
-		}
-	}
-
-	// Fallback to tool name if no binaries found
-	if len(binaries) == 0 {
-		binaries[name] = true
-	}
-
-	// Remove symlinks
-	for binaryName := range binaries {
-		symlinkPath := m.config.CurrentSymlink(binaryName)
-		_ = os.Remove(symlinkPath) // Ignore errors - symlink may not exist
+	// Remove or hand back the tool's entries in current/
+	if err := m.releaseBinaries(name, toolState); err != nil {
+		return err
 	}
 
 	// Remove from state
@@ -2,6 +2,7 @@ package install
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,27 +20,42 @@ type VersionState struct {
 	BinaryChecksums map[string]string `json:"binary_checksums,omitempty"` // SHA256 checksums of installed binaries (path -> hex hash)
 	InstalledAt     time.Time         `json:"installed_at"`               // When this version was installed
 	Plan            *Plan             `json:"plan,omitempty"`             // Installation plan (if generated)
+	Store           bool              `json:"store,omitempty"`            // Files are hardlinked from the content-addressed store
+	TreeDigest      string            `json:"tree_digest,omitempty"`      // Normalized digest of the built install tree (see ComputeBuildManifest)
 }
 
 // Plan represents a stored installation plan. This is a simplified view of
 // executor.InstallationPlan that can be stored in state.json.
-// The full plan structure is preserved for plan inspection and future replay.
+// The full plan structure is preserved for plan inspection and for replay by
+// "tsuku reinstall".
 type Plan struct {
-	FormatVersion int          `json:"format_version"`
-	Tool          string       `json:"tool"`
-	Version       string       `json:"version"`
-	Platform      PlanPlatform `json:"platform"`
-	GeneratedAt   time.Time    `json:"generated_at"`
-	RecipeHash    string       `json:"recipe_hash"`
-	RecipeSource  string       `json:"recipe_source"`
-	Deterministic bool         `json:"deterministic"`
-	Steps         []PlanStep   `json:"steps"`
+	FormatVersion int              `json:"format_version"`
+	Tool          string           `json:"tool"`
+	Version       string           `json:"version"`
+	Platform      PlanPlatform     `json:"platform"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	RecipeHash    string           `json:"recipe_hash"`
+	RecipeSource  string           `json:"recipe_source"`
+	Deterministic bool             `json:"deterministic"`
+	Steps         []PlanStep       `json:"steps"`
+	Confinement   *PlanConfinement `json:"confinement,omitempty"`
 }
 
-// PlanPlatform identifies the target OS and architecture for a plan.
+// PlanConfinement records a recipe's exceptions to Landlock confinement.
+type PlanConfinement struct {
+	Unconfined bool     `json:"unconfined,omitempty"`
+	Writable   []string `json:"writable,omitempty"`
+	Readable   []string `json:"readable,omitempty"`
+	Reason     string   `json:"reason"`
+}
+
+// PlanPlatform identifies the target OS and architecture for a plan, and on
+// Linux its C library and distribution.
 type PlanPlatform struct {
-	OS   string `json:"os"`
-	Arch string `json:"arch"`
+	OS     string `json:"os"`
+	Arch   string `json:"arch"`
+	Libc   string `json:"libc,omitempty"`
+	Distro string `json:"distro,omitempty"`
 }
 
 // PlanStep represents a resolved installation step.
@@ -111,9 +127,21 @@ type LLMUsage struct {
 
 // State represents the global state of installed tools and libraries
 type State struct {
-	Installed map[string]ToolState                      `json:"installed"`
-	Libs      map[string]map[string]LibraryVersionState `json:"libs,omitempty"`      // map[libName]map[version]LibraryVersionState
-	LLMUsage  *LLMUsage                                 `json:"llm_usage,omitempty"` // LLM generation tracking
+	SchemaVersion int                                       `json:"schema_version"` // See CurrentStateSchemaVersion
+	Installed     map[string]ToolState                      `json:"installed"`
+	Libs          map[string]map[string]LibraryVersionState `json:"libs,omitempty"`      // map[libName]map[version]LibraryVersionState
+	LLMUsage      *LLMUsage                                 `json:"llm_usage,omitempty"` // LLM generation tracking
+	Alternatives  map[string]BinaryAlternatives             `json:"alternatives"`        // map[binary]providers of tools/current/<binary>
+}
+
+// newState returns an empty state in the current schema
+func newState() *State {
+	return &State{
+		SchemaVersion: CurrentStateSchemaVersion,
+		Installed:     make(map[string]ToolState),
+		Libs:          make(map[string]map[string]LibraryVersionState),
+		Alternatives:  make(map[string]BinaryAlternatives),
+	}
 }
 
 // StateManager handles reading and writing the state file
@@ -139,54 +167,57 @@ func (sm *StateManager) lockPath() string {
 	return filepath.Join(sm.config.HomeDir, "state.json.lock")
 }
 
-// Load reads the state from disk
+// Load reads the state from disk. State written by an older tsuku is migrated
+// to the current schema and saved, keeping a backup of the previous file.
+// Saving is best effort: when $TSUKU_HOME is not writable the migrated state
+// is still returned, so read-only commands keep working.
+// State written by a newer tsuku is refused with a StateSchemaTooNewError.
 func (sm *StateManager) Load() (*State, error) {
 	sm.mu.RLock()
-	defer sm.mu.RUnlock()
+	state, from, err := sm.loadWithLock()
+	sm.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if from == CurrentStateSchemaVersion {
+		return state, nil
+	}
 
-	return sm.loadWithLock()
+	result, err := sm.Migrate(false)
+	if err != nil {
+		var tooNew *StateSchemaTooNewError
+		if errors.As(err, &tooNew) {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: could not save migrated state (schema v%d to v%d): %v\n",
+			from, CurrentStateSchemaVersion, err)
+		return state, nil
+	}
+	return result.State, nil
 }
 
-// loadWithLock reads the state from disk with file locking.
+// loadWithLock reads the state from disk with file locking and returns it
+// migrated in memory, along with the schema version found on disk.
 // Caller must hold sm.mu (read or write lock).
-func (sm *StateManager) loadWithLock() (*State, error) {
+func (sm *StateManager) loadWithLock() (*State, int, error) {
 	path := sm.statePath()
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return &State{
-			Installed: make(map[string]ToolState),
-			Libs:      make(map[string]map[string]LibraryVersionState),
-		}, nil
+		return newState(), CurrentStateSchemaVersion, nil
 	}
 
 	// Acquire shared file lock for reading
 	lock := NewFileLock(sm.lockPath())
 	if err := lock.LockShared(); err != nil {
-		return nil, fmt.Errorf("failed to acquire read lock: %w", err)
+		return nil, 0, fmt.Errorf("failed to acquire read lock: %w", err)
 	}
 	defer func() { _ = lock.Unlock() }()
 
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read state file: %w", err)
+		return nil, 0, fmt.Errorf("failed to read state file: %w", err)
 	}
 
-	var state State
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to parse state file: %w", err)
-	}
-
-	// Initialize maps if nil (backward compatibility)
-	if state.Installed == nil {
-		state.Installed = make(map[string]ToolState)
-	}
-	if state.Libs == nil {
-		state.Libs = make(map[string]map[string]LibraryVersionState)
-	}
-
-	// Migrate old single-version format to new multi-version format
-	state.migrateToMultiVersion()
-
-	return &state, nil
+	return decodeState(data)
 }
 
 // Save writes the state to disk
@@ -200,6 +231,7 @@ func (sm *StateManager) Save(state *State) error {
 // saveWithLock writes the state to disk with file locking and atomic write.
 // Caller must hold sm.mu write lock.
 func (sm *StateManager) saveWithLock(state *State) error {
+	state.SchemaVersion = CurrentStateSchemaVersion
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal state: %w", err)
@@ -230,14 +262,13 @@ func (sm *StateManager) saveWithLock(state *State) error {
 }
 
 // loadWithoutLock reads the state from disk without acquiring the file lock.
+// If the file uses an older schema it is backed up first, since the caller is
+// about to save the migrated state over it.
 // Caller must already hold both sm.mu and the file lock.
 func (sm *StateManager) loadWithoutLock() (*State, error) {
 	path := sm.statePath()
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return &State{
-			Installed: make(map[string]ToolState),
-			Libs:      make(map[string]map[string]LibraryVersionState),
-		}, nil
+		return newState(), nil
 	}
 
 	data, err := os.ReadFile(path)
@@ -245,28 +276,23 @@ func (sm *StateManager) loadWithoutLock() (*State, error) {
 		return nil, fmt.Errorf("failed to read state file: %w", err)
 	}
 
-	var state State
-	if err := json.Unmarshal(data, &state); err != nil {
-		return nil, fmt.Errorf("failed to parse state file: %w", err)
-	}
-
-	// Initialize maps if nil (backward compatibility)
-	if state.Installed == nil {
-		state.Installed = make(map[string]ToolState)
+	state, from, err := decodeState(data)
+	if err != nil {
+		return nil, err
 	}
-	if state.Libs == nil {
-		state.Libs = make(map[string]map[string]LibraryVersionState)
+	if from != CurrentStateSchemaVersion {
+		if _, err := sm.backupState(data, from); err != nil {
+			return nil, err
+		}
 	}
 
-	// Migrate old single-version format to new multi-version format
-	state.migrateToMultiVersion()
-
-	return &state, nil
+	return state, nil
 }
 
 // saveWithoutLock writes the state to disk without acquiring the file lock.
 // Caller must already hold both sm.mu and the file lock.
 func (sm *StateManager) saveWithoutLock(state *State) error {
+	state.SchemaVersion = CurrentStateSchemaVersion
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal state: %w", err)
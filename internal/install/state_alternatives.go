@@ -0,0 +1,209 @@
+package install
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// BinaryAlternatives records which installed tools provide a binary name and
+// which of them owns the tools/current/<binary> entry.
+type BinaryAlternatives struct {
+	Active    string   `json:"active"`    // Tool that tools/current/<binary> points to
+	Providers []string `json:"providers"` // Tools that ship the binary, in install order
+}
+
+// hasProvider reports whether tool provides the binary
+func (a BinaryAlternatives) hasProvider(tool string) bool {
+	for _, p := range a.Providers {
+		if p == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// linkNames returns the tools/current entry names for a tool's binaries.
+// Tools without declared binaries are linked under their own name.
+func linkNames(toolName string, binaries []string) []string {
+	if len(binaries) == 0 {
+		return []string{toolName}
+	}
+	names := make([]string, 0, len(binaries))
+	for _, b := range binaries {
+		names = append(names, filepath.Base(b))
+	}
+	return names
+}
+
+// updateState runs a read-modify-write of the whole state with the exclusive
+// lock held for the entire cycle.
+func (sm *StateManager) updateState(update func(*State) error) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	lock := NewFileLock(sm.lockPath())
+	if err := lock.LockExclusive(); err != nil {
+		return fmt.Errorf("failed to acquire lock for update: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	state, err := sm.loadWithoutLock()
+	if err != nil {
+		return err
+	}
+	if err := update(state); err != nil {
+		return err
+	}
+	return sm.saveWithoutLock(state)
+}
+
+// activeProviderInstalled reports whether the active provider of a binary is
+// still installed and visible
+func (s *State) activeProviderInstalled(alt BinaryAlternatives) bool {
+	ts, ok := s.Installed[alt.Active]
+	return ok && !ts.IsHidden
+}
+
+// RegisterBinaries records tool as a provider of each binary and claims the
+// binaries nobody else owns. It returns the binaries that stay with another
+// tool, mapped to their owner.
+func (sm *StateManager) RegisterBinaries(tool string, binaries []string) (map[string]string, error) {
+	conflicts := make(map[string]string)
+	err := sm.updateState(func(state *State) error {
+		for _, name := range binaries {
+			alt := state.Alternatives[name]
+			if !alt.hasProvider(tool) {
+				alt.Providers = append(alt.Providers, tool)
+			}
+			if alt.Active == "" || alt.Active == tool || !state.activeProviderInstalled(alt) {
+				alt.Active = tool
+			} else {
+				conflicts[name] = alt.Active
+			}
+			state.Alternatives[name] = alt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflicts, nil
+}
+
+// UnregisterBinaries removes tool as a provider of every binary. For each
+// binary tool owned, it returns the provider that takes it over, or an empty
+// string when no other tool provides it.
+func (sm *StateManager) UnregisterBinaries(tool string) (map[string]string, error) {
+	return sm.unregisterBinaries(tool, nil)
+}
+
+// UnregisterBinaryNames is UnregisterBinaries limited to the named binaries,
+// for when a tool stops shipping some of them.
+func (sm *StateManager) UnregisterBinaryNames(tool string, binaries []string) (map[string]string, error) {
+	only := make(map[string]bool, len(binaries))
+	for _, name := range binaries {
+		only[name] = true
+	}
+	return sm.unregisterBinaries(tool, only)
+}
+
+// unregisterBinaries removes tool as a provider of the binaries in only, or
+// of every binary when only is nil
+func (sm *StateManager) unregisterBinaries(tool string, only map[string]bool) (map[string]string, error) {
+	released := make(map[string]string)
+	err := sm.updateState(func(state *State) error {
+		for name, alt := range state.Alternatives {
+			if !alt.hasProvider(tool) || (only != nil && !only[name]) {
+				continue
+			}
+			providers := make([]string, 0, len(alt.Providers))
+			for _, p := range alt.Providers {
+				if p != tool {
+					providers = append(providers, p)
+				}
+			}
+			alt.Providers = providers
+
+			if alt.Active == tool {
+				// The most recently installed remaining provider takes over
+				alt.Active = ""
+				for i := len(providers) - 1; i >= 0; i-- {
+					if ts, ok := state.Installed[providers[i]]; ok && !ts.IsHidden {
+						alt.Active = providers[i]
+						break
+					}
+				}
+				released[name] = alt.Active
+			}
+
+			if len(alt.Providers) == 0 {
+				delete(state.Alternatives, name)
+			} else {
+				state.Alternatives[name] = alt
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// SetAlternative makes tool the owner of a binary. The tool must be one of the
+// binary's providers.
+func (sm *StateManager) SetAlternative(binary, tool string) error {
+	return sm.updateState(func(state *State) error {
+		alt, ok := state.Alternatives[binary]
+		if !ok {
+			return fmt.Errorf("no installed tool provides %q", binary)
+		}
+		if !alt.hasProvider(tool) {
+			return fmt.Errorf("%s does not provide %q (providers: %v)", tool, binary, alt.Providers)
+		}
+		alt.Active = tool
+		state.Alternatives[binary] = alt
+		return nil
+	})
+}
+
+// migrateAlternatives records the providers of each tools/current entry for
+// state written before ownership was tracked. Without the history of which
+// install ran last, the most recently installed provider is assumed to own
+// each entry, matching the last-install-wins behavior of older versions.
+func (s *State) migrateAlternatives() {
+	if s.Alternatives != nil {
+		return
+	}
+	s.Alternatives = make(map[string]BinaryAlternatives)
+
+	type provider struct {
+		tool string
+		vs   VersionState
+	}
+	var installs []provider
+	for name, ts := range s.Installed {
+		if ts.IsHidden || ts.ActiveVersion == "" {
+			continue
+		}
+		installs = append(installs, provider{name, ts.Versions[ts.ActiveVersion]})
+	}
+	sort.Slice(installs, func(i, j int) bool {
+		if !installs[i].vs.InstalledAt.Equal(installs[j].vs.InstalledAt) {
+			return installs[i].vs.InstalledAt.Before(installs[j].vs.InstalledAt)
+		}
+		return installs[i].tool < installs[j].tool
+	})
+
+	for _, in := range installs {
+		for _, name := range linkNames(in.tool, in.vs.Binaries) {
+			alt := s.Alternatives[name]
+			if !alt.hasProvider(in.tool) {
+				alt.Providers = append(alt.Providers, in.tool)
+			}
+			alt.Active = in.tool
+			s.Alternatives[name] = alt
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// CurrentStateSchemaVersion is the state.json schema version written by this
+// version of tsuku. Files without a schema_version field are version 0.
+const CurrentStateSchemaVersion = 3
+
+// StateMigration upgrades state to schema version To from the version before it.
+//
+// Version 0 covers every file written before schema versioning, so migrations
+// must leave state that is already in the target shape unchanged. Every
+// migration runs on every load, which also normalizes entries written through
+// the deprecated fields by code that predates them; the schema version decides
+// when the file on disk is backed up and rewritten.
+type StateMigration struct {
+	To          int
+	Description string
+	Migrate     func(*State)
+}
+
+// stateMigrations is the ordered registry of state migrations. To add a
+// schema change, append a migration and bump CurrentStateSchemaVersion.
+var stateMigrations = []StateMigration{
+	{
+		To:          1,
+		Description: "move single-version tool entries (version, binaries) to active_version and versions",
+		Migrate:     (*State).migrateToMultiVersion,
+	},
+	{
+		To:          2,
+		Description: "fill deprecated version and binaries fields from the active version and normalize empty lists",
+		Migrate:     (*State).migrateLegacyFields,
+	},
+	{
+		To:          3,
+		Description: "record which tool owns each tools/current binary",
+		Migrate:     (*State).migrateAlternatives,
+	},
+}
+
+// StateSchemaTooNewError is returned when state.json was written by a newer
+// tsuku with a schema this version doesn't understand.
+type StateSchemaTooNewError struct {
+	Version   int
+	Supported int
+}
+
+func (e *StateSchemaTooNewError) Error() string {
+	return fmt.Sprintf("state.json has schema version %d but this tsuku supports up to version %d; upgrade tsuku to use this installation",
+		e.Version, e.Supported)
+}
+
+// PendingStateMigrations returns the migrations needed to bring state at
+// schema version from up to CurrentStateSchemaVersion, in order.
+func PendingStateMigrations(from int) []StateMigration {
+	var pending []StateMigration
+	for _, m := range stateMigrations {
+		if m.To > from {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// decodeState parses state.json content and migrates it to the current schema
+// in memory. It returns the schema version the content was written with.
+func decodeState(data []byte) (*State, int, error) {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse state file: %w", err)
+	}
+
+	from := state.SchemaVersion
+	if from > CurrentStateSchemaVersion {
+		return nil, from, &StateSchemaTooNewError{Version: from, Supported: CurrentStateSchemaVersion}
+	}
+
+	// Initialize maps if nil (backward compatibility)
+	if state.Installed == nil {
+		state.Installed = make(map[string]ToolState)
+	}
+	if state.Libs == nil {
+		state.Libs = make(map[string]map[string]LibraryVersionState)
+	}
+
+	for _, m := range stateMigrations {
+		m.Migrate(&state)
+	}
+	state.SchemaVersion = CurrentStateSchemaVersion
+
+	return &state, from, nil
+}
+
+// backupPath returns the path the state file is copied to before migrating
+// from schema version from
+func (sm *StateManager) backupPath(from int) string {
+	return fmt.Sprintf("%s.v%d.bak", sm.statePath(), from)
+}
+
+// backupState writes the pre-migration state file content next to state.json
+func (sm *StateManager) backupState(data []byte, from int) (string, error) {
+	path := sm.backupPath(from)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to back up state file before migration: %w", err)
+	}
+	return path, nil
+}
+
+// StateMigrationResult describes a state.json migration
+type StateMigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Applied     []StateMigration
+	BackupPath  string // Empty for dry runs and when nothing was migrated
+	State       *State
+}
+
+// Migrate upgrades state.json to the current schema, first copying the old
+// file to state.json.v<N>.bak. With dryRun, the migrated state is computed and
+// returned but nothing is written.
+func (sm *StateManager) Migrate(dryRun bool) (*StateMigrationResult, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	lock := NewFileLock(sm.lockPath())
+	if err := lock.LockExclusive(); err != nil {
+		return nil, fmt.Errorf("failed to acquire lock for migration: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	data, err := os.ReadFile(sm.statePath())
+	if os.IsNotExist(err) {
+		return &StateMigrationResult{
+			FromVersion: CurrentStateSchemaVersion,
+			ToVersion:   CurrentStateSchemaVersion,
+			State:       newState(),
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	state, from, err := decodeState(data)
+	if err != nil {
+		return nil, err
+	}
+	result := &StateMigrationResult{
+		FromVersion: from,
+		ToVersion:   CurrentStateSchemaVersion,
+		Applied:     PendingStateMigrations(from),
+		State:       state,
+	}
+	if dryRun || from == CurrentStateSchemaVersion {
+		return result, nil
+	}
+
+	if result.BackupPath, err = sm.backupState(data, from); err != nil {
+		return nil, err
+	}
+	if err := sm.saveWithoutLock(state); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// migrateLegacyFields keeps the deprecated single-version fields in sync with
+// the active version, which older code paths still read, and replaces null
+// lists with empty ones.
+func (s *State) migrateLegacyFields() {
+	for name, tool := range s.Installed {
+		if tool.ActiveVersion != "" {
+			if tool.Version == "" {
+				tool.Version = tool.ActiveVersion
+			}
+			if len(tool.Binaries) == 0 {
+				tool.Binaries = tool.Versions[tool.ActiveVersion].Binaries
+			}
+		}
+		if tool.RequiredBy == nil {
+			tool.RequiredBy = []string{}
+		}
+		s.Installed[name] = tool
+	}
+
+	for _, versions := range s.Libs {
+		for version, ls := range versions {
+			if ls.UsedBy == nil {
+				ls.UsedBy = []string{}
+				versions[version] = ls
+			}
+		}
+	}
+}
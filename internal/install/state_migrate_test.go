@@ -0,0 +1,273 @@
+package install
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+// stateFixturesDir holds state.json files in every historical shape
+const stateFixturesDir = "../../testdata/states"
+
+// loadStateFixture writes a fixture as state.json in a fresh TSUKU_HOME
+func loadStateFixture(t *testing.T, name string) (*StateManager, []byte) {
+	t.Helper()
+	cfg, cleanup := testutil.NewTestConfig(t)
+	t.Cleanup(cleanup)
+
+	data, err := os.ReadFile(filepath.Join(stateFixturesDir, name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.HomeDir, "state.json"), data, 0644); err != nil {
+		t.Fatalf("failed to write state: %v", err)
+	}
+	return NewStateManager(cfg), data
+}
+
+func TestStateFixtures_MigrateOnLoad(t *testing.T) {
+	tests := []struct {
+		fixture string
+		from    int
+		tools   int
+	}{
+		{"empty.json", 0, 0},
+		{"single-tool.json", 0, 1},
+		{"with-dependencies.json", 0, 2},
+		{"multi-version.json", 0, 2},
+		{"active-version-only.json", 0, 1},
+		{"shared-binary.json", 2, 3},
+		{"current.json", CurrentStateSchemaVersion, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			sm, original := loadStateFixture(t, tt.fixture)
+
+			state, err := sm.Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if state.SchemaVersion != CurrentStateSchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", state.SchemaVersion, CurrentStateSchemaVersion)
+			}
+			if len(state.Installed) != tt.tools {
+				t.Errorf("Installed count = %d, want %d", len(state.Installed), tt.tools)
+			}
+
+			for name, ts := range state.Installed {
+				if ts.ActiveVersion == "" || ts.Version != ts.ActiveVersion {
+					t.Errorf("%s: ActiveVersion = %q, Version = %q", name, ts.ActiveVersion, ts.Version)
+				}
+				if _, ok := ts.Versions[ts.ActiveVersion]; !ok {
+					t.Errorf("%s: active version missing from Versions", name)
+				}
+				if ts.RequiredBy == nil {
+					t.Errorf("%s: RequiredBy is nil", name)
+				}
+			}
+			for name, versions := range state.Libs {
+				for v, ls := range versions {
+					if ls.UsedBy == nil {
+						t.Errorf("library %s-%s: UsedBy is nil", name, v)
+					}
+				}
+			}
+
+			// The migrated file is saved and the original kept as a backup
+			data, err := os.ReadFile(sm.statePath())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var onDisk struct {
+				SchemaVersion int `json:"schema_version"`
+			}
+			if err := json.Unmarshal(data, &onDisk); err != nil {
+				t.Fatal(err)
+			}
+			if onDisk.SchemaVersion != CurrentStateSchemaVersion {
+				t.Errorf("schema_version on disk = %d, want %d", onDisk.SchemaVersion, CurrentStateSchemaVersion)
+			}
+
+			backup, err := os.ReadFile(sm.backupPath(tt.from))
+			if tt.from == CurrentStateSchemaVersion {
+				if err == nil {
+					t.Error("current state should not be backed up")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("backup not written: %v", err)
+			}
+			if string(backup) != string(original) {
+				t.Error("backup differs from the original state file")
+			}
+		})
+	}
+}
+
+func TestStateFixtures_MultiVersionPreserved(t *testing.T) {
+	sm, _ := loadStateFixture(t, "multi-version.json")
+	state, err := sm.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	nodejs := state.Installed["nodejs"]
+	if len(nodejs.Versions) != 2 || nodejs.ActiveVersion != "20.10.0" {
+		t.Errorf("nodejs = %+v", nodejs)
+	}
+	if plan := nodejs.Versions["20.10.0"].Plan; plan == nil || len(plan.Steps) != 1 {
+		t.Errorf("stored plan not preserved: %+v", plan)
+	}
+	if state.LLMUsage == nil || state.LLMUsage.DailyCost != 0.12 {
+		t.Errorf("LLMUsage = %+v", state.LLMUsage)
+	}
+	if got := state.Libs["openssl"]["3.2.0"].UsedBy; len(got) != 1 || got[0] != "ruby-3.4.0" {
+		t.Errorf("openssl UsedBy = %v", got)
+	}
+}
+
+func TestStateFixtures_FutureSchemaRefused(t *testing.T) {
+	sm, original := loadStateFixture(t, "future-schema.json")
+
+	_, err := sm.Load()
+	var tooNew *StateSchemaTooNewError
+	if !errors.As(err, &tooNew) {
+		t.Fatalf("Load() error = %v, want StateSchemaTooNewError", err)
+	}
+	if tooNew.Version != 99 || tooNew.Supported != CurrentStateSchemaVersion {
+		t.Errorf("error = %+v", tooNew)
+	}
+
+	// Writes must not clobber state from a newer tsuku
+	if err := sm.UpdateTool("kubectl", func(ts *ToolState) { ts.IsExplicit = false }); err == nil {
+		t.Error("UpdateTool() should fail on a newer schema")
+	}
+	data, _ := os.ReadFile(sm.statePath())
+	if string(data) != string(original) {
+		t.Error("state written by a newer tsuku was modified")
+	}
+}
+
+func TestStateFixtures_MigrateOnReadOnlyHome(t *testing.T) {
+	sm, original := loadStateFixture(t, "with-dependencies.json")
+	home := sm.config.HomeDir
+
+	// Take the lock file before the home becomes read-only, as any earlier
+	// tsuku run would have. Directories in the way of the backup and the
+	// temp state file make the writes fail even when running as root.
+	lock := NewFileLock(sm.lockPath())
+	if err := lock.LockShared(); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+	_ = lock.Unlock()
+	for _, path := range []string{sm.backupPath(0), sm.statePath() + ".tmp"} {
+		if err := os.Mkdir(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(home, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(home, 0755) })
+
+	state, err := sm.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v, want the migrated state", err)
+	}
+	if state.SchemaVersion != CurrentStateSchemaVersion || len(state.Installed) != 2 {
+		t.Errorf("Load() = schema %d with %d tools, want schema %d with 2 tools",
+			state.SchemaVersion, len(state.Installed), CurrentStateSchemaVersion)
+	}
+	data, _ := os.ReadFile(sm.statePath())
+	if string(data) != string(original) {
+		t.Error("state.json changed on a read-only home")
+	}
+}
+
+func TestStateFixtures_Corrupted(t *testing.T) {
+	sm, _ := loadStateFixture(t, "corrupted.json")
+	if _, err := sm.Load(); err == nil {
+		t.Fatal("Load() error = nil, want parse error")
+	}
+}
+
+func TestStateManager_Migrate_DryRun(t *testing.T) {
+	sm, original := loadStateFixture(t, "single-tool.json")
+
+	result, err := sm.Migrate(true)
+	if err != nil {
+		t.Fatalf("Migrate(true) error = %v", err)
+	}
+	if result.FromVersion != 0 || result.ToVersion != CurrentStateSchemaVersion {
+		t.Errorf("result = %d -> %d", result.FromVersion, result.ToVersion)
+	}
+	if len(result.Applied) != len(stateMigrations) {
+		t.Errorf("Applied = %d migrations, want %d", len(result.Applied), len(stateMigrations))
+	}
+	if result.BackupPath != "" {
+		t.Errorf("BackupPath = %q, want empty for a dry run", result.BackupPath)
+	}
+	if result.State.Installed["kubectl"].ActiveVersion != "1.29.0" {
+		t.Error("dry run should return the migrated state")
+	}
+
+	data, _ := os.ReadFile(sm.statePath())
+	if string(data) != string(original) {
+		t.Error("dry run modified state.json")
+	}
+
+	result, err = sm.Migrate(false)
+	if err != nil {
+		t.Fatalf("Migrate(false) error = %v", err)
+	}
+	if result.BackupPath != sm.backupPath(0) {
+		t.Errorf("BackupPath = %q, want %q", result.BackupPath, sm.backupPath(0))
+	}
+
+	// Nothing left to do
+	result, err = sm.Migrate(false)
+	if err != nil {
+		t.Fatalf("second Migrate() error = %v", err)
+	}
+	if result.FromVersion != CurrentStateSchemaVersion || len(result.Applied) != 0 {
+		t.Errorf("second Migrate() = %+v, want no migrations", result)
+	}
+}
+
+func TestStateMigrations_Ordered(t *testing.T) {
+	for i, m := range stateMigrations {
+		if m.To != i+1 {
+			t.Errorf("migration %d has To = %d, want %d", i, m.To, i+1)
+		}
+	}
+	if last := stateMigrations[len(stateMigrations)-1].To; last != CurrentStateSchemaVersion {
+		t.Errorf("last migration = %d, want CurrentStateSchemaVersion %d", last, CurrentStateSchemaVersion)
+	}
+}
+
+func TestStateFixtures_AlternativesMigrated(t *testing.T) {
+	sm, _ := loadStateFixture(t, "shared-binary.json")
+	state, err := sm.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// The most recent install owns the shared binary, as it did on disk
+	goAlt := state.Alternatives["go"]
+	if goAlt.Active != "go" || len(goAlt.Providers) != 2 || goAlt.Providers[0] != "golang" {
+		t.Errorf("go alternatives = %+v, want go active with providers [golang go]", goAlt)
+	}
+	if gofmt := state.Alternatives["gofmt"]; gofmt.Active != "golang" {
+		t.Errorf("gofmt alternatives = %+v, want golang active", gofmt)
+	}
+	// Hidden tools have no entries in current/
+	if _, ok := state.Alternatives["zig"]; ok {
+		t.Error("hidden tool zig should not own a binary")
+	}
+}
@@ -0,0 +1,288 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsukumogami/tsuku/internal/config"
+)
+
+// Store is a content-addressed file store under $TSUKU_HOME/store.
+//
+// Every regular file of a tool installed through the store is kept once in
+// objects/, keyed by its SHA-256, and hardlinked into the tool directory, so
+// versions that share files (patch releases of a JDK or Go toolchain) share
+// disk space. Objects are read-only because every hardlink shares the same
+// inode. Where hardlinks aren't possible (another filesystem, link limit),
+// files are reflinked or copied instead.
+//
+// The hardlink count of an object is its reference count: an object whose
+// only link is its store entry is no longer used by any tool and is removed
+// by GC.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store handle for the store directory in cfg.
+func NewStore(cfg *config.Config) *Store {
+	return &Store{dir: cfg.StoreDir}
+}
+
+// objectsDir returns the directory holding the store objects
+func (s *Store) objectsDir() string {
+	return filepath.Join(s.dir, "objects")
+}
+
+// objectPath returns the path of an object. Executable files are stored
+// separately from non-executable ones with the same content, since hardlinks
+// share permissions.
+func (s *Store) objectPath(hash string, executable bool) string {
+	name := hash
+	if executable {
+		name += ".x"
+	}
+	return filepath.Join(s.objectsDir(), hash[:2], name)
+}
+
+// Materialize recreates the tree at src in dst with every regular file
+// hardlinked from the store, adding files the store doesn't have yet.
+// Directories and symlinks are created in dst as copyDir would.
+func (s *Store) Materialize(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			return copySymlink(path, target)
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode().IsRegular():
+			return s.materializeFile(path, target, rel, info)
+		default:
+			return nil // Skip sockets, devices and other special files
+		}
+	})
+}
+
+// materializeFile adds one file to the store and links it at target
+func (s *Store) materializeFile(path, target, rel string, info os.FileInfo) error {
+	object, err := s.add(path, info)
+	if err != nil {
+		return fmt.Errorf("failed to store %s: %w", rel, err)
+	}
+	err = linkObject(object, target)
+	if err != nil && errors.Is(err, fs.ErrNotExist) {
+		// A concurrent GC may remove an existing object before it is linked;
+		// add it again
+		if object, err = s.add(path, info); err == nil {
+			err = linkObject(object, target)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to link %s: %w", rel, err)
+	}
+	return nil
+}
+
+// add stores a file and returns its object path. Existing objects are reused.
+func (s *Store) add(path string, info os.FileInfo) (string, error) {
+	hash, err := ComputeFileChecksum(path)
+	if err != nil {
+		return "", err
+	}
+
+	executable := info.Mode().Perm()&0111 != 0
+	object := s.objectPath(hash, executable)
+	if _, err := os.Lstat(object); err == nil {
+		return object, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(object), 0755); err != nil {
+		return "", fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	// Write to a temporary file and rename, so concurrent installs adding the
+	// same object never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(object), ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+
+	if err := copyFile(path, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	mode := os.FileMode(0444)
+	if executable {
+		mode = 0555
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Rename(tmpPath, object); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return object, nil
+}
+
+// linkObject places an object at dst as a hardlink, falling back to a
+// reflink and then a plain copy
+func linkObject(object, dst string) error {
+	os.Remove(dst)
+	if err := os.Link(object, dst); err == nil {
+		return nil
+	}
+	if err := cloneFile(object, dst); err == nil {
+		return nil
+	}
+	os.Remove(dst)
+	return copyFile(object, dst)
+}
+
+// StoreStats summarizes the contents of the store
+type StoreStats struct {
+	Objects      int   // Number of stored files
+	Size         int64 // Disk space used by the objects
+	Unreferenced int   // Objects no tool links to
+	Saved        int64 // Disk space saved compared to a full copy per tool version
+}
+
+// storeObject is an object found while walking the store
+type storeObject struct {
+	path  string
+	hash  string
+	size  int64
+	links uint64 // Hardlink count; 0 if unknown on this platform
+}
+
+// walkObjects calls fn for every object in the store
+func (s *Store) walkObjects(fn func(storeObject) error) error {
+	err := filepath.WalkDir(s.objectsDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		links, _ := linkCount(info)
+		return fn(storeObject{
+			path:  path,
+			hash:  strings.TrimSuffix(d.Name(), ".x"),
+			size:  info.Size(),
+			links: links,
+		})
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Stats returns a summary of the store contents.
+func (s *Store) Stats() (*StoreStats, error) {
+	stats := &StoreStats{}
+	err := s.walkObjects(func(o storeObject) error {
+		stats.Objects++
+		stats.Size += o.size
+		switch {
+		case o.links == 1:
+			stats.Unreferenced++
+		case o.links > 2:
+			stats.Saved += o.size * int64(o.links-2)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+	return stats, nil
+}
+
+// GC removes objects that no tool directory links to, returning the number
+// of objects removed and the bytes freed. On platforms without hardlink
+// counts nothing is removed.
+func (s *Store) GC() (int, int64, error) {
+	var removed int
+	var freed int64
+	err := s.walkObjects(func(o storeObject) error {
+		if o.links != 1 {
+			return nil
+		}
+		if err := os.Remove(o.path); err != nil {
+			return err
+		}
+		removed++
+		freed += o.size
+		return nil
+	})
+	if err != nil {
+		return removed, freed, fmt.Errorf("failed to collect store garbage: %w", err)
+	}
+	return removed, freed, nil
+}
+
+// Verify checks that every object still matches the checksum it is stored
+// under. Since objects are shared by hardlinks, a mismatch means every tool
+// linking the object is affected.
+func (s *Store) Verify() ([]ChecksumMismatch, error) {
+	var mismatches []ChecksumMismatch
+	err := s.walkObjects(func(o storeObject) error {
+		rel, _ := filepath.Rel(s.dir, o.path)
+		actual, err := ComputeFileChecksum(o.path)
+		if err != nil {
+			mismatches = append(mismatches, ChecksumMismatch{Path: rel, Expected: o.hash, Error: err})
+			return nil
+		}
+		if actual != o.hash {
+			mismatches = append(mismatches, ChecksumMismatch{Path: rel, Expected: o.hash, Actual: actual})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+	return mismatches, nil
+}
+
+// GetStore returns the content-addressed store
+func (m *Manager) GetStore() *Store {
+	return m.store
+}
+
+// collectStoreGarbage removes store objects left unreferenced by a removed
+// tool version. Failures only leave garbage behind, so they are reported as
+// warnings.
+func (m *Manager) collectStoreGarbage() {
+	removed, _, err := m.store.GC()
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	if removed > 0 {
+		fmt.Printf("🧹 Removed %d unused store objects\n", removed)
+	}
+}
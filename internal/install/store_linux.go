@@ -0,0 +1,31 @@
+//go:build linux
+
+package install
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// cloneFile creates dst as a reflink of src (FICLONE), sharing data blocks
+// on filesystems that support it (btrfs, XFS).
+func cloneFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	return unix.IoctlFileClone(int(out.Fd()), int(in.Fd()))
+}
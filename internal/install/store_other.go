@@ -0,0 +1,10 @@
+//go:build !linux
+
+package install
+
+import "errors"
+
+// cloneFile is only implemented on Linux; other platforms copy instead.
+func cloneFile(src, dst string) error {
+	return errors.ErrUnsupported
+}
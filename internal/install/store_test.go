@@ -0,0 +1,214 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+// writeInstallTree creates a work dir whose .install holds the given files
+func writeInstallTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	workDir, cleanup := testutil.TempDir(t)
+	t.Cleanup(cleanup)
+
+	for name, content := range files {
+		path := filepath.Join(workDir, ".install", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return workDir
+}
+
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	ai, err := os.Stat(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(ai, bi)
+}
+
+func TestInstallWithOptions_UseStoreDeduplicates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hardlink counts are not available on Windows")
+	}
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	opts := DefaultInstallOptions()
+	opts.Binaries = []string{"bin/tool"}
+	opts.UseStore = true
+
+	for _, version := range []string{"1.0.0", "1.0.1"} {
+		workDir := writeInstallTree(t, map[string]string{
+			"bin/tool":    "#!/bin/sh\necho " + version,
+			"lib/shared":  "shared library",
+			"share/doc/a": "docs",
+		})
+		if err := mgr.InstallWithOptions("tool", version, workDir, opts); err != nil {
+			t.Fatalf("InstallWithOptions(%s) error = %v", version, err)
+		}
+	}
+
+	v1 := cfg.ToolDir("tool", "1.0.0")
+	v2 := cfg.ToolDir("tool", "1.0.1")
+	if !sameFile(t, filepath.Join(v1, "lib", "shared"), filepath.Join(v2, "lib", "shared")) {
+		t.Error("identical files were not deduplicated")
+	}
+	if sameFile(t, filepath.Join(v1, "bin", "tool"), filepath.Join(v2, "bin", "tool")) {
+		t.Error("different files share an object")
+	}
+
+	stats, err := mgr.GetStore().Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Objects != 4 {
+		t.Errorf("Objects = %d, want 4", stats.Objects)
+	}
+	if stats.Saved == 0 {
+		t.Error("Saved = 0, want space saved by shared objects")
+	}
+
+	state, _ := mgr.GetState().Load()
+	if !state.Installed["tool"].Versions["1.0.0"].Store {
+		t.Error("version state not marked as store-backed")
+	}
+}
+
+func TestRemoveVersion_CollectsStoreGarbage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hardlink counts are not available on Windows")
+	}
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	mgr := New(cfg)
+
+	opts := DefaultInstallOptions()
+	opts.UseStore = true
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		workDir := writeInstallTree(t, map[string]string{
+			"bin/tool":   version,
+			"lib/shared": "shared",
+		})
+		if err := mgr.InstallWithOptions("tool", version, workDir, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := mgr.RemoveVersion("tool", "1.0.0"); err != nil {
+		t.Fatalf("RemoveVersion() error = %v", err)
+	}
+	stats, _ := mgr.GetStore().Stats()
+	if stats.Objects != 2 {
+		t.Errorf("Objects = %d after removing 1.0.0, want 2 (shared object kept)", stats.Objects)
+	}
+
+	if err := mgr.RemoveAllVersions("tool"); err != nil {
+		t.Fatal(err)
+	}
+	stats, _ = mgr.GetStore().Stats()
+	if stats.Objects != 0 {
+		t.Errorf("Objects = %d after removing all versions, want 0", stats.Objects)
+	}
+}
+
+func TestStore_MaterializePreservesTree(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	store := NewStore(cfg)
+
+	workDir := writeInstallTree(t, map[string]string{"bin/tool-1.0": "binary"})
+	src := filepath.Join(workDir, ".install")
+	if err := os.Chmod(filepath.Join(src, "bin", "tool-1.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "README"), []byte("readme"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("tool-1.0", filepath.Join(src, "bin", "tool")); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(cfg.ToolsDir, "tool-1.0")
+	if err := store.Materialize(src, dst); err != nil {
+		t.Fatalf("Materialize() error = %v", err)
+	}
+
+	target, err := os.Readlink(filepath.Join(dst, "bin", "tool"))
+	if err != nil || target != "tool-1.0" {
+		t.Errorf("symlink = %q, %v; want tool-1.0", target, err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "bin", "tool"))
+	if err != nil || string(data) != "binary" {
+		t.Errorf("content = %q, %v", data, err)
+	}
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(filepath.Join(dst, "bin", "tool-1.0"))
+		if info.Mode().Perm()&0111 == 0 {
+			t.Errorf("executable lost its mode: %v", info.Mode())
+		}
+		info, _ = os.Stat(filepath.Join(dst, "README"))
+		if info.Mode().Perm()&0111 != 0 {
+			t.Errorf("README became executable: %v", info.Mode())
+		}
+	}
+}
+
+func TestStore_VerifyDetectsCorruption(t *testing.T) {
+	cfg, cleanup := testutil.NewTestConfig(t)
+	defer cleanup()
+	store := NewStore(cfg)
+
+	workDir := writeInstallTree(t, map[string]string{"a": "a", "b": "b"})
+	dst := filepath.Join(cfg.ToolsDir, "tool-1.0")
+	if err := store.Materialize(filepath.Join(workDir, ".install"), dst); err != nil {
+		t.Fatal(err)
+	}
+
+	mismatches, err := store.Verify()
+	if err != nil || len(mismatches) != 0 {
+		t.Fatalf("Verify() = %v, %v; want no mismatches", mismatches, err)
+	}
+
+	object, err := store.add(filepath.Join(workDir, ".install", "a"), mustStat(t, filepath.Join(workDir, ".install", "a")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(object, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(object, []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mismatches, err = store.Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 1 || mismatches[0].Actual == mismatches[0].Expected {
+		t.Errorf("Verify() = %+v, want one mismatch", mismatches)
+	}
+}
+
+func mustStat(t *testing.T, path string) os.FileInfo {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
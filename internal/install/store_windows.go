@@ -0,0 +1,11 @@
+//go:build windows
+
+package install
+
+import "os"
+
+// linkCount reports hardlink counts as unknown; os.FileInfo doesn't carry
+// them on Windows, so store objects are never garbage collected there.
+func linkCount(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,194 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/config"
+)
+
+const (
+	// toolLockPollInterval is how often a busy tool lock is retried
+	toolLockPollInterval = 200 * time.Millisecond
+
+	// toolLockProgressInterval is how often waiting is reported
+	toolLockProgressInterval = 5 * time.Second
+)
+
+// ToolLockTimeoutError is returned when a tool lock held by another process
+// isn't released within the lock timeout.
+type ToolLockTimeoutError struct {
+	Tool    string
+	Path    string
+	Timeout time.Duration
+}
+
+func (e *ToolLockTimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %v waiting for another tsuku process to finish with %s (lock: %s)",
+		e.Timeout, e.Tool, e.Path)
+}
+
+// ToolLocks manages the per-tool install locks held by this process.
+//
+// Each tool has a lock file under $TSUKU_HOME/locks, held exclusively while the
+// tool is being installed, so concurrent tsuku processes can install
+// unrelated tools in parallel but never stage the same tool twice. Locks are
+// reentrant within a ToolLocks: acquiring a tool that is already held is a
+// no-op, which lets a dependency chain lock everything up front and then
+// install each dependency under the same locks.
+type ToolLocks struct {
+	dir     string
+	timeout time.Duration
+
+	// OnWait is called periodically while waiting for a lock held by
+	// another process. It may be nil.
+	OnWait func(tool string, waited time.Duration)
+
+	pollInterval     time.Duration
+	progressInterval time.Duration
+
+	mu   sync.Mutex
+	held map[string]*FileLock
+}
+
+// NewToolLocks creates a lock set for the locks directory in cfg. Waiting for
+// a lock gives up after timeout.
+func NewToolLocks(cfg *config.Config, timeout time.Duration) *ToolLocks {
+	return &ToolLocks{
+		dir:              cfg.LocksDir,
+		timeout:          timeout,
+		pollInterval:     toolLockPollInterval,
+		progressInterval: toolLockProgressInterval,
+		held:             make(map[string]*FileLock),
+	}
+}
+
+// lockPath returns the lock file path for a tool
+func (l *ToolLocks) lockPath(name string) string {
+	return filepath.Join(l.dir, name+".lock")
+}
+
+// Held reports whether this process holds the lock for a tool.
+func (l *ToolLocks) Held(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.held[name]
+	return ok
+}
+
+// Acquire locks the given tools, skipping any already held, and returns a
+// function that releases the locks taken by this call.
+//
+// Locks are always taken in sorted name order, so two processes locking
+// overlapping dependency chains cannot deadlock. If any lock can't be taken,
+// the locks acquired so far by this call are released.
+func (l *ToolLocks) Acquire(ctx context.Context, names ...string) (func(), error) {
+	sorted := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if err := validateLockName(name); err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create locks directory: %w", err)
+	}
+
+	var acquired []string
+	release := func() {
+		l.release(acquired)
+	}
+
+	for _, name := range sorted {
+		if l.Held(name) {
+			continue
+		}
+		lock, err := l.wait(ctx, name)
+		if err != nil {
+			release()
+			return nil, err
+		}
+		l.mu.Lock()
+		l.held[name] = lock
+		l.mu.Unlock()
+		acquired = append(acquired, name)
+	}
+
+	return release, nil
+}
+
+// wait takes the lock for a tool, polling while another process holds it
+func (l *ToolLocks) wait(ctx context.Context, name string) (*FileLock, error) {
+	lock := NewFileLock(l.lockPath(name))
+	start := time.Now()
+	lastReport := start
+
+	for {
+		ok, err := lock.TryLockExclusive()
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", name, err)
+		}
+		if ok {
+			return lock, nil
+		}
+
+		waited := time.Since(start)
+		if waited >= l.timeout {
+			return nil, &ToolLockTimeoutError{Tool: name, Path: l.lockPath(name), Timeout: l.timeout}
+		}
+		if l.OnWait != nil && (waited < l.pollInterval || time.Since(lastReport) >= l.progressInterval) {
+			l.OnWait(name, waited)
+			lastReport = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(l.pollInterval):
+		}
+	}
+}
+
+// release unlocks the named tools in reverse acquisition order
+func (l *ToolLocks) release(names []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := len(names) - 1; i >= 0; i-- {
+		if lock, ok := l.held[names[i]]; ok {
+			_ = lock.Unlock()
+			delete(l.held, names[i])
+		}
+	}
+}
+
+// ReleaseAll unlocks every tool held by this lock set.
+func (l *ToolLocks) ReleaseAll() {
+	l.mu.Lock()
+	names := make([]string, 0, len(l.held))
+	for name := range l.held {
+		names = append(names, name)
+	}
+	l.mu.Unlock()
+	sort.Strings(names)
+	l.release(names)
+}
+
+// validateLockName rejects tool names that can't be used as a lock file name
+func validateLockName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid tool name for lock: %q", name)
+	}
+	return nil
+}
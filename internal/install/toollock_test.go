@@ -0,0 +1,147 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/testutil"
+)
+
+func newTestToolLocks(t *testing.T, timeout time.Duration) *ToolLocks {
+	t.Helper()
+	cfg, cleanup := testutil.NewTestConfig(t)
+	t.Cleanup(cleanup)
+
+	l := NewToolLocks(cfg, timeout)
+	l.pollInterval = 10 * time.Millisecond
+	l.progressInterval = 20 * time.Millisecond
+	return l
+}
+
+// holdLock takes a tool lock through a separate file handle, as another
+// process would
+func holdLock(t *testing.T, l *ToolLocks, name string) *FileLock {
+	t.Helper()
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	lock := NewFileLock(l.lockPath(name))
+	if err := lock.LockExclusive(); err != nil {
+		t.Fatalf("LockExclusive() error = %v", err)
+	}
+	t.Cleanup(func() { _ = lock.Unlock() })
+	return lock
+}
+
+func TestToolLocks_AcquireAndRelease(t *testing.T) {
+	l := newTestToolLocks(t, time.Second)
+
+	release, err := l.Acquire(context.Background(), "nodejs", "serverless", "nodejs")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if !l.Held("nodejs") || !l.Held("serverless") {
+		t.Fatal("expected nodejs and serverless to be held")
+	}
+	if _, err := os.Stat(l.lockPath("nodejs")); err != nil {
+		t.Errorf("lock file not created: %v", err)
+	}
+
+	// Reacquiring held locks is a no-op and its release keeps them held
+	inner, err := l.Acquire(context.Background(), "nodejs")
+	if err != nil {
+		t.Fatalf("nested Acquire() error = %v", err)
+	}
+	inner()
+	if !l.Held("nodejs") {
+		t.Error("nested release dropped a lock taken by the outer Acquire")
+	}
+
+	release()
+	if l.Held("nodejs") || l.Held("serverless") {
+		t.Error("locks still held after release")
+	}
+
+	// Another process can now take the lock
+	ok, err := NewFileLock(l.lockPath("nodejs")).TryLockExclusive()
+	if err != nil || !ok {
+		t.Errorf("TryLockExclusive() after release = %v, %v; want true, nil", ok, err)
+	}
+}
+
+func TestToolLocks_WaitsForOtherProcess(t *testing.T) {
+	l := newTestToolLocks(t, 5*time.Second)
+	other := holdLock(t, l, "rust")
+
+	var waits []string
+	l.OnWait = func(tool string, waited time.Duration) {
+		waits = append(waits, tool)
+	}
+
+	go func() {
+		time.Sleep(60 * time.Millisecond)
+		_ = other.Unlock()
+	}()
+
+	release, err := l.Acquire(context.Background(), "rust")
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer release()
+
+	if len(waits) == 0 || waits[0] != "rust" {
+		t.Errorf("OnWait calls = %v, want progress reports for rust", waits)
+	}
+}
+
+func TestToolLocks_Timeout(t *testing.T) {
+	l := newTestToolLocks(t, 50*time.Millisecond)
+	holdLock(t, l, "zig")
+
+	_, err := l.Acquire(context.Background(), "aaa", "zig")
+	var timeoutErr *ToolLockTimeoutError
+	if !errors.As(err, &timeoutErr) {
+		t.Fatalf("Acquire() error = %v, want ToolLockTimeoutError", err)
+	}
+	if timeoutErr.Tool != "zig" {
+		t.Errorf("Tool = %q, want zig", timeoutErr.Tool)
+	}
+	// Locks taken earlier in the same call are released on failure
+	if l.Held("aaa") {
+		t.Error("aaa still held after failed Acquire")
+	}
+}
+
+func TestToolLocks_ContextCanceled(t *testing.T) {
+	l := newTestToolLocks(t, 5*time.Second)
+	holdLock(t, l, "go")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Acquire(ctx, "go"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestToolLocks_InvalidName(t *testing.T) {
+	l := newTestToolLocks(t, time.Second)
+	for _, name := range []string{"", "..", "../state", `a\b`} {
+		if _, err := l.Acquire(context.Background(), name); err == nil {
+			t.Errorf("Acquire(%q) succeeded, want error", name)
+		}
+	}
+}
+
+func TestToolLocks_ReleaseAll(t *testing.T) {
+	l := newTestToolLocks(t, time.Second)
+	if _, err := l.Acquire(context.Background(), "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	l.ReleaseAll()
+	if l.Held("a") || l.Held("b") {
+		t.Error("locks still held after ReleaseAll")
+	}
+}
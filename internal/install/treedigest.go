@@ -0,0 +1,162 @@
+package install
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TreeManifest describes an installed tree in a normalized form, mapping each
+// path relative to the tree root to its type, content and mode. Modification
+// times, ownership and write permissions are left out, so two builds of the
+// same plan can be compared regardless of when or where they ran.
+type TreeManifest map[string]string
+
+// treePathPlaceholder replaces the machine-specific directories passed to
+// ComputeTreeManifest in file contents and symlink targets
+const treePathPlaceholder = "@@TSUKU_PATH@@"
+
+// ComputeTreeManifest scans the tree at root. Occurrences of prefixes (such as
+// the install directory and $TSUKU_HOME) in file contents and symlink targets
+// are replaced by a placeholder first, so trees built under different homes
+// compare equal.
+func ComputeTreeManifest(root string, prefixes ...string) (TreeManifest, error) {
+	// Replace longer prefixes first, so a prefix nested in another is not
+	// replaced partially
+	var replace [][]byte
+	for _, p := range prefixes {
+		if p != "" {
+			replace = append(replace, []byte(filepath.Clean(p)))
+		}
+	}
+	sort.Slice(replace, func(i, j int) bool { return len(replace[i]) > len(replace[j]) })
+	normalize := func(data []byte) []byte {
+		for _, p := range replace {
+			data = bytes.ReplaceAll(data, p, []byte(treePathPlaceholder))
+		}
+		return data
+	}
+
+	manifest := make(TreeManifest)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			manifest[rel] = "symlink " + string(normalize([]byte(target)))
+		case info.IsDir():
+			manifest[rel] = "dir"
+		case info.Mode().IsRegular():
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			sum := sha256.Sum256(normalize(data))
+			// Only the executable bit is meaningful; store objects are read-only
+			mode := "0644"
+			if info.Mode().Perm()&0111 != 0 {
+				mode = "0755"
+			}
+			manifest[rel] = fmt.Sprintf("file %s %s", mode, hex.EncodeToString(sum[:]))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return manifest, nil
+}
+
+// Digest returns a SHA256 digest of the whole manifest.
+func (m TreeManifest) Digest() string {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, p := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", p, m[p])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// DiffTreeManifests returns the sorted paths that differ between two
+// manifests, including paths present in only one of them.
+func DiffTreeManifests(a, b TreeManifest) []string {
+	var diff []string
+	for p, entry := range a {
+		if b[p] != entry {
+			diff = append(diff, p)
+		}
+	}
+	for p := range b {
+		if _, ok := a[p]; !ok {
+			diff = append(diff, p)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// describeTreeEntry returns a short human-readable form of a manifest entry
+func describeTreeEntry(entry string) string {
+	if entry == "" {
+		return "missing"
+	}
+	fields := strings.Fields(entry)
+	if fields[0] == "file" && len(fields) == 3 {
+		return fmt.Sprintf("file %s %s", fields[1], fields[2][:12])
+	}
+	return entry
+}
+
+// DescribeTreeDifference explains how a path differs between two manifests.
+func DescribeTreeDifference(a, b TreeManifest, path string) string {
+	return fmt.Sprintf("%s -> %s", describeTreeEntry(a[path]), describeTreeEntry(b[path]))
+}
+
+// ComputeBuildManifest scans the install tree a plan execution staged in
+// workDir/.install. Paths under workDir and home are normalized; builds embed
+// their temporary prefix, so this is the manifest both installs and
+// reproducibility checks record.
+func ComputeBuildManifest(workDir, home string) (TreeManifest, error) {
+	return ComputeTreeManifest(filepath.Join(workDir, ".install"), workDir, home)
+}
+
+// computeTreeDigest returns the normalized digest of the tree staged in
+// workDir, or "" if it can't be computed. Like binary checksums, the digest
+// is informational and never fails an installation.
+func (m *Manager) computeTreeDigest(workDir string) string {
+	manifest, err := ComputeBuildManifest(workDir, m.config.HomeDir)
+	if err != nil {
+		fmt.Printf("⚠️  Could not compute tree digest: %v\n", err)
+		return ""
+	}
+	return manifest.Digest()
+}
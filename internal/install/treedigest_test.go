@@ -0,0 +1,85 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTree creates a small install tree under root that embeds root in a
+// text file and a symlink target
+func writeTree(t *testing.T, root, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "bin", "tool"), []byte(content), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "prefix.txt"), []byte("prefix="+root+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(root, "bin", "tool"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestComputeTreeManifest_NormalizesPrefixes(t *testing.T) {
+	a := filepath.Join(t.TempDir(), "tool-1.0")
+	b := filepath.Join(t.TempDir(), "elsewhere", "tool-1.0")
+	writeTree(t, a, "#!/bin/sh\necho hi\n")
+	writeTree(t, b, "#!/bin/sh\necho hi\n")
+
+	// Modification times are ignored
+	old := time.Now().Add(-24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(b, "bin", "tool"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	ma, err := ComputeTreeManifest(a, a)
+	if err != nil {
+		t.Fatalf("ComputeTreeManifest() error = %v", err)
+	}
+	mb, err := ComputeTreeManifest(b, b)
+	if err != nil {
+		t.Fatalf("ComputeTreeManifest() error = %v", err)
+	}
+
+	if diff := DiffTreeManifests(ma, mb); len(diff) != 0 {
+		t.Errorf("DiffTreeManifests() = %v, want no differences", diff)
+	}
+	if ma.Digest() != mb.Digest() {
+		t.Error("digests differ for trees built under different prefixes")
+	}
+}
+
+func TestDiffTreeManifests(t *testing.T) {
+	a := filepath.Join(t.TempDir(), "tool")
+	b := filepath.Join(t.TempDir(), "tool")
+	writeTree(t, a, "v1")
+	writeTree(t, b, "v2")
+	if err := os.WriteFile(filepath.Join(b, "extra"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(filepath.Join(b, "prefix.txt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	ma, _ := ComputeTreeManifest(a, a)
+	mb, _ := ComputeTreeManifest(b, b)
+
+	diff := DiffTreeManifests(ma, mb)
+	want := []string{"bin/tool", "extra", "prefix.txt"}
+	if len(diff) != len(want) {
+		t.Fatalf("DiffTreeManifests() = %v, want %v", diff, want)
+	}
+	for i := range want {
+		if diff[i] != want[i] {
+			t.Errorf("DiffTreeManifests()[%d] = %q, want %q", i, diff[i], want[i])
+		}
+	}
+	if got := DescribeTreeDifference(ma, mb, "extra"); got[:7] != "missing" {
+		t.Errorf("DescribeTreeDifference(extra) = %q, want missing in first tree", got)
+	}
+}
@@ -0,0 +1,99 @@
+// Package landlock confines processes started by tsuku actions with Linux
+// Landlock rules, so build scripts and ecosystem installers can only write to
+// the directories of the step they belong to.
+//
+// Landlock restricts the calling thread and everything it executes later.
+// The tsuku process itself stays unrestricted: commands are started through
+// a launcher (tsuku re-executed with LauncherArg) that restricts itself and
+// then replaces its image with the real command.
+package landlock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+)
+
+// LauncherArg is the first argument that makes the tsuku binary act as the
+// launcher of a confined command
+const LauncherArg = "__landlock-exec"
+
+// Ruleset lists the paths a confined process may access. Everything else
+// on the filesystem is denied.
+type Ruleset struct {
+	// ReadWrite paths may be read, written, executed and modified
+	ReadWrite []string `json:"read_write"`
+
+	// ReadOnly paths may be read and executed
+	ReadOnly []string `json:"read_only"`
+}
+
+// Supported reports whether the running kernel enforces Landlock
+func Supported() bool {
+	return ABIVersion() > 0
+}
+
+// Wrap rewrites cmd to start through launcher, which applies rs before
+// executing the original command. The command must already be resolved
+// (as done by exec.Command).
+func Wrap(cmd *exec.Cmd, launcher string, rs Ruleset) error {
+	if cmd.Err != nil {
+		return cmd.Err
+	}
+	rules, err := json.Marshal(rs.normalize())
+	if err != nil {
+		return err
+	}
+	args := append([]string{launcher, LauncherArg, string(rules), cmd.Path}, cmd.Args...)
+	cmd.Path = launcher
+	cmd.Args = args
+	return nil
+}
+
+// normalize makes paths absolute and removes duplicates, so the rules are
+// stable across runs
+func (rs Ruleset) normalize() Ruleset {
+	clean := func(paths []string) []string {
+		seen := make(map[string]bool)
+		var out []string
+		for _, p := range paths {
+			if p == "" {
+				continue
+			}
+			if abs, err := filepath.Abs(p); err == nil {
+				p = abs
+			}
+			if !seen[p] {
+				seen[p] = true
+				out = append(out, p)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	return Ruleset{ReadWrite: clean(rs.ReadWrite), ReadOnly: clean(rs.ReadOnly)}
+}
+
+// Main runs the launcher: args are the encoded ruleset, the program path
+// and its argv. It only returns on failure, after reporting it; the exit
+// code 126 matches the shell's "cannot execute".
+func Main(args []string) {
+	if err := launch(args); err != nil {
+		fmt.Fprintf(os.Stderr, "tsuku: confinement: %v\n", err)
+		os.Exit(126)
+	}
+}
+
+func launch(args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("usage: %s <rules> <path> <argv...>", LauncherArg)
+	}
+	var rs Ruleset
+	if err := json.Unmarshal([]byte(args[0]), &rs); err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+	return restrictAndExec(rs, args[1], args[2:])
+}
@@ -0,0 +1,146 @@
+//go:build linux
+
+package landlock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Filesystem rights by the Landlock ABI version that introduced them
+const (
+	accessFSv1 = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+	accessFSv2 = accessFSv1 | unix.LANDLOCK_ACCESS_FS_REFER
+	accessFSv3 = accessFSv2 | unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	accessFSv5 = accessFSv3 | unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+
+	accessRead = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR
+
+	// Rights that apply to regular files; rules on files may only grant these
+	accessFile = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE |
+		unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+)
+
+var abiOnce = sync.OnceValue(func() int {
+	v, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0
+	}
+	return int(v)
+})
+
+// ABIVersion returns the Landlock ABI version of the running kernel, or 0
+// when Landlock is unavailable or disabled
+func ABIVersion() int {
+	return abiOnce()
+}
+
+// handledAccess returns every filesystem right the kernel can restrict
+func handledAccess(abi int) uint64 {
+	switch {
+	case abi >= 5:
+		return accessFSv5
+	case abi >= 3:
+		return accessFSv3
+	case abi == 2:
+		return accessFSv2
+	default:
+		return accessFSv1
+	}
+}
+
+// restrictAndExec confines the current thread and executes path in its
+// place. The thread stays locked so the exec happens from the restricted
+// thread.
+func restrictAndExec(rs Ruleset, path string, argv []string) error {
+	runtime.LockOSThread()
+
+	abi := ABIVersion()
+	if abi == 0 {
+		return errors.New("Landlock is not supported by this kernel")
+	}
+	handled := handledAccess(abi)
+
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("failed to create ruleset: %w", errno)
+	}
+	rulesetFd := int(fd)
+	defer unix.Close(rulesetFd)
+
+	for _, p := range rs.ReadWrite {
+		if err := addPathRule(rulesetFd, p, handled); err != nil {
+			return err
+		}
+	}
+	for _, p := range rs.ReadOnly {
+		if err := addPathRule(rulesetFd, p, accessRead&handled); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(rulesetFd), 0, 0); errno != 0 {
+		return fmt.Errorf("failed to enforce ruleset: %w", errno)
+	}
+
+	if err := syscall.Exec(path, argv, os.Environ()); err != nil {
+		return fmt.Errorf("failed to execute %s: %w", path, err)
+	}
+	return nil
+}
+
+// addPathRule grants access beneath path. Missing paths are skipped: the
+// default rules name directories that don't exist on every system.
+func addPathRule(rulesetFd int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if err != nil {
+		if errors.Is(err, unix.ENOENT) || errors.Is(err, unix.ENOTDIR) || errors.Is(err, unix.EACCES) {
+			return nil
+		}
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var st unix.Stat_t
+	if err := unix.Fstat(fd, &st); err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= accessFile
+	}
+
+	rule := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(rulesetFd), unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&rule)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to add rule for %s: %w", path, errno)
+	}
+	return nil
+}
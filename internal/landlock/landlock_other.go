@@ -0,0 +1,15 @@
+//go:build !linux
+
+package landlock
+
+import "errors"
+
+// ABIVersion returns 0: Landlock only exists on Linux
+func ABIVersion() int {
+	return 0
+}
+
+// restrictAndExec is only implemented on Linux
+func restrictAndExec(rs Ruleset, path string, argv []string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,106 @@
+package landlock
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// TestMain lets the test binary act as the launcher, as the tsuku binary
+// does in production
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == LauncherArg {
+		Main(os.Args[2:])
+	}
+	os.Exit(m.Run())
+}
+
+func TestWrap(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "true")
+	target := cmd.Path
+
+	rs := Ruleset{
+		ReadWrite: []string{"/work", "/tmp", "/work", ""},
+		ReadOnly:  []string{"/usr"},
+	}
+	if err := Wrap(cmd, "/opt/tsuku", rs); err != nil {
+		t.Fatalf("Wrap() error = %v", err)
+	}
+
+	if cmd.Path != "/opt/tsuku" {
+		t.Errorf("Path = %q, want launcher", cmd.Path)
+	}
+	if len(cmd.Args) != 7 {
+		t.Fatalf("Args = %q, want 7 elements", cmd.Args)
+	}
+	if cmd.Args[1] != LauncherArg || cmd.Args[3] != target {
+		t.Errorf("Args = %q, want launcher arg and resolved target %q", cmd.Args, target)
+	}
+	if got := strings.Join(cmd.Args[4:], " "); got != "sh -c true" {
+		t.Errorf("original argv = %q, want %q", got, "sh -c true")
+	}
+
+	var decoded Ruleset
+	if err := json.Unmarshal([]byte(cmd.Args[2]), &decoded); err != nil {
+		t.Fatalf("rules are not valid JSON: %v", err)
+	}
+	if got := strings.Join(decoded.ReadWrite, ","); got != "/tmp,/work" {
+		t.Errorf("ReadWrite = %q, want sorted and deduplicated paths", got)
+	}
+}
+
+func TestWrap_LookPathError(t *testing.T) {
+	cmd := exec.Command("tsuku-test-missing-command")
+	if err := Wrap(cmd, "/opt/tsuku", Ruleset{}); err == nil {
+		t.Error("expected the lookup error of the original command")
+	}
+}
+
+func TestLaunch_InvalidArgs(t *testing.T) {
+	if err := launch([]string{"{}"}); err == nil {
+		t.Error("expected error for missing command")
+	}
+	if err := launch([]string{"not json", "/bin/true", "true"}); err == nil {
+		t.Error("expected error for invalid rules")
+	}
+}
+
+func TestConfinedCommand(t *testing.T) {
+	if runtime.GOOS != "linux" || !Supported() {
+		t.Skip("Landlock is not supported on this system")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+
+	allowed := t.TempDir()
+	denied := t.TempDir()
+	readOnly := []string{"/usr", "/bin", "/lib", "/lib64", "/etc", filepath.Dir(sh)}
+
+	run := func(script string) error {
+		cmd := exec.Command(sh, "-c", script)
+		if err := Wrap(cmd, os.Args[0], Ruleset{ReadWrite: []string{allowed, "/dev"}, ReadOnly: readOnly}); err != nil {
+			t.Fatal(err)
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Logf("output: %s", out)
+		}
+		return err
+	}
+
+	if err := run("echo ok > " + filepath.Join(allowed, "file")); err != nil {
+		t.Errorf("write to allowed directory failed: %v", err)
+	}
+	if err := run("echo no > " + filepath.Join(denied, "file")); err == nil {
+		t.Error("write outside the allowed directories succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(denied, "file")); err == nil {
+		t.Error("file was created outside the allowed directories")
+	}
+}
@@ -174,6 +174,7 @@ func fromAnthropicResponse(resp *anthropic.Message) *CompletionResponse {
 		Usage: Usage{
 			InputTokens:  int(resp.Usage.InputTokens),
 			OutputTokens: int(resp.Usage.OutputTokens),
+			Provider:     "claude",
 		},
 	}
 
@@ -71,11 +71,12 @@ type generationContext struct {
 
 // AssetPattern contains the discovered pattern for matching release assets to platforms.
 type AssetPattern struct {
-	Mappings       []PlatformMapping `json:"mappings"`
-	Executable     string            `json:"executable"`
-	VerifyCommand  string            `json:"verify_command"`
-	StripPrefix    string            `json:"strip_prefix,omitempty"`
-	InstallSubpath string            `json:"install_subpath,omitempty"`
+	Mappings        []PlatformMapping     `json:"mappings"`
+	Executable      string                `json:"executable"`
+	VerifyCommand   string                `json:"verify_command"`
+	StripPrefix     string                `json:"strip_prefix,omitempty"`
+	InstallSubpath  string                `json:"install_subpath,omitempty"`
+	FunctionalTests []FunctionalTestInput `json:"functional_tests,omitempty"`
 }
 
 // GenerateRecipe runs a multi-turn conversation until extract_pattern is called.
@@ -214,11 +215,12 @@ func (c *Client) executeToolCall(ctx context.Context, genCtx *generationContext,
 			return "", nil, fmt.Errorf("invalid extract_pattern input: %w", err)
 		}
 		pattern := &AssetPattern{
-			Mappings:       input.Mappings,
-			Executable:     input.Executable,
-			VerifyCommand:  input.VerifyCommand,
-			StripPrefix:    input.StripPrefix,
-			InstallSubpath: input.InstallSubpath,
+			Mappings:        input.Mappings,
+			Executable:      input.Executable,
+			VerifyCommand:   input.VerifyCommand,
+			StripPrefix:     input.StripPrefix,
+			InstallSubpath:  input.InstallSubpath,
+			FunctionalTests: input.FunctionalTests,
 		}
 		return "", pattern, nil
 
@@ -7,6 +7,16 @@ import "fmt"
 type Usage struct {
 	InputTokens  int
 	OutputTokens int
+
+	// Provider identifies the provider that produced this usage.
+	// Usage from the local provider is free; all other usage is
+	// priced at Claude Sonnet 4 rates. Empty when usage from several
+	// providers was accumulated.
+	Provider string
+
+	// byProvider holds per-provider sub-totals once usage from more than
+	// one provider has been accumulated, so each part is priced on its own
+	byProvider map[string]Usage
 }
 
 // Pricing constants for Claude Sonnet 4 (per 1M tokens in USD).
@@ -17,12 +27,57 @@ const (
 
 // Add accumulates usage from another Usage into this one.
 func (u *Usage) Add(other Usage) {
+	// Build a new map so copies of u don't share sub-totals
+	parts := make(map[string]Usage)
+	for _, src := range []Usage{*u, other} {
+		for provider, part := range src.parts() {
+			total := parts[provider]
+			total.InputTokens += part.InputTokens
+			total.OutputTokens += part.OutputTokens
+			total.Provider = provider
+			parts[provider] = total
+		}
+	}
+
 	u.InputTokens += other.InputTokens
 	u.OutputTokens += other.OutputTokens
+	u.Provider = ""
+	u.byProvider = nil
+	switch len(parts) {
+	case 0:
+	case 1:
+		for provider := range parts {
+			u.Provider = provider
+		}
+	default:
+		u.byProvider = parts
+	}
+}
+
+// parts returns the usage split by provider. A zero Usage has no parts.
+func (u Usage) parts() map[string]Usage {
+	if u.byProvider != nil {
+		return u.byProvider
+	}
+	if u.InputTokens == 0 && u.OutputTokens == 0 && u.Provider == "" {
+		return nil
+	}
+	return map[string]Usage{u.Provider: {InputTokens: u.InputTokens, OutputTokens: u.OutputTokens, Provider: u.Provider}}
 }
 
 // Cost returns the estimated cost in USD based on Claude Sonnet 4 pricing.
+// Usage from the local provider always costs zero.
 func (u Usage) Cost() float64 {
+	if u.byProvider != nil {
+		var total float64
+		for _, part := range u.byProvider {
+			total += part.Cost()
+		}
+		return total
+	}
+	if u.Provider == LocalProviderName {
+		return 0
+	}
 	inputCost := float64(u.InputTokens) * inputPricePerMillion / 1_000_000
 	outputCost := float64(u.OutputTokens) * outputPricePerMillion / 1_000_000
 	return inputCost + outputCost
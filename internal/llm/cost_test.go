@@ -79,3 +79,41 @@ func TestUsage_String(t *testing.T) {
 		t.Errorf("Usage.String() = %q, want %q", got, want)
 	}
 }
+
+func TestUsage_Cost_LocalProviderIsFree(t *testing.T) {
+	var total Usage
+	total.Add(Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000, Provider: LocalProviderName})
+	total.Add(Usage{InputTokens: 5000, OutputTokens: 500, Provider: LocalProviderName})
+
+	if got := total.Cost(); got != 0 {
+		t.Errorf("Usage.Cost() = %v, want 0 for local provider", got)
+	}
+	if total.InputTokens != 1_005_000 {
+		t.Errorf("InputTokens = %d, want 1005000", total.InputTokens)
+	}
+}
+
+func TestUsage_Cost_MixedProviders(t *testing.T) {
+	var total Usage
+	total.Add(Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000, Provider: LocalProviderName})
+	total.Add(Usage{InputTokens: 1_000_000, OutputTokens: 0, Provider: "claude"})
+	total.Add(Usage{InputTokens: 0, OutputTokens: 1_000_000, Provider: "gemini"})
+
+	// Only the cloud usage is priced: $3 input (claude) + $15 output (gemini)
+	if got := total.Cost(); got != 18.0 {
+		t.Errorf("Usage.Cost() = %v, want 18", got)
+	}
+	if total.Provider != "" {
+		t.Errorf("Provider = %q, want empty for mixed usage", total.Provider)
+	}
+	if total.InputTokens != 2_000_000 || total.OutputTokens != 2_000_000 {
+		t.Errorf("tokens = %d/%d, want 2000000/2000000", total.InputTokens, total.OutputTokens)
+	}
+
+	// Copies keep their own sub-totals
+	snapshot := total
+	total.Add(Usage{InputTokens: 1_000_000, Provider: "claude"})
+	if got := snapshot.Cost(); got != 18.0 {
+		t.Errorf("snapshot Cost() = %v after adding to the original, want 18", got)
+	}
+}
@@ -22,6 +22,13 @@ type LLMConfig interface {
 	LLMProviders() []string
 }
 
+// LocalLLMConfig provides settings for the OpenAI-compatible local provider.
+// Configs passed to WithConfig may optionally implement it; userconfig.Config does.
+type LocalLLMConfig interface {
+	LLMLocalBaseURL() string
+	LLMLocalModel() string
+}
+
 // ErrLLMDisabled is returned when LLM features are disabled via configuration.
 var ErrLLMDisabled = fmt.Errorf("LLM features are disabled via configuration")
 
@@ -31,6 +38,8 @@ type factoryOptions struct {
 	enabled         bool
 	preferredOrder  []string
 	enabledExplicit bool // Whether enabled was explicitly set
+	localBaseURL    string
+	localModel      string
 }
 
 // FactoryOption configures a Factory.
@@ -47,6 +56,8 @@ func WithPrimaryProvider(name string) FactoryOption {
 // WithConfig applies LLM configuration settings.
 // If config.LLMEnabled() returns false, NewFactory will return ErrLLMDisabled.
 // If config.LLMProviders() returns a non-empty list, the first provider becomes primary.
+// If config also implements LocalLLMConfig, its base URL and model configure
+// the local provider.
 func WithConfig(cfg LLMConfig) FactoryOption {
 	return func(o *factoryOptions) {
 		o.enabled = cfg.LLMEnabled()
@@ -56,6 +67,19 @@ func WithConfig(cfg LLMConfig) FactoryOption {
 			o.preferredOrder = providers
 			o.primary = providers[0]
 		}
+		if local, ok := cfg.(LocalLLMConfig); ok {
+			o.localBaseURL = local.LLMLocalBaseURL()
+			o.localModel = local.LLMLocalModel()
+		}
+	}
+}
+
+// WithLocalProvider configures the OpenAI-compatible local provider.
+// The provider is only created when "local" appears in the provider order.
+func WithLocalProvider(baseURL, model string) FactoryOption {
+	return func(o *factoryOptions) {
+		o.localBaseURL = baseURL
+		o.localModel = model
 	}
 }
 
@@ -82,6 +106,10 @@ func WithProviderOrder(providers []string) FactoryOption {
 // It auto-detects available providers based on environment variables:
 // - Claude: Available if ANTHROPIC_API_KEY is set
 // - Gemini: Available if GOOGLE_API_KEY or GEMINI_API_KEY is set
+// - Local: Available if "local" is in the provider order and a base URL and model are configured
+//
+// When a provider order is configured, only the listed providers are created,
+// so failover never reaches a provider the user has not allowed.
 //
 // Returns ErrLLMDisabled if LLM features are explicitly disabled via WithConfig or WithEnabled.
 // Returns an error if no providers are available.
@@ -107,7 +135,7 @@ func NewFactory(ctx context.Context, opts ...FactoryOption) (*Factory, error) {
 	}
 
 	// Auto-detect and initialize Claude provider
-	if os.Getenv("ANTHROPIC_API_KEY") != "" {
+	if o.allows("claude") && os.Getenv("ANTHROPIC_API_KEY") != "" {
 		provider, err := NewClaudeProvider()
 		if err == nil {
 			f.providers["claude"] = provider
@@ -116,7 +144,7 @@ func NewFactory(ctx context.Context, opts ...FactoryOption) (*Factory, error) {
 	}
 
 	// Auto-detect and initialize Gemini provider
-	if os.Getenv("GOOGLE_API_KEY") != "" || os.Getenv("GEMINI_API_KEY") != "" {
+	if o.allows("gemini") && (os.Getenv("GOOGLE_API_KEY") != "" || os.Getenv("GEMINI_API_KEY") != "") {
 		provider, err := NewGeminiProvider(ctx)
 		if err == nil {
 			f.providers["gemini"] = provider
@@ -124,13 +152,43 @@ func NewFactory(ctx context.Context, opts ...FactoryOption) (*Factory, error) {
 		}
 	}
 
+	// Initialize the local provider only when explicitly selected, since it
+	// has no environment variable to signal availability
+	if o.selected(LocalProviderName) {
+		provider, err := NewLocalProvider(o.localBaseURL, o.localModel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local LLM provider: %w", err)
+		}
+		f.providers[LocalProviderName] = provider
+		f.breakers[LocalProviderName] = NewCircuitBreaker(LocalProviderName)
+	}
+
 	if len(f.providers) == 0 {
-		return nil, fmt.Errorf("no LLM providers available: set ANTHROPIC_API_KEY or GOOGLE_API_KEY")
+		return nil, fmt.Errorf("no LLM providers available: set ANTHROPIC_API_KEY or GOOGLE_API_KEY, or configure llm.providers = [\"local\"]")
 	}
 
 	return f, nil
 }
 
+// allows reports whether the named provider may be created.
+// All providers are allowed when no provider order is configured.
+func (o *factoryOptions) allows(name string) bool {
+	if len(o.preferredOrder) == 0 {
+		return true
+	}
+	return o.selected(name)
+}
+
+// selected reports whether the named provider appears in the configured provider order.
+func (o *factoryOptions) selected(name string) bool {
+	for _, p := range o.preferredOrder {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetProvider returns an available provider, respecting circuit breaker state.
 // Returns the primary provider if available and its breaker allows requests.
 // Otherwise, falls back to any available provider with an open breaker.
@@ -555,3 +555,64 @@ func TestWithConfigEmptyProviders(t *testing.T) {
 		t.Errorf("GetProvider returned %q, want %q (default)", provider.Name(), "claude")
 	}
 }
+
+// mockLocalLLMConfig adds local provider settings to mockLLMConfig.
+type mockLocalLLMConfig struct {
+	mockLLMConfig
+	baseURL string
+	model   string
+}
+
+func (m *mockLocalLLMConfig) LLMLocalBaseURL() string {
+	return m.baseURL
+}
+
+func (m *mockLocalLLMConfig) LLMLocalModel() string {
+	return m.model
+}
+
+func TestNewFactoryWithLocalProvider(t *testing.T) {
+	// Set an API key to verify that unlisted providers are not created
+	originalAnthropic := os.Getenv("ANTHROPIC_API_KEY")
+	_ = os.Setenv("ANTHROPIC_API_KEY", "test-key")
+	defer func() {
+		_ = os.Setenv("ANTHROPIC_API_KEY", originalAnthropic)
+	}()
+
+	cfg := &mockLocalLLMConfig{
+		mockLLMConfig: mockLLMConfig{enabled: true, providers: []string{"local"}},
+		baseURL:       "http://localhost:11434/v1",
+		model:         "llama3.1",
+	}
+
+	ctx := context.Background()
+	factory, err := NewFactory(ctx, WithConfig(cfg))
+	if err != nil {
+		t.Fatalf("NewFactory failed: %v", err)
+	}
+
+	if !factory.HasProvider("local") {
+		t.Error("factory should have local provider")
+	}
+	if factory.HasProvider("claude") {
+		t.Error("factory should not create claude when providers list excludes it")
+	}
+
+	provider, err := factory.GetProvider(ctx)
+	if err != nil {
+		t.Fatalf("GetProvider failed: %v", err)
+	}
+	if provider.Name() != "local" {
+		t.Errorf("GetProvider returned %q, want %q", provider.Name(), "local")
+	}
+}
+
+func TestNewFactoryWithLocalProviderMissingModel(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewFactory(ctx,
+		WithProviderOrder([]string{"local"}),
+		WithLocalProvider("http://localhost:11434/v1", ""))
+	if err == nil {
+		t.Error("NewFactory should fail when local provider is selected without a model")
+	}
+}
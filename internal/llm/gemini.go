@@ -239,6 +239,7 @@ func (p *GeminiProvider) convertResponse(resp *genai.GenerateContentResponse) *C
 		result.Usage = Usage{
 			InputTokens:  int(resp.UsageMetadata.PromptTokenCount),
 			OutputTokens: int(resp.UsageMetadata.CandidatesTokenCount),
+			Provider:     "gemini",
 		}
 	}
 
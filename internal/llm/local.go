@@ -0,0 +1,281 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LocalProviderName is the provider identifier for OpenAI-compatible servers.
+const LocalProviderName = "local"
+
+// localRequestTimeout bounds a single completion request. Local models on
+// modest hardware can take minutes to answer, so this is much longer than
+// the timeouts used for hosted APIs.
+const localRequestTimeout = 10 * time.Minute
+
+// maxLocalResponseSize limits the response body read from the server (10MB).
+const maxLocalResponseSize = 10 * 1024 * 1024
+
+// LocalProvider implements Provider for servers that speak the OpenAI-compatible
+// chat completions protocol with tool calling, such as Ollama, vLLM and the
+// llama.cpp server. Requests never leave the configured base URL, which makes
+// this provider suitable when repository content must not reach external APIs.
+type LocalProvider struct {
+	httpClient *http.Client
+	baseURL    string
+	model      string
+}
+
+// NewLocalProvider creates a provider for an OpenAI-compatible server.
+// baseURL is the API root including any version prefix
+// (e.g., "http://localhost:11434/v1"); model is the served model name.
+func NewLocalProvider(baseURL, model string) (*LocalProvider, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("local LLM base URL not configured (set llm.local_base_url)")
+	}
+	if model == "" {
+		return nil, fmt.Errorf("local LLM model not configured (set llm.local_model)")
+	}
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		return nil, fmt.Errorf("invalid local LLM base URL %q: must start with http:// or https://", baseURL)
+	}
+
+	return &LocalProvider{
+		httpClient: &http.Client{Timeout: localRequestTimeout},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		model:      model,
+	}, nil
+}
+
+// Name returns the provider identifier.
+func (p *LocalProvider) Name() string {
+	return LocalProviderName
+}
+
+// Complete sends messages to the chat completions endpoint and returns a single response.
+func (p *LocalProvider) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	body := openAIChatRequest{
+		Model:    p.model,
+		Messages: toOpenAIMessages(req.SystemPrompt, req.Messages),
+		Tools:    toOpenAITools(req.Tools),
+	}
+	if req.MaxTokens > 0 {
+		body.MaxTokens = req.MaxTokens
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("local LLM API call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLocalResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local LLM response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("local LLM API returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(data, &chatResp); err != nil {
+		return nil, fmt.Errorf("failed to parse local LLM response: %w", err)
+	}
+
+	return fromOpenAIResponse(&chatResp)
+}
+
+// openAIChatRequest is the request body for POST /chat/completions.
+type openAIChatRequest struct {
+	Model     string          `json:"model"`
+	Messages  []openAIMessage `json:"messages"`
+	Tools     []openAITool    `json:"tools,omitempty"`
+	MaxTokens int             `json:"max_tokens,omitempty"`
+}
+
+// openAIMessage is a single chat message in OpenAI wire format.
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+// openAIToolCall is a function call requested by the assistant.
+type openAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function openAIFunctionCall `json:"function"`
+}
+
+// openAIFunctionCall carries the function name and JSON-encoded arguments.
+type openAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// openAITool declares a callable function.
+type openAITool struct {
+	Type     string            `json:"type"`
+	Function openAIFunctionDef `json:"function"`
+}
+
+// openAIFunctionDef describes a function and its JSON Schema parameters.
+type openAIFunctionDef struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+// openAIChatResponse is the response body from POST /chat/completions.
+type openAIChatResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// toOpenAIMessages converts common Messages to OpenAI format.
+// The system prompt becomes a leading system message.
+func toOpenAIMessages(systemPrompt string, msgs []Message) []openAIMessage {
+	result := make([]openAIMessage, 0, len(msgs)+1)
+
+	if systemPrompt != "" {
+		result = append(result, openAIMessage{Role: "system", Content: systemPrompt})
+	}
+
+	for _, msg := range msgs {
+		switch msg.Role {
+		case RoleUser:
+			if msg.ToolResult != nil {
+				// Tool results are sent with the dedicated "tool" role
+				content := msg.ToolResult.Content
+				if msg.ToolResult.IsError {
+					content = "Error: " + content
+				}
+				result = append(result, openAIMessage{
+					Role:       "tool",
+					Content:    content,
+					ToolCallID: msg.ToolResult.CallID,
+				})
+			} else {
+				result = append(result, openAIMessage{Role: "user", Content: msg.Content})
+			}
+		case RoleAssistant:
+			out := openAIMessage{Role: "assistant", Content: msg.Content}
+			for _, tc := range msg.ToolCalls {
+				args, err := json.Marshal(tc.Arguments)
+				if err != nil || tc.Arguments == nil {
+					args = []byte("{}")
+				}
+				out.ToolCalls = append(out.ToolCalls, openAIToolCall{
+					ID:   tc.ID,
+					Type: "function",
+					Function: openAIFunctionCall{
+						Name:      tc.Name,
+						Arguments: string(args),
+					},
+				})
+			}
+			result = append(result, out)
+		}
+	}
+
+	return result
+}
+
+// toOpenAITools converts common ToolDefs to OpenAI function tools.
+func toOpenAITools(tools []ToolDef) []openAITool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	result := make([]openAITool, 0, len(tools))
+	for _, tool := range tools {
+		result = append(result, openAITool{
+			Type: "function",
+			Function: openAIFunctionDef{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	return result
+}
+
+// fromOpenAIResponse converts an OpenAI chat response to common format.
+func fromOpenAIResponse(resp *openAIChatResponse) (*CompletionResponse, error) {
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("local LLM returned no choices")
+	}
+
+	choice := resp.Choices[0]
+	result := &CompletionResponse{
+		Content: choice.Message.Content,
+		Usage: Usage{
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+			Provider:     LocalProviderName,
+		},
+	}
+
+	for i, tc := range choice.Message.ToolCalls {
+		var args map[string]any
+		if tc.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(tc.Function.Arguments), &args); err != nil {
+				return nil, fmt.Errorf("invalid arguments for tool call %q: %w", tc.Function.Name, err)
+			}
+		}
+
+		// Some servers omit call IDs; synthesize one so results can be correlated
+		id := tc.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d", i)
+		}
+
+		result.ToolCalls = append(result.ToolCalls, ToolCall{
+			ID:        id,
+			Name:      tc.Function.Name,
+			Arguments: args,
+		})
+	}
+
+	switch choice.FinishReason {
+	case "length":
+		result.StopReason = "max_tokens"
+	case "tool_calls":
+		result.StopReason = "tool_use"
+	default:
+		if len(result.ToolCalls) > 0 {
+			result.StopReason = "tool_use"
+		} else {
+			result.StopReason = "end_turn"
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,185 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewLocalProvider_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string
+		model   string
+		wantErr bool
+	}{
+		{"valid", "http://localhost:11434/v1", "llama3", false},
+		{"missing base URL", "", "llama3", true},
+		{"missing model", "http://localhost:11434/v1", "", true},
+		{"missing scheme", "localhost:11434/v1", "llama3", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLocalProvider(tt.baseURL, tt.model)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLocalProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLocalProvider_Name(t *testing.T) {
+	p, err := NewLocalProvider("http://localhost:8000/v1", "model")
+	if err != nil {
+		t.Fatalf("NewLocalProvider failed: %v", err)
+	}
+	if got := p.Name(); got != "local" {
+		t.Errorf("Name() = %q, want %q", got, "local")
+	}
+}
+
+func TestLocalProvider_Complete_ToolCall(t *testing.T) {
+	var got openAIChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"choices": [{
+				"message": {
+					"role": "assistant",
+					"content": "",
+					"tool_calls": [{
+						"id": "call_abc",
+						"type": "function",
+						"function": {"name": "fetch_file", "arguments": "{\"path\":\"INSTALL.md\"}"}
+					}]
+				},
+				"finish_reason": "tool_calls"
+			}],
+			"usage": {"prompt_tokens": 120, "completion_tokens": 30}
+		}`))
+	}))
+	defer server.Close()
+
+	p, err := NewLocalProvider(server.URL+"/v1/", "qwen2.5-coder")
+	if err != nil {
+		t.Fatalf("NewLocalProvider failed: %v", err)
+	}
+
+	resp, err := p.Complete(context.Background(), &CompletionRequest{
+		SystemPrompt: "You are helpful.",
+		Messages:     []Message{{Role: RoleUser, Content: "Find the install docs"}},
+		Tools:        buildToolDefs(),
+		MaxTokens:    512,
+	})
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	// Request conversion
+	if got.Model != "qwen2.5-coder" {
+		t.Errorf("request model = %q, want %q", got.Model, "qwen2.5-coder")
+	}
+	if got.MaxTokens != 512 {
+		t.Errorf("request max_tokens = %d, want 512", got.MaxTokens)
+	}
+	if len(got.Messages) != 2 || got.Messages[0].Role != "system" || got.Messages[1].Role != "user" {
+		t.Errorf("unexpected request messages: %+v", got.Messages)
+	}
+	if len(got.Tools) != len(buildToolDefs()) || got.Tools[0].Type != "function" {
+		t.Errorf("unexpected request tools: %+v", got.Tools)
+	}
+
+	// Response conversion
+	if resp.StopReason != "tool_use" {
+		t.Errorf("StopReason = %q, want %q", resp.StopReason, "tool_use")
+	}
+	if len(resp.ToolCalls) != 1 {
+		t.Fatalf("ToolCalls = %d, want 1", len(resp.ToolCalls))
+	}
+	tc := resp.ToolCalls[0]
+	if tc.ID != "call_abc" || tc.Name != "fetch_file" || tc.Arguments["path"] != "INSTALL.md" {
+		t.Errorf("unexpected tool call: %+v", tc)
+	}
+	if resp.Usage.InputTokens != 120 || resp.Usage.OutputTokens != 30 {
+		t.Errorf("unexpected usage: %+v", resp.Usage)
+	}
+	if resp.Usage.Cost() != 0 {
+		t.Errorf("Usage.Cost() = %v, want 0", resp.Usage.Cost())
+	}
+}
+
+func TestLocalProvider_Complete_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p, err := NewLocalProvider(server.URL, "missing")
+	if err != nil {
+		t.Fatalf("NewLocalProvider failed: %v", err)
+	}
+
+	_, err = p.Complete(context.Background(), &CompletionRequest{
+		Messages: []Message{{Role: RoleUser, Content: "hi"}},
+	})
+	if err == nil {
+		t.Fatal("Complete should fail on non-200 status")
+	}
+}
+
+func TestToOpenAIMessages_ToolRoundTrip(t *testing.T) {
+	msgs := []Message{
+		{Role: RoleUser, Content: "start"},
+		{
+			Role: RoleAssistant,
+			ToolCalls: []ToolCall{
+				{ID: "call_1", Name: "inspect_archive", Arguments: map[string]any{"url": "https://example.com/a.tar.gz"}},
+			},
+		},
+		{Role: RoleUser, ToolResult: &ToolResult{CallID: "call_1", Content: "404", IsError: true}},
+	}
+
+	result := toOpenAIMessages("", msgs)
+	if len(result) != 3 {
+		t.Fatalf("got %d messages, want 3", len(result))
+	}
+	if len(result[1].ToolCalls) != 1 || result[1].ToolCalls[0].Function.Arguments != `{"url":"https://example.com/a.tar.gz"}` {
+		t.Errorf("unexpected assistant tool call: %+v", result[1].ToolCalls)
+	}
+	if result[2].Role != "tool" || result[2].ToolCallID != "call_1" || result[2].Content != "Error: 404" {
+		t.Errorf("unexpected tool result message: %+v", result[2])
+	}
+}
+
+func TestFromOpenAIResponse_MissingCallID(t *testing.T) {
+	resp := &openAIChatResponse{}
+	resp.Choices = append(resp.Choices, struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	}{
+		Message: openAIMessage{
+			ToolCalls: []openAIToolCall{{Function: openAIFunctionCall{Name: "extract_pattern", Arguments: "{}"}}},
+		},
+		FinishReason: "stop",
+	})
+
+	result, err := fromOpenAIResponse(resp)
+	if err != nil {
+		t.Fatalf("fromOpenAIResponse failed: %v", err)
+	}
+	if result.ToolCalls[0].ID != "call_0" {
+		t.Errorf("synthesized ID = %q, want %q", result.ToolCalls[0].ID, "call_0")
+	}
+	if result.StopReason != "tool_use" {
+		t.Errorf("StopReason = %q, want %q", result.StopReason, "tool_use")
+	}
+}
@@ -25,14 +25,60 @@ type PlatformMapping struct {
 	Format string `json:"format"`
 }
 
+// FunctionalTestInput is a functional test case proposed for the recipe.
+// It exercises the tool beyond a version check (see recipe.FunctionalTest).
+type FunctionalTestInput struct {
+	Name     string `json:"name,omitempty"`
+	Command  string `json:"command"`
+	Stdin    string `json:"stdin,omitempty"`
+	Stdout   string `json:"stdout,omitempty"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+}
+
 // ExtractPatternInput is the input schema for the extract_pattern tool.
 // When this tool is called, the conversation ends.
 type ExtractPatternInput struct {
-	Mappings       []PlatformMapping `json:"mappings"`
-	Executable     string            `json:"executable"`
-	VerifyCommand  string            `json:"verify_command"`
-	StripPrefix    string            `json:"strip_prefix,omitempty"`
-	InstallSubpath string            `json:"install_subpath,omitempty"`
+	Mappings        []PlatformMapping     `json:"mappings"`
+	Executable      string                `json:"executable"`
+	VerifyCommand   string                `json:"verify_command"`
+	StripPrefix     string                `json:"strip_prefix,omitempty"`
+	InstallSubpath  string                `json:"install_subpath,omitempty"`
+	FunctionalTests []FunctionalTestInput `json:"functional_tests,omitempty"`
+}
+
+// FunctionalTestsSchema returns the JSON schema of the optional functional_tests
+// parameter shared by recipe generation tools.
+func FunctionalTestsSchema() map[string]any {
+	return map[string]any{
+		"type":        "array",
+		"description": "Optional test cases that exercise the tool's real functionality offline (e.g., format a small input on stdin). Each runs in an empty temporary directory.",
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name": map[string]any{
+					"type":        "string",
+					"description": "Short description of what the test checks",
+				},
+				"command": map[string]any{
+					"type":        "string",
+					"description": "Shell command to run (e.g., 'jq -r .name')",
+				},
+				"stdin": map[string]any{
+					"type":        "string",
+					"description": "Input fed to the command's standard input",
+				},
+				"stdout": map[string]any{
+					"type":        "string",
+					"description": "Regular expression the command's output must match",
+				},
+				"exit_code": map[string]any{
+					"type":        "integer",
+					"description": "Expected exit code (default 0)",
+				},
+			},
+			"required": []string{"command"},
+		},
+	}
 }
 
 // buildToolDefs returns tool definitions using the common ToolDef format.
@@ -115,6 +161,7 @@ func buildToolDefs() []ToolDef {
 						"type":        "string",
 						"description": "Optional subpath within the archive where the executable is located",
 					},
+					"functional_tests": FunctionalTestsSchema(),
 				},
 				"required": []string{"mappings", "executable", "verify_command"},
 			},
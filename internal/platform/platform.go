@@ -0,0 +1,135 @@
+// Package platform detects host properties beyond GOOS and GOARCH that decide
+// which binaries can run: the C library and the Linux distribution.
+package platform
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// C libraries a Linux binary can be linked against
+const (
+	LibcGlibc = "glibc"
+	LibcMusl  = "musl"
+)
+
+// SupportedLibc returns the libc values accepted in recipes and plans
+func SupportedLibc() []string {
+	return []string{LibcGlibc, LibcMusl}
+}
+
+// IsValidLibc reports whether libc is a supported libc value
+func IsValidLibc(libc string) bool {
+	return libc == LibcGlibc || libc == LibcMusl
+}
+
+// Distro identifies a Linux distribution from os-release
+type Distro struct {
+	ID        string // e.g., "ubuntu", "alpine", "fedora"
+	IDLike    string // Space-separated related distributions, e.g., "debian"
+	VersionID string // e.g., "22.04", "3.19"
+}
+
+// Like returns the distribution ID followed by the IDs it declares itself
+// similar to, most specific first
+func (d Distro) Like() []string {
+	var ids []string
+	if d.ID != "" {
+		ids = append(ids, d.ID)
+	}
+	return append(ids, strings.Fields(d.IDLike)...)
+}
+
+var (
+	detectOnce sync.Once
+	hostLibc   string
+	hostDistro Distro
+)
+
+func detect() {
+	detectOnce.Do(func() {
+		if runtime.GOOS != "linux" {
+			return
+		}
+		hostLibc = detectLibc("/")
+		hostDistro = detectDistro("/")
+	})
+}
+
+// Libc returns the host's C library: "glibc" or "musl" on Linux, "" elsewhere
+func Libc() string {
+	detect()
+	return hostLibc
+}
+
+// HostDistro returns the host's Linux distribution, or an empty Distro when it
+// can't be determined or the host isn't Linux
+func HostDistro() Distro {
+	detect()
+	return hostDistro
+}
+
+// DefaultLibc returns the libc to target for an OS: the host's libc when
+// targeting the host's OS, glibc for other Linux targets, and "" for non-Linux.
+func DefaultLibc(targetOS string) string {
+	if targetOS != "linux" {
+		return ""
+	}
+	if runtime.GOOS == "linux" {
+		return Libc()
+	}
+	return LibcGlibc
+}
+
+// detectLibc determines the C library of the Linux system rooted at root. musl
+// systems ship their dynamic loader as /lib/ld-musl-<arch>.so.1; everything else
+// is assumed to be glibc.
+func detectLibc(root string) string {
+	matches, _ := filepath.Glob(filepath.Join(root, "lib", "ld-musl-*.so.1"))
+	if len(matches) > 0 {
+		return LibcMusl
+	}
+	if detectDistro(root).ID == "alpine" {
+		return LibcMusl
+	}
+	return LibcGlibc
+}
+
+// detectDistro reads os-release from the system rooted at root
+func detectDistro(root string) Distro {
+	for _, path := range []string{"etc/os-release", "usr/lib/os-release"} {
+		data, err := os.ReadFile(filepath.Join(root, path))
+		if err == nil {
+			return ParseOSRelease(data)
+		}
+	}
+	return Distro{}
+}
+
+// ParseOSRelease extracts the distribution ID, ID_LIKE and VERSION_ID from
+// os-release content
+func ParseOSRelease(data []byte) Distro {
+	var d Distro
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			d.ID = strings.ToLower(value)
+		case "ID_LIKE":
+			d.IDLike = strings.ToLower(value)
+		case "VERSION_ID":
+			d.VersionID = value
+		}
+	}
+	return d
+}
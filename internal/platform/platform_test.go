@@ -0,0 +1,91 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParseOSRelease(t *testing.T) {
+	data := []byte(`NAME="Alpine Linux"
+ID=alpine
+VERSION_ID=3.19.1
+PRETTY_NAME="Alpine Linux v3.19"
+`)
+	d := ParseOSRelease(data)
+	if d.ID != "alpine" || d.VersionID != "3.19.1" {
+		t.Errorf("ParseOSRelease() = %+v", d)
+	}
+
+	d = ParseOSRelease([]byte("ID=\"Ubuntu\"\nVERSION_ID=\"22.04\"\n"))
+	if d.ID != "ubuntu" || d.VersionID != "22.04" {
+		t.Errorf("ParseOSRelease() with quotes = %+v", d)
+	}
+
+	d = ParseOSRelease([]byte("ID=rocky\nID_LIKE=\"rhel centos fedora\"\n"))
+	if got := strings.Join(d.Like(), " "); got != "rocky rhel centos fedora" {
+		t.Errorf("Like() = %q", got)
+	}
+}
+
+func TestDetectLibc(t *testing.T) {
+	t.Run("musl loader", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "lib", "ld-musl-x86_64.so.1"), "")
+		if got := detectLibc(root); got != LibcMusl {
+			t.Errorf("detectLibc() = %q, want musl", got)
+		}
+	})
+
+	t.Run("alpine os-release", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "etc", "os-release"), "ID=alpine\n")
+		if got := detectLibc(root); got != LibcMusl {
+			t.Errorf("detectLibc() = %q, want musl", got)
+		}
+	})
+
+	t.Run("glibc", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "etc", "os-release"), "ID=debian\nVERSION_ID=\"12\"\n")
+		if got := detectLibc(root); got != LibcGlibc {
+			t.Errorf("detectLibc() = %q, want glibc", got)
+		}
+	})
+}
+
+func TestDetectDistro_Fallback(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "usr", "lib", "os-release"), "ID=fedora\nVERSION_ID=40\n")
+	if d := detectDistro(root); d.ID != "fedora" || d.VersionID != "40" {
+		t.Errorf("detectDistro() = %+v", d)
+	}
+	if d := detectDistro(t.TempDir()); d != (Distro{}) {
+		t.Errorf("detectDistro() without os-release = %+v", d)
+	}
+}
+
+func TestDefaultLibc(t *testing.T) {
+	if got := DefaultLibc("darwin"); got != "" {
+		t.Errorf("DefaultLibc(darwin) = %q, want empty", got)
+	}
+	want := LibcGlibc
+	if runtime.GOOS == "linux" {
+		want = Libc()
+	}
+	if got := DefaultLibc("linux"); got != want || !IsValidLibc(got) {
+		t.Errorf("DefaultLibc(linux) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,332 @@
+// Package policy restricts what tsuku may install. Policy files are read
+// from a system-wide location and from $TSUKU_HOME, and each one is
+// enforced on its own: a user policy can add restrictions but cannot lift
+// those of the system policy. Rules are evaluated against the recipe and
+// the generated installation plan before anything is executed.
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/tsukumogami/tsuku/internal/actions"
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// SystemPath is the system-wide policy file, typically managed by an
+// administrator or configuration management
+var SystemPath = "/etc/tsuku/policy.toml"
+
+// Rules are the restrictions of one policy file. The zero value allows
+// everything.
+type Rules struct {
+	// AllowedRecipes lists the recipes that may be installed, as glob
+	// patterns ("node*"). Empty allows every recipe.
+	AllowedRecipes []string `toml:"allowed_recipes"`
+
+	// DeniedRecipes lists recipes that may not be installed, as glob
+	// patterns. Takes precedence over AllowedRecipes.
+	DeniedRecipes []string `toml:"denied_recipes"`
+
+	// AllowedRegistries lists the registry URLs recipes may be fetched
+	// from. Empty allows any registry. Recipes embedded in tsuku are always
+	// allowed.
+	AllowedRegistries []string `toml:"allowed_registries"`
+
+	// AllowLocalRecipes permits recipes from $TSUKU_HOME/recipes. Defaults
+	// to true.
+	AllowLocalRecipes *bool `toml:"allow_local_recipes"`
+
+	// MaxTier is the highest installation tier allowed (1=binary,
+	// 2=package manager, 3=nix). Zero allows every tier. Recipes that don't
+	// declare a tier are tier 1.
+	MaxTier int `toml:"max_tier"`
+
+	// ForbidRunCommand rejects plans with run_command steps
+	ForbidRunCommand bool `toml:"forbid_run_command"`
+
+	// ForbidNetworkActions rejects plans with steps that need network
+	// access while executing, such as ecosystem builds and system package
+	// managers
+	ForbidNetworkActions bool `toml:"forbid_network_actions"`
+
+	// RequireSignatures requires every download to be signature-verified
+	RequireSignatures bool `toml:"require_signatures"`
+
+	// ForbidSudo rejects recipes that declare requires_sudo
+	ForbidSudo bool `toml:"forbid_sudo"`
+}
+
+// File is a loaded policy file
+type File struct {
+	Path  string
+	Rules Rules
+}
+
+// Policy is the set of policy files in effect
+type Policy struct {
+	Files []*File
+}
+
+// Load reads the system policy and the user policy at userPath. Missing
+// files are skipped.
+func Load(userPath string) (*Policy, error) {
+	return LoadFiles(SystemPath, userPath)
+}
+
+// LoadFiles reads the given policy files, skipping those that don't exist.
+// Unknown keys are errors, so a mistyped rule is never silently ignored.
+func LoadFiles(paths ...string) (*Policy, error) {
+	p := &Policy{}
+	for _, filePath := range paths {
+		if filePath == "" {
+			continue
+		}
+		data, err := os.ReadFile(filePath)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy %s: %w", filePath, err)
+		}
+
+		f := &File{Path: filePath}
+		meta, err := toml.Decode(string(data), &f.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy %s: %w", filePath, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("unknown key %q in policy %s", undecoded[0].String(), filePath)
+		}
+		for _, pattern := range append(f.Rules.AllowedRecipes, f.Rules.DeniedRecipes...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid recipe pattern %q in policy %s", pattern, filePath)
+			}
+		}
+		p.Files = append(p.Files, f)
+	}
+	return p, nil
+}
+
+// Empty reports whether no policy file is in effect
+func (p *Policy) Empty() bool {
+	return p == nil || len(p.Files) == 0
+}
+
+// Subject is what a policy is evaluated against. Recipe and Plan are
+// optional: recipe rules are checked when a recipe is known and plan rules
+// when a plan has been generated.
+type Subject struct {
+	Name     string
+	Recipe   *recipe.Recipe
+	Source   recipe.RecipeSource
+	Registry string // Registry URL, for recipes from the registry
+	Plan     *executor.InstallationPlan
+}
+
+// Violation is a rule that a subject breaks
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	Policy  string `json:"policy"` // Path of the policy file
+}
+
+// Error reports the violations that block an installation. It implements
+// errmsg.Suggester.
+type Error struct {
+	Tool       string
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "installing '%s' is blocked by policy:", e.Tool)
+	for _, v := range e.Violations {
+		fmt.Fprintf(&b, "\n  - %s (%s in %s)", v.Message, v.Rule, v.Policy)
+	}
+	return b.String()
+}
+
+// Suggestion points at the policy files involved
+func (e *Error) Suggestion() string {
+	seen := make(map[string]bool)
+	var files []string
+	for _, v := range e.Violations {
+		if !seen[v.Policy] {
+			seen[v.Policy] = true
+			files = append(files, v.Policy)
+		}
+	}
+	return fmt.Sprintf("Install policy is set in %s. Ask its maintainer for an exception, or run 'tsuku policy check %s' to review the rules",
+		strings.Join(files, " and "), e.Tool)
+}
+
+// Check evaluates the subject against every policy file. It returns an
+// *Error listing all violations, or nil.
+func (p *Policy) Check(s Subject) error {
+	if p.Empty() {
+		return nil
+	}
+	var violations []Violation
+	for _, f := range p.Files {
+		violations = append(violations, f.check(s)...)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &Error{Tool: s.Name, Violations: violations}
+}
+
+func (f *File) check(s Subject) []Violation {
+	var out []Violation
+	add := func(rule, format string, a ...interface{}) {
+		out = append(out, Violation{Rule: rule, Message: fmt.Sprintf(format, a...), Policy: f.Path})
+	}
+	r := f.Rules
+
+	// Every recipe the installation pulls in, including plan dependencies
+	names := []string{s.Name}
+	if s.Plan != nil {
+		names = append(names, dependencyNames(s.Plan.Dependencies)...)
+	}
+	for _, name := range names {
+		if pattern := matchAny(r.DeniedRecipes, name); pattern != "" {
+			add("denied_recipes", "recipe '%s' is denied (matches %q)", name, pattern)
+		} else if len(r.AllowedRecipes) > 0 && matchAny(r.AllowedRecipes, name) == "" {
+			add("allowed_recipes", "recipe '%s' is not in the allowed recipes", name)
+		}
+	}
+
+	switch s.Source {
+	case recipe.SourceLocal:
+		if r.AllowLocalRecipes != nil && !*r.AllowLocalRecipes {
+			add("allow_local_recipes", "recipe '%s' comes from the local recipes directory", s.Name)
+		}
+	case recipe.SourceRegistry:
+		if len(r.AllowedRegistries) > 0 && !registryAllowed(r.AllowedRegistries, s.Registry) {
+			add("allowed_registries", "recipe '%s' comes from registry %s, which is not allowed", s.Name, s.Registry)
+		}
+	}
+
+	if s.Recipe != nil {
+		tier := s.Recipe.Metadata.Tier
+		if tier == 0 {
+			tier = 1
+		}
+		if r.MaxTier > 0 && tier > r.MaxTier {
+			add("max_tier", "recipe '%s' is tier %d, above the maximum of %d", s.Name, tier, r.MaxTier)
+		}
+		if r.ForbidSudo && s.Recipe.Metadata.RequiresSudo {
+			add("forbid_sudo", "recipe '%s' requires sudo", s.Name)
+		}
+	}
+
+	if s.Plan != nil {
+		steps := planSteps(s.Plan)
+		if r.ForbidRunCommand && containsAction(steps, "run_command") {
+			add("forbid_run_command", "plan for '%s' runs arbitrary commands (run_command)", s.Name)
+		}
+		if r.ForbidNetworkActions {
+			if network := networkActions(steps); len(network) > 0 {
+				add("forbid_network_actions", "plan for '%s' uses %s, which need network access while installing",
+					s.Name, strings.Join(network, ", "))
+			}
+		}
+		// tsuku verifies downloads against SHA-256 checksums only, so a plan
+		// that downloads anything cannot satisfy the rule
+		if r.RequireSignatures {
+			if n := countDownloads(steps); n > 0 {
+				add("require_signatures", "plan for '%s' has %d download(s) that cannot be signature-verified (tsuku verifies SHA-256 checksums only)", s.Name, n)
+			}
+		}
+	}
+	return out
+}
+
+// matchAny returns the first pattern matching name, or ""
+func matchAny(patterns []string, name string) string {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// registryAllowed compares registry URLs ignoring trailing slashes
+func registryAllowed(allowed []string, registry string) bool {
+	registry = strings.TrimSuffix(registry, "/")
+	for _, a := range allowed {
+		if strings.TrimSuffix(a, "/") == registry {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencyNames lists the tools of nested dependency plans
+func dependencyNames(deps []executor.DependencyPlan) []string {
+	var names []string
+	for _, d := range deps {
+		names = append(names, d.Tool)
+		names = append(names, dependencyNames(d.Dependencies)...)
+	}
+	return names
+}
+
+// planSteps returns the steps of a plan and all of its dependency plans
+func planSteps(plan *executor.InstallationPlan) []executor.ResolvedStep {
+	steps := append([]executor.ResolvedStep(nil), plan.Steps...)
+	var walk func([]executor.DependencyPlan)
+	walk = func(deps []executor.DependencyPlan) {
+		for _, d := range deps {
+			steps = append(steps, d.Steps...)
+			walk(d.Dependencies)
+		}
+	}
+	walk(plan.Dependencies)
+	return steps
+}
+
+func containsAction(steps []executor.ResolvedStep, action string) bool {
+	for _, step := range steps {
+		if step.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
+// networkActions returns the sorted names of actions that need network
+// access while executing
+func networkActions(steps []executor.ResolvedStep) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, step := range steps {
+		if seen[step.Action] {
+			continue
+		}
+		if nv, ok := actions.Get(step.Action).(actions.NetworkValidator); ok && nv.RequiresNetwork() {
+			seen[step.Action] = true
+			out = append(out, step.Action)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// countDownloads counts the steps that fetch a file
+func countDownloads(steps []executor.ResolvedStep) int {
+	n := 0
+	for _, step := range steps {
+		if step.URL != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,166 @@
+package policy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/errmsg"
+	"github.com/tsukumogami/tsuku/internal/executor"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+func writePolicy(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func violationRules(err error) []string {
+	var pe *Error
+	if !errors.As(err, &pe) {
+		return nil
+	}
+	var rules []string
+	for _, v := range pe.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return rules
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	system := writePolicy(t, dir, "system.toml", "denied_recipes = [\"*-nightly\"]\n")
+	p, err := LoadFiles(system, filepath.Join(dir, "missing.toml"), "")
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+	if len(p.Files) != 1 || p.Files[0].Path != system {
+		t.Errorf("loaded %d files, want only the system policy", len(p.Files))
+	}
+
+	typo := writePolicy(t, dir, "typo.toml", "forbid_run_commands = true\n")
+	if _, err := LoadFiles(typo); err == nil || !strings.Contains(err.Error(), "forbid_run_commands") {
+		t.Errorf("LoadFiles() error = %v, want unknown key error", err)
+	}
+
+	badPattern := writePolicy(t, dir, "pattern.toml", "allowed_recipes = [\"[\"]\n")
+	if _, err := LoadFiles(badPattern); err == nil {
+		t.Error("expected error for an invalid pattern")
+	}
+}
+
+func TestCheck_RecipeRules(t *testing.T) {
+	dir := t.TempDir()
+	p, err := LoadFiles(writePolicy(t, dir, "policy.toml", `
+allowed_recipes = ["node*", "rg", "hello-nix"]
+denied_recipes = ["nodejs-nightly"]
+allowed_registries = ["https://recipes.example.com/"]
+allow_local_recipes = false
+max_tier = 2
+forbid_sudo = true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    string
+	}{
+		{"allowed", Subject{Name: "nodejs", Source: recipe.SourceEmbedded}, ""},
+		{"denied wins over allowed", Subject{Name: "nodejs-nightly"}, "denied_recipes"},
+		{"not allowed", Subject{Name: "jq"}, "allowed_recipes"},
+		{"registry allowed", Subject{Name: "rg", Source: recipe.SourceRegistry, Registry: "https://recipes.example.com"}, ""},
+		{"registry not allowed", Subject{Name: "rg", Source: recipe.SourceRegistry, Registry: "https://evil.example.com"}, "allowed_registries"},
+		{"local", Subject{Name: "rg", Source: recipe.SourceLocal}, "allow_local_recipes"},
+		{"tier", Subject{Name: "hello-nix", Recipe: &recipe.Recipe{Metadata: recipe.MetadataSection{Tier: 3}}}, "max_tier"},
+		{"untiered is tier 1", Subject{Name: "rg", Recipe: &recipe.Recipe{}}, ""},
+		{"sudo", Subject{Name: "rg", Recipe: &recipe.Recipe{Metadata: recipe.MetadataSection{RequiresSudo: true}}}, "forbid_sudo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(violationRules(p.Check(tt.subject)), ",")
+			if got != tt.want {
+				t.Errorf("Check() violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_PlanRules(t *testing.T) {
+	dir := t.TempDir()
+	p, err := LoadFiles(writePolicy(t, dir, "policy.toml", `
+denied_recipes = ["rust"]
+forbid_run_command = true
+forbid_network_actions = true
+require_signatures = true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &executor.InstallationPlan{
+		Tool: "ripgrep",
+		Steps: []executor.ResolvedStep{
+			{Action: "cargo_build"},
+			{Action: "install_binaries"},
+		},
+		Dependencies: []executor.DependencyPlan{{
+			Tool: "rust",
+			Steps: []executor.ResolvedStep{
+				{Action: "download_file", URL: "https://example.com/rust.tar.gz", Checksum: "abc"},
+				{Action: "run_command"},
+			},
+		}},
+	}
+	err = p.Check(Subject{Name: "ripgrep", Plan: plan})
+	got := strings.Join(violationRules(err), ",")
+	want := "denied_recipes,forbid_run_command,forbid_network_actions,require_signatures"
+	if got != want {
+		t.Fatalf("Check() violations = %q, want %q", got, want)
+	}
+	if !strings.Contains(err.Error(), "cargo_build, run_command") {
+		t.Errorf("error does not name the network actions: %v", err)
+	}
+
+	offline := &executor.InstallationPlan{Tool: "tool", Steps: []executor.ResolvedStep{{Action: "chmod"}}}
+	if err := p.Check(Subject{Name: "tool", Plan: offline}); err != nil {
+		t.Errorf("Check() error = %v, want nil", err)
+	}
+}
+
+func TestCheck_EachFileEnforced(t *testing.T) {
+	dir := t.TempDir()
+	system := writePolicy(t, dir, "system.toml", "allowed_recipes = [\"rg\"]\n")
+	user := writePolicy(t, dir, "user.toml", "allowed_recipes = [\"rg\", \"jq\"]\n")
+	p, err := LoadFiles(system, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The user policy cannot widen the system allow-list
+	err = p.Check(Subject{Name: "jq"})
+	var pe *Error
+	if !errors.As(err, &pe) || len(pe.Violations) != 1 || pe.Violations[0].Policy != system {
+		t.Fatalf("Check() = %v, want one violation of the system policy", err)
+	}
+
+	formatted := errmsg.FormatError(err)
+	if !strings.Contains(formatted, "Suggestion:") || !strings.Contains(formatted, system) {
+		t.Errorf("FormatError() = %q, want a suggestion naming the policy file", formatted)
+	}
+}
+
+func TestCheck_EmptyPolicy(t *testing.T) {
+	var p *Policy
+	if err := p.Check(Subject{Name: "anything", Recipe: &recipe.Recipe{}}); err != nil {
+		t.Errorf("Check() error = %v, want nil", err)
+	}
+}
@@ -211,6 +211,20 @@ const (
 	SourceRegistry RecipeSource = "registry"
 )
 
+// Source reports where Get resolves a recipe from, following the same
+// priority order: local, embedded, then registry
+func (l *Loader) Source(name string) RecipeSource {
+	if l.recipesDir != "" {
+		if _, err := os.Stat(filepath.Join(l.recipesDir, name+".toml")); err == nil {
+			return SourceLocal
+		}
+	}
+	if l.embedded != nil && l.embedded.Has(name) {
+		return SourceEmbedded
+	}
+	return SourceRegistry
+}
+
 // RecipeInfo contains a recipe with its source information
 type RecipeInfo struct {
 	Name        string
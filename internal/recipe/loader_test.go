@@ -868,3 +868,22 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestLoader_Source(t *testing.T) {
+	recipesDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(recipesDir, "local-tool.toml"), []byte("[metadata]\nname = \"local-tool\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loader := NewWithLocalRecipes(registry.New(t.TempDir()), recipesDir)
+
+	tests := map[string]RecipeSource{
+		"local-tool":   SourceLocal,
+		"go":           SourceEmbedded,
+		"not-embedded": SourceRegistry,
+	}
+	for name, want := range tests {
+		if got := loader.Source(name); got != want {
+			t.Errorf("Source(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -10,12 +10,13 @@ import (
 
 // Recipe represents an action-based recipe
 type Recipe struct {
-	Metadata  MetadataSection `toml:"metadata"`
-	Version   VersionSection  `toml:"version"`
-	Resources []Resource      `toml:"resources,omitempty"`
-	Patches   []Patch         `toml:"patches,omitempty"`
-	Steps     []Step          `toml:"steps"`
-	Verify    VerifySection   `toml:"verify"`
+	Metadata    MetadataSection     `toml:"metadata"`
+	Version     VersionSection      `toml:"version"`
+	Resources   []Resource          `toml:"resources,omitempty"`
+	Patches     []Patch             `toml:"patches,omitempty"`
+	Steps       []Step              `toml:"steps"`
+	Verify      VerifySection       `toml:"verify"`
+	Confinement *ConfinementSection `toml:"confinement,omitempty"`
 }
 
 // Resource represents an additional download required for source builds.
@@ -27,6 +28,16 @@ type Resource struct {
 	Dest     string `toml:"dest"`     // Destination directory relative to source (e.g., "deps/tree-sitter-c")
 }
 
+// ConfinementSection relaxes the Landlock confinement applied to the
+// commands of a recipe's steps. Every exception needs a reason, which is
+// carried into the installation plan for auditing.
+type ConfinementSection struct {
+	Unconfined bool     `toml:"unconfined,omitempty"` // Run commands without confinement
+	Writable   []string `toml:"writable,omitempty"`   // Extra writable paths (~ and $VAR are expanded)
+	Readable   []string `toml:"readable,omitempty"`   // Extra readable paths (~ and $VAR are expanded)
+	Reason     string   `toml:"reason"`               // Why the recipe needs the exception
+}
+
 // Patch represents a source modification to apply before building.
 // Patches can be URL-based or inline (embedded in the recipe).
 type Patch struct {
@@ -74,6 +85,24 @@ func (r *Recipe) ToTOML() ([]byte, error) {
 	if r.Version.GitHubRepo != "" {
 		buf.WriteString(fmt.Sprintf("github_repo = %q\n", r.Version.GitHubRepo))
 	}
+	if r.Version.Repo != "" {
+		buf.WriteString(fmt.Sprintf("repo = %q\n", r.Version.Repo))
+	}
+	if r.Version.BaseURL != "" {
+		buf.WriteString(fmt.Sprintf("base_url = %q\n", r.Version.BaseURL))
+	}
+	if r.Version.GitURL != "" {
+		buf.WriteString(fmt.Sprintf("git_url = %q\n", r.Version.GitURL))
+	}
+	if r.Version.URL != "" {
+		buf.WriteString(fmt.Sprintf("url = %q\n", r.Version.URL))
+	}
+	if r.Version.Regex != "" {
+		buf.WriteString(fmt.Sprintf("regex = %q\n", r.Version.Regex))
+	}
+	if r.Version.JSONPath != "" {
+		buf.WriteString(fmt.Sprintf("json_path = %q\n", r.Version.JSONPath))
+	}
 	if r.Version.TagPrefix != "" {
 		buf.WriteString(fmt.Sprintf("tag_prefix = %q\n", r.Version.TagPrefix))
 	}
@@ -138,10 +167,71 @@ func (r *Recipe) ToTOML() ([]byte, error) {
 	if r.Verify.Pattern != "" {
 		buf.WriteString(fmt.Sprintf("pattern = %q\n", r.Verify.Pattern))
 	}
+	if r.Verify.PatternRegex != "" {
+		buf.WriteString(fmt.Sprintf("pattern_regex = %q\n", r.Verify.PatternRegex))
+	}
+	if r.Verify.VersionFormat != "" {
+		buf.WriteString(fmt.Sprintf("version_format = %q\n", r.Verify.VersionFormat))
+	}
+	if r.Verify.Mode != "" {
+		buf.WriteString(fmt.Sprintf("mode = %q\n", r.Verify.Mode))
+	}
+	for _, test := range r.Verify.Tests {
+		buf.WriteString("\n[[verify.tests]]\n")
+		if test.Name != "" {
+			buf.WriteString(fmt.Sprintf("name = %q\n", test.Name))
+		}
+		buf.WriteString(fmt.Sprintf("command = %q\n", test.Command))
+		if test.Stdin != "" {
+			buf.WriteString(fmt.Sprintf("stdin = %q\n", test.Stdin))
+		}
+		if test.Stdout != "" {
+			buf.WriteString(fmt.Sprintf("stdout = %q\n", test.Stdout))
+		}
+		if test.ExitCode != nil {
+			buf.WriteString(fmt.Sprintf("exit_code = %d\n", *test.ExitCode))
+		}
+		for _, file := range test.Files {
+			buf.WriteString("\n[[verify.tests.files]]\n")
+			buf.WriteString(fmt.Sprintf("path = %q\n", file.Path))
+			if file.Contains != "" {
+				buf.WriteString(fmt.Sprintf("contains = %q\n", file.Contains))
+			}
+			if file.Absent {
+				buf.WriteString("absent = true\n")
+			}
+		}
+	}
+
+	// Encode confinement exceptions, so they are covered by the recipe hash
+	if c := r.Confinement; c != nil {
+		buf.WriteString("\n[confinement]\n")
+		if c.Unconfined {
+			buf.WriteString("unconfined = true\n")
+		}
+		if len(c.Writable) > 0 {
+			buf.WriteString(fmt.Sprintf("writable = %s\n", tomlStringArray(c.Writable)))
+		}
+		if len(c.Readable) > 0 {
+			buf.WriteString(fmt.Sprintf("readable = %s\n", tomlStringArray(c.Readable)))
+		}
+		if c.Reason != "" {
+			buf.WriteString(fmt.Sprintf("reason = %q\n", c.Reason))
+		}
+	}
 
 	return []byte(buf.String()), nil
 }
 
+// tomlStringArray formats values as a TOML array of basic strings
+func tomlStringArray(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
 // MetadataSection contains recipe metadata
 type MetadataSection struct {
 	Name                     string   `toml:"name"`
@@ -166,11 +256,17 @@ type MetadataSection struct {
 
 // VersionSection specifies how to resolve versions
 type VersionSection struct {
-	Source     string `toml:"source"`      // e.g., "nodejs_dist", "github_releases", "npm_registry", "homebrew"
-	GitHubRepo string `toml:"github_repo"` // e.g., "rust-lang/rust" - use GitHub for version detection only
-	TagPrefix  string `toml:"tag_prefix"`  // e.g., "ruby-" - filter tags by prefix and strip it from version
-	Module     string `toml:"module"`      // Go module path for goproxy version resolution (when different from install path)
-	Formula    string `toml:"formula"`     // Homebrew formula name for version resolution (e.g., "libyaml")
+	Source     string `toml:"source"`              // e.g., "nodejs_dist", "github_releases", "npm_registry", "homebrew"
+	GitHubRepo string `toml:"github_repo"`         // e.g., "rust-lang/rust" - use GitHub for version detection only
+	Repo       string `toml:"repo,omitempty"`      // GitLab project or Gitea/Forgejo repo for gitlab_releases/gitea_releases
+	BaseURL    string `toml:"base_url,omitempty"`  // Forge instance URL (e.g., "https://codeberg.org"); gitlab.com if empty
+	GitURL     string `toml:"git_url,omitempty"`   // Repository URL for git_tags (e.g., "https://git.savannah.gnu.org/git/make.git")
+	URL        string `toml:"url,omitempty"`       // Download page or manifest URL for url_regex/json_path
+	Regex      string `toml:"regex,omitempty"`     // Version regex for url_regex (named group "version" or first group)
+	JSONPath   string `toml:"json_path,omitempty"` // JSONPath selecting versions for json_path (e.g., "$.releases[*].version")
+	TagPrefix  string `toml:"tag_prefix"`          // e.g., "ruby-" - filter tags by prefix and strip it from version
+	Module     string `toml:"module"`              // Go module path for goproxy version resolution (when different from install path)
+	Formula    string `toml:"formula"`             // Homebrew formula name for version resolution (e.g., "libyaml")
 }
 
 // Step represents a single action step
@@ -265,6 +361,8 @@ const (
 	VerifyModeVersion = "version"
 	// VerifyModeOutput matches a pattern in command output without version check
 	VerifyModeOutput = "output"
+	// VerifyModeFunctional runs the recipe's functional test cases against the installed tool
+	VerifyModeFunctional = "functional"
 )
 
 // Version format transforms
@@ -283,11 +381,39 @@ const (
 type VerifySection struct {
 	Command       string             `toml:"command"`
 	Pattern       string             `toml:"pattern"`
+	PatternRegex  string             `toml:"pattern_regex,omitempty"` // Regular expression; a named "version" group is compared to the installed version
 	Mode          string             `toml:"mode,omitempty"`
 	VersionFormat string             `toml:"version_format,omitempty"`
 	Reason        string             `toml:"reason,omitempty"`
 	ExitCode      *int               `toml:"exit_code,omitempty"` // Expected exit code (default: 0)
 	Additional    []AdditionalVerify `toml:"additional,omitempty"`
+	Tests         []FunctionalTest   `toml:"tests,omitempty"` // Functional test cases (see VerifyModeFunctional)
+}
+
+// FunctionalTest is a test case exercising the installed tool beyond a version check.
+// Each test runs with a fresh temporary directory as its working directory.
+type FunctionalTest struct {
+	Name     string          `toml:"name,omitempty" json:"name,omitempty"`
+	Command  string          `toml:"command" json:"command"`
+	Stdin    string          `toml:"stdin,omitempty" json:"stdin,omitempty"`         // Fixture fed to standard input
+	Stdout   string          `toml:"stdout,omitempty" json:"stdout,omitempty"`       // Regular expression stdout (without trailing newlines) must match
+	ExitCode *int            `toml:"exit_code,omitempty" json:"exit_code,omitempty"` // Expected exit code (default: 0)
+	Files    []FileAssertion `toml:"files,omitempty" json:"files,omitempty"`         // Files expected in the test directory afterwards
+}
+
+// FileAssertion checks a file in a functional test's temporary directory
+type FileAssertion struct {
+	Path     string `toml:"path" json:"path"`                             // Relative to the test directory
+	Contains string `toml:"contains,omitempty" json:"contains,omitempty"` // Regular expression the content must match
+	Absent   bool   `toml:"absent,omitempty" json:"absent,omitempty"`     // The file must not exist
+}
+
+// DisplayName returns the test's name, falling back to its command
+func (t FunctionalTest) DisplayName() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Command
 }
 
 // AdditionalVerify represents additional verification commands
@@ -417,6 +543,10 @@ func (r *Recipe) HasChecksumVerification() bool {
 		"download_archive": true,
 		"github_archive":   true,
 		"github_file":      true,
+		"gitlab_archive":   true,
+		"gitlab_file":      true,
+		"gitea_archive":    true,
+		"gitea_file":       true,
 	}
 
 	hasDownloadStep := false
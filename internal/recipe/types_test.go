@@ -1904,3 +1904,28 @@ func TestRecipe_ToTOML_ResourcesAndPatches_Combined(t *testing.T) {
 		t.Errorf("Roundtrip: Patches length = %d, want 1", len(parsed.Patches))
 	}
 }
+
+func TestRecipe_ToTOML_WithConfinement(t *testing.T) {
+	recipe := Recipe{
+		Metadata: MetadataSection{Name: "test-tool"},
+		Steps:    []Step{{Action: "run_command", Params: map[string]interface{}{"command": "make"}}},
+		Confinement: &ConfinementSection{
+			Writable: []string{"~/.cache/test-tool"},
+			Reason:   "build writes its own cache",
+		},
+	}
+
+	data, err := recipe.ToTOML()
+	if err != nil {
+		t.Fatalf("ToTOML() error = %v", err)
+	}
+
+	var parsed Recipe
+	if _, err := toml.Decode(string(data), &parsed); err != nil {
+		t.Fatalf("ToTOML() output is not valid TOML: %v\n%s", err, data)
+	}
+	if parsed.Confinement == nil || parsed.Confinement.Reason != "build writes its own cache" ||
+		len(parsed.Confinement.Writable) != 1 || parsed.Confinement.Writable[0] != "~/.cache/test-tool" {
+		t.Errorf("confinement not preserved: %+v", parsed.Confinement)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/tsukumogami/tsuku/internal/platform"
 )
 
 // ValidationError represents a single validation error
@@ -104,6 +107,7 @@ func runRecipeValidations(result *ValidationResult, r *Recipe) {
 	validatePatches(result, r)
 	validateSteps(result, r)
 	validateVerify(result, r)
+	validateConfinement(result, r)
 	validatePlatformConstraints(result, r)
 	// Note: Shadowed dependency validation is done at the CLI layer
 	// to avoid circular dependencies between recipe and actions packages
@@ -144,6 +148,11 @@ func validateVersion(result *ValidationResult, r *Recipe) {
 	validSources := map[string]bool{
 		"github_releases": true,
 		"github_tags":     true,
+		"gitlab_releases": true,
+		"gitea_releases":  true,
+		"git_tags":        true,
+		"url_regex":       true,
+		"json_path":       true,
 		"nodejs_dist":     true,
 		"npm":             true,
 		"pypi":            true,
@@ -188,6 +197,50 @@ func validateVersion(result *ValidationResult, r *Recipe) {
 			result.addWarning("version.github_repo", "github_repo is recommended when using github version source")
 		}
 	}
+
+	// Forge release sources need a project and, for Gitea/Forgejo, the instance URL
+	if source == "gitlab_releases" || source == "gitea_releases" {
+		if r.Version.Repo == "" {
+			result.addError("version.repo", fmt.Sprintf("repo is required when using %s version source", source))
+		}
+		if source == "gitea_releases" && r.Version.BaseURL == "" {
+			result.addError("version.base_url", "base_url is required when using gitea_releases version source")
+		}
+	}
+	if r.Version.BaseURL != "" && !strings.HasPrefix(r.Version.BaseURL, "https://") {
+		result.addError("version.base_url", "base_url must use https://")
+	}
+
+	// Generic git tags need the repository URL (tags are read over smart HTTP, no git binary)
+	if source == "git_tags" && r.Version.GitURL == "" {
+		result.addError("version.git_url", "git_url is required when using git_tags version source")
+	}
+	if r.Version.GitURL != "" && !strings.HasPrefix(r.Version.GitURL, "https://") {
+		result.addError("version.git_url", "git_url must use https://")
+	}
+
+	// Scraping sources extract versions from a vendor page or JSON manifest
+	if source == "url_regex" || source == "json_path" {
+		if r.Version.URL == "" {
+			result.addError("version.url", fmt.Sprintf("url is required when using %s version source", source))
+		} else if !strings.HasPrefix(r.Version.URL, "https://") {
+			result.addError("version.url", "url must use https://")
+		}
+	}
+	if source == "url_regex" {
+		if r.Version.Regex == "" {
+			result.addError("version.regex", "regex is required when using url_regex version source")
+		} else if _, err := regexp.Compile(r.Version.Regex); err != nil {
+			result.addError("version.regex", fmt.Sprintf("invalid regex: %v", err))
+		}
+	}
+	if source == "json_path" {
+		if r.Version.JSONPath == "" {
+			result.addError("version.json_path", "json_path is required when using json_path version source")
+		} else if !strings.HasPrefix(r.Version.JSONPath, "$") {
+			result.addError("version.json_path", "json_path must start with '$'")
+		}
+	}
 }
 
 // canInferVersionFromActions checks if version source can be inferred from install actions.
@@ -223,7 +276,17 @@ func canInferVersionFromActions(r *Recipe) bool {
 			if _, ok := step.Params["repo"].(string); ok {
 				return true // InferredGitHubStrategy
 			}
-		case "require_system":
+		case "gitlab_archive", "gitlab_file":
+			if _, ok := step.Params["repo"].(string); ok {
+				return true // InferredForgeStrategy
+			}
+		case "gitea_archive", "gitea_file":
+			_, hasRepo := step.Params["repo"].(string)
+			_, hasBaseURL := step.Params["base_url"].(string)
+			if hasRepo && hasBaseURL {
+				return true // InferredForgeStrategy
+			}
+		case "require_system", "system_package":
 			// System dependencies don't use version providers - version is detected directly
 			return true
 		}
@@ -263,6 +326,29 @@ func validatePatches(result *ValidationResult, r *Recipe) {
 	}
 }
 
+// validateConfinement checks the confinement exceptions of a recipe
+func validateConfinement(result *ValidationResult, r *Recipe) {
+	c := r.Confinement
+	if c == nil {
+		return
+	}
+	if !c.Unconfined && len(c.Writable) == 0 && len(c.Readable) == 0 {
+		result.addWarning("confinement", "section grants no exception and can be removed")
+	}
+	if strings.TrimSpace(c.Reason) == "" {
+		result.addError("confinement.reason", "reason is required to relax confinement")
+	}
+	checkPaths := func(field string, paths []string) {
+		for i, p := range paths {
+			if !strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "~") && !strings.HasPrefix(p, "$") {
+				result.addError(fmt.Sprintf("confinement.%s[%d]", field, i), "path must be absolute (or start with ~ or $VAR)")
+			}
+		}
+	}
+	checkPaths("writable", c.Writable)
+	checkPaths("readable", c.Readable)
+}
+
 // validateSteps checks all steps
 func validateSteps(result *ValidationResult, r *Recipe) {
 	if len(r.Steps) == 0 {
@@ -317,6 +403,22 @@ func validateSteps(result *ValidationResult, r *Recipe) {
 
 		// Validate path-like parameters for security (path traversal, etc.)
 		validatePathParams(result, stepField, &step)
+
+		validateWhen(result, stepField, &step)
+	}
+}
+
+// validateWhen checks platform conditions that can be validated statically
+func validateWhen(result *ValidationResult, stepField string, step *Step) {
+	libc, ok := step.When["libc"]
+	if !ok {
+		return
+	}
+	if !platform.IsValidLibc(libc) {
+		result.addError(stepField+".when.libc", fmt.Sprintf("unknown libc '%s' (valid: %s)", libc, strings.Join(platform.SupportedLibc(), ", ")))
+	}
+	if osCondition, hasOS := step.When["os"]; hasOS && osCondition != "linux" {
+		result.addError(stepField+".when.libc", fmt.Sprintf("libc condition never matches os '%s' (libc only applies to linux)", osCondition))
 	}
 }
 
@@ -453,10 +555,37 @@ func validateVerify(result *ValidationResult, r *Recipe) {
 	// Check for dangerous patterns in verify command
 	validateDangerousPatterns(result, r.Verify.Command)
 
+	validateVerifyPatterns(result, r)
+
 	// Validate verification mode
 	validateVerifyMode(result, r)
 }
 
+// validateVerifyPatterns checks pattern_regex and version_format
+func validateVerifyPatterns(result *ValidationResult, r *Recipe) {
+	if r.Verify.PatternRegex != "" {
+		if r.Verify.Pattern != "" {
+			result.addError("verify.pattern_regex", "pattern and pattern_regex cannot both be set")
+		}
+
+		// Placeholders are substituted before compiling, so check the expression with them replaced
+		expr := strings.NewReplacer("{version}", "1.0.0", "{install_dir}", "/tmp").Replace(r.Verify.PatternRegex)
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			result.addError("verify.pattern_regex", fmt.Sprintf("invalid regular expression: %v", err))
+		} else if re.SubexpIndex("version") < 0 && !strings.Contains(r.Verify.PatternRegex, "{version}") &&
+			(r.Verify.Mode == "" || r.Verify.Mode == VerifyModeVersion) {
+			result.addWarning("verify.pattern_regex", "version mode pattern_regex should capture (?P<version>...) or include {version}")
+		}
+	}
+
+	switch r.Verify.VersionFormat {
+	case "", VersionFormatRaw, VersionFormatSemver, VersionFormatSemverFull, VersionFormatStripV:
+	default:
+		result.addWarning("verify.version_format", fmt.Sprintf("unknown version_format '%s' (valid: raw, semver, semver_full, strip_v); the version is used unchanged", r.Verify.VersionFormat))
+	}
+}
+
 // validateDangerousPatterns checks for potentially dangerous patterns in verify commands
 func validateDangerousPatterns(result *ValidationResult, command string) {
 	// Patterns with word boundaries to avoid false positives on tool names (e.g., "terraform")
@@ -528,14 +657,58 @@ func validateVerifyMode(result *ValidationResult, r *Recipe) {
 			result.addError("verify.reason", "output mode requires a reason explaining why version verification is not possible")
 		}
 
-	case "functional":
-		// Functional mode is reserved for v2
-		result.addError("verify.mode", "functional mode is reserved for future implementation")
+	case VerifyModeFunctional:
+		// Functional mode requires test cases to run
+		if len(r.Verify.Tests) == 0 {
+			result.addError("verify.tests", "functional mode requires at least one [[verify.tests]] entry")
+		}
 
 	default:
 		// Unknown mode - error
 		if mode != "" {
-			result.addError("verify.mode", fmt.Sprintf("unknown verification mode '%s' (valid: version, output)", mode))
+			result.addError("verify.mode", fmt.Sprintf("unknown verification mode '%s' (valid: version, output, functional)", mode))
+		}
+	}
+
+	validateFunctionalTests(result, r)
+}
+
+// validateFunctionalTests checks the functional test cases in the verify section
+func validateFunctionalTests(result *ValidationResult, r *Recipe) {
+	for i, test := range r.Verify.Tests {
+		field := fmt.Sprintf("verify.tests[%d]", i)
+
+		if test.Command == "" {
+			result.addError(field+".command", "command is required")
+		} else {
+			validateDangerousPatterns(result, test.Command)
+		}
+
+		if test.Stdout != "" {
+			if _, err := regexp.Compile(test.Stdout); err != nil {
+				result.addError(field+".stdout", fmt.Sprintf("invalid regular expression: %v", err))
+			}
+		}
+
+		if test.ExitCode != nil && (*test.ExitCode < 0 || *test.ExitCode > 255) {
+			result.addError(field+".exit_code", fmt.Sprintf("exit code %d is out of range (0-255)", *test.ExitCode))
+		}
+
+		for j, file := range test.Files {
+			fileField := fmt.Sprintf("%s.files[%d]", field, j)
+			switch {
+			case file.Path == "":
+				result.addError(fileField+".path", "path is required")
+			case filepath.IsAbs(file.Path) || strings.HasPrefix(filepath.Clean(file.Path), ".."):
+				result.addError(fileField+".path", "path must be relative to the test directory")
+			}
+			if file.Contains != "" {
+				if file.Absent {
+					result.addError(fileField+".contains", "contains cannot be combined with absent")
+				} else if _, err := regexp.Compile(file.Contains); err != nil {
+					result.addError(fileField+".contains", fmt.Sprintf("invalid regular expression: %v", err))
+				}
+			}
 		}
 	}
 }
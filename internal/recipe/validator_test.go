@@ -543,6 +543,58 @@ command = "test"
 	}
 }
 
+func TestValidateBytes_VersionSourceParams(t *testing.T) {
+	tests := []struct {
+		name      string
+		version   string
+		wantField string
+	}{
+		{"gitlab with repo", "source = \"gitlab_releases\"\nrepo = \"group/tool\"", ""},
+		{"gitlab missing repo", "source = \"gitlab_releases\"", "version.repo"},
+		{"gitea missing base_url", "source = \"gitea_releases\"\nrepo = \"owner/tool\"", "version.base_url"},
+		{"gitea plain http", "source = \"gitea_releases\"\nrepo = \"owner/tool\"\nbase_url = \"http://codeberg.org\"", "version.base_url"},
+		{"gitea complete", "source = \"gitea_releases\"\nrepo = \"owner/tool\"\nbase_url = \"https://codeberg.org\"", ""},
+		{"git_tags missing git_url", "source = \"git_tags\"", "version.git_url"},
+		{"git_tags plain http", "source = \"git_tags\"\ngit_url = \"http://example.com/tool.git\"", "version.git_url"},
+		{"git_tags complete", "source = \"git_tags\"\ngit_url = \"https://git.savannah.gnu.org/git/make.git\"", ""},
+		{"url_regex missing url", "source = \"url_regex\"\nregex = 'tool-([\\d.]+)'", "version.url"},
+		{"url_regex invalid regex", "source = \"url_regex\"\nurl = \"https://example.com/dl\"\nregex = 'tool-('", "version.regex"},
+		{"url_regex complete", "source = \"url_regex\"\nurl = \"https://example.com/dl\"\nregex = 'tool-([\\d.]+)'", ""},
+		{"json_path plain http", "source = \"json_path\"\nurl = \"http://example.com/v.json\"\njson_path = \"$.version\"", "version.url"},
+		{"json_path missing expression", "source = \"json_path\"\nurl = \"https://example.com/v.json\"", "version.json_path"},
+		{"json_path complete", "source = \"json_path\"\nurl = \"https://example.com/v.json\"\njson_path = \"$.releases[*].version\"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := `
+[metadata]
+name = "test"
+
+[version]
+` + tt.version + `
+
+[[steps]]
+action = "run_command"
+command = "echo test"
+
+[verify]
+command = "test"
+`
+			result := ValidateBytes([]byte(recipe))
+			var gotField string
+			for _, e := range result.Errors {
+				if strings.HasPrefix(e.Field, "version.") {
+					gotField = e.Field
+				}
+			}
+			if gotField != tt.wantField {
+				t.Errorf("version error field = %q, want %q (errors: %v)", gotField, tt.wantField, result.Errors)
+			}
+		})
+	}
+}
+
 func TestLevenshteinDistance(t *testing.T) {
 	tests := []struct {
 		s1, s2   string
@@ -1272,7 +1324,45 @@ reason = "Tool does not output version in a parseable format"
 }
 
 func TestValidateBytes_VerifyModeFunctional(t *testing.T) {
-	// Functional mode should be error (reserved for v2)
+	// Functional mode with test cases should be valid
+	recipe := `
+[metadata]
+name = "test"
+
+[[steps]]
+action = "run_command"
+command = "echo test"
+
+[verify]
+command = "jq --version"
+mode = "functional"
+
+[[verify.tests]]
+name = "filter"
+command = "jq -r .name"
+stdin = '{"name": "tsuku"}'
+stdout = "^tsuku$"
+
+[[verify.tests]]
+command = "jq -n '1' > out.json"
+
+[[verify.tests.files]]
+path = "out.json"
+contains = "1"
+
+[[verify.tests]]
+command = "jq ."
+stdin = "not json"
+exit_code = 5
+`
+	result := ValidateBytes([]byte(recipe))
+
+	if !result.Valid {
+		t.Errorf("expected valid recipe, got errors: %v", result.Errors)
+	}
+}
+
+func TestValidateBytes_VerifyModeFunctionalWithoutTests(t *testing.T) {
 	recipe := `
 [metadata]
 name = "test"
@@ -1288,10 +1378,52 @@ mode = "functional"
 	result := ValidateBytes([]byte(recipe))
 
 	if result.Valid {
-		t.Error("expected invalid recipe due to functional mode being reserved")
+		t.Error("expected invalid recipe without functional tests")
 	}
-	if !hasError(result, "verify.mode", "reserved") {
-		t.Errorf("expected error about functional mode being reserved, got errors: %v", result.Errors)
+	if !hasError(result, "verify.tests", "at least one") {
+		t.Errorf("expected error about missing tests, got errors: %v", result.Errors)
+	}
+}
+
+func TestValidateBytes_FunctionalTestErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		test      string
+		wantField string
+	}{
+		{"missing command", `stdout = "x"`, "verify.tests[0].command"},
+		{"invalid stdout regex", "command = \"t\"\nstdout = \"(\"", "verify.tests[0].stdout"},
+		{"exit code out of range", "command = \"t\"\nexit_code = 300", "verify.tests[0].exit_code"},
+		{"absolute file path", "command = \"t\"\n[[verify.tests.files]]\npath = \"/etc/passwd\"", "verify.tests[0].files[0].path"},
+		{"escaping file path", "command = \"t\"\n[[verify.tests.files]]\npath = \"../x\"", "verify.tests[0].files[0].path"},
+		{"invalid contains regex", "command = \"t\"\n[[verify.tests.files]]\npath = \"x\"\ncontains = \"[\"", "verify.tests[0].files[0].contains"},
+		{"contains with absent", "command = \"t\"\n[[verify.tests.files]]\npath = \"x\"\ncontains = \"a\"\nabsent = true", "verify.tests[0].files[0].contains"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := `
+[metadata]
+name = "test"
+
+[[steps]]
+action = "run_command"
+command = "echo test"
+
+[verify]
+command = "test --version"
+mode = "functional"
+
+[[verify.tests]]
+` + tt.test + "\n"
+			result := ValidateBytes([]byte(recipe))
+			if result.Valid {
+				t.Fatal("expected invalid recipe")
+			}
+			if !hasError(result, tt.wantField, "") {
+				t.Errorf("expected error on %s, got errors: %v", tt.wantField, result.Errors)
+			}
+		})
 	}
 }
 
@@ -1772,3 +1904,135 @@ command = "test-tool --version"
 			len(bytesResult.Warnings), len(recipeResult.Warnings))
 	}
 }
+
+func TestValidateBytes_VerifyPatternRegex(t *testing.T) {
+	tests := []struct {
+		name        string
+		verify      string
+		wantValid   bool
+		wantField   string
+		wantWarning bool
+	}{
+		{"version group", `pattern_regex = 'tool v?(?P<version>\S+)'`, true, "", false},
+		{"version placeholder", `pattern_regex = 'tool {version}$'`, true, "", false},
+		{"no version", `pattern_regex = '^tool \d+'`, true, "verify.pattern_regex", true},
+		{"invalid regex", `pattern_regex = '(?P<version>'`, false, "verify.pattern_regex", false},
+		{"both patterns", "pattern = \"tool {version}\"\npattern_regex = '(?P<version>\\S+)'", false, "verify.pattern_regex", false},
+		{"unknown version_format", "pattern = \"{version}\"\nversion_format = \"calver\"", true, "verify.version_format", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := `
+[metadata]
+name = "test"
+
+[[steps]]
+action = "run_command"
+command = "echo test"
+
+[verify]
+command = "tool --version"
+` + tt.verify + "\n"
+			result := ValidateBytes([]byte(recipe))
+			if result.Valid != tt.wantValid {
+				t.Fatalf("Valid = %v, errors: %v", result.Valid, result.Errors)
+			}
+			if !tt.wantValid && !hasError(result, tt.wantField, "") {
+				t.Errorf("expected error on %s, got errors: %v", tt.wantField, result.Errors)
+			}
+			if tt.wantWarning && !hasWarning(result, tt.wantField, "") {
+				t.Errorf("expected warning on %s, got warnings: %v", tt.wantField, result.Warnings)
+			}
+			if !tt.wantWarning && tt.wantValid {
+				for _, w := range result.Warnings {
+					if strings.HasPrefix(w.Field, "verify.") {
+						t.Errorf("unexpected warning: %v", w)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateBytes_WhenLibc(t *testing.T) {
+	tests := []struct {
+		name      string
+		when      string
+		wantValid bool
+	}{
+		{"musl", `{ libc = "musl" }`, true},
+		{"glibc on linux", `{ os = "linux", libc = "glibc" }`, true},
+		{"unknown libc", `{ libc = "gnu" }`, false},
+		{"libc on darwin", `{ os = "darwin", libc = "musl" }`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recipe := `
+[metadata]
+name = "test"
+
+[[steps]]
+action = "run_command"
+command = "echo test"
+when = ` + tt.when + `
+
+[verify]
+command = "test --version"
+pattern = "{version}"
+`
+			result := ValidateBytes([]byte(recipe))
+			if result.Valid != tt.wantValid {
+				t.Errorf("Valid = %v, errors: %v", result.Valid, result.Errors)
+			}
+			if !tt.wantValid && !hasError(result, "steps[0].when.libc", "") {
+				t.Errorf("expected error on steps[0].when.libc, got: %v", result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateBytes_Confinement(t *testing.T) {
+	base := `
+[metadata]
+name = "test-tool"
+
+[[steps]]
+action = "run_command"
+command = "make"
+
+[verify]
+command = "test-tool --version"
+`
+	tests := []struct {
+		name      string
+		section   string
+		wantField string
+	}{
+		{"valid", "[confinement]\nwritable = [\"~/.cache/test-tool\"]\nreason = \"cache\"\n", ""},
+		{"missing reason", "[confinement]\nunconfined = true\n", "confinement.reason"},
+		{"relative path", "[confinement]\nreadable = [\"sdk\"]\nreason = \"sdk\"\n", "confinement.readable[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateBytes([]byte(base + "\n" + tt.section))
+			if tt.wantField == "" {
+				if !result.Valid {
+					t.Errorf("expected valid recipe, got errors: %v", result.Errors)
+				}
+				return
+			}
+			found := false
+			for _, err := range result.Errors {
+				if err.Field == tt.wantField {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected error on %s, got: %v", tt.wantField, result.Errors)
+			}
+		})
+	}
+}
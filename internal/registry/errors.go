@@ -32,6 +32,8 @@ const (
 	ErrTypeConnection
 	// ErrTypeTLS indicates TLS/SSL certificate errors
 	ErrTypeTLS
+	// ErrTypeOffline indicates the recipe is not cached and offline mode forbids fetching it
+	ErrTypeOffline
 )
 
 // RegistryError provides structured error information for registry operations
@@ -69,6 +71,8 @@ func (e *RegistryError) Suggestion() string {
 		return "The registry may be down or blocked. Check if you can access GitHub"
 	case ErrTypeTLS:
 		return "There may be a certificate issue. Check your system time is correct"
+	case ErrTypeOffline:
+		return "Run 'tsuku info <tool>' while online to cache the recipe, or drop --offline"
 	case ErrTypeNotFound:
 		return "Verify the recipe name is correct. Run 'tsuku recipes' to list available recipes"
 	case ErrTypeNetwork:
@@ -110,6 +110,11 @@ func TestRegistryError_Suggestion(t *testing.T) {
 			errorType:  ErrTypeNotFound,
 			wantSubstr: "tsuku recipes",
 		},
+		{
+			name:       "offline has suggestion",
+			errorType:  ErrTypeOffline,
+			wantSubstr: "--offline",
+		},
 		{
 			name:       "generic network has suggestion",
 			errorType:  ErrTypeNetwork,
@@ -95,6 +95,14 @@ func (r *Registry) FetchRecipe(ctx context.Context, name string) ([]byte, error)
 		}
 	}
 
+	if config.IsOffline() {
+		return nil, &RegistryError{
+			Type:    ErrTypeOffline,
+			Recipe:  name,
+			Message: fmt.Sprintf("offline mode: recipe %s is not in the local registry cache", name),
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, &RegistryError{
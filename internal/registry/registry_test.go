@@ -2,10 +2,12 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -217,6 +219,34 @@ func TestFetchRecipeContextCancellation(t *testing.T) {
 	}
 }
 
+func TestFetchRecipe_Offline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	t.Setenv("TSUKU_OFFLINE", "1")
+	reg := &Registry{
+		BaseURL:  server.URL,
+		CacheDir: t.TempDir(),
+		client:   &http.Client{},
+	}
+
+	_, err := reg.FetchRecipe(context.Background(), "test-tool")
+	var regErr *RegistryError
+	if !errors.As(err, &regErr) || regErr.Type != ErrTypeOffline {
+		t.Fatalf("FetchRecipe() error = %v, want RegistryError of type ErrTypeOffline", err)
+	}
+	if !strings.Contains(err.Error(), "test-tool") {
+		t.Errorf("error should name the missing recipe, got %q", err.Error())
+	}
+	if requests != 0 {
+		t.Errorf("offline fetch made %d requests, want 0", requests)
+	}
+}
+
 func TestClearCache_NoCacheDir(t *testing.T) {
 	reg := &Registry{
 		CacheDir: "",
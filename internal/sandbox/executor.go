@@ -12,6 +12,7 @@ import (
 	"github.com/tsukumogami/tsuku/internal/executor"
 	"github.com/tsukumogami/tsuku/internal/log"
 	"github.com/tsukumogami/tsuku/internal/validate"
+	"github.com/tsukumogami/tsuku/internal/verify"
 )
 
 // TempDirPrefix is the prefix for temporary directories created by the sandbox.
@@ -27,7 +28,7 @@ type SandboxResult struct {
 	ExitCode int    // Container exit code
 	Stdout   string // Container stdout
 	Stderr   string // Container stderr
-	Error    error  // Error if sandbox failed to run
+	Error    error  // Error if sandbox failed to run or verify output didn't match
 }
 
 // Executor orchestrates container-based sandbox testing.
@@ -109,7 +110,7 @@ func findTsukuBinary() string {
 // 3. Generate sandbox script based on requirements
 // 4. Mount tsuku binary, plan, and cache into container
 // 5. Run container with configured limits
-// 6. Check verification output
+// 6. Check verification output (install, then functional tests if the plan has any)
 func (e *Executor) Sandbox(
 	ctx context.Context,
 	plan *executor.InstallationPlan,
@@ -253,15 +254,21 @@ func (e *Executor) Sandbox(
 		}, nil
 	}
 
-	// Check if verification passed (exit code 0 for now)
-	passed := result.ExitCode == 0
-
-	return &SandboxResult{
-		Passed:   passed,
+	// Check if the install and verification passed
+	sandboxResult := &SandboxResult{
+		Passed:   result.ExitCode == 0,
 		ExitCode: result.ExitCode,
 		Stdout:   result.Stdout,
 		Stderr:   result.Stderr,
-	}, nil
+	}
+	if sandboxResult.Passed {
+		if err := checkVerifyOutput(plan, result.Stdout); err != nil {
+			sandboxResult.Passed = false
+			sandboxResult.Error = err
+		}
+	}
+
+	return sandboxResult, nil
 }
 
 // buildSandboxScript creates the shell script for sandbox testing.
@@ -301,5 +308,59 @@ func (e *Executor) buildSandboxScript(
 	sb.WriteString("# Run tsuku install with pre-generated plan\n")
 	sb.WriteString("tsuku install --plan /workspace/plan.json --force\n")
 
+	if plan.Verify == nil || plan.Verify.Command == "" {
+		return sb.String()
+	}
+	sb.WriteString("export PATH=/workspace/tsuku/tools/current:$PATH\n")
+
+	// Run the verify command, capturing its output for checkVerifyOutput
+	sb.WriteString("\n# Run verify command to capture output for pattern matching\n")
+	command := strings.ReplaceAll(plan.Verify.Command, "{version}", plan.Version)
+	command = strings.ReplaceAll(command, "{install_dir}", containerInstallDir(plan))
+	sb.WriteString(verify.ContainerScript(command))
+
+	// Run the recipe's functional tests against the installed tool, so recipes are
+	// validated beyond a successful install
+	if len(plan.Verify.Tests) > 0 {
+		sb.WriteString("\n# Run functional verification from the plan\n")
+		sb.WriteString(fmt.Sprintf("tsuku verify %s --plan /workspace/plan.json\n", shellQuote(plan.Tool)))
+	}
+
 	return sb.String()
 }
+
+// containerInstallDir returns the tool's installation directory in the sandbox
+func containerInstallDir(plan *executor.InstallationPlan) string {
+	return fmt.Sprintf("/workspace/tsuku/tools/%s-%s", plan.Tool, plan.Version)
+}
+
+// checkVerifyOutput checks the verify command's output captured by the sandbox
+// script against the plan's expected exit code and pattern. Plans without a verify
+// command pass.
+func checkVerifyOutput(plan *executor.InstallationPlan, stdout string) error {
+	if plan.Verify == nil || plan.Verify.Command == "" {
+		return nil
+	}
+	output, exitCode, ok := verify.ParseContainerOutput(stdout)
+	if !ok {
+		return fmt.Errorf("verify command output not found in sandbox output")
+	}
+
+	section := plan.Verify.Section()
+	expectedExitCode := 0
+	if section.ExitCode != nil {
+		expectedExitCode = *section.ExitCode
+	}
+	if exitCode != expectedExitCode {
+		return fmt.Errorf("verify command exited with code %d, expected %d", exitCode, expectedExitCode)
+	}
+	if _, err := verify.MatchOutput(section, output, plan.Version, containerInstallDir(plan)); err != nil {
+		return fmt.Errorf("verify command output mismatch: %w", err)
+	}
+	return nil
+}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
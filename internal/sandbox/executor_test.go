@@ -2,13 +2,16 @@ package sandbox
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/tsukumogami/tsuku/internal/executor"
 	"github.com/tsukumogami/tsuku/internal/log"
+	"github.com/tsukumogami/tsuku/internal/recipe"
 	"github.com/tsukumogami/tsuku/internal/validate"
+	"github.com/tsukumogami/tsuku/internal/verify"
 )
 
 func TestNewExecutor(t *testing.T) {
@@ -76,6 +79,119 @@ func TestBuildSandboxScript_OfflineRequirements(t *testing.T) {
 	}
 }
 
+func TestBuildSandboxScript_FunctionalTests(t *testing.T) {
+	t.Parallel()
+
+	exec := &Executor{}
+	reqs := &SandboxRequirements{
+		Image:     DefaultSandboxImage,
+		Resources: DefaultLimits(),
+	}
+
+	// Plans without functional tests only install
+	plan := &executor.InstallationPlan{
+		Tool:    "jq",
+		Version: "1.7.1",
+		Verify:  &executor.PlanVerify{Command: "jq --version"},
+	}
+	if script := exec.buildSandboxScript(plan, reqs); strings.Contains(script, "tsuku verify") {
+		t.Error("Script should not run verification without functional tests")
+	}
+
+	plan.Verify.Tests = []recipe.FunctionalTest{{Command: "jq -r .a", Stdin: `{"a": 1}`, Stdout: "^1$"}}
+	script := exec.buildSandboxScript(plan, reqs)
+
+	install := strings.Index(script, "tsuku install --plan")
+	verifyIdx := strings.Index(script, "tsuku verify 'jq' --plan /workspace/plan.json")
+	if verifyIdx == -1 {
+		t.Fatalf("Script should run functional verification from the plan:\n%s", script)
+	}
+	if verifyIdx < install {
+		t.Error("Functional verification should run after install")
+	}
+	if !strings.Contains(script, "/workspace/tsuku/tools/current") {
+		t.Error("Script should put installed binaries on PATH for verification")
+	}
+}
+
+func TestBuildSandboxScript_VerifyCommand(t *testing.T) {
+	t.Parallel()
+
+	exec := &Executor{}
+	reqs := &SandboxRequirements{
+		Image:     DefaultSandboxImage,
+		Resources: DefaultLimits(),
+	}
+
+	plan := &executor.InstallationPlan{
+		Tool:    "jq",
+		Version: "1.7.1",
+		Verify:  &executor.PlanVerify{Command: "{install_dir}/bin/jq --version # {version}"},
+	}
+	script := exec.buildSandboxScript(plan, reqs)
+	if !strings.Contains(script, "/workspace/tsuku/tools/jq-1.7.1/bin/jq --version # 1.7.1") {
+		t.Errorf("Script should run the verify command with placeholders expanded:\n%s", script)
+	}
+	if !strings.Contains(script, verify.ContainerScript("/workspace/tsuku/tools/jq-1.7.1/bin/jq --version # 1.7.1")) {
+		t.Error("Script should capture the verify command's output")
+	}
+
+	plan.Verify = nil
+	if script := exec.buildSandboxScript(plan, reqs); strings.Contains(script, "Run verify command") {
+		t.Error("Script should not run a verify command for plans without one")
+	}
+}
+
+func TestCheckVerifyOutput(t *testing.T) {
+	t.Parallel()
+
+	exitCode2 := 2
+	tests := []struct {
+		name    string
+		verify  *executor.PlanVerify
+		stdout  string
+		wantErr string
+	}{
+		{"no verify", nil, "", ""},
+		{"pattern match", &executor.PlanVerify{Command: "jq --version", Pattern: "jq-{version}"}, markedOutput("jq-1.7.1", 0), ""},
+		{"regex version match", &executor.PlanVerify{Command: "jq --version", PatternRegex: `jq-(?P<version>\S+)`}, markedOutput("jq-1.7.1", 0), ""},
+		{"regex version mismatch", &executor.PlanVerify{Command: "jq --version", PatternRegex: `jq-(?P<version>\S+)`}, markedOutput("jq-1.6", 0), "reports version 1.6"},
+		{"install log is ignored", &executor.PlanVerify{Command: "jq --version", Pattern: "1.7.1"}, "Executing plan: jq@1.7.1\n" + markedOutput("jq-dev", 0), "does not contain"},
+		{"exit code", &executor.PlanVerify{Command: "jq --version"}, markedOutput("", 1), "exited with code 1"},
+		{"expected exit code", &executor.PlanVerify{Command: "jq -h", ExitCode: &exitCode2}, markedOutput("usage", 2), ""},
+		{"missing output", &executor.PlanVerify{Command: "jq --version"}, "install failed", "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &executor.InstallationPlan{Tool: "jq", Version: "1.7.1", Verify: tt.verify}
+			err := checkVerifyOutput(plan, tt.stdout)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkVerifyOutput() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkVerifyOutput() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// markedOutput returns container stdout as written by verify.ContainerScript
+func markedOutput(output string, exitCode int) string {
+	return fmt.Sprintf("::tsuku-verify-output::\n%s\n::tsuku-verify-exit=%d::\n", output, exitCode)
+}
+
+func TestShellQuote(t *testing.T) {
+	t.Parallel()
+
+	if got := shellQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shellQuote() = %s", got)
+	}
+}
+
 func TestBuildSandboxScript_NetworkRequirements(t *testing.T) {
 	t.Parallel()
 
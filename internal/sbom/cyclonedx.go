@@ -0,0 +1,110 @@
+package sbom
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// CycloneDXSpecVersion is the CycloneDX specification version written by WriteCycloneDX
+const CycloneDXSpecVersion = "1.5"
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies,omitempty"`
+}
+
+type cdxMetadata struct {
+	Timestamp string `json:"timestamp"`
+	Tools     struct {
+		Components []cdxComponent `json:"components"`
+	} `json:"tools"`
+}
+
+type cdxComponent struct {
+	Type               string           `json:"type"`
+	BOMRef             string           `json:"bom-ref,omitempty"`
+	Name               string           `json:"name"`
+	Version            string           `json:"version,omitempty"`
+	PURL               string           `json:"purl,omitempty"`
+	ExternalReferences []cdxExternalRef `json:"externalReferences,omitempty"`
+	Properties         []cdxProperty    `json:"properties,omitempty"`
+	Components         []cdxComponent   `json:"components,omitempty"`
+}
+
+type cdxExternalRef struct {
+	Type   string    `json:"type"`
+	URL    string    `json:"url"`
+	Hashes []cdxHash `json:"hashes,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// WriteCycloneDX writes doc as a CycloneDX JSON document. Downloads are
+// distribution references carrying their SHA-256, recipe details are
+// "tsuku:" properties and lockfile packages are nested components.
+func WriteCycloneDX(w io.Writer, doc *Document) error {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  CycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + doc.SerialNumber,
+		Version:      1,
+		Components:   []cdxComponent{},
+	}
+	bom.Metadata.Timestamp = doc.Timestamp.Format(time.RFC3339)
+	bom.Metadata.Tools.Components = []cdxComponent{{Type: TypeApplication, Name: "tsuku", Version: doc.ToolVersion}}
+
+	for _, c := range doc.Components {
+		bom.Components = append(bom.Components, toCycloneDX(c))
+		bom.Dependencies = append(bom.Dependencies, cdxDependency{Ref: c.Ref, DependsOn: c.DependsOn})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bom)
+}
+
+func toCycloneDX(c *Component) cdxComponent {
+	out := cdxComponent{
+		Type:    c.Type,
+		BOMRef:  c.Ref,
+		Name:    c.Name,
+		Version: c.Version,
+		PURL:    c.PURL,
+	}
+	for _, d := range c.Downloads {
+		ref := cdxExternalRef{Type: "distribution", URL: d.URL}
+		if d.SHA256 != "" {
+			ref.Hashes = []cdxHash{{Alg: "SHA-256", Content: d.SHA256}}
+		}
+		out.ExternalReferences = append(out.ExternalReferences, ref)
+	}
+	if c.RecipeHash != "" {
+		out.Properties = append(out.Properties, cdxProperty{Name: "tsuku:recipe_hash", Value: c.RecipeHash})
+	}
+	if c.RecipeSource != "" {
+		out.Properties = append(out.Properties, cdxProperty{Name: "tsuku:recipe_source", Value: c.RecipeSource})
+	}
+	for _, p := range c.Packages {
+		out.Components = append(out.Components, toCycloneDX(p))
+	}
+	return out
+}
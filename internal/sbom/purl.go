@@ -0,0 +1,220 @@
+package sbom
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+// ecosystemPackages returns the package URL of a tool installed through a
+// package ecosystem and the packages pinned by its captured lockfile. Plans
+// of other tools have neither.
+func ecosystemPackages(plan *install.Plan) (string, []*Component) {
+	for _, step := range plan.Steps {
+		p := step.Params
+		switch step.Action {
+		case "npm_exec":
+			name, version := param(p, "package"), param(p, "version")
+			return purl("npm", name, version), npmLockPackages(param(p, "package_lock"))
+		case "pip_exec":
+			name, version := param(p, "package"), param(p, "version")
+			return purl("pypi", normalizePyPIName(name), version), requirementsPackages(param(p, "locked_requirements"))
+		case "cargo_build":
+			if crate := param(p, "crate"); crate != "" {
+				return purl("cargo", crate, param(p, "version")), cargoLockPackages(param(p, "lock_data"))
+			}
+		case "gem_exec":
+			return purl("gem", param(p, "gem"), param(p, "version")), gemfileLockPackages(param(p, "lock_data"))
+		case "go_build":
+			if module := param(p, "module"); module != "" {
+				return purl("golang", module, param(p, "version")), goSumPackages(param(p, "go_sum"))
+			}
+		case "cpan_install":
+			if dist := param(p, "distribution"); dist != "" {
+				return purl("cpan", dist, plan.Version), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// purl formats a package URL (https://github.com/package-url/purl-spec).
+// Path segments of name are escaped individually, so namespaces like npm
+// scopes and Go module paths are kept.
+func purl(ecosystem, name, version string) string {
+	if name == "" {
+		return ""
+	}
+	segments := strings.Split(name, "/")
+	for i, s := range segments {
+		segments[i] = purlEscape(s)
+	}
+	p := fmt.Sprintf("pkg:%s/%s", ecosystem, strings.Join(segments, "/"))
+	if version != "" {
+		p += "@" + purlEscape(version)
+	}
+	return p
+}
+
+// purlEscape percent-encodes a purl component, including the "@" that
+// separates the version (as in npm scopes)
+func purlEscape(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "@", "%40")
+}
+
+// newPackage creates the component of an ecosystem package
+func newPackage(ecosystem, name, version string) *Component {
+	return &Component{
+		Type:    TypeLibrary,
+		Name:    name,
+		Version: version,
+		PURL:    purl(ecosystem, name, version),
+	}
+}
+
+// sortPackages orders packages by package URL and drops duplicates
+func sortPackages(packages []*Component) []*Component {
+	sort.Slice(packages, func(i, j int) bool { return packages[i].PURL < packages[j].PURL })
+	var out []*Component
+	for i, p := range packages {
+		if i > 0 && p.PURL == packages[i-1].PURL {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// npmLockPackages lists the packages of a package-lock.json. Lockfile
+// version 2 and 3 list every installed package under "packages", keyed by its
+// node_modules path; version 1 nests them under "dependencies".
+func npmLockPackages(lock string) []*Component {
+	if lock == "" {
+		return nil
+	}
+	var parsed struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]npmLockDependency `json:"dependencies"`
+	}
+	if err := json.Unmarshal([]byte(lock), &parsed); err != nil {
+		return nil
+	}
+
+	var packages []*Component
+	if len(parsed.Packages) > 0 {
+		for path, pkg := range parsed.Packages {
+			i := strings.LastIndex(path, "node_modules/")
+			if i < 0 || pkg.Version == "" {
+				continue // The root project
+			}
+			packages = append(packages, newPackage("npm", path[i+len("node_modules/"):], pkg.Version))
+		}
+	} else {
+		var walk func(map[string]npmLockDependency)
+		walk = func(deps map[string]npmLockDependency) {
+			for name, dep := range deps {
+				packages = append(packages, newPackage("npm", name, dep.Version))
+				walk(dep.Dependencies)
+			}
+		}
+		walk(parsed.Dependencies)
+	}
+	return sortPackages(packages)
+}
+
+// npmLockDependency is a package in a version 1 package-lock.json
+type npmLockDependency struct {
+	Version      string                       `json:"version"`
+	Dependencies map[string]npmLockDependency `json:"dependencies"`
+}
+
+// requirementPattern matches a pinned requirement ("name==version")
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?==([^\s;\\]+)`)
+
+// requirementsPackages lists the pinned packages of a requirements file
+// produced by pip-compile or pip freeze
+func requirementsPackages(requirements string) []*Component {
+	var packages []*Component
+	scanner := bufio.NewScanner(strings.NewReader(requirements))
+	for scanner.Scan() {
+		m := requirementPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m != nil {
+			packages = append(packages, newPackage("pypi", normalizePyPIName(m[1]), m[2]))
+		}
+	}
+	return sortPackages(packages)
+}
+
+// normalizePyPIName applies the purl rules for PyPI names: lowercase, with
+// underscores replaced by dashes
+func normalizePyPIName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
+
+// cargoLockPackages lists the registry crates of a Cargo.lock. Path and git
+// dependencies are part of the crate being built and are skipped.
+func cargoLockPackages(lock string) []*Component {
+	var parsed struct {
+		Package []struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+			Source  string `toml:"source"`
+		} `toml:"package"`
+	}
+	if _, err := toml.Decode(lock, &parsed); err != nil {
+		return nil
+	}
+	var packages []*Component
+	for _, pkg := range parsed.Package {
+		if strings.HasPrefix(pkg.Source, "registry+") {
+			packages = append(packages, newPackage("cargo", pkg.Name, pkg.Version))
+		}
+	}
+	return sortPackages(packages)
+}
+
+// gemSpecPattern matches a gem in the specs section of a Gemfile.lock,
+// which is indented by exactly four spaces ("    rake (13.0.6)")
+var gemSpecPattern = regexp.MustCompile(`^ {4}([A-Za-z0-9._-]+) \(([^)]+)\)$`)
+
+// gemfileLockPackages lists the gems of a Gemfile.lock
+func gemfileLockPackages(lock string) []*Component {
+	var packages []*Component
+	scanner := bufio.NewScanner(strings.NewReader(lock))
+	for scanner.Scan() {
+		if m := gemSpecPattern.FindStringSubmatch(scanner.Text()); m != nil {
+			packages = append(packages, newPackage("gem", m[1], m[2]))
+		}
+	}
+	return sortPackages(packages)
+}
+
+// goSumPackages lists the modules of a go.sum. Entries for go.mod files
+// only are skipped; they belong to modules that weren't needed for the build.
+func goSumPackages(goSum string) []*Component {
+	var packages []*Component
+	scanner := bufio.NewScanner(strings.NewReader(goSum))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		packages = append(packages, newPackage("golang", fields[0], fields[1]))
+	}
+	return sortPackages(packages)
+}
+
+// param returns a string step parameter, or ""
+func param(params map[string]interface{}, key string) string {
+	s, _ := params[key].(string)
+	return s
+}
@@ -0,0 +1,101 @@
+package sbom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+func purls(packages []*Component) string {
+	var out []string
+	for _, p := range packages {
+		out = append(out, p.PURL)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestPURL(t *testing.T) {
+	tests := []struct {
+		ecosystem, name, version, want string
+	}{
+		{"npm", "prettier", "3.0.0", "pkg:npm/prettier@3.0.0"},
+		{"npm", "@babel/core", "7.24.0", "pkg:npm/%40babel/core@7.24.0"},
+		{"golang", "github.com/junegunn/fzf", "v0.44.1", "pkg:golang/github.com/junegunn/fzf@v0.44.1"},
+		{"pypi", "ruff", "", "pkg:pypi/ruff"},
+		{"cargo", "", "1.0.0", ""},
+	}
+	for _, tt := range tests {
+		if got := purl(tt.ecosystem, tt.name, tt.version); got != tt.want {
+			t.Errorf("purl(%q, %q, %q) = %q, want %q", tt.ecosystem, tt.name, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestNpmLockPackages(t *testing.T) {
+	v3 := `{"lockfileVersion": 3, "packages": {
+		"": {"name": "tsuku-npm-eval"},
+		"node_modules/prettier": {"version": "3.0.0"},
+		"node_modules/@scope/a": {"version": "1.0.0"},
+		"node_modules/@scope/a/node_modules/b": {"version": "2.0.0"}
+	}}`
+	if got, want := purls(npmLockPackages(v3)), "pkg:npm/%40scope/a@1.0.0 pkg:npm/b@2.0.0 pkg:npm/prettier@3.0.0"; got != want {
+		t.Errorf("npmLockPackages(v3) = %s, want %s", got, want)
+	}
+
+	v1 := `{"lockfileVersion": 1, "dependencies": {"a": {"version": "1.0.0", "dependencies": {"b": {"version": "2.0.0"}}}}}`
+	if got, want := purls(npmLockPackages(v1)), "pkg:npm/a@1.0.0 pkg:npm/b@2.0.0"; got != want {
+		t.Errorf("npmLockPackages(v1) = %s, want %s", got, want)
+	}
+}
+
+func TestRequirementsPackages(t *testing.T) {
+	requirements := `# generated by pip-compile
+Ruff==0.4.1 \
+    --hash=sha256:abc
+typing_extensions==4.11.0 ; python_version < "3.12"
+requests[socks]==2.31.0
+-e ./local
+`
+	want := "pkg:pypi/requests@2.31.0 pkg:pypi/ruff@0.4.1 pkg:pypi/typing-extensions@4.11.0"
+	if got := purls(requirementsPackages(requirements)); got != want {
+		t.Errorf("requirementsPackages() = %s, want %s", got, want)
+	}
+}
+
+func TestCargoLockPackages(t *testing.T) {
+	lock := `version = 3
+
+[[package]]
+name = "ripgrep"
+version = "14.1.0"
+
+[[package]]
+name = "memchr"
+version = "2.7.1"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`
+	if got, want := purls(cargoLockPackages(lock)), "pkg:cargo/memchr@2.7.1"; got != want {
+		t.Errorf("cargoLockPackages() = %s, want %s", got, want)
+	}
+}
+
+func TestGoSumPackages(t *testing.T) {
+	goSum := `github.com/a/b v1.0.0 h1:abc=
+github.com/a/b v1.0.0/go.mod h1:def=
+github.com/c/d v0.1.0/go.mod h1:ghi=
+`
+	if got, want := purls(goSumPackages(goSum)), "pkg:golang/github.com/a/b@v1.0.0"; got != want {
+		t.Errorf("goSumPackages() = %s, want %s", got, want)
+	}
+}
+
+func TestEcosystemPackages_CPAN(t *testing.T) {
+	plan := &install.Plan{
+		Version: "3.7.0",
+		Steps:   []install.PlanStep{{Action: "cpan_install", Params: map[string]interface{}{"distribution": "App-Ack"}}},
+	}
+	if p, _ := ecosystemPackages(plan); p != "pkg:cpan/App-Ack@3.7.0" {
+		t.Errorf("ecosystemPackages() purl = %q", p)
+	}
+}
@@ -0,0 +1,214 @@
+// Package sbom builds software bills of materials for tools installed by
+// tsuku, from state.json and the installation plans stored with each version,
+// and writes them as CycloneDX or SPDX JSON.
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/buildinfo"
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+// Component types
+const (
+	TypeApplication = "application" // A tool installed by tsuku
+	TypeLibrary     = "library"     // A library installed by tsuku, or an ecosystem package bundled with a tool
+)
+
+// Document is an SBOM independent of the output format.
+type Document struct {
+	SerialNumber string // Random UUID identifying this document
+	Timestamp    time.Time
+	ToolVersion  string // Version of tsuku that produced the document
+	Components   []*Component
+}
+
+// Component is one installed tool or library version.
+type Component struct {
+	Ref          string // Unique reference within the document
+	Type         string // TypeApplication or TypeLibrary
+	Name         string
+	Version      string
+	PURL         string // Package URL of ecosystem installs, "" otherwise
+	RecipeHash   string
+	RecipeSource string
+	Downloads    []Download
+	DependsOn    []string     // Refs of tools and libraries this component uses
+	Packages     []*Component // Ecosystem packages bundled with the tool (from its lockfile)
+}
+
+// Download is a file fetched while installing a component.
+type Download struct {
+	URL    string
+	SHA256 string
+}
+
+// Build creates a document covering the given tools and everything they
+// depend on, or every installed tool and library when tools is empty.
+// Every installed version of a tool is included.
+func Build(state *install.State, tools []string) (*Document, error) {
+	doc := &Document{SerialNumber: newUUID(), Timestamp: time.Now().UTC(), ToolVersion: buildinfo.Version()}
+
+	// Component refs of every tool version and library version, keyed by the
+	// "name-version" form used in library UsedBy lists
+	byNameVersion := make(map[string]string)
+	components := make(map[string]*Component)
+
+	for name, ts := range state.Installed {
+		for version, vs := range ts.Versions {
+			c := toolComponent(name, version, vs)
+			components[c.Ref] = c
+			byNameVersion[name+"-"+version] = c.Ref
+		}
+	}
+	for name, versions := range state.Libs {
+		for version := range versions {
+			c := &Component{
+				Ref:     libraryRef(name, version),
+				Type:    TypeLibrary,
+				Name:    name,
+				Version: version,
+			}
+			components[c.Ref] = c
+		}
+	}
+
+	// Tool dependencies: RequiredBy lists the tools that use a tool, which
+	// depend on its active version
+	for name, ts := range state.Installed {
+		dep := toolRef(name, activeVersion(ts))
+		if components[dep] == nil {
+			continue
+		}
+		for _, dependent := range ts.RequiredBy {
+			dts, ok := state.Installed[dependent]
+			if !ok {
+				continue
+			}
+			for version := range dts.Versions {
+				addDependency(components[toolRef(dependent, version)], dep)
+			}
+		}
+	}
+
+	// Library dependencies: UsedBy lists "name-version" of the tools using it
+	for name, versions := range state.Libs {
+		for version, ls := range versions {
+			for _, user := range ls.UsedBy {
+				if ref, ok := byNameVersion[user]; ok {
+					addDependency(components[ref], libraryRef(name, version))
+				}
+			}
+		}
+	}
+
+	// Select the requested tools and their dependencies
+	selected := make(map[string]bool)
+	if len(tools) == 0 {
+		for ref := range components {
+			selected[ref] = true
+		}
+	} else {
+		var queue []string
+		for _, name := range tools {
+			ts, ok := state.Installed[name]
+			if !ok {
+				return nil, fmt.Errorf("tool '%s' is not installed", name)
+			}
+			for version := range ts.Versions {
+				queue = append(queue, toolRef(name, version))
+			}
+		}
+		for len(queue) > 0 {
+			ref := queue[0]
+			queue = queue[1:]
+			if selected[ref] {
+				continue
+			}
+			selected[ref] = true
+			queue = append(queue, components[ref].DependsOn...)
+		}
+	}
+
+	for ref := range selected {
+		doc.Components = append(doc.Components, components[ref])
+	}
+	sort.Slice(doc.Components, func(i, j int) bool { return doc.Components[i].Ref < doc.Components[j].Ref })
+	return doc, nil
+}
+
+// toolComponent creates the component of an installed tool version from its
+// stored plan
+func toolComponent(name, version string, vs install.VersionState) *Component {
+	c := &Component{
+		Ref:     toolRef(name, version),
+		Type:    TypeApplication,
+		Name:    name,
+		Version: version,
+	}
+	if vs.Plan == nil {
+		return c
+	}
+
+	c.RecipeHash = vs.Plan.RecipeHash
+	c.RecipeSource = vs.Plan.RecipeSource
+	for _, step := range vs.Plan.Steps {
+		if step.URL != "" {
+			c.Downloads = append(c.Downloads, Download{URL: step.URL, SHA256: step.Checksum})
+		}
+	}
+
+	purl, packages := ecosystemPackages(vs.Plan)
+	c.PURL = purl
+	for _, p := range packages {
+		if p.PURL == c.PURL {
+			continue
+		}
+		p.Ref = c.Ref + "/" + p.PURL
+		c.Packages = append(c.Packages, p)
+	}
+	return c
+}
+
+// addDependency records that c depends on ref
+func addDependency(c *Component, ref string) {
+	if c == nil {
+		return
+	}
+	for _, existing := range c.DependsOn {
+		if existing == ref {
+			return
+		}
+	}
+	c.DependsOn = append(c.DependsOn, ref)
+	sort.Strings(c.DependsOn)
+}
+
+func toolRef(name, version string) string {
+	return fmt.Sprintf("tool:%s@%s", name, version)
+}
+
+func libraryRef(name, version string) string {
+	return fmt.Sprintf("lib:%s@%s", name, version)
+}
+
+// activeVersion returns the version a tool's symlinks point to
+func activeVersion(ts install.ToolState) string {
+	if ts.ActiveVersion != "" {
+		return ts.ActiveVersion
+	}
+	return ts.Version
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
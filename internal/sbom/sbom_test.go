@@ -0,0 +1,186 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/install"
+)
+
+func testState() *install.State {
+	return &install.State{
+		Installed: map[string]install.ToolState{
+			"ruby": {
+				ActiveVersion: "3.4.0",
+				Versions: map[string]install.VersionState{
+					"3.4.0": {Plan: &install.Plan{
+						Tool:         "ruby",
+						Version:      "3.4.0",
+						RecipeHash:   "abc123",
+						RecipeSource: "registry",
+						Steps: []install.PlanStep{
+							{Action: "download_file", URL: "https://example.com/ruby-3.4.0.tar.gz", Checksum: "deadbeef"},
+							{Action: "extract"},
+						},
+					}},
+				},
+				RequiredBy: []string{"jekyll"},
+			},
+			"jekyll": {
+				ActiveVersion: "4.3.3",
+				IsExplicit:    true,
+				Versions: map[string]install.VersionState{
+					"4.3.3": {Plan: &install.Plan{
+						Tool:    "jekyll",
+						Version: "4.3.3",
+						Steps: []install.PlanStep{{
+							Action: "gem_exec",
+							Params: map[string]interface{}{
+								"gem":       "jekyll",
+								"version":   "4.3.3",
+								"lock_data": "GEM\n  remote: https://rubygems.org/\n  specs:\n    jekyll (4.3.3)\n      rouge (>= 3.0)\n    rouge (4.2.0)\n",
+							},
+						}},
+					}},
+				},
+			},
+			"jq": {
+				ActiveVersion: "1.7.1",
+				IsExplicit:    true,
+				Versions:      map[string]install.VersionState{"1.7.1": {}},
+			},
+		},
+		Libs: map[string]map[string]install.LibraryVersionState{
+			"libyaml": {"0.2.5": {UsedBy: []string{"ruby-3.4.0"}}},
+		},
+	}
+}
+
+func findComponent(doc *Document, ref string) *Component {
+	for _, c := range doc.Components {
+		if c.Ref == ref {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestBuild_AllComponents(t *testing.T) {
+	doc, err := Build(testState(), nil)
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(doc.Components) != 4 {
+		t.Fatalf("Build() returned %d components, want 4", len(doc.Components))
+	}
+
+	ruby := findComponent(doc, "tool:ruby@3.4.0")
+	if ruby == nil {
+		t.Fatal("ruby component missing")
+	}
+	if len(ruby.Downloads) != 1 || ruby.Downloads[0].SHA256 != "deadbeef" {
+		t.Errorf("ruby downloads = %+v", ruby.Downloads)
+	}
+	if ruby.RecipeHash != "abc123" || ruby.RecipeSource != "registry" {
+		t.Errorf("ruby recipe = %q %q", ruby.RecipeHash, ruby.RecipeSource)
+	}
+	if len(ruby.DependsOn) != 1 || ruby.DependsOn[0] != "lib:libyaml@0.2.5" {
+		t.Errorf("ruby DependsOn = %v, want libyaml", ruby.DependsOn)
+	}
+
+	jekyll := findComponent(doc, "tool:jekyll@4.3.3")
+	if jekyll.PURL != "pkg:gem/jekyll@4.3.3" {
+		t.Errorf("jekyll PURL = %q", jekyll.PURL)
+	}
+	if len(jekyll.DependsOn) != 1 || jekyll.DependsOn[0] != "tool:ruby@3.4.0" {
+		t.Errorf("jekyll DependsOn = %v, want ruby", jekyll.DependsOn)
+	}
+	// The tool's own gem is not repeated as a bundled package
+	if len(jekyll.Packages) != 1 || jekyll.Packages[0].PURL != "pkg:gem/rouge@4.2.0" {
+		t.Errorf("jekyll Packages = %+v, want only rouge", jekyll.Packages)
+	}
+}
+
+func TestBuild_SelectsToolsAndDependencies(t *testing.T) {
+	doc, err := Build(testState(), []string{"jekyll"})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	var refs []string
+	for _, c := range doc.Components {
+		refs = append(refs, c.Ref)
+	}
+	want := "lib:libyaml@0.2.5 tool:jekyll@4.3.3 tool:ruby@3.4.0"
+	if got := strings.Join(refs, " "); got != want {
+		t.Errorf("components = %s, want %s", got, want)
+	}
+
+	if _, err := Build(testState(), []string{"missing"}); err == nil {
+		t.Error("expected error for a tool that is not installed")
+	}
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	doc, _ := Build(testState(), nil)
+	var buf bytes.Buffer
+	if err := WriteCycloneDX(&buf, doc); err != nil {
+		t.Fatalf("WriteCycloneDX() error = %v", err)
+	}
+
+	var bom cdxBOM
+	if err := json.Unmarshal(buf.Bytes(), &bom); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if bom.BOMFormat != "CycloneDX" || bom.SpecVersion != CycloneDXSpecVersion {
+		t.Errorf("bomFormat/specVersion = %s/%s", bom.BOMFormat, bom.SpecVersion)
+	}
+	if !strings.HasPrefix(bom.SerialNumber, "urn:uuid:") {
+		t.Errorf("serialNumber = %q", bom.SerialNumber)
+	}
+	if len(bom.Components) != 4 || len(bom.Dependencies) != 4 {
+		t.Errorf("got %d components and %d dependency entries, want 4 each", len(bom.Components), len(bom.Dependencies))
+	}
+	for _, c := range bom.Components {
+		if c.BOMRef == "tool:ruby@3.4.0" {
+			if len(c.ExternalReferences) != 1 || c.ExternalReferences[0].Hashes[0].Content != "deadbeef" {
+				t.Errorf("ruby externalReferences = %+v", c.ExternalReferences)
+			}
+		}
+	}
+}
+
+func TestWriteSPDX(t *testing.T) {
+	doc, _ := Build(testState(), nil)
+	var buf bytes.Buffer
+	if err := WriteSPDX(&buf, doc); err != nil {
+		t.Fatalf("WriteSPDX() error = %v", err)
+	}
+
+	var out spdxDocument
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if out.SPDXVersion != SPDXVersion {
+		t.Errorf("spdxVersion = %q", out.SPDXVersion)
+	}
+	// 4 components plus rouge bundled with jekyll
+	if len(out.Packages) != 5 {
+		t.Errorf("got %d packages, want 5", len(out.Packages))
+	}
+
+	counts := make(map[string]int)
+	for _, r := range out.Relationships {
+		counts[r.RelationshipType]++
+	}
+	if counts["DESCRIBES"] != 4 || counts["DEPENDS_ON"] != 2 || counts["CONTAINS"] != 1 {
+		t.Errorf("relationships = %v, want 4 DESCRIBES, 2 DEPENDS_ON, 1 CONTAINS", counts)
+	}
+
+	for _, p := range out.Packages {
+		if strings.ContainsAny(strings.TrimPrefix(p.SPDXID, "SPDXRef-"), ":@/") {
+			t.Errorf("invalid SPDXID %q", p.SPDXID)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SPDXVersion is the SPDX specification version written by WriteSPDX
+const SPDXVersion = "SPDX-2.3"
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string         `json:"name"`
+	SPDXID           string         `json:"SPDXID"`
+	VersionInfo      string         `json:"versionInfo,omitempty"`
+	DownloadLocation string         `json:"downloadLocation"`
+	FilesAnalyzed    bool           `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum `json:"checksums,omitempty"`
+	SourceInfo       string         `json:"sourceInfo,omitempty"`
+	ExternalRefs     []spdxExternal `json:"externalRefs,omitempty"`
+	PrimaryPurpose   string         `json:"primaryPackagePurpose,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternal struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// WriteSPDX writes doc as an SPDX JSON document. Each component is a package
+// described by the document; dependencies are DEPENDS_ON relationships and
+// lockfile packages are packages the tool CONTAINS. SPDX packages have a
+// single download location, so further downloads are listed in sourceInfo.
+func WriteSPDX(w io.Writer, doc *Document) error {
+	out := spdxDocument{
+		SPDXVersion:       SPDXVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "tsuku-installed-tools",
+		DocumentNamespace: "https://tsuku.dev/spdx/" + doc.SerialNumber,
+		CreationInfo: spdxCreationInfo{
+			Created:  doc.Timestamp.Format(time.RFC3339),
+			Creators: []string{"Tool: tsuku-" + doc.ToolVersion},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for _, c := range doc.Components {
+		id := spdxID(c.Ref)
+		out.Packages = append(out.Packages, toSPDX(c))
+		out.Relationships = append(out.Relationships, spdxRelationship{
+			SPDXElementID: out.SPDXID, RelationshipType: "DESCRIBES", RelatedSPDXElement: id,
+		})
+		for _, dep := range c.DependsOn {
+			out.Relationships = append(out.Relationships, spdxRelationship{
+				SPDXElementID: id, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: spdxID(dep),
+			})
+		}
+		for _, p := range c.Packages {
+			out.Packages = append(out.Packages, toSPDX(p))
+			out.Relationships = append(out.Relationships, spdxRelationship{
+				SPDXElementID: id, RelationshipType: "CONTAINS", RelatedSPDXElement: spdxID(p.Ref),
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func toSPDX(c *Component) spdxPackage {
+	pkg := spdxPackage{
+		Name:             c.Name,
+		SPDXID:           spdxID(c.Ref),
+		VersionInfo:      c.Version,
+		DownloadLocation: "NOASSERTION",
+		PrimaryPurpose:   strings.ToUpper(c.Type),
+	}
+
+	var extra []string
+	for i, d := range c.Downloads {
+		if i == 0 {
+			pkg.DownloadLocation = d.URL
+		} else {
+			extra = append(extra, d.URL)
+		}
+		if d.SHA256 != "" {
+			pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: "SHA256", ChecksumValue: d.SHA256})
+		}
+	}
+
+	var source []string
+	if c.RecipeSource != "" {
+		source = append(source, "recipe source: "+c.RecipeSource)
+	}
+	if c.RecipeHash != "" {
+		source = append(source, "recipe hash: "+c.RecipeHash)
+	}
+	if len(extra) > 0 {
+		source = append(source, "additional downloads: "+strings.Join(extra, ", "))
+	}
+	pkg.SourceInfo = strings.Join(source, "; ")
+
+	if c.PURL != "" {
+		pkg.ExternalRefs = []spdxExternal{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  c.PURL,
+		}}
+	}
+	return pkg
+}
+
+// spdxIDInvalid matches characters not allowed in SPDX identifiers
+var spdxIDInvalid = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// spdxID converts a component reference to an SPDX identifier
+func spdxID(ref string) string {
+	return fmt.Sprintf("SPDXRef-%s", strings.Trim(spdxIDInvalid.ReplaceAllString(ref, "-"), "-"))
+}
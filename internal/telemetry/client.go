@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/tsukumogami/tsuku/internal/config"
 	"github.com/tsukumogami/tsuku/internal/userconfig"
 )
 
@@ -24,14 +28,31 @@ const (
 
 	// DefaultTimeout is the HTTP request timeout.
 	DefaultTimeout = 2 * time.Second
+
+	// maxSpoolSize caps the events kept while offline; later events are dropped.
+	maxSpoolSize = 1 << 20
+
+	// staleClaimAge is how old a claimed spool must be before another process
+	// takes it over. Flushing is bounded by the client timeout, so a claim
+	// this old was left behind by a process that exited mid-flush.
+	staleClaimAge = time.Minute
 )
 
 // Client sends telemetry events. It is safe for concurrent use.
+//
+// In offline mode (--offline / TSUKU_OFFLINE) events are appended to a spool
+// file instead of being sent. The first event sent by an online client
+// flushes the spool before it is sent.
 type Client struct {
 	endpoint string
 	timeout  time.Duration
 	disabled bool
 	debug    bool
+
+	offline   bool
+	spoolPath string
+	spoolMu   sync.Mutex
+	flushOnce sync.Once
 }
 
 // NewClient creates a telemetry client.
@@ -51,12 +72,17 @@ func NewClient() *Client {
 		}
 	}
 
-	return &Client{
+	c := &Client{
 		endpoint: DefaultEndpoint,
 		timeout:  DefaultTimeout,
 		disabled: disabled,
 		debug:    os.Getenv(EnvDebug) != "",
+		offline:  config.IsOffline(),
+	}
+	if cfg, err := config.DefaultConfig(); err == nil {
+		c.spoolPath = cfg.TelemetrySpool
 	}
+	return c
 }
 
 // NewClientWithOptions creates a telemetry client with custom options.
@@ -75,7 +101,8 @@ func (c *Client) IsDisabled() bool {
 	return c.disabled
 }
 
-// Send sends an event asynchronously. It never blocks and never returns errors.
+// Send sends an event asynchronously. It never returns errors and only blocks
+// on the first event, to flush the events spooled while offline.
 // If telemetry is disabled, this is a no-op.
 // If debug mode is enabled, the event is printed to stderr instead of being sent.
 func (c *Client) Send(event Event) {
@@ -89,6 +116,13 @@ func (c *Client) Send(event Event) {
 		return
 	}
 
+	if c.offline {
+		c.spool(event)
+		return
+	}
+
+	c.flushOnce.Do(c.flushSpool)
+
 	// Fire-and-forget: spawn goroutine, no waiting
 	go c.send(event)
 }
@@ -98,7 +132,8 @@ func (c *Client) send(event Event) {
 	c.sendJSON(event)
 }
 
-// SendLLM sends an LLM event asynchronously. It never blocks and never returns errors.
+// SendLLM sends an LLM event asynchronously. It never returns errors and only blocks
+// on the first event, to flush the events spooled while offline.
 // If telemetry is disabled, this is a no-op.
 // If debug mode is enabled, the event is printed to stderr instead of being sent.
 func (c *Client) SendLLM(event LLMEvent) {
@@ -112,30 +147,140 @@ func (c *Client) SendLLM(event LLMEvent) {
 		return
 	}
 
+	if c.offline {
+		c.spool(event)
+		return
+	}
+
+	c.flushOnce.Do(c.flushSpool)
+
 	// Fire-and-forget: spawn goroutine, no waiting
 	go c.sendJSON(event)
 }
 
 // sendJSON performs the actual HTTP request for any event type.
 func (c *Client) sendJSON(event interface{}) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
-	defer cancel()
-
 	data, err := json.Marshal(event)
 	if err != nil {
 		return // Silent failure
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	_ = c.post(ctx, data)
+}
 
+// post sends an encoded event.
+func (c *Client) post(ctx context.Context, data []byte) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(data))
 	if err != nil {
-		return // Silent failure
+		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return // Silent failure (timeout, network error, etc.)
+		return err // Timeout, network error, etc.
 	}
 	resp.Body.Close()
 	// Ignore response status - we don't retry
+	return nil
+}
+
+// spool appends an event to the spool file. Like sending, it fails silently.
+func (c *Client) spool(event interface{}) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	c.appendSpool(append(data, '\n'))
+}
+
+// appendSpool appends encoded events to the spool file, unless that would
+// grow it past maxSpoolSize.
+func (c *Client) appendSpool(data []byte) {
+	if c.spoolPath == "" {
+		return
+	}
+
+	c.spoolMu.Lock()
+	defer c.spoolMu.Unlock()
+
+	if info, err := os.Stat(c.spoolPath); err == nil && info.Size()+int64(len(data)) >= maxSpoolSize {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.spoolPath), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(c.spoolPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.Write(data)
+}
+
+// flushSpool sends the events spooled while offline, within one client
+// timeout overall. The spool is claimed by renaming it first, so concurrent
+// tsuku processes don't send it twice; claims left behind by processes that
+// exited mid-flush are taken over once stale. Events that could not be sent
+// go back to the spool for the next flush.
+func (c *Client) flushSpool() {
+	if c.spoolPath == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	for _, claimed := range c.claimSpool() {
+		c.flushClaimed(ctx, claimed)
+	}
+}
+
+// claimSpool renames the spool and any stale claims to claims of this
+// process and returns them.
+func (c *Client) claimSpool() []string {
+	var claims []string
+	claim := func(path string) {
+		claimed := fmt.Sprintf("%s.%d.%d", c.spoolPath, os.Getpid(), len(claims))
+		if os.Rename(path, claimed) == nil {
+			claims = append(claims, claimed)
+		}
+	}
+
+	leftovers, _ := filepath.Glob(c.spoolPath + ".*")
+	for _, path := range leftovers {
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > staleClaimAge {
+			claim(path)
+		}
+	}
+
+	// Mark the spool as fresh so the claim isn't mistaken for a stale one
+	now := time.Now()
+	if os.Chtimes(c.spoolPath, now, now) == nil {
+		claim(c.spoolPath)
+	}
+	return claims
+}
+
+// flushClaimed sends the events of a claimed spool and removes it. Events
+// left unsent when a request fails or ctx expires are spooled again.
+func (c *Client) flushClaimed(ctx context.Context, claimed string) {
+	data, err := os.ReadFile(claimed)
+	if err != nil {
+		return
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	for i, line := range lines {
+		if ctx.Err() != nil || c.post(ctx, []byte(line)) != nil {
+			c.appendSpool([]byte(strings.Join(lines[i:], "\n") + "\n"))
+			break
+		}
+	}
+	os.Remove(claimed)
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -332,3 +333,162 @@ func TestSendLLM_Success(t *testing.T) {
 		t.Error("event not received within timeout")
 	}
 }
+
+func TestSend_OfflineSpools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("offline client must not send events")
+	}))
+	defer server.Close()
+
+	spool := filepath.Join(t.TempDir(), "cache", "telemetry.jsonl")
+	c := NewClientWithOptions(server.URL, time.Second, false, false)
+	c.offline = true
+	c.spoolPath = spool
+
+	c.Send(NewInstallEvent("nodejs", "@LTS", "22.0.0", false))
+	c.SendLLM(NewLLMGenerationCompletedEvent("gemini", "serve", true, 1500, 2))
+
+	data, err := os.ReadFile(spool)
+	if err != nil {
+		t.Fatalf("spool not written: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("spool has %d events, want 2", len(lines))
+	}
+	var event Event
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil || event.Recipe != "nodejs" {
+		t.Errorf("first spooled event = %q, want the install event", lines[0])
+	}
+}
+
+func TestSend_FlushesSpool(t *testing.T) {
+	received := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode event: %v", err)
+		}
+		received <- event.Recipe
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	spool := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	offline := NewClientWithOptions(server.URL, time.Second, false, false)
+	offline.offline = true
+	offline.spoolPath = spool
+	offline.Send(NewInstallEvent("spooled-a", "", "1.0.0", false))
+	offline.Send(NewInstallEvent("spooled-b", "", "1.0.0", false))
+
+	online := NewClientWithOptions(server.URL, time.Second, false, false)
+	online.spoolPath = spool
+	online.Send(NewInstallEvent("live", "", "1.0.0", false))
+
+	// The spool is flushed before Send returns
+	if _, err := os.Stat(spool); !os.IsNotExist(err) {
+		t.Error("spool should be removed once Send returns")
+	}
+	if leftovers, _ := filepath.Glob(spool + ".*"); len(leftovers) != 0 {
+		t.Errorf("claimed spools left behind: %v", leftovers)
+	}
+
+	got := map[string]bool{}
+	for range 3 {
+		select {
+		case recipe := <-received:
+			got[recipe] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("received %v, want spooled-a, spooled-b and live", got)
+		}
+	}
+	if !got["spooled-a"] || !got["spooled-b"] || !got["live"] {
+		t.Errorf("received %v, want spooled-a, spooled-b and live", got)
+	}
+}
+
+func TestSend_FlushesStaleClaims(t *testing.T) {
+	received := make(chan string, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode event: %v", err)
+		}
+		received <- event.Recipe
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	spool := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	c := NewClientWithOptions(server.URL, time.Second, false, false)
+	c.spoolPath = spool
+
+	// A claim left by a process that exited mid-flush, and one still in flight
+	writeEvent := func(path, recipe string, age time.Duration) {
+		t.Helper()
+		data, _ := json.Marshal(NewInstallEvent(recipe, "", "1.0.0", false))
+		if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeEvent(spool+".4242", "stale", time.Hour)
+	writeEvent(spool+".4343", "in-flight", 0)
+
+	c.flushSpool()
+
+	select {
+	case recipe := <-received:
+		if recipe != "stale" {
+			t.Errorf("flushed %q, want the stale claim", recipe)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stale claim was not flushed")
+	}
+	select {
+	case recipe := <-received:
+		t.Errorf("flushed %q, want only the stale claim", recipe)
+	default:
+	}
+	if _, err := os.Stat(spool + ".4242"); !os.IsNotExist(err) {
+		t.Error("stale claim should be removed after flushing")
+	}
+	if _, err := os.Stat(spool + ".4343"); err != nil {
+		t.Errorf("in-flight claim of another process should be left alone: %v", err)
+	}
+}
+
+func TestFlushSpool_RespoolsUnsentEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	endpoint := server.URL
+	server.Close() // Requests fail as if the network were down
+
+	spool := filepath.Join(t.TempDir(), "telemetry.jsonl")
+	offline := NewClientWithOptions(endpoint, time.Second, false, false)
+	offline.offline = true
+	offline.spoolPath = spool
+	offline.Send(NewInstallEvent("spooled-a", "", "1.0.0", false))
+	offline.Send(NewInstallEvent("spooled-b", "", "1.0.0", false))
+	before, err := os.ReadFile(spool)
+	if err != nil {
+		t.Fatalf("spool not written: %v", err)
+	}
+
+	online := NewClientWithOptions(endpoint, time.Second, false, false)
+	online.spoolPath = spool
+	online.flushSpool()
+
+	after, err := os.ReadFile(spool)
+	if err != nil {
+		t.Fatalf("unsent events were dropped: %v", err)
+	}
+	if string(after) != string(before) {
+		t.Errorf("spool after failed flush = %q, want %q", after, before)
+	}
+	if leftovers, _ := filepath.Glob(spool + ".*"); len(leftovers) != 0 {
+		t.Errorf("claimed spools left behind: %v", leftovers)
+	}
+}
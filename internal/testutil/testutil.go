@@ -34,7 +34,15 @@ func NewTestConfig(t *testing.T) (*config.Config, func()) {
 		CacheDir:         filepath.Join(tmpDir, "cache"),
 		VersionCacheDir:  filepath.Join(tmpDir, "cache", "versions"),
 		DownloadCacheDir: filepath.Join(tmpDir, "cache", "downloads"),
+		ArtifactCacheDir: filepath.Join(tmpDir, "cache", "artifacts"),
+		BuildCacheDir:    filepath.Join(tmpDir, "cache", "build"),
+		OSVCacheDir:      filepath.Join(tmpDir, "cache", "osv"),
+		GoModCacheDir:    filepath.Join(tmpDir, ".gomodcache"),
+		TelemetrySpool:   filepath.Join(tmpDir, "cache", "telemetry.jsonl"),
+		LocksDir:         filepath.Join(tmpDir, "locks"),
+		StoreDir:         filepath.Join(tmpDir, "store"),
 		ConfigFile:       filepath.Join(tmpDir, "config.toml"),
+		PolicyFile:       filepath.Join(tmpDir, "policy.toml"),
 	}
 
 	// Create directories
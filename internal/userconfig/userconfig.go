@@ -21,6 +21,60 @@ type Config struct {
 
 	// LLM contains LLM-related configuration.
 	LLM LLMConfig `toml:"llm"`
+
+	// Store contains content-addressed store configuration.
+	Store StoreConfig `toml:"store"`
+
+	// BinaryCache contains prebuilt artifact cache configuration.
+	BinaryCache BinaryCacheConfig `toml:"binary_cache"`
+
+	// BuildCache contains compiler cache configuration for source builds.
+	BuildCache BuildCacheConfig `toml:"build_cache"`
+
+	// Confinement contains sandboxing settings for action commands.
+	Confinement ConfinementConfig `toml:"confinement"`
+}
+
+// ConfinementConfig holds settings for confining the commands started by
+// actions (build scripts, ecosystem installers).
+type ConfinementConfig struct {
+	// Enabled applies Landlock rules on Linux so commands can only write to
+	// their work, install and cache directories. Default is false.
+	Enabled bool `toml:"enabled"`
+}
+
+// BuildCacheConfig holds settings for the ccache/sccache compiler caches
+// used by source builds.
+type BuildCacheConfig struct {
+	// Enabled runs C/C++ compilers through ccache and rustc through sccache
+	// when they are installed, keeping their caches in $TSUKU_HOME/cache/build.
+	// Default is false.
+	Enabled bool `toml:"enabled"`
+
+	// MaxSize limits the size of each compiler cache (e.g., "5G", "500M").
+	// Default is DefaultBuildCacheMaxSize.
+	MaxSize string `toml:"max_size,omitempty"`
+}
+
+// BinaryCacheConfig holds settings for the cache of prebuilt install trees
+// produced by source and ecosystem builds.
+type BinaryCacheConfig struct {
+	// Enabled stores build results in $TSUKU_HOME/cache/artifacts and reuses
+	// them instead of rebuilding. Default is false.
+	Enabled bool `toml:"enabled"`
+
+	// URL is an HTTPS cache consulted when the local cache misses, laid
+	// out like the local cache directory (<url>/<key>.tar.gz and its
+	// <key>.tar.gz.sha256 digest).
+	URL string `toml:"url,omitempty"`
+}
+
+// StoreConfig holds content-addressed store settings.
+type StoreConfig struct {
+	// Enabled installs tool files into $TSUKU_HOME/store and hardlinks them
+	// into tool directories, so identical files are kept once.
+	// Default is false (each version is a full copy).
+	Enabled bool `toml:"enabled"`
 }
 
 // LLMConfig holds LLM-specific settings.
@@ -41,6 +95,13 @@ type LLMConfig struct {
 	// HourlyRateLimit is the maximum LLM generations per hour.
 	// Default is 10. Set to 0 to disable the limit.
 	HourlyRateLimit *int `toml:"hourly_rate_limit,omitempty"`
+
+	// LocalBaseURL is the API root of an OpenAI-compatible server used by
+	// the "local" provider (e.g., "http://localhost:11434/v1" for Ollama).
+	LocalBaseURL string `toml:"local_base_url,omitempty"`
+
+	// LocalModel is the model name served at LocalBaseURL.
+	LocalModel string `toml:"local_model,omitempty"`
 }
 
 const (
@@ -49,6 +110,9 @@ const (
 
 	// DefaultHourlyRateLimit is the default maximum LLM generations per hour.
 	DefaultHourlyRateLimit = 10
+
+	// DefaultBuildCacheMaxSize is the default size limit of each compiler cache.
+	DefaultBuildCacheMaxSize = "5G"
 )
 
 // DefaultConfig returns a Config with default values.
@@ -154,6 +218,16 @@ func (c *Config) LLMHourlyRateLimit() int {
 	return *c.LLM.HourlyRateLimit
 }
 
+// LLMLocalBaseURL returns the base URL of the OpenAI-compatible local provider.
+func (c *Config) LLMLocalBaseURL() string {
+	return c.LLM.LocalBaseURL
+}
+
+// LLMLocalModel returns the model name for the local provider.
+func (c *Config) LLMLocalModel() string {
+	return c.LLM.LocalModel
+}
+
 // Get returns the value of a config key as a string.
 // Returns empty string and false if the key doesn't exist.
 func (c *Config) Get(key string) (string, bool) {
@@ -171,6 +245,22 @@ func (c *Config) Get(key string) (string, bool) {
 		return strconv.FormatFloat(c.LLMDailyBudget(), 'g', -1, 64), true
 	case "llm.hourly_rate_limit":
 		return strconv.Itoa(c.LLMHourlyRateLimit()), true
+	case "llm.local_base_url":
+		return c.LLM.LocalBaseURL, true
+	case "llm.local_model":
+		return c.LLM.LocalModel, true
+	case "store.enabled":
+		return strconv.FormatBool(c.Store.Enabled), true
+	case "binary_cache.enabled":
+		return strconv.FormatBool(c.BinaryCache.Enabled), true
+	case "binary_cache.url":
+		return c.BinaryCache.URL, true
+	case "build_cache.enabled":
+		return strconv.FormatBool(c.BuildCache.Enabled), true
+	case "build_cache.max_size":
+		return c.BuildCacheMaxSize(), true
+	case "confinement.enabled":
+		return strconv.FormatBool(c.Confinement.Enabled), true
 	default:
 		return "", false
 	}
@@ -225,18 +315,96 @@ func (c *Config) Set(key, value string) error {
 		}
 		c.LLM.HourlyRateLimit = &i
 		return nil
+	case "llm.local_base_url":
+		if value != "" && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			return fmt.Errorf("invalid value for llm.local_base_url: must start with http:// or https://")
+		}
+		c.LLM.LocalBaseURL = value
+		return nil
+	case "llm.local_model":
+		c.LLM.LocalModel = value
+		return nil
+	case "store.enabled":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for store.enabled: must be true or false")
+		}
+		c.Store.Enabled = b
+		return nil
+	case "binary_cache.enabled":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for binary_cache.enabled: must be true or false")
+		}
+		c.BinaryCache.Enabled = b
+		return nil
+	case "binary_cache.url":
+		if value != "" && !strings.HasPrefix(value, "https://") {
+			return fmt.Errorf("invalid value for binary_cache.url: must start with https://")
+		}
+		c.BinaryCache.URL = strings.TrimSuffix(value, "/")
+		return nil
+	case "build_cache.enabled":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for build_cache.enabled: must be true or false")
+		}
+		c.BuildCache.Enabled = b
+		return nil
+	case "build_cache.max_size":
+		size := strings.ToUpper(strings.TrimSpace(value))
+		if !isValidCacheSize(size) {
+			return fmt.Errorf("invalid value for build_cache.max_size: must be a size like 500M or 5G")
+		}
+		c.BuildCache.MaxSize = size
+		return nil
+	case "confinement.enabled":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for confinement.enabled: must be true or false")
+		}
+		c.Confinement.Enabled = b
+		return nil
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
 }
 
+// BuildCacheMaxSize returns the compiler cache size limit.
+// Returns DefaultBuildCacheMaxSize if not configured.
+func (c *Config) BuildCacheMaxSize() string {
+	if c.BuildCache.MaxSize == "" {
+		return DefaultBuildCacheMaxSize
+	}
+	return c.BuildCache.MaxSize
+}
+
+// isValidCacheSize reports whether size is a positive number with an optional
+// K, M, G or T suffix, the format understood by both ccache and sccache.
+func isValidCacheSize(size string) bool {
+	number := strings.TrimRight(size, "KMGT")
+	if len(size)-len(number) > 1 {
+		return false
+	}
+	n, err := strconv.ParseUint(number, 10, 64)
+	return err == nil && n > 0
+}
+
 // AvailableKeys returns a list of all configurable keys with descriptions.
 func AvailableKeys() map[string]string {
 	return map[string]string{
 		"telemetry":             "Enable anonymous usage statistics (true/false)",
 		"llm.enabled":           "Enable LLM features for recipe generation (true/false)",
-		"llm.providers":         "Preferred LLM provider order (comma-separated, e.g., claude,gemini)",
+		"llm.providers":         "Preferred LLM provider order (comma-separated, e.g., claude,gemini,local)",
 		"llm.daily_budget":      "Daily LLM cost limit in USD (default: 5.0, 0 to disable)",
 		"llm.hourly_rate_limit": "Max LLM generations per hour (default: 10, 0 to disable)",
+		"llm.local_base_url":    "OpenAI-compatible API URL for the local provider (e.g., http://localhost:11434/v1)",
+		"llm.local_model":       "Model name for the local provider (e.g., qwen2.5-coder:14b)",
+		"store.enabled":         "Deduplicate tool files through the content-addressed store (true/false)",
+		"binary_cache.enabled":  "Reuse cached build results instead of rebuilding from source (true/false)",
+		"binary_cache.url":      "HTTP(S) binary cache consulted when the local cache misses",
+		"build_cache.enabled":   "Cache compiler output of source builds with ccache/sccache (true/false)",
+		"build_cache.max_size":  "Size limit of each compiler cache (default: 5G)",
+		"confinement.enabled":   "Confine build and installer commands with Landlock on Linux (true/false)",
 	}
 }
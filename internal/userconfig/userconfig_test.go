@@ -703,3 +703,113 @@ func TestAvailableKeysIncludesBudgetSettings(t *testing.T) {
 		t.Error("expected llm.hourly_rate_limit in available keys")
 	}
 }
+
+func TestSetLLMLocalProvider(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if err := cfg.Set("llm.local_base_url", "http://localhost:11434/v1"); err != nil {
+		t.Fatalf("Set(llm.local_base_url) failed: %v", err)
+	}
+	if err := cfg.Set("llm.local_model", "qwen2.5-coder:14b"); err != nil {
+		t.Fatalf("Set(llm.local_model) failed: %v", err)
+	}
+
+	if got := cfg.LLMLocalBaseURL(); got != "http://localhost:11434/v1" {
+		t.Errorf("LLMLocalBaseURL() = %q, want %q", got, "http://localhost:11434/v1")
+	}
+	if got, ok := cfg.Get("llm.local_model"); !ok || got != "qwen2.5-coder:14b" {
+		t.Errorf("Get(llm.local_model) = %q, %v; want %q, true", got, ok, "qwen2.5-coder:14b")
+	}
+
+	if err := cfg.Set("llm.local_base_url", "localhost:11434"); err == nil {
+		t.Error("expected error for base URL without scheme")
+	}
+}
+
+func TestSetStoreEnabled(t *testing.T) {
+	cfg := DefaultConfig()
+	if got, _ := cfg.Get("store.enabled"); got != "false" {
+		t.Errorf("default store.enabled = %q, want false", got)
+	}
+
+	if err := cfg.Set("store.enabled", "true"); err != nil {
+		t.Fatalf("Set(store.enabled) failed: %v", err)
+	}
+	if !cfg.Store.Enabled {
+		t.Error("expected Store.Enabled after Set")
+	}
+	if err := cfg.Set("store.enabled", "maybe"); err == nil {
+		t.Error("expected error for invalid store.enabled value")
+	}
+	if _, ok := AvailableKeys()["store.enabled"]; !ok {
+		t.Error("expected store.enabled in available keys")
+	}
+}
+
+func TestSetBinaryCache(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Set("binary_cache.enabled", "true"); err != nil {
+		t.Fatalf("Set(binary_cache.enabled) failed: %v", err)
+	}
+	if !cfg.BinaryCache.Enabled {
+		t.Error("expected BinaryCache.Enabled after Set")
+	}
+
+	if err := cfg.Set("binary_cache.url", "https://cache.example.com/tsuku/"); err != nil {
+		t.Fatalf("Set(binary_cache.url) failed: %v", err)
+	}
+	if got, _ := cfg.Get("binary_cache.url"); got != "https://cache.example.com/tsuku" {
+		t.Errorf("binary_cache.url = %q, want trailing slash trimmed", got)
+	}
+	if err := cfg.Set("binary_cache.url", "ftp://cache.example.com"); err == nil {
+		t.Error("expected error for non-HTTP binary_cache.url")
+	}
+	if err := cfg.Set("binary_cache.url", "http://cache.example.com"); err == nil {
+		t.Error("expected error for plain HTTP binary_cache.url")
+	}
+}
+
+func TestSetBuildCache(t *testing.T) {
+	cfg := DefaultConfig()
+	if got, _ := cfg.Get("build_cache.max_size"); got != DefaultBuildCacheMaxSize {
+		t.Errorf("default build_cache.max_size = %q, want %q", got, DefaultBuildCacheMaxSize)
+	}
+
+	if err := cfg.Set("build_cache.enabled", "true"); err != nil {
+		t.Fatalf("Set(build_cache.enabled) failed: %v", err)
+	}
+	if !cfg.BuildCache.Enabled {
+		t.Error("expected BuildCache.Enabled after Set")
+	}
+
+	if err := cfg.Set("build_cache.max_size", "500m"); err != nil {
+		t.Fatalf("Set(build_cache.max_size) failed: %v", err)
+	}
+	if got := cfg.BuildCacheMaxSize(); got != "500M" {
+		t.Errorf("BuildCacheMaxSize() = %q, want 500M", got)
+	}
+
+	for _, invalid := range []string{"", "0", "5GB", "big", "-1G"} {
+		if err := cfg.Set("build_cache.max_size", invalid); err == nil {
+			t.Errorf("expected error for build_cache.max_size %q", invalid)
+		}
+	}
+}
+
+func TestSetConfinement(t *testing.T) {
+	cfg := DefaultConfig()
+	if got, _ := cfg.Get("confinement.enabled"); got != "false" {
+		t.Errorf("default confinement.enabled = %q, want false", got)
+	}
+
+	if err := cfg.Set("confinement.enabled", "true"); err != nil {
+		t.Fatalf("Set(confinement.enabled) failed: %v", err)
+	}
+	if !cfg.Confinement.Enabled {
+		t.Error("expected Confinement.Enabled after Set")
+	}
+
+	if err := cfg.Set("confinement.enabled", "maybe"); err == nil {
+		t.Error("expected error for invalid confinement.enabled")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	planexec "github.com/tsukumogami/tsuku/internal/executor"
 	"github.com/tsukumogami/tsuku/internal/log"
 	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/verify"
 )
 
 // DefaultValidationImage is the container image used for validation.
@@ -220,7 +221,7 @@ func (e *Executor) Validate(ctx context.Context, r *recipe.Recipe, assetURL stri
 	}
 
 	// Build the validation script that runs tsuku install --plan
-	script := e.buildPlanInstallScript(r)
+	script := e.buildPlanInstallScript(r, plan.Version)
 
 	// Create the install script in workspace
 	scriptPath := filepath.Join(workspaceDir, "validate.sh")
@@ -283,7 +284,7 @@ func (e *Executor) Validate(ctx context.Context, r *recipe.Recipe, assetURL stri
 	}
 
 	// Check if verification passed
-	passed := e.checkVerification(r, result)
+	passed := e.checkVerification(r, plan.Version, result)
 
 	return &ValidationResult{
 		Passed:   passed,
@@ -296,7 +297,7 @@ func (e *Executor) Validate(ctx context.Context, r *recipe.Recipe, assetURL stri
 // buildPlanInstallScript creates a shell script that runs tsuku install --plan.
 // This script is used for offline validation where the plan and cached downloads
 // are pre-generated on the host and mounted into the container.
-func (e *Executor) buildPlanInstallScript(r *recipe.Recipe) string {
+func (e *Executor) buildPlanInstallScript(r *recipe.Recipe, version string) string {
 	var sb strings.Builder
 
 	sb.WriteString("#!/bin/sh\n")
@@ -323,31 +324,51 @@ func (e *Executor) buildPlanInstallScript(r *recipe.Recipe) string {
 	if r.Verify.Command != "" {
 		sb.WriteString("# Run verify command to capture output for pattern matching\n")
 		sb.WriteString("export PATH=\"/workspace/tsuku/tools/current:$PATH\"\n")
-		sb.WriteString(fmt.Sprintf("%s\n", r.Verify.Command))
+		sb.WriteString(verify.ContainerScript(containerVerifyCommand(r, version)))
 	}
 
 	return sb.String()
 }
 
+// containerInstallDir returns the tool's installation directory inside the
+// validation container
+func containerInstallDir(r *recipe.Recipe, version string) string {
+	return fmt.Sprintf("/workspace/tsuku/tools/%s-%s", r.Metadata.Name, version)
+}
+
+// containerVerifyCommand returns the verify command with placeholders expanded
+// for the validation container
+func containerVerifyCommand(r *recipe.Recipe, version string) string {
+	command := strings.ReplaceAll(r.Verify.Command, "{version}", version)
+	return strings.ReplaceAll(command, "{install_dir}", containerInstallDir(r, version))
+}
+
 // checkVerification checks if the verification output matches expectations.
-func (e *Executor) checkVerification(r *recipe.Recipe, result *RunResult) bool {
-	// If exit code is non-zero, verification failed
+// The verify command's output and exit code are taken from the markers written by
+// verify.ContainerScript when present, so that install logs can't satisfy the
+// pattern; otherwise the whole container output and exit code are used.
+func (e *Executor) checkVerification(r *recipe.Recipe, version string, result *RunResult) bool {
 	expectedExitCode := 0
 	if r.Verify.ExitCode != nil {
 		expectedExitCode = *r.Verify.ExitCode
 	}
-	if result.ExitCode != expectedExitCode {
-		return false
-	}
 
-	// If no pattern specified, just check exit code
-	if r.Verify.Pattern == "" {
-		return true
+	output, exitCode, ok := verify.ParseContainerOutput(result.Stdout)
+	if ok {
+		// The script itself must still have succeeded
+		if result.ExitCode != 0 {
+			return false
+		}
+	} else {
+		output = result.Stdout + result.Stderr
+		exitCode = result.ExitCode
+	}
+	if exitCode != expectedExitCode {
+		return false
 	}
 
-	// Check if pattern appears in stdout or stderr
-	output := result.Stdout + result.Stderr
-	return strings.Contains(output, r.Verify.Pattern)
+	_, err := verify.MatchOutput(r.Verify, output, version, containerInstallDir(r, version))
+	return err == nil
 }
 
 // GetAssetChecksum returns the SHA256 checksum of a downloaded asset.
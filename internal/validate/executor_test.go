@@ -3,10 +3,13 @@ package validate
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/tsukumogami/tsuku/internal/log"
 	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/verify"
 )
 
 // mockRuntime is a mock Runtime for testing.
@@ -453,3 +456,49 @@ func TestExecutor_WithOptions(t *testing.T) {
 		t.Errorf("expected 200 pids, got %d", executor.limits.PidsMax)
 	}
 }
+
+func TestExecutor_CheckVerification(t *testing.T) {
+	marked := func(output string, exitCode int) string {
+		return fmt.Sprintf("Executing plan: mytool@1.2.3\n::tsuku-verify-output::\n%s\n::tsuku-verify-exit=%d::\n", output, exitCode)
+	}
+	exitCode2 := 2
+
+	tests := []struct {
+		name   string
+		verify recipe.VerifySection
+		result RunResult
+		want   bool
+	}{
+		{"regex version", recipe.VerifySection{PatternRegex: `mytool v(?P<version>\S+)`}, RunResult{Stdout: marked("mytool v1.2.3", 0)}, true},
+		{"regex version mismatch", recipe.VerifySection{PatternRegex: `mytool v(?P<version>\S+)`}, RunResult{Stdout: marked("mytool v1.2.4", 0)}, false},
+		{"pattern placeholder", recipe.VerifySection{Pattern: "mytool {version}"}, RunResult{Stdout: marked("mytool 1.2.3", 0)}, true},
+		{"install log does not match", recipe.VerifySection{Pattern: "1.2.3"}, RunResult{Stdout: marked("mytool dev", 0)}, false},
+		{"verify exit code", recipe.VerifySection{Pattern: "usage"}, RunResult{Stdout: marked("usage", 1)}, false},
+		{"expected verify exit code", recipe.VerifySection{Pattern: "usage", ExitCode: &exitCode2}, RunResult{Stdout: marked("usage", 2)}, true},
+		{"without markers", recipe.VerifySection{Pattern: "1.2.3"}, RunResult{Stdout: "mytool 1.2.3"}, true},
+	}
+
+	e := &Executor{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recipe.Recipe{Metadata: recipe.MetadataSection{Name: "mytool"}, Verify: tt.verify}
+			r.Verify.Command = "mytool --version"
+			if got := e.checkVerification(r, "1.2.3", &tt.result); got != tt.want {
+				t.Errorf("checkVerification() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_BuildPlanInstallScript_Verify(t *testing.T) {
+	e := &Executor{}
+	r := &recipe.Recipe{
+		Metadata: recipe.MetadataSection{Name: "mytool"},
+		Verify:   recipe.VerifySection{Command: "{install_dir}/bin/mytool --version"},
+	}
+
+	script := e.buildPlanInstallScript(r, "1.2.3")
+	if !strings.Contains(script, verify.ContainerScript("/workspace/tsuku/tools/mytool-1.2.3/bin/mytool --version")) {
+		t.Errorf("script should capture the expanded verify command:\n%s", script)
+	}
+}
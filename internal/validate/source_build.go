@@ -12,6 +12,7 @@ import (
 	"github.com/tsukumogami/tsuku/internal/actions"
 	planexec "github.com/tsukumogami/tsuku/internal/executor"
 	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/verify"
 )
 
 // SourceBuildValidationImage is the container image used for source build validation.
@@ -136,7 +137,7 @@ func (e *Executor) ValidateSourceBuild(ctx context.Context, r *recipe.Recipe) (*
 	}
 
 	// Build the validation script for source builds with plan support
-	script := e.buildSourceBuildPlanScript(r)
+	script := e.buildSourceBuildPlanScript(r, plan.Version)
 
 	// Create the install script in workspace
 	scriptPath := filepath.Join(workspaceDir, "validate.sh")
@@ -198,7 +199,7 @@ func (e *Executor) ValidateSourceBuild(ctx context.Context, r *recipe.Recipe) (*
 	}
 
 	// Check if verification passed
-	passed := e.checkVerification(r, result)
+	passed := e.checkVerification(r, plan.Version, result)
 
 	return &ValidationResult{
 		Passed:   passed,
@@ -210,7 +211,7 @@ func (e *Executor) ValidateSourceBuild(ctx context.Context, r *recipe.Recipe) (*
 
 // buildSourceBuildPlanScript creates a shell script for source build validation
 // using a pre-generated plan. It installs build tools and runs tsuku install --plan.
-func (e *Executor) buildSourceBuildPlanScript(r *recipe.Recipe) string {
+func (e *Executor) buildSourceBuildPlanScript(r *recipe.Recipe, version string) string {
 	var sb strings.Builder
 
 	sb.WriteString("#!/bin/bash\n")
@@ -242,6 +243,13 @@ func (e *Executor) buildSourceBuildPlanScript(r *recipe.Recipe) string {
 	sb.WriteString("# Run tsuku install with pre-generated plan\n")
 	sb.WriteString("tsuku install --plan /workspace/plan.json --force\n")
 
+	// Run the verify command to capture its output for pattern matching
+	if r.Verify.Command != "" {
+		sb.WriteString("\n# Run verify command to capture output for pattern matching\n")
+		sb.WriteString("export PATH=\"/workspace/tsuku/tools/current:$PATH\"\n")
+		sb.WriteString(verify.ContainerScript(containerVerifyCommand(r, version)))
+	}
+
 	return sb.String()
 }
 
@@ -0,0 +1,210 @@
+// Package verify checks installed tools against their recipe's verify section:
+// version output matching and functional tests.
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// DefaultTimeout bounds how long a single functional test may run
+const DefaultTimeout = 60 * time.Second
+
+// Options configures how functional tests are run
+type Options struct {
+	// Env is the environment for test commands. Defaults to the current environment.
+	Env []string
+
+	// Vars holds placeholder values (e.g., "version", "install_dir") expanded as
+	// {name} in commands, stdin, expected output and file assertions.
+	Vars map[string]string
+
+	// Timeout bounds each test. Defaults to DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Result is the outcome of a single functional test
+type Result struct {
+	Name     string
+	Command  string
+	ExitCode int
+	Stdout   string
+	Stderr   string
+	Failures []string // Failed expectations; empty when the test passed
+}
+
+// Passed reports whether all expectations of the test held
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// maxReportOutput bounds the command output included in a failure report
+const maxReportOutput = 2000
+
+// Report describes a failed test: the unmet expectations followed by the
+// command's output, truncated to keep repair prompts and terminals readable.
+func (r *Result) Report() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "functional test %q failed\n", r.Name)
+	fmt.Fprintf(&sb, "  command: %s\n", r.Command)
+	for _, f := range r.Failures {
+		fmt.Fprintf(&sb, "  - %s\n", f)
+	}
+	if out := strings.TrimSpace(r.Stdout); out != "" {
+		fmt.Fprintf(&sb, "  stdout:\n%s\n", truncate(out))
+	}
+	if out := strings.TrimSpace(r.Stderr); out != "" {
+		fmt.Fprintf(&sb, "  stderr:\n%s\n", truncate(out))
+	}
+	return sb.String()
+}
+
+func truncate(s string) string {
+	if len(s) > maxReportOutput {
+		return s[:maxReportOutput] + "\n...(truncated)"
+	}
+	return s
+}
+
+// RunFunctionalTests runs each test in order and returns one result per test
+func RunFunctionalTests(ctx context.Context, tests []recipe.FunctionalTest, opts Options) []Result {
+	results := make([]Result, 0, len(tests))
+	for _, test := range tests {
+		results = append(results, RunFunctionalTest(ctx, test, opts))
+	}
+	return results
+}
+
+// RunFunctionalTest runs a test command with a fresh temporary working directory
+// and checks its exit code, standard output and the files it left behind.
+func RunFunctionalTest(ctx context.Context, test recipe.FunctionalTest, opts Options) Result {
+	command := expand(test.Command, opts.Vars, false)
+	res := Result{Name: test.DisplayName(), Command: command}
+
+	dir, err := os.MkdirTemp("", "tsuku-verify-")
+	if err != nil {
+		res.Failures = append(res.Failures, fmt.Sprintf("failed to create test directory: %v", err))
+		return res
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = opts.Env
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Stdin = strings.NewReader(expand(test.Stdin, opts.Vars, false))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on background processes still holding the output pipes after a timeout
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Run()
+	res.Stdout = stdout.String()
+	res.Stderr = stderr.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case runCtx.Err() == context.DeadlineExceeded:
+		res.ExitCode = -1
+		res.Failures = append(res.Failures, fmt.Sprintf("timed out after %s", timeout))
+		return res
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		res.ExitCode = -1
+		res.Failures = append(res.Failures, fmt.Sprintf("failed to run command: %v", err))
+		return res
+	}
+
+	wantExit := 0
+	if test.ExitCode != nil {
+		wantExit = *test.ExitCode
+	}
+	if res.ExitCode != wantExit {
+		res.Failures = append(res.Failures, fmt.Sprintf("exit code %d, expected %d", res.ExitCode, wantExit))
+	}
+
+	if test.Stdout != "" {
+		pattern := expand(test.Stdout, opts.Vars, true)
+		if re, err := regexp.Compile(pattern); err != nil {
+			res.Failures = append(res.Failures, fmt.Sprintf("invalid stdout pattern %q: %v", pattern, err))
+		} else if !re.MatchString(strings.TrimRight(res.Stdout, "\r\n")) {
+			res.Failures = append(res.Failures, fmt.Sprintf("stdout does not match %q", pattern))
+		}
+	}
+
+	for _, file := range test.Files {
+		if failure := checkFile(dir, file, opts.Vars); failure != "" {
+			res.Failures = append(res.Failures, failure)
+		}
+	}
+
+	return res
+}
+
+// checkFile evaluates a file assertion, returning a failure message or ""
+func checkFile(dir string, file recipe.FileAssertion, vars map[string]string) string {
+	rel := expand(file.Path, vars, false)
+	if filepath.IsAbs(rel) || strings.HasPrefix(filepath.Clean(rel), "..") {
+		return fmt.Sprintf("file %s: path must be relative to the test directory", rel)
+	}
+	path := filepath.Join(dir, rel)
+
+	data, err := os.ReadFile(path)
+	if file.Absent {
+		if err == nil || !os.IsNotExist(err) {
+			return fmt.Sprintf("file %s: expected not to exist", rel)
+		}
+		return ""
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Sprintf("file %s: expected to exist", rel)
+		}
+		return fmt.Sprintf("file %s: %v", rel, err)
+	}
+
+	if file.Contains != "" {
+		pattern := expand(file.Contains, vars, true)
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Sprintf("file %s: invalid pattern %q: %v", rel, pattern, err)
+		}
+		if !re.Match(data) {
+			return fmt.Sprintf("file %s: content does not match %q", rel, pattern)
+		}
+	}
+	return ""
+}
+
+// expand replaces {name} placeholders with their values. Values substituted into
+// regular expressions are quoted so that e.g. the dots in a version match literally.
+func expand(s string, vars map[string]string, quote bool) string {
+	for name, value := range vars {
+		if quote {
+			value = regexp.QuoteMeta(value)
+		}
+		s = strings.ReplaceAll(s, "{"+name+"}", value)
+	}
+	return s
+}
@@ -0,0 +1,130 @@
+package verify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestRunFunctionalTest(t *testing.T) {
+	opts := Options{Vars: map[string]string{"version": "1.2.3"}}
+
+	tests := []struct {
+		name       string
+		test       recipe.FunctionalTest
+		wantPassed bool
+		wantFail   string
+	}{
+		{
+			name:       "stdin to stdout",
+			test:       recipe.FunctionalTest{Command: "tr a-z A-Z", Stdin: "hello\n", Stdout: "^HELLO$"},
+			wantPassed: true,
+		},
+		{
+			name:     "stdout mismatch",
+			test:     recipe.FunctionalTest{Command: "echo nope", Stdout: "^yes"},
+			wantFail: "stdout does not match",
+		},
+		{
+			name:       "version placeholder is quoted in patterns",
+			test:       recipe.FunctionalTest{Command: "echo tool {version}", Stdout: "tool {version}$"},
+			wantPassed: true,
+		},
+		{
+			name:     "version placeholder dots match literally",
+			test:     recipe.FunctionalTest{Command: "echo tool 1x2x3", Stdout: "{version}"},
+			wantFail: "stdout does not match",
+		},
+		{
+			name:       "expected exit code",
+			test:       recipe.FunctionalTest{Command: "exit 3", ExitCode: intPtr(3)},
+			wantPassed: true,
+		},
+		{
+			name:     "unexpected exit code",
+			test:     recipe.FunctionalTest{Command: "exit 1"},
+			wantFail: "exit code 1, expected 0",
+		},
+		{
+			name: "file assertions",
+			test: recipe.FunctionalTest{
+				Command: "printf 'result: 42' > out.txt",
+				Files: []recipe.FileAssertion{
+					{Path: "out.txt", Contains: `result: \d+`},
+					{Path: "missing.txt", Absent: true},
+				},
+			},
+			wantPassed: true,
+		},
+		{
+			name:     "missing file",
+			test:     recipe.FunctionalTest{Command: "true", Files: []recipe.FileAssertion{{Path: "out.txt"}}},
+			wantFail: "expected to exist",
+		},
+		{
+			name:     "file content mismatch",
+			test:     recipe.FunctionalTest{Command: "echo a > out.txt", Files: []recipe.FileAssertion{{Path: "out.txt", Contains: "b"}}},
+			wantFail: "content does not match",
+		},
+		{
+			name:     "unexpected file",
+			test:     recipe.FunctionalTest{Command: "touch tmp", Files: []recipe.FileAssertion{{Path: "tmp", Absent: true}}},
+			wantFail: "expected not to exist",
+		},
+		{
+			name:     "escaping path",
+			test:     recipe.FunctionalTest{Command: "true", Files: []recipe.FileAssertion{{Path: "../x"}}},
+			wantFail: "must be relative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := RunFunctionalTest(context.Background(), tt.test, opts)
+			if res.Passed() != tt.wantPassed {
+				t.Fatalf("Passed() = %v, failures: %v", res.Passed(), res.Failures)
+			}
+			if tt.wantFail != "" && !strings.Contains(strings.Join(res.Failures, "\n"), tt.wantFail) {
+				t.Errorf("failures %v do not mention %q", res.Failures, tt.wantFail)
+			}
+		})
+	}
+}
+
+func TestRunFunctionalTest_FreshDirectory(t *testing.T) {
+	test := recipe.FunctionalTest{
+		Command: "test ! -e marker && touch marker",
+	}
+	results := RunFunctionalTests(context.Background(), []recipe.FunctionalTest{test, test}, Options{})
+	for i, res := range results {
+		if !res.Passed() {
+			t.Errorf("run %d: each test should start in an empty directory: %v", i, res.Failures)
+		}
+	}
+}
+
+func TestRunFunctionalTest_Timeout(t *testing.T) {
+	res := RunFunctionalTest(context.Background(), recipe.FunctionalTest{Command: "sleep 5"}, Options{Timeout: 50 * time.Millisecond})
+	if res.Passed() || !strings.Contains(res.Failures[0], "timed out") {
+		t.Errorf("expected timeout failure, got %v", res.Failures)
+	}
+}
+
+func TestResult_Report(t *testing.T) {
+	res := RunFunctionalTest(context.Background(), recipe.FunctionalTest{
+		Name:    "greeting",
+		Command: "echo hi; echo oops >&2; exit 2",
+	}, Options{})
+
+	report := res.Report()
+	for _, want := range []string{`"greeting"`, "exit code 2, expected 0", "hi", "oops"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package verify
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/tsukumogami/tsuku/internal/recipe"
+	"github.com/tsukumogami/tsuku/internal/version"
+)
+
+// OutputMatch describes how a verification command's output satisfied the recipe
+type OutputMatch struct {
+	Pattern string // Pattern or pattern_regex after placeholder expansion ("" if none)
+	Version string // Version extracted by a pattern_regex "version" group ("" if none)
+}
+
+// ExpectedVersion returns the installed version normalized by the verify section's
+// version_format, which is what the tool is expected to report.
+func ExpectedVersion(section recipe.VerifySection, installed string) string {
+	if section.VersionFormat == "" {
+		return installed
+	}
+	transformed, err := version.TransformVersion(installed, section.VersionFormat)
+	if err != nil {
+		return installed
+	}
+	return transformed
+}
+
+// MatchOutput checks a verification command's output against the verify section.
+//
+// With pattern_regex, the expression must match the output. If it has a named
+// "version" group, the captured version is normalized with version_format and must
+// be semantically equal to the installed version (so "v1.2.3", "1.2.3+build" and
+// "1.2.3" all match an installed "1.2.3"). With pattern, the output must contain
+// the pattern literally. {version} and {install_dir} are expanded in both.
+func MatchOutput(section recipe.VerifySection, output, installedVersion, installDir string) (*OutputMatch, error) {
+	expected := ExpectedVersion(section, installedVersion)
+
+	if section.PatternRegex != "" {
+		expr := strings.ReplaceAll(section.PatternRegex, "{version}", regexp.QuoteMeta(expected))
+		expr = strings.ReplaceAll(expr, "{install_dir}", regexp.QuoteMeta(installDir))
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern_regex %q: %w", expr, err)
+		}
+
+		match := re.FindStringSubmatch(output)
+		if match == nil {
+			return nil, fmt.Errorf("output does not match pattern_regex %q", expr)
+		}
+
+		result := &OutputMatch{Pattern: expr}
+		if idx := re.SubexpIndex("version"); idx >= 0 && match[idx] != "" {
+			reported := match[idx]
+			if normalized, err := version.TransformVersion(reported, section.VersionFormat); err == nil {
+				reported = normalized
+			}
+			if !VersionsEqual(reported, expected) {
+				return nil, fmt.Errorf("output reports version %s, expected %s", reported, expected)
+			}
+			result.Version = reported
+		}
+		return result, nil
+	}
+
+	if section.Pattern != "" {
+		pattern := strings.ReplaceAll(section.Pattern, "{version}", expected)
+		pattern = strings.ReplaceAll(pattern, "{install_dir}", installDir)
+		if !strings.Contains(output, pattern) {
+			return nil, fmt.Errorf("output does not contain %q", pattern)
+		}
+		return &OutputMatch{Pattern: pattern}, nil
+	}
+
+	return &OutputMatch{}, nil
+}
+
+// VersionsEqual reports whether two versions are semantically equal. Versions that
+// parse as semver are compared ignoring a leading "v", missing minor/patch
+// components and build metadata; others are compared as strings.
+func VersionsEqual(a, b string) bool {
+	va, errA := semver.NewVersion(a)
+	vb, errB := semver.NewVersion(b)
+	if errA == nil && errB == nil {
+		return va.Equal(vb)
+	}
+	return strings.TrimPrefix(strings.TrimPrefix(a, "v"), "V") == strings.TrimPrefix(strings.TrimPrefix(b, "v"), "V")
+}
+
+// Markers delimiting the verification command's output in container logs, so
+// patterns are matched against the command's output rather than install logs
+const (
+	outputMarker     = "::tsuku-verify-output::"
+	exitMarkerPrefix = "::tsuku-verify-exit="
+	exitMarkerSuffix = "::"
+)
+
+// ContainerScript returns shell lines that run a verification command in a
+// container script, capturing its combined output and exit code between markers
+// for ParseContainerOutput. The lines leave errexit enabled afterwards.
+func ContainerScript(command string) string {
+	var sb strings.Builder
+	sb.WriteString("set +e\n")
+	sb.WriteString(fmt.Sprintf("echo '%s'\n", outputMarker))
+	sb.WriteString(fmt.Sprintf("(\n%s\n) 2>&1\n", command))
+	sb.WriteString(fmt.Sprintf("echo \"%s$?%s\"\n", exitMarkerPrefix, exitMarkerSuffix))
+	sb.WriteString("set -e\n")
+	return sb.String()
+}
+
+// ParseContainerOutput extracts the verification command's output and exit code
+// from the stdout of a script built with ContainerScript. ok is false if the
+// markers are missing (e.g., the script failed before verification ran).
+func ParseContainerOutput(stdout string) (output string, exitCode int, ok bool) {
+	start := strings.LastIndex(stdout, outputMarker+"\n")
+	if start < 0 {
+		return "", 0, false
+	}
+	rest := stdout[start+len(outputMarker)+1:]
+
+	end := strings.LastIndex(rest, exitMarkerPrefix)
+	if end < 0 {
+		return "", 0, false
+	}
+	codeStr := rest[end+len(exitMarkerPrefix):]
+	codeStr = codeStr[:strings.Index(codeStr+exitMarkerSuffix, exitMarkerSuffix)]
+	exitCode, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return "", 0, false
+	}
+	return rest[:end], exitCode, true
+}
@@ -0,0 +1,157 @@
+package verify
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+func TestMatchOutput(t *testing.T) {
+	tests := []struct {
+		name        string
+		section     recipe.VerifySection
+		output      string
+		installed   string
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name:      "literal pattern",
+			section:   recipe.VerifySection{Pattern: "tool {version}"},
+			output:    "tool 1.2.3 (linux)",
+			installed: "1.2.3",
+		},
+		{
+			name:      "literal pattern mismatch",
+			section:   recipe.VerifySection{Pattern: "tool {version}"},
+			output:    "tool 1.2.4",
+			installed: "1.2.3",
+			wantErr:   `does not contain "tool 1.2.3"`,
+		},
+		{
+			name:      "literal pattern uses version_format",
+			section:   recipe.VerifySection{Pattern: "tool {version}", VersionFormat: "strip_v"},
+			output:    "tool 1.2.3",
+			installed: "v1.2.3",
+		},
+		{
+			name:        "regex version group",
+			section:     recipe.VerifySection{PatternRegex: `tool v?(?P<version>\S+)`},
+			output:      "tool v1.2.3\n",
+			installed:   "1.2.3",
+			wantVersion: "1.2.3",
+		},
+		{
+			name:        "regex version compared semantically",
+			section:     recipe.VerifySection{PatternRegex: `version (?P<version>\S+)`},
+			output:      "version 1.2.3+build.7",
+			installed:   "v1.2.3",
+			wantVersion: "1.2.3+build.7",
+		},
+		{
+			name:        "regex version normalized with version_format",
+			section:     recipe.VerifySection{PatternRegex: `^(?P<version>\S+)`, VersionFormat: "semver"},
+			output:      "go1.21.0 linux/amd64",
+			installed:   "1.21.0",
+			wantVersion: "1.21.0",
+		},
+		{
+			name:      "regex version mismatch",
+			section:   recipe.VerifySection{PatternRegex: `tool (?P<version>\S+)`},
+			output:    "tool 1.2.4",
+			installed: "1.2.3",
+			wantErr:   "reports version 1.2.4, expected 1.2.3",
+		},
+		{
+			name:      "regex no match",
+			section:   recipe.VerifySection{PatternRegex: `^other`},
+			output:    "tool 1.2.3",
+			installed: "1.2.3",
+			wantErr:   "does not match pattern_regex",
+		},
+		{
+			name:      "regex version placeholder is quoted",
+			section:   recipe.VerifySection{PatternRegex: `tool {version}$`},
+			output:    "tool 1x2x3",
+			installed: "1.2.3",
+			wantErr:   "does not match pattern_regex",
+		},
+		{
+			name:      "no pattern",
+			section:   recipe.VerifySection{Command: "tool --version"},
+			output:    "anything",
+			installed: "1.2.3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := MatchOutput(tt.section, tt.output, tt.installed, "/tools/tool-1.2.3")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MatchOutput() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MatchOutput() error = %v", err)
+			}
+			if match.Version != tt.wantVersion {
+				t.Errorf("Version = %q, want %q", match.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestVersionsEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.2.3", "1.2.3", true},
+		{"v1.2.3", "1.2.3", true},
+		{"1.2", "1.2.0", true},
+		{"1.2.3+build", "1.2.3", true},
+		{"1.2.3-rc.1", "1.2.3", false},
+		{"1.2.4", "1.2.3", false},
+		{"2024.01.15", "2024.01.15", true},
+		{"r42", "r42", true},
+		{"r42", "r43", false},
+	}
+	for _, tt := range tests {
+		if got := VersionsEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("VersionsEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestContainerScript_RoundTrip(t *testing.T) {
+	script := "echo 'Executing plan: tool@1.2.3'\nset -e\n" +
+		ContainerScript("echo tool 9.9.9; echo warning >&2; exit 3")
+	out, err := exec.Command("sh", "-c", script).Output()
+	if err != nil {
+		t.Fatalf("script failed: %v", err)
+	}
+
+	output, exitCode, ok := ParseContainerOutput(string(out))
+	if !ok {
+		t.Fatalf("markers not found in %q", out)
+	}
+	if exitCode != 3 {
+		t.Errorf("exitCode = %d, want 3", exitCode)
+	}
+	if strings.Contains(output, "Executing plan") {
+		t.Errorf("output includes text before the verify command: %q", output)
+	}
+	if !strings.Contains(output, "tool 9.9.9") || !strings.Contains(output, "warning") {
+		t.Errorf("output = %q, want command stdout and stderr", output)
+	}
+}
+
+func TestParseContainerOutput_Missing(t *testing.T) {
+	if _, _, ok := ParseContainerOutput("install failed\n"); ok {
+		t.Error("expected ok = false without markers")
+	}
+}
@@ -100,15 +100,34 @@ func (c *CachedVersionLister) SourceDescription() string {
 
 // cacheFilePath returns the path to the cache file for this provider
 func (c *CachedVersionLister) cacheFilePath() string {
+	return versionCacheFile(c.cacheDir, c.underlying.SourceDescription())
+}
+
+// readCache reads and parses a cache file
+func (c *CachedVersionLister) readCache(path string) (*cacheEntry, error) {
+	return readCacheEntry(path)
+}
+
+// writeCache atomically writes a cache entry to disk
+func (c *CachedVersionLister) writeCache(path string, versions []string) error {
+	return writeCacheEntry(c.cacheDir, path, cacheEntry{
+		Versions:  versions,
+		CachedAt:  time.Now(),
+		Source:    c.underlying.SourceDescription(),
+		ExpiresAt: time.Now().Add(c.ttl),
+	})
+}
+
+// versionCacheFile returns the path to the cache file for a version source
+func versionCacheFile(cacheDir, source string) string {
 	// Use SHA256 of source description for unique, filesystem-safe filename
-	source := c.underlying.SourceDescription()
 	hash := sha256.Sum256([]byte(source))
 	filename := hex.EncodeToString(hash[:8]) + ".json" // Use first 8 bytes (16 hex chars)
-	return filepath.Join(c.cacheDir, filename)
+	return filepath.Join(cacheDir, filename)
 }
 
-// readCache reads and parses a cache file
-func (c *CachedVersionLister) readCache(path string) (*cacheEntry, error) {
+// readCacheEntry reads and parses a cache file
+func readCacheEntry(path string) (*cacheEntry, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -122,28 +141,33 @@ func (c *CachedVersionLister) readCache(path string) (*cacheEntry, error) {
 	return &entry, nil
 }
 
-// writeCache atomically writes a cache entry to disk
-func (c *CachedVersionLister) writeCache(path string, versions []string) error {
+// writeCacheEntry atomically writes a cache entry to path inside cacheDir
+func writeCacheEntry(cacheDir, path string, entry cacheEntry) error {
 	// Ensure cache directory exists
-	if err := os.MkdirAll(c.cacheDir, 0755); err != nil {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	entry := cacheEntry{
-		Versions:  versions,
-		CachedAt:  time.Now(),
-		Source:    c.underlying.SourceDescription(),
-		ExpiresAt: time.Now().Add(c.ttl),
-	}
-
 	data, err := json.MarshalIndent(entry, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal cache entry: %w", err)
 	}
 
-	// Atomic write: write to temp file, then rename
-	tempFile := path + ".tmp"
-	if err := os.WriteFile(tempFile, data, 0644); err != nil {
+	// Atomic write: write to a unique temp file, then rename
+	tmp, err := os.CreateTemp(cacheDir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp cache file: %w", err)
+	}
+	tempFile := tmp.Name()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile, 0644)
+	}
+	if err != nil {
+		os.Remove(tempFile)
 		return fmt.Errorf("failed to write temp cache file: %w", err)
 	}
 
@@ -155,6 +179,28 @@ func (c *CachedVersionLister) writeCache(path string, versions []string) error {
 	return nil
 }
 
+// recordVersion adds a version resolved online to the cached version list of
+// source, so offline mode can resolve it later. An existing entry keeps its
+// expiry; a new entry starts out expired so that listing versions online
+// still fetches the full list.
+func recordVersion(cacheDir, source, tag string) error {
+	path := versionCacheFile(cacheDir, source)
+	entry, err := readCacheEntry(path)
+	if err != nil {
+		entry = &cacheEntry{CachedAt: time.Now(), Source: source}
+	}
+
+	normalized := normalizeVersion(tag)
+	for _, v := range entry.Versions {
+		if v == tag || normalizeVersion(v) == normalized {
+			return nil
+		}
+	}
+	entry.Versions = append(entry.Versions, tag)
+
+	return writeCacheEntry(cacheDir, path, *entry)
+}
+
 // CacheInfo returns information about the cache entry for this provider
 type CacheInfo struct {
 	Exists    bool
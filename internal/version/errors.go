@@ -37,6 +37,8 @@ const (
 	ErrTypeConnection
 	// ErrTypeTLS indicates TLS/SSL certificate errors
 	ErrTypeTLS
+	// ErrTypeOffline indicates the answer is not cached and offline mode forbids fetching it
+	ErrTypeOffline
 )
 
 // ResolverError provides structured error information for version resolution failures
@@ -74,6 +76,8 @@ func (e *ResolverError) Suggestion() string {
 		return "The service may be down or blocked. Check if you can access it in a browser"
 	case ErrTypeTLS:
 		return "There may be a certificate issue. Check your system time is correct"
+	case ErrTypeOffline:
+		return "Install the tool or run 'tsuku versions <tool>' while online to cache its versions, or drop --offline"
 	case ErrTypeNotFound:
 		return "Verify the tool/package name is correct"
 	case ErrTypeNetwork:
@@ -88,6 +88,7 @@ func TestErrorType_Constants(t *testing.T) {
 		ErrTypeDNS,
 		ErrTypeConnection,
 		ErrTypeTLS,
+		ErrTypeOffline,
 	}
 
 	seen := make(map[ErrorType]bool)
@@ -131,6 +132,11 @@ func TestResolverError_Suggestion(t *testing.T) {
 			errorType:  ErrTypeTLS,
 			wantSubstr: "certificate issue",
 		},
+		{
+			name:       "offline has suggestion",
+			errorType:  ErrTypeOffline,
+			wantSubstr: "tsuku versions",
+		},
 		{
 			name:       "not found has suggestion",
 			errorType:  ErrTypeNotFound,
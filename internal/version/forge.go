@@ -0,0 +1,342 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ForgeGitLab identifies GitLab (gitlab.com or self-hosted) release APIs
+	ForgeGitLab = "gitlab"
+	// ForgeGitea identifies Gitea and Forgejo release APIs (Forgejo is API-compatible)
+	ForgeGitea = "gitea"
+
+	// DefaultGitLabURL is used when a GitLab recipe does not specify base_url
+	DefaultGitLabURL = "https://gitlab.com"
+
+	// maxForgeResponseSize limits response body to prevent memory exhaustion (10MB)
+	maxForgeResponseSize = 10 * 1024 * 1024
+
+	// Release list page sizes (the maximum each API allows by default) and
+	// the number of pages followed, so older pinned versions are found
+	gitlabPageSize = 100
+	giteaPageSize  = 50
+	maxForgePages  = 10
+)
+
+// ForgeRelease is a release published on a GitLab or Gitea/Forgejo instance,
+// reduced to the fields tsuku needs for version and asset resolution.
+type ForgeRelease struct {
+	Tag        string
+	Prerelease bool
+	Assets     []ForgeAsset
+}
+
+// ForgeAsset is a downloadable file attached to a forge release.
+type ForgeAsset struct {
+	Name string
+	URL  string
+}
+
+// AssetNames returns the names of all assets attached to the release.
+func (r *ForgeRelease) AssetNames() []string {
+	names := make([]string, 0, len(r.Assets))
+	for _, a := range r.Assets {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// GitLab API response structures
+type gitlabRelease struct {
+	TagName         string `json:"tag_name"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []struct {
+			Name           string `json:"name"`
+			URL            string `json:"url"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+// Gitea/Forgejo API response structures
+type giteaRelease struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Pre-compile regex for forge path segments (group, subgroup, project, owner, repo)
+var forgePathSegmentRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// isValidForgeProject validates a forge project path.
+// GitLab allows nested groups ("group/subgroup/project"); Gitea requires exactly "owner/repo".
+func isValidForgeProject(forge, project string) bool {
+	if project == "" || len(project) > 255 {
+		return false
+	}
+	parts := strings.Split(project, "/")
+	if len(parts) < 2 || (forge == ForgeGitea && len(parts) != 2) {
+		return false
+	}
+	for _, p := range parts {
+		if p == "." || p == ".." || !forgePathSegmentRegex.MatchString(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseForgeBaseURL validates a forge base URL and returns it without a trailing slash
+func parseForgeBaseURL(source, baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return "", &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  source,
+			Message: fmt.Sprintf("invalid base URL: %s", baseURL),
+		}
+	}
+	return strings.TrimSuffix(baseURL, "/"), nil
+}
+
+// ListForgeReleases fetches releases from a GitLab or Gitea/Forgejo instance (newest first).
+// Draft releases are never returned. Pages are followed until a short page,
+// up to maxForgePages.
+//
+// GitLab API: {base}/api/v4/projects/{url-encoded project}/releases
+// Gitea API:  {base}/api/v1/repos/{owner}/{repo}/releases
+func (r *Resolver) ListForgeReleases(ctx context.Context, forge, baseURL, project string) ([]ForgeRelease, error) {
+	var result []ForgeRelease
+	for page := 1; page <= maxForgePages; page++ {
+		switch forge {
+		case ForgeGitLab:
+			var releases []gitlabRelease
+			endpoint := fmt.Sprintf("releases?per_page=%d&page=%d", gitlabPageSize, page)
+			if err := r.fetchForgeJSON(ctx, forge, baseURL, project, endpoint, &releases); err != nil {
+				return nil, err
+			}
+			for i := range releases {
+				result = append(result, releases[i].toForgeRelease())
+			}
+			if len(releases) < gitlabPageSize {
+				return result, nil
+			}
+		case ForgeGitea:
+			var releases []giteaRelease
+			endpoint := fmt.Sprintf("releases?limit=%d&page=%d", giteaPageSize, page)
+			if err := r.fetchForgeJSON(ctx, forge, baseURL, project, endpoint, &releases); err != nil {
+				return nil, err
+			}
+			for i := range releases {
+				if releases[i].Draft {
+					continue
+				}
+				result = append(result, releases[i].toForgeRelease())
+			}
+			if len(releases) < giteaPageSize {
+				return result, nil
+			}
+		default:
+			return nil, &ResolverError{
+				Type:    ErrTypeUnknownSource,
+				Source:  forge,
+				Message: fmt.Sprintf("unsupported forge: %s", forge),
+			}
+		}
+	}
+	return result, nil
+}
+
+// GetForgeRelease fetches a single release by tag from a GitLab or Gitea/Forgejo instance.
+func (r *Resolver) GetForgeRelease(ctx context.Context, forge, baseURL, project, tag string) (*ForgeRelease, error) {
+	if tag == "" {
+		return nil, &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  forge,
+			Message: "release tag must not be empty",
+		}
+	}
+
+	switch forge {
+	case ForgeGitLab:
+		var release gitlabRelease
+		if err := r.fetchForgeJSON(ctx, forge, baseURL, project, "releases/"+url.PathEscape(tag), &release); err != nil {
+			return nil, err
+		}
+		result := release.toForgeRelease()
+		return &result, nil
+	case ForgeGitea:
+		var release giteaRelease
+		if err := r.fetchForgeJSON(ctx, forge, baseURL, project, "releases/tags/"+url.PathEscape(tag), &release); err != nil {
+			return nil, err
+		}
+		result := release.toForgeRelease()
+		return &result, nil
+	default:
+		return nil, &ResolverError{
+			Type:    ErrTypeUnknownSource,
+			Source:  forge,
+			Message: fmt.Sprintf("unsupported forge: %s", forge),
+		}
+	}
+}
+
+// fetchForgeJSON performs a GET against a forge project API endpoint and decodes the JSON body.
+func (r *Resolver) fetchForgeJSON(ctx context.Context, forge, baseURL, project, endpoint string, v any) error {
+	if !isValidForgeProject(forge, project) {
+		return &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  forge,
+			Message: fmt.Sprintf("invalid project path: %s", project),
+		}
+	}
+
+	if baseURL == "" && forge == ForgeGitLab {
+		baseURL = r.gitlabURL
+		if baseURL == "" {
+			baseURL = DefaultGitLabURL
+		}
+	}
+	base, err := parseForgeBaseURL(forge, baseURL)
+	if err != nil {
+		return err
+	}
+
+	var apiURL string
+	if forge == ForgeGitLab {
+		// GitLab addresses projects by URL-encoded full path (group%2Fproject)
+		apiURL = fmt.Sprintf("%s/api/v4/projects/%s/%s", base, url.PathEscape(project), endpoint)
+	} else {
+		apiURL = fmt.Sprintf("%s/api/v1/repos/%s/%s", base, project, endpoint)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  forge,
+			Message: "failed to create request",
+			Err:     err,
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return WrapNetworkError(err, forge, "failed to fetch releases")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  forge,
+			Message: fmt.Sprintf("project or release not found: %s (%s)", project, endpoint),
+		}
+	}
+
+	if resp.StatusCode == 429 {
+		return &ResolverError{
+			Type:    ErrTypeRateLimit,
+			Source:  forge,
+			Message: fmt.Sprintf("%s rate limit exceeded", base),
+		}
+	}
+
+	if resp.StatusCode != 200 {
+		return &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  forge,
+			Message: fmt.Sprintf("%s returned status %d", base, resp.StatusCode),
+		}
+	}
+
+	// SECURITY: Validate Content-Type to prevent MIME confusion
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, "application/json") {
+		return &ResolverError{
+			Type:    ErrTypeParsing,
+			Source:  forge,
+			Message: fmt.Sprintf("unexpected content-type: %s (expected application/json)", contentType),
+		}
+	}
+
+	// Limit response size to prevent memory exhaustion
+	limitedReader := io.LimitReader(resp.Body, maxForgeResponseSize)
+	if err := json.NewDecoder(limitedReader).Decode(v); err != nil {
+		return &ResolverError{
+			Type:    ErrTypeParsing,
+			Source:  forge,
+			Message: "failed to parse release response",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+func (g *gitlabRelease) toForgeRelease() ForgeRelease {
+	release := ForgeRelease{Tag: g.TagName, Prerelease: g.UpcomingRelease}
+	for _, link := range g.Assets.Links {
+		// direct_asset_url is the stable permalink; url may point at a package registry page
+		assetURL := link.DirectAssetURL
+		if assetURL == "" {
+			assetURL = link.URL
+		}
+		release.addAsset(link.Name, assetURL)
+	}
+	return release
+}
+
+func (g *giteaRelease) toForgeRelease() ForgeRelease {
+	release := ForgeRelease{Tag: g.TagName, Prerelease: g.Prerelease}
+	for _, a := range g.Assets {
+		release.addAsset(a.Name, a.BrowserDownloadURL)
+	}
+	return release
+}
+
+// addAsset appends an asset, reducing its name to a plain file name.
+// Asset names come from the remote API and are used as download
+// destinations, so names that don't denote a file are dropped.
+func (r *ForgeRelease) addAsset(name, assetURL string) {
+	name = filepath.Base(filepath.FromSlash(strings.ReplaceAll(name, "\\", "/")))
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return
+	}
+	r.Assets = append(r.Assets, ForgeAsset{Name: name, URL: assetURL})
+}
+
+// MatchForgeAsset selects the release asset matching pattern.
+// Patterns containing glob wildcards are matched with MatchAssetPattern;
+// otherwise the asset name must match exactly.
+func MatchForgeAsset(release *ForgeRelease, pattern string) (*ForgeAsset, error) {
+	name := pattern
+	if ContainsWildcards(pattern) {
+		matched, err := MatchAssetPattern(pattern, release.AssetNames())
+		if err != nil {
+			return nil, err
+		}
+		name = matched
+	}
+
+	for i := range release.Assets {
+		if release.Assets[i].Name == name {
+			return &release.Assets[i], nil
+		}
+	}
+	return nil, formatNoMatchError(name, release.AssetNames())
+}
@@ -0,0 +1,366 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+const gitlabReleasesJSON = `[
+	{"tag_name": "v2.0.0-rc1", "upcoming_release": false, "assets": {"links": []}},
+	{"tag_name": "v1.4.2", "upcoming_release": false, "assets": {"links": [
+		{"name": "tool-linux-amd64.tar.gz", "url": "https://gitlab.example.com/-/package_files/1", "direct_asset_url": "https://gitlab.example.com/group/tool/-/releases/v1.4.2/downloads/tool-linux-amd64.tar.gz"},
+		{"name": "tool-darwin-arm64.tar.gz", "url": "https://gitlab.example.com/-/package_files/2"}
+	]}},
+	{"tag_name": "v1.3.0", "upcoming_release": false, "assets": {"links": []}}
+]`
+
+const giteaReleasesJSON = `[
+	{"tag_name": "v0.9.0", "draft": true, "prerelease": false, "assets": []},
+	{"tag_name": "v0.8.0", "draft": false, "prerelease": true, "assets": []},
+	{"tag_name": "v0.7.1", "draft": false, "prerelease": false, "assets": [
+		{"name": "tool_0.7.1_linux_amd64.tar.gz", "browser_download_url": "https://codeberg.org/owner/tool/releases/download/v0.7.1/tool_0.7.1_linux_amd64.tar.gz"}
+	]}
+]`
+
+func newForgeServer(t *testing.T, wantPath, body string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != wantPath {
+			t.Errorf("request path = %q, want %q", r.URL.EscapedPath(), wantPath)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestIsValidForgeProject(t *testing.T) {
+	tests := []struct {
+		forge   string
+		project string
+		want    bool
+	}{
+		{ForgeGitLab, "group/project", true},
+		{ForgeGitLab, "group/subgroup/project", true},
+		{ForgeGitLab, "project", false},
+		{ForgeGitLab, "group/../project", false},
+		{ForgeGitLab, "group/pro ject", false},
+		{ForgeGitea, "owner/repo", true},
+		{ForgeGitea, "owner/sub/repo", false},
+		{ForgeGitea, "", false},
+	}
+
+	for _, tt := range tests {
+		if got := isValidForgeProject(tt.forge, tt.project); got != tt.want {
+			t.Errorf("isValidForgeProject(%q, %q) = %v, want %v", tt.forge, tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestListForgeReleases_GitLab(t *testing.T) {
+	server := newForgeServer(t, "/api/v4/projects/group%2Ftool/releases", gitlabReleasesJSON)
+	defer server.Close()
+
+	resolver := New(WithGitLabURL(server.URL))
+	releases, err := resolver.ListForgeReleases(context.Background(), ForgeGitLab, "", "group/tool")
+	if err != nil {
+		t.Fatalf("ListForgeReleases failed: %v", err)
+	}
+
+	if len(releases) != 3 {
+		t.Fatalf("got %d releases, want 3", len(releases))
+	}
+	assets := releases[1].Assets
+	if len(assets) != 2 {
+		t.Fatalf("got %d assets, want 2", len(assets))
+	}
+	// direct_asset_url is preferred over url
+	if assets[0].URL != "https://gitlab.example.com/group/tool/-/releases/v1.4.2/downloads/tool-linux-amd64.tar.gz" {
+		t.Errorf("asset URL = %q, want direct_asset_url", assets[0].URL)
+	}
+	if assets[1].URL != "https://gitlab.example.com/-/package_files/2" {
+		t.Errorf("asset URL = %q, want url fallback", assets[1].URL)
+	}
+}
+
+func TestListForgeReleases_GiteaSkipsDrafts(t *testing.T) {
+	server := newForgeServer(t, "/api/v1/repos/owner/tool/releases", giteaReleasesJSON)
+	defer server.Close()
+
+	resolver := New()
+	releases, err := resolver.ListForgeReleases(context.Background(), ForgeGitea, server.URL+"/", "owner/tool")
+	if err != nil {
+		t.Fatalf("ListForgeReleases failed: %v", err)
+	}
+
+	if len(releases) != 2 {
+		t.Fatalf("got %d releases, want 2 (draft excluded)", len(releases))
+	}
+	if releases[0].Tag != "v0.8.0" || !releases[0].Prerelease {
+		t.Errorf("unexpected first release: %+v", releases[0])
+	}
+}
+
+func TestListForgeReleases_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	resolver := New()
+	_, err := resolver.ListForgeReleases(context.Background(), ForgeGitea, server.URL, "owner/missing")
+	resolverErr, ok := err.(*ResolverError)
+	if !ok {
+		t.Fatalf("expected *ResolverError, got %T: %v", err, err)
+	}
+	if resolverErr.Type != ErrTypeNotFound {
+		t.Errorf("error type = %v, want ErrTypeNotFound", resolverErr.Type)
+	}
+}
+
+func TestListForgeReleases_GiteaPaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var releases []string
+		switch r.URL.Query().Get("page") {
+		case "1":
+			for i := 0; i < giteaPageSize; i++ {
+				releases = append(releases, fmt.Sprintf(`{"tag_name": "v1.%d.0", "assets": []}`, giteaPageSize-i))
+			}
+		case "2":
+			releases = append(releases, `{"tag_name": "v0.1.0", "assets": []}`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[" + strings.Join(releases, ",") + "]"))
+	}))
+	defer server.Close()
+
+	resolver := New()
+	releases, err := resolver.ListForgeReleases(context.Background(), ForgeGitea, server.URL, "owner/tool")
+	if err != nil {
+		t.Fatalf("ListForgeReleases failed: %v", err)
+	}
+	if len(releases) != giteaPageSize+1 {
+		t.Fatalf("got %d releases, want %d", len(releases), giteaPageSize+1)
+	}
+	if releases[len(releases)-1].Tag != "v0.1.0" {
+		t.Errorf("last release = %q, want v0.1.0 from the second page", releases[len(releases)-1].Tag)
+	}
+}
+
+func TestListForgeReleases_SanitizesAssetNames(t *testing.T) {
+	server := newForgeServer(t, "/api/v1/repos/owner/tool/releases", `[
+		{"tag_name": "v1.0.0", "assets": [
+			{"name": "../../../.bashrc", "browser_download_url": "https://example.com/a"},
+			{"name": "..\\..\\evil.exe", "browser_download_url": "https://example.com/b"},
+			{"name": "..", "browser_download_url": "https://example.com/c"},
+			{"name": "", "browser_download_url": "https://example.com/d"},
+			{"name": "tool.tar.gz", "browser_download_url": "https://example.com/e"}
+		]}
+	]`)
+	defer server.Close()
+
+	resolver := New()
+	releases, err := resolver.ListForgeReleases(context.Background(), ForgeGitea, server.URL, "owner/tool")
+	if err != nil {
+		t.Fatalf("ListForgeReleases failed: %v", err)
+	}
+
+	got := releases[0].AssetNames()
+	want := []string{".bashrc", "evil.exe", "tool.tar.gz"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("asset names = %v, want %v", got, want)
+	}
+}
+
+func TestGetForgeRelease_Gitea(t *testing.T) {
+	server := newForgeServer(t, "/api/v1/repos/owner/tool/releases/tags/v0.7.1",
+		`{"tag_name": "v0.7.1", "assets": [{"name": "tool.tar.gz", "browser_download_url": "https://example.com/tool.tar.gz"}]}`)
+	defer server.Close()
+
+	resolver := New()
+	release, err := resolver.GetForgeRelease(context.Background(), ForgeGitea, server.URL, "owner/tool", "v0.7.1")
+	if err != nil {
+		t.Fatalf("GetForgeRelease failed: %v", err)
+	}
+	if release.Tag != "v0.7.1" || len(release.Assets) != 1 {
+		t.Errorf("unexpected release: %+v", release)
+	}
+}
+
+func TestForgeProvider_ResolveLatestSkipsPrereleases(t *testing.T) {
+	server := newForgeServer(t, "/api/v4/projects/group%2Ftool/releases", gitlabReleasesJSON)
+	defer server.Close()
+
+	provider := NewGitLabProvider(New(), server.URL, "group/tool", "")
+	info, err := provider.ResolveLatest(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveLatest failed: %v", err)
+	}
+	if info.Version != "1.4.2" || info.Tag != "v1.4.2" {
+		t.Errorf("ResolveLatest = %+v, want 1.4.2 (v1.4.2)", info)
+	}
+
+	// Gitea marks prereleases explicitly
+	giteaServer := newForgeServer(t, "/api/v1/repos/owner/tool/releases", giteaReleasesJSON)
+	defer giteaServer.Close()
+
+	giteaProvider := NewGiteaProvider(New(), giteaServer.URL, "owner/tool", "")
+	info, err = giteaProvider.ResolveLatest(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveLatest failed: %v", err)
+	}
+	if info.Version != "0.7.1" {
+		t.Errorf("ResolveLatest = %+v, want 0.7.1", info)
+	}
+}
+
+func TestForgeProvider_ResolveVersion(t *testing.T) {
+	server := newForgeServer(t, "/api/v4/projects/group%2Ftool/releases", gitlabReleasesJSON)
+	defer server.Close()
+
+	provider := NewGitLabProvider(New(), server.URL, "group/tool", "")
+	ctx := context.Background()
+
+	tests := []struct {
+		input   string
+		wantTag string
+	}{
+		{"1.4.2", "v1.4.2"},
+		{"v1.3.0", "v1.3.0"},
+		{"1.4", "v1.4.2"},
+	}
+	for _, tt := range tests {
+		info, err := provider.ResolveVersion(ctx, tt.input)
+		if err != nil {
+			t.Errorf("ResolveVersion(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if info.Tag != tt.wantTag {
+			t.Errorf("ResolveVersion(%q).Tag = %q, want %q", tt.input, info.Tag, tt.wantTag)
+		}
+	}
+
+	if _, err := provider.ResolveVersion(ctx, "9.9.9"); err == nil {
+		t.Error("ResolveVersion should fail for unknown version")
+	}
+}
+
+func TestForgeProvider_SourceDescription(t *testing.T) {
+	resolver := New()
+	tests := []struct {
+		provider *ForgeProvider
+		want     string
+	}{
+		{NewGitLabProvider(resolver, "", "group/tool", ""), "GitLab:group/tool"},
+		{NewGitLabProvider(resolver, "https://gitlab.gnome.org", "GNOME/tool", ""), "GitLab:gitlab.gnome.org/GNOME/tool"},
+		{NewGiteaProvider(resolver, "https://codeberg.org", "owner/tool", ""), "Gitea:codeberg.org/owner/tool"},
+	}
+	for _, tt := range tests {
+		if got := tt.provider.SourceDescription(); got != tt.want {
+			t.Errorf("SourceDescription() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMatchForgeAsset(t *testing.T) {
+	release := &ForgeRelease{
+		Tag: "v1.0.0",
+		Assets: []ForgeAsset{
+			{Name: "tool-linux-amd64.tar.gz", URL: "https://example.com/linux"},
+			{Name: "tool-darwin-arm64.tar.gz", URL: "https://example.com/darwin"},
+		},
+	}
+
+	asset, err := MatchForgeAsset(release, "tool-darwin-arm64.tar.gz")
+	if err != nil || asset.URL != "https://example.com/darwin" {
+		t.Errorf("exact match = %+v, %v", asset, err)
+	}
+
+	asset, err = MatchForgeAsset(release, "*-linux-*")
+	if err != nil || asset.URL != "https://example.com/linux" {
+		t.Errorf("wildcard match = %+v, %v", asset, err)
+	}
+
+	if _, err := MatchForgeAsset(release, "tool-windows.zip"); err == nil {
+		t.Error("expected error for missing asset")
+	}
+}
+
+func TestForgeStrategies(t *testing.T) {
+	factory := NewProviderFactory()
+	resolver := New()
+
+	tests := []struct {
+		name     string
+		recipe   *recipe.Recipe
+		wantDesc string
+		wantErr  bool
+	}{
+		{
+			name: "gitlab_releases source",
+			recipe: &recipe.Recipe{
+				Version: recipe.VersionSection{Source: "gitlab_releases", Repo: "group/tool"},
+			},
+			wantDesc: "GitLab:group/tool",
+		},
+		{
+			name: "gitea_releases source",
+			recipe: &recipe.Recipe{
+				Version: recipe.VersionSection{Source: "gitea_releases", Repo: "owner/tool", BaseURL: "https://codeberg.org"},
+			},
+			wantDesc: "Gitea:codeberg.org/owner/tool",
+		},
+		{
+			name: "gitea_releases rejects http base URL",
+			recipe: &recipe.Recipe{
+				Version: recipe.VersionSection{Source: "gitea_releases", Repo: "owner/tool", BaseURL: "http://codeberg.org"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "inferred from gitea_archive",
+			recipe: &recipe.Recipe{
+				Steps: []recipe.Step{{
+					Action: "gitea_archive",
+					Params: map[string]interface{}{"repo": "owner/tool", "base_url": "https://codeberg.org"},
+				}},
+			},
+			wantDesc: "Gitea:codeberg.org/owner/tool",
+		},
+		{
+			name: "inferred from gitlab_file",
+			recipe: &recipe.Recipe{
+				Steps: []recipe.Step{{
+					Action: "gitlab_file",
+					Params: map[string]interface{}{"repo": "group/sub/tool"},
+				}},
+			},
+			wantDesc: "GitLab:group/sub/tool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := factory.ProviderFromRecipe(resolver, tt.recipe)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProviderFromRecipe failed: %v", err)
+			}
+			if got := provider.SourceDescription(); got != tt.wantDesc {
+				t.Errorf("SourceDescription() = %q, want %q", got, tt.wantDesc)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// maxHashiCorpIndexSize limits the product index response. The index lists every
+// build of every release, so popular products (terraform, vault) are several MB (32MB).
+const maxHashiCorpIndexSize = 32 * 1024 * 1024
+
+// hashicorpProductRegex validates product names (e.g., "terraform", "terraform-ls")
+var hashicorpProductRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// hashicorpIndex is the response of https://releases.hashicorp.com/{product}/index.json
+type hashicorpIndex struct {
+	Name     string `json:"name"`
+	Versions map[string]struct {
+		Version string `json:"version"`
+	} `json:"versions"`
+}
+
+// ListHashiCorpVersions fetches all open-source versions of a HashiCorp product
+//
+// API: https://releases.hashicorp.com/{product}/index.json
+// Enterprise builds ("1.15.4+ent") are excluded. Returns versions unsorted.
+func (r *Resolver) ListHashiCorpVersions(ctx context.Context, product string) ([]string, error) {
+	if !hashicorpProductRegex.MatchString(product) {
+		return nil, &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  "hashicorp",
+			Message: fmt.Sprintf("invalid HashiCorp product name: %s", product),
+		}
+	}
+
+	indexURL := fmt.Sprintf("%s/%s/index.json", r.hashicorpURL, product)
+	req, err := http.NewRequestWithContext(ctx, "GET", indexURL, nil)
+	if err != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  "hashicorp",
+			Message: "failed to create request",
+			Err:     err,
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, WrapNetworkError(err, "hashicorp", "failed to fetch HashiCorp releases")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  "hashicorp",
+			Message: fmt.Sprintf("HashiCorp product %s not found", product),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  "hashicorp",
+			Message: fmt.Sprintf("releases.hashicorp.com returned status %d", resp.StatusCode),
+		}
+	}
+
+	var index hashicorpIndex
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxHashiCorpIndexSize)).Decode(&index); err != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeParsing,
+			Source:  "hashicorp",
+			Message: "failed to parse HashiCorp release index",
+			Err:     err,
+		}
+	}
+
+	versions := make([]string, 0, len(index.Versions))
+	for key, v := range index.Versions {
+		version := v.Version
+		if version == "" {
+			version = key
+		}
+		if strings.Contains(version, "+ent") {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// ResolveHashiCorp resolves the latest stable version of a HashiCorp product
+func (r *Resolver) ResolveHashiCorp(ctx context.Context, product string) (*VersionInfo, error) {
+	return NewHashiCorpProvider(r, product).ResolveLatest(ctx)
+}
@@ -0,0 +1,350 @@
+package version
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled JSONPath expression.
+//
+// Supported subset (enough for vendor version manifests):
+//
+//	$                 root
+//	.name  ['name']   child member
+//	.*  [*]           all members / elements
+//	[n]               array index (negative counts from the end)
+//	..name  ..*       recursive descent
+//	[?(@.a.b)]        filter: member present and not null/false
+//	[?(@.a == 'x')]   filter: comparison (==, !=) against a string, number or boolean
+type jsonPath struct {
+	expr  string
+	steps []jsonPathStep
+}
+
+type jsonPathStepKind int
+
+const (
+	jsonPathChild jsonPathStepKind = iota
+	jsonPathWildcard
+	jsonPathIndex
+	jsonPathFilter
+)
+
+type jsonPathStep struct {
+	kind      jsonPathStepKind
+	recursive bool // step applies to the node and all of its descendants ("..")
+	name      string
+	index     int
+	filter    *jsonPathPredicate
+}
+
+// jsonPathPredicate is a filter expression relative to the current element (@)
+type jsonPathPredicate struct {
+	path  []string
+	op    string // "", "==" or "!="
+	value interface{}
+}
+
+// compileJSONPath parses a JSONPath expression
+func compileJSONPath(expr string) (*jsonPath, error) {
+	p := &jsonPathParser{s: strings.TrimSpace(expr)}
+	if !strings.HasPrefix(p.s, "$") {
+		return nil, fmt.Errorf("JSONPath must start with '$': %q", expr)
+	}
+	p.pos = 1
+
+	var steps []jsonPathStep
+	for p.pos < len(p.s) {
+		step, err := p.step()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath %q: %w", expr, err)
+		}
+		steps = append(steps, step)
+	}
+	return &jsonPath{expr: expr, steps: steps}, nil
+}
+
+// evaluate applies the path to a decoded JSON document and returns all matches
+func (jp *jsonPath) evaluate(doc interface{}) []interface{} {
+	nodes := []interface{}{doc}
+	for _, step := range jp.steps {
+		var next []interface{}
+		for _, node := range nodes {
+			candidates := []interface{}{node}
+			if step.recursive {
+				candidates = jsonDescendants(node, candidates)
+			}
+			for _, c := range candidates {
+				next = append(next, step.apply(c)...)
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func (s jsonPathStep) apply(node interface{}) []interface{} {
+	switch s.kind {
+	case jsonPathChild:
+		if obj, ok := node.(map[string]interface{}); ok {
+			if v, ok := obj[s.name]; ok {
+				return []interface{}{v}
+			}
+		}
+	case jsonPathWildcard:
+		return jsonChildren(node)
+	case jsonPathIndex:
+		if arr, ok := node.([]interface{}); ok {
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []interface{}{arr[i]}
+			}
+		}
+	case jsonPathFilter:
+		var out []interface{}
+		for _, child := range jsonChildren(node) {
+			if s.filter.matches(child) {
+				out = append(out, child)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func (f *jsonPathPredicate) matches(node interface{}) bool {
+	v := node
+	for _, name := range f.path {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if v, ok = obj[name]; !ok {
+			return false
+		}
+	}
+
+	switch f.op {
+	case "==":
+		return v == f.value
+	case "!=":
+		return v != f.value
+	default:
+		return v != nil && v != false
+	}
+}
+
+// jsonChildren returns array elements or object values (in key order for determinism)
+func jsonChildren(node interface{}) []interface{} {
+	switch n := node.(type) {
+	case []interface{}:
+		return n
+	case map[string]interface{}:
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, n[k])
+		}
+		return out
+	}
+	return nil
+}
+
+// jsonDescendants appends all descendants of node (pre-order) to acc
+func jsonDescendants(node interface{}, acc []interface{}) []interface{} {
+	for _, child := range jsonChildren(node) {
+		acc = append(acc, child)
+		acc = jsonDescendants(child, acc)
+	}
+	return acc
+}
+
+type jsonPathParser struct {
+	s   string
+	pos int
+}
+
+func (p *jsonPathParser) step() (jsonPathStep, error) {
+	var step jsonPathStep
+
+	switch p.s[p.pos] {
+	case '.':
+		p.pos++
+		if p.pos < len(p.s) && p.s[p.pos] == '.' {
+			step.recursive = true
+			p.pos++
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '[' {
+			if !step.recursive {
+				return step, fmt.Errorf("unexpected '[' after '.' at offset %d", p.pos)
+			}
+			return p.bracket(step)
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '*' {
+			p.pos++
+			step.kind = jsonPathWildcard
+			return step, nil
+		}
+		name := p.identifier()
+		if name == "" {
+			return step, fmt.Errorf("expected member name at offset %d", p.pos)
+		}
+		step.kind = jsonPathChild
+		step.name = name
+		return step, nil
+	case '[':
+		return p.bracket(step)
+	default:
+		return step, fmt.Errorf("unexpected %q at offset %d", p.s[p.pos], p.pos)
+	}
+}
+
+func (p *jsonPathParser) bracket(step jsonPathStep) (jsonPathStep, error) {
+	p.pos++ // '['
+	p.skipSpaces()
+	if p.pos >= len(p.s) {
+		return step, fmt.Errorf("unterminated '['")
+	}
+
+	switch c := p.s[p.pos]; {
+	case c == '*':
+		p.pos++
+		step.kind = jsonPathWildcard
+	case c == '\'' || c == '"':
+		name, err := p.quoted()
+		if err != nil {
+			return step, err
+		}
+		step.kind = jsonPathChild
+		step.name = name
+	case c == '?':
+		filter, err := p.filter()
+		if err != nil {
+			return step, err
+		}
+		step.kind = jsonPathFilter
+		step.filter = filter
+	default:
+		start := p.pos
+		for p.pos < len(p.s) && (p.s[p.pos] == '-' || (p.s[p.pos] >= '0' && p.s[p.pos] <= '9')) {
+			p.pos++
+		}
+		index, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil {
+			return step, fmt.Errorf("invalid array index at offset %d", start)
+		}
+		step.kind = jsonPathIndex
+		step.index = index
+	}
+
+	p.skipSpaces()
+	if p.pos >= len(p.s) || p.s[p.pos] != ']' {
+		return step, fmt.Errorf("expected ']' at offset %d", p.pos)
+	}
+	p.pos++
+	return step, nil
+}
+
+// filter parses "?(@.a.b)" or "?(@.a.b <op> <literal>)"
+func (p *jsonPathParser) filter() (*jsonPathPredicate, error) {
+	if !strings.HasPrefix(p.s[p.pos:], "?(@") {
+		return nil, fmt.Errorf("filter must start with '?(@' at offset %d", p.pos)
+	}
+	p.pos += 3
+
+	f := &jsonPathPredicate{}
+	for p.pos < len(p.s) && p.s[p.pos] == '.' {
+		p.pos++
+		name := p.identifier()
+		if name == "" {
+			return nil, fmt.Errorf("expected member name at offset %d", p.pos)
+		}
+		f.path = append(f.path, name)
+	}
+
+	p.skipSpaces()
+	if strings.HasPrefix(p.s[p.pos:], "==") || strings.HasPrefix(p.s[p.pos:], "!=") {
+		f.op = p.s[p.pos : p.pos+2]
+		p.pos += 2
+		p.skipSpaces()
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		f.value = value
+		p.skipSpaces()
+	}
+
+	if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+		return nil, fmt.Errorf("expected ')' at offset %d", p.pos)
+	}
+	p.pos++
+	return f, nil
+}
+
+func (p *jsonPathParser) literal() (interface{}, error) {
+	if p.pos >= len(p.s) {
+		return nil, fmt.Errorf("expected value in filter")
+	}
+	if c := p.s[p.pos]; c == '\'' || c == '"' {
+		return p.quoted()
+	}
+
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(" )", rune(p.s[p.pos])) {
+		p.pos++
+	}
+	token := p.s[start:p.pos]
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter value %q", token)
+	}
+	return n, nil
+}
+
+func (p *jsonPathParser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	end := strings.IndexByte(p.s[p.pos+1:], quote)
+	if end == -1 {
+		return "", fmt.Errorf("unterminated string at offset %d", p.pos)
+	}
+	value := p.s[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+func (p *jsonPathParser) identifier() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '_' || c == '-' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
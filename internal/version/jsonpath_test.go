@@ -0,0 +1,71 @@
+package version
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const jsonPathDoc = `{
+	"name": "tool",
+	"releases": [
+		{"version": "1.2.0", "channel": "stable", "lts": "Iron"},
+		{"version": "1.3.0-beta", "channel": "beta", "lts": false},
+		{"version": "1.1.0", "channel": "stable", "lts": null}
+	],
+	"platforms": {"linux": {"latest": "1.2.0"}, "darwin": {"latest": "1.1.0"}},
+	"feature": 21
+}`
+
+func TestJSONPath_Evaluate(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(jsonPathDoc), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want []interface{}
+	}{
+		{"$.name", []interface{}{"tool"}},
+		{"$['name']", []interface{}{"tool"}},
+		{"$.releases[*].version", []interface{}{"1.2.0", "1.3.0-beta", "1.1.0"}},
+		{"$.releases[0].version", []interface{}{"1.2.0"}},
+		{"$.releases[-1].version", []interface{}{"1.1.0"}},
+		{"$.platforms.*.latest", []interface{}{"1.1.0", "1.2.0"}},
+		{"$..latest", []interface{}{"1.1.0", "1.2.0"}},
+		{"$.releases[?(@.channel == 'stable')].version", []interface{}{"1.2.0", "1.1.0"}},
+		{"$.releases[?(@.channel != \"stable\")].version", []interface{}{"1.3.0-beta"}},
+		{"$.releases[?(@.lts)].version", []interface{}{"1.2.0"}},
+		{"$.feature", []interface{}{float64(21)}},
+		{"$.missing", nil},
+	}
+
+	for _, tt := range tests {
+		jp, err := compileJSONPath(tt.expr)
+		if err != nil {
+			t.Errorf("compileJSONPath(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got := jp.evaluate(doc); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("evaluate(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestJSONPath_CompileErrors(t *testing.T) {
+	for _, expr := range []string{
+		"releases[*]",
+		"$.",
+		"$[",
+		"$[abc]",
+		"$['unterminated]",
+		"$[?(@.a == )]",
+		"$[?(.a)]",
+		"$.a.[0]",
+	} {
+		if _, err := compileJSONPath(expr); err == nil {
+			t.Errorf("compileJSONPath(%q) expected error", expr)
+		}
+	}
+}
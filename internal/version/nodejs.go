@@ -4,64 +4,79 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
-	"strings"
 )
 
-// ResolveNodeJS resolves the latest LTS version from Node.js dist site
-func (r *Resolver) ResolveNodeJS(ctx context.Context) (*VersionInfo, error) {
-	// Node.js publishes version info at https://nodejs.org/dist/index.json
-	url := "https://nodejs.org/dist/index.json"
+// maxNodeJSIndexSize limits the dist index response to prevent memory exhaustion (10MB)
+const maxNodeJSIndexSize = 10 * 1024 * 1024
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// NodeRelease is a Node.js release from the dist index
+type NodeRelease struct {
+	Version string // Release tag with "v" prefix (e.g., "v20.11.0")
+	LTS     string // LTS codename (e.g., "Iron"), empty for non-LTS releases
+}
+
+// nodeIndexEntry is an entry of https://nodejs.org/dist/index.json
+type nodeIndexEntry struct {
+	Version string      `json:"version"`
+	LTS     interface{} `json:"lts"` // Can be string (LTS name) or false
+}
+
+// ListNodeJSReleases fetches all releases from the Node.js dist index
+//
+// API: https://nodejs.org/dist/index.json
+// Returns: releases in index order (newest release date first)
+func (r *Resolver) ListNodeJSReleases(ctx context.Context) ([]NodeRelease, error) {
+	indexURL := r.nodejsDistURL + "/dist/index.json"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", indexURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  "nodejs_dist",
+			Message: "failed to create request",
+			Err:     err,
+		}
 	}
+	req.Header.Set("Accept", "application/json")
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		if strings.Contains(err.Error(), "network is unreachable") ||
-			strings.Contains(err.Error(), "no such host") ||
-			strings.Contains(err.Error(), "dial tcp") {
-			return nil, fmt.Errorf("network unavailable: %w", err)
-		}
-		return nil, fmt.Errorf("failed to fetch Node.js versions: %w", err)
+		return nil, WrapNetworkError(err, "nodejs_dist", "failed to fetch Node.js versions")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Node.js dist site returned status %d", resp.StatusCode)
-	}
-
-	var versions []struct {
-		Version string      `json:"version"`
-		LTS     interface{} `json:"lts"` // Can be string (LTS name) or false
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&versions); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  "nodejs_dist",
+			Message: fmt.Sprintf("Node.js dist site returned status %d", resp.StatusCode),
+		}
 	}
 
-	// Find the latest LTS version
-	for _, v := range versions {
-		if v.LTS != nil && v.LTS != false {
-			// Strip "v" prefix from version
-			version := normalizeVersion(v.Version)
-			return &VersionInfo{
-				Tag:     v.Version, // Keep "v" prefix for URL
-				Version: version,   // Without "v" for display
-			}, nil
+	var entries []nodeIndexEntry
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxNodeJSIndexSize)).Decode(&entries); err != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeParsing,
+			Source:  "nodejs_dist",
+			Message: "failed to parse Node.js dist index",
+			Err:     err,
 		}
 	}
 
-	// Fallback: use the latest version (first in list)
-	if len(versions) > 0 {
-		version := normalizeVersion(versions[0].Version)
-		return &VersionInfo{
-			Tag:     versions[0].Version,
-			Version: version,
-		}, nil
+	releases := make([]NodeRelease, 0, len(entries))
+	for _, e := range entries {
+		if e.Version == "" {
+			continue
+		}
+		lts, _ := e.LTS.(string)
+		releases = append(releases, NodeRelease{Version: e.Version, LTS: lts})
 	}
+	return releases, nil
+}
 
-	return nil, fmt.Errorf("no Node.js versions found")
+// ResolveNodeJS resolves the latest LTS version from Node.js dist site
+func (r *Resolver) ResolveNodeJS(ctx context.Context) (*VersionInfo, error) {
+	return NewNodeJSDistProvider(r).ResolveLatest(ctx)
 }
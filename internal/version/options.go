@@ -58,3 +58,41 @@ func WithGoProxyURL(url string) Option {
 		r.goProxyURL = url
 	}
 }
+
+// WithGitLabURL sets the GitLab instance used when a recipe does not specify base_url
+func WithGitLabURL(url string) Option {
+	return func(r *Resolver) {
+		r.gitlabURL = url
+	}
+}
+
+// WithNodeJSDistURL sets a custom Node.js dist site URL
+func WithNodeJSDistURL(url string) Option {
+	return func(r *Resolver) {
+		r.nodejsDistURL = url
+	}
+}
+
+// WithHashiCorpReleasesURL sets a custom HashiCorp releases URL
+func WithHashiCorpReleasesURL(url string) Option {
+	return func(r *Resolver) {
+		r.hashicorpURL = url
+	}
+}
+
+// WithOffline makes providers answer only from the version lists cached in
+// cacheDir, without network access
+func WithOffline(cacheDir string) Option {
+	return func(r *Resolver) {
+		r.offline = true
+		r.versionCacheDir = cacheDir
+	}
+}
+
+// WithVersionCache records every version resolved online in the version
+// cache in cacheDir, so that offline mode can resolve it later
+func WithVersionCache(cacheDir string) Option {
+	return func(r *Resolver) {
+		r.versionCacheDir = cacheDir
+	}
+}
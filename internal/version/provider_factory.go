@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 
 	"github.com/tsukumogami/tsuku/internal/recipe"
 )
@@ -63,6 +64,13 @@ func NewProviderFactory() *ProviderFactory {
 	f.Register(&GoProxySourceStrategy{})     // PriorityKnownRegistry (100) - intercepts source="goproxy"
 	f.Register(&MetaCPANSourceStrategy{})    // PriorityKnownRegistry (100) - intercepts source="metacpan"
 	f.Register(&HomebrewSourceStrategy{})    // PriorityKnownRegistry (100) - intercepts source="homebrew"
+	f.Register(&GitLabSourceStrategy{})      // PriorityKnownRegistry (100) - intercepts source="gitlab_releases"
+	f.Register(&GiteaSourceStrategy{})       // PriorityKnownRegistry (100) - intercepts source="gitea_releases"
+	f.Register(&GitTagsSourceStrategy{})     // PriorityKnownRegistry (100) - intercepts source="git_tags"
+	f.Register(&URLRegexSourceStrategy{})    // PriorityKnownRegistry (100) - intercepts source="url_regex"
+	f.Register(&JSONPathSourceStrategy{})    // PriorityKnownRegistry (100) - intercepts source="json_path"
+	f.Register(&NodeJSDistSourceStrategy{})  // PriorityKnownRegistry (100) - intercepts source="nodejs_dist"
+	f.Register(&HashiCorpSourceStrategy{})   // PriorityKnownRegistry (100) - intercepts source="hashicorp"
 	f.Register(&GitHubRepoStrategy{})        // PriorityExplicitHint (90)
 	f.Register(&ExplicitSourceStrategy{})    // PriorityExplicitSource (80) - catch-all for custom sources
 	f.Register(&InferredNpmStrategy{})       // PriorityInferred (10)
@@ -72,6 +80,7 @@ func NewProviderFactory() *ProviderFactory {
 	f.Register(&InferredMetaCPANStrategy{})  // PriorityInferred (10)
 	f.Register(&InferredGitHubStrategy{})    // PriorityInferred (10)
 	f.Register(&InferredGoProxyStrategy{})   // PriorityInferred (10)
+	f.Register(&InferredForgeStrategy{})     // PriorityInferred (10)
 
 	return f
 }
@@ -91,7 +100,17 @@ func (f *ProviderFactory) Register(strategy ProviderStrategy) {
 func (f *ProviderFactory) ProviderFromRecipe(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
 	for _, strategy := range f.strategies {
 		if strategy.CanHandle(r) {
-			return strategy.Create(resolver, r)
+			provider, err := strategy.Create(resolver, r)
+			if err != nil || resolver == nil {
+				return provider, err
+			}
+			if resolver.offline {
+				return NewOfflineProvider(provider, resolver.versionCacheDir), nil
+			}
+			if resolver.versionCacheDir != "" {
+				return newRecordingProvider(provider, resolver.versionCacheDir), nil
+			}
+			return provider, nil
 		}
 	}
 
@@ -545,6 +564,210 @@ func (s *HomebrewSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (V
 	return NewHomebrewProvider(resolver, r.Version.Formula), nil
 }
 
+// forgeActions maps forge composite actions to the forge they download from
+var forgeActions = map[string]string{
+	"gitlab_archive": ForgeGitLab,
+	"gitlab_file":    ForgeGitLab,
+	"gitea_archive":  ForgeGitea,
+	"gitea_file":     ForgeGitea,
+}
+
+// forgeLocation returns the base URL and project for a forge, preferring the
+// [version] section and falling back to the first matching forge action step.
+// GitLab may omit the base URL (gitlab.com); Gitea/Forgejo always needs one.
+func forgeLocation(r *recipe.Recipe, forge string) (baseURL, project string, ok bool) {
+	if r.Version.Repo != "" {
+		baseURL, project = r.Version.BaseURL, r.Version.Repo
+	} else {
+		for _, step := range r.Steps {
+			if forgeActions[step.Action] != forge {
+				continue
+			}
+			if repo, hasRepo := step.Params["repo"].(string); hasRepo {
+				project = repo
+				baseURL, _ = step.Params["base_url"].(string)
+				break
+			}
+		}
+	}
+	if project == "" || (forge == ForgeGitea && baseURL == "") {
+		return "", "", false
+	}
+	return baseURL, project, true
+}
+
+// newForgeProvider validates the forge location and creates the provider.
+// Recipes must use https:// base URLs.
+func newForgeProvider(resolver *Resolver, r *recipe.Recipe, forge string) (VersionProvider, error) {
+	baseURL, project, ok := forgeLocation(r, forge)
+	if !ok {
+		return nil, fmt.Errorf("no %s project found in [version] section or steps", forge)
+	}
+	if baseURL != "" && !strings.HasPrefix(baseURL, "https://") {
+		return nil, fmt.Errorf("invalid %s base_url %q: must use https://", forge, baseURL)
+	}
+	if !isValidForgeProject(forge, project) {
+		return nil, fmt.Errorf("invalid %s project path: %s", forge, project)
+	}
+	if forge == ForgeGitLab {
+		return NewGitLabProvider(resolver, baseURL, project, r.Version.TagPrefix), nil
+	}
+	return NewGiteaProvider(resolver, baseURL, project, r.Version.TagPrefix), nil
+}
+
+// GitLabSourceStrategy handles recipes with [version] source = "gitlab_releases"
+type GitLabSourceStrategy struct{}
+
+func (s *GitLabSourceStrategy) Priority() int { return PriorityKnownRegistry }
+
+func (s *GitLabSourceStrategy) CanHandle(r *recipe.Recipe) bool {
+	if r.Version.Source != "gitlab_releases" {
+		return false
+	}
+	_, _, ok := forgeLocation(r, ForgeGitLab)
+	return ok
+}
+
+func (s *GitLabSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	return newForgeProvider(resolver, r, ForgeGitLab)
+}
+
+// GiteaSourceStrategy handles recipes with [version] source = "gitea_releases"
+// Forgejo instances (e.g., codeberg.org) use the same API and this same source.
+type GiteaSourceStrategy struct{}
+
+func (s *GiteaSourceStrategy) Priority() int { return PriorityKnownRegistry }
+
+func (s *GiteaSourceStrategy) CanHandle(r *recipe.Recipe) bool {
+	if r.Version.Source != "gitea_releases" {
+		return false
+	}
+	_, _, ok := forgeLocation(r, ForgeGitea)
+	return ok
+}
+
+func (s *GiteaSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	return newForgeProvider(resolver, r, ForgeGitea)
+}
+
+// GitTagsSourceStrategy handles recipes with [version] source = "git_tags"
+// Tags are read from any git host over the smart HTTP protocol.
+type GitTagsSourceStrategy struct{}
+
+func (s *GitTagsSourceStrategy) Priority() int { return PriorityKnownRegistry }
+
+func (s *GitTagsSourceStrategy) CanHandle(r *recipe.Recipe) bool {
+	return r.Version.Source == "git_tags" && r.Version.GitURL != ""
+}
+
+func (s *GitTagsSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	if !strings.HasPrefix(r.Version.GitURL, "https://") {
+		return nil, fmt.Errorf("invalid git_url %q: must use https://", r.Version.GitURL)
+	}
+	return NewGitTagsProvider(resolver, r.Version.GitURL, r.Version.TagPrefix), nil
+}
+
+// URLRegexSourceStrategy handles recipes with [version] source = "url_regex"
+// Versions are matched with a regex in a vendor download page.
+type URLRegexSourceStrategy struct{}
+
+func (s *URLRegexSourceStrategy) Priority() int { return PriorityKnownRegistry }
+
+func (s *URLRegexSourceStrategy) CanHandle(r *recipe.Recipe) bool {
+	return r.Version.Source == "url_regex" && r.Version.URL != "" && r.Version.Regex != ""
+}
+
+func (s *URLRegexSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	if !strings.HasPrefix(r.Version.URL, "https://") {
+		return nil, fmt.Errorf("invalid url %q: must use https://", r.Version.URL)
+	}
+	return NewURLRegexProvider(resolver, r.Version.URL, r.Version.Regex, r.Version.TagPrefix)
+}
+
+// JSONPathSourceStrategy handles recipes with [version] source = "json_path"
+// Versions are selected with a JSONPath expression from a JSON manifest.
+type JSONPathSourceStrategy struct{}
+
+func (s *JSONPathSourceStrategy) Priority() int { return PriorityKnownRegistry }
+
+func (s *JSONPathSourceStrategy) CanHandle(r *recipe.Recipe) bool {
+	return r.Version.Source == "json_path" && r.Version.URL != "" && r.Version.JSONPath != ""
+}
+
+func (s *JSONPathSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	if !strings.HasPrefix(r.Version.URL, "https://") {
+		return nil, fmt.Errorf("invalid url %q: must use https://", r.Version.URL)
+	}
+	return NewJSONPathProvider(resolver, r.Version.URL, r.Version.JSONPath, r.Version.TagPrefix)
+}
+
+// NodeJSDistSourceStrategy handles recipes with [version] source = "nodejs_dist"
+// This intercepts source="nodejs_dist" so Node.js versions can be listed and pinned
+// instead of going through the latest-only custom registry
+type NodeJSDistSourceStrategy struct{}
+
+func (s *NodeJSDistSourceStrategy) Priority() int { return PriorityKnownRegistry }
+
+func (s *NodeJSDistSourceStrategy) CanHandle(r *recipe.Recipe) bool {
+	return r.Version.Source == "nodejs_dist"
+}
+
+func (s *NodeJSDistSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	return NewNodeJSDistProvider(resolver), nil
+}
+
+// HashiCorpSourceStrategy handles recipes with [version] source = "hashicorp"
+// The product defaults to the recipe name; "hashicorp:<product>" overrides it.
+type HashiCorpSourceStrategy struct{}
+
+func (s *HashiCorpSourceStrategy) Priority() int { return PriorityKnownRegistry }
+
+func (s *HashiCorpSourceStrategy) CanHandle(r *recipe.Recipe) bool {
+	return r.Version.Source == "hashicorp" || strings.HasPrefix(r.Version.Source, "hashicorp:")
+}
+
+func (s *HashiCorpSourceStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	product := r.Metadata.Name
+	if p, ok := strings.CutPrefix(r.Version.Source, "hashicorp:"); ok {
+		product = p
+	}
+	if !hashicorpProductRegex.MatchString(product) {
+		return nil, fmt.Errorf("invalid HashiCorp product name: %q", product)
+	}
+	return NewHashiCorpProvider(resolver, product), nil
+}
+
+// InferredForgeStrategy infers GitLab or Gitea releases from gitlab_*/gitea_* actions
+type InferredForgeStrategy struct{}
+
+func (s *InferredForgeStrategy) Priority() int { return PriorityInferred }
+
+func (s *InferredForgeStrategy) CanHandle(r *recipe.Recipe) bool {
+	return s.forge(r) != ""
+}
+
+func (s *InferredForgeStrategy) Create(resolver *Resolver, r *recipe.Recipe) (VersionProvider, error) {
+	forge := s.forge(r)
+	if forge == "" {
+		return nil, fmt.Errorf("no GitLab or Gitea project found in steps")
+	}
+	return newForgeProvider(resolver, r, forge)
+}
+
+// forge returns the forge of the first step that fully identifies a project
+func (s *InferredForgeStrategy) forge(r *recipe.Recipe) string {
+	for _, step := range r.Steps {
+		forge, ok := forgeActions[step.Action]
+		if !ok {
+			continue
+		}
+		if _, _, ok := forgeLocation(r, forge); ok {
+			return forge
+		}
+	}
+	return ""
+}
+
 // InferredGoProxyStrategy infers goproxy from go_install action
 type InferredGoProxyStrategy struct{}
 
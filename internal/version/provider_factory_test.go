@@ -991,7 +991,7 @@ func TestProviderFactory_ProviderFromRecipe_WithExplicitSource(t *testing.T) {
 
 	r := &recipe.Recipe{
 		Metadata: recipe.MetadataSection{Name: "test-tool"},
-		Version:  recipe.VersionSection{Source: "nodejs_dist"},
+		Version:  recipe.VersionSection{Source: "custom_dist"},
 		Steps: []recipe.Step{
 			{
 				Action: "download",
@@ -1014,8 +1014,8 @@ func TestProviderFactory_ProviderFromRecipe_WithExplicitSource(t *testing.T) {
 	if !ok {
 		t.Errorf("ProviderFromRecipe() returned %T, want *CustomProvider", provider)
 	}
-	if customProvider != nil && customProvider.SourceDescription() != "custom:nodejs_dist" {
-		t.Errorf("SourceDescription() = %q, want %q", customProvider.SourceDescription(), "custom:nodejs_dist")
+	if customProvider != nil && customProvider.SourceDescription() != "custom:custom_dist" {
+		t.Errorf("SourceDescription() = %q, want %q", customProvider.SourceDescription(), "custom:custom_dist")
 	}
 }
 
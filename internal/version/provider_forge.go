@@ -0,0 +1,137 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ForgeProvider resolves versions from GitLab or Gitea/Forgejo releases.
+// Implements both VersionResolver and VersionLister interfaces.
+type ForgeProvider struct {
+	resolver  *Resolver
+	forge     string // ForgeGitLab or ForgeGitea
+	baseURL   string // instance URL (e.g., "https://codeberg.org"); empty means gitlab.com for GitLab
+	project   string // "group/project" for GitLab, "owner/repo" for Gitea
+	tagPrefix string // optional prefix to filter tags (e.g., "cli-")
+}
+
+// NewGitLabProvider creates a provider for tools released on GitLab.
+// baseURL may be empty to use gitlab.com.
+func NewGitLabProvider(resolver *Resolver, baseURL, project, tagPrefix string) *ForgeProvider {
+	return &ForgeProvider{
+		resolver:  resolver,
+		forge:     ForgeGitLab,
+		baseURL:   baseURL,
+		project:   project,
+		tagPrefix: tagPrefix,
+	}
+}
+
+// NewGiteaProvider creates a provider for tools released on a Gitea or Forgejo instance.
+func NewGiteaProvider(resolver *Resolver, baseURL, repo, tagPrefix string) *ForgeProvider {
+	return &ForgeProvider{
+		resolver:  resolver,
+		forge:     ForgeGitea,
+		baseURL:   baseURL,
+		project:   repo,
+		tagPrefix: tagPrefix,
+	}
+}
+
+// releases returns the project's releases as VersionInfo (newest first),
+// filtered by tag prefix and paired with their prerelease flag.
+func (p *ForgeProvider) releases(ctx context.Context) ([]VersionInfo, []bool, error) {
+	releases, err := p.resolver.ListForgeReleases(ctx, p.forge, p.baseURL, p.project)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var infos []VersionInfo
+	var prerelease []bool
+	for _, rel := range releases {
+		if !strings.HasPrefix(rel.Tag, p.tagPrefix) {
+			continue
+		}
+		v := normalizeVersion(strings.TrimPrefix(rel.Tag, p.tagPrefix))
+		if !isValidVersion(v) {
+			continue
+		}
+		infos = append(infos, VersionInfo{Tag: rel.Tag, Version: v})
+		prerelease = append(prerelease, rel.Prerelease)
+	}
+	return infos, prerelease, nil
+}
+
+// ListVersions returns all released versions (newest first)
+func (p *ForgeProvider) ListVersions(ctx context.Context) ([]string, error) {
+	infos, _, err := p.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(infos))
+	for _, info := range infos {
+		versions = append(versions, info.Version)
+	}
+	return versions, nil
+}
+
+// ResolveLatest returns the newest stable release.
+// Prereleases are skipped unless no stable release exists.
+func (p *ForgeProvider) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	infos, prerelease, err := p.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  p.forge,
+			Message: fmt.Sprintf("no releases found for %s", p.project),
+		}
+	}
+
+	for i, info := range infos {
+		if !prerelease[i] && isStableVersion(info.Version) {
+			return &infos[i], nil
+		}
+	}
+
+	// Fallback to newest release if no stable release found
+	return &infos[0], nil
+}
+
+// ResolveVersion resolves a specific version, keeping the original release tag.
+// Supports fuzzy matching (e.g., "1.2" matches "1.2.3")
+func (p *ForgeProvider) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	infos, _, err := p.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, ok := matchVersionInfo(infos, version); ok {
+		return info, nil
+	}
+
+	return nil, fmt.Errorf("version %s not found in %s releases for %s", version, p.forge, p.project)
+}
+
+// SourceDescription returns a human-readable source description
+func (p *ForgeProvider) SourceDescription() string {
+	if p.forge == ForgeGitLab {
+		if p.baseURL == "" || p.baseURL == DefaultGitLabURL {
+			return fmt.Sprintf("GitLab:%s", p.project)
+		}
+		return fmt.Sprintf("GitLab:%s/%s", hostOf(p.baseURL), p.project)
+	}
+	return fmt.Sprintf("Gitea:%s/%s", hostOf(p.baseURL), p.project)
+}
+
+// hostOf returns the host portion of a URL for display, or the input if it cannot be parsed
+func hostOf(rawURL string) string {
+	s := strings.TrimPrefix(strings.TrimPrefix(rawURL, "https://"), "http://")
+	if idx := strings.Index(s, "/"); idx != -1 {
+		s = s[:idx]
+	}
+	return s
+}
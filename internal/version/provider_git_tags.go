@@ -0,0 +1,322 @@
+package version
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxGitRefsResponseSize limits the info/refs advertisement to prevent memory exhaustion (10MB)
+	maxGitRefsResponseSize = 10 * 1024 * 1024
+
+	// maxPktLineSize is the largest pkt-line allowed by the git protocol (65520 bytes including header)
+	maxPktLineSize = 65520
+
+	// gitUploadPackAdvertisement is the Content-Type of a smart HTTP ref advertisement
+	gitUploadPackAdvertisement = "application/x-git-upload-pack-advertisement"
+)
+
+// ListGitTags fetches tag names from a git repository over HTTP without a git binary.
+//
+// Protocol: GET {gitURL}/info/refs?service=git-upload-pack
+// Smart HTTP servers answer with a pkt-line encoded ref advertisement; servers that
+// only support the dumb protocol answer with a plain "<sha>\t<ref>" listing. Both are
+// accepted. Peeled refs ("refs/tags/v1.0^{}") are collapsed into their tag.
+//
+// Returns: tag names in server order (unsorted), without the "refs/tags/" prefix
+func (r *Resolver) ListGitTags(ctx context.Context, gitURL string) ([]string, error) {
+	u, err := url.Parse(gitURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  "git_tags",
+			Message: fmt.Sprintf("invalid git URL: %s", gitURL),
+		}
+	}
+	if u.User != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  "git_tags",
+			Message: "git URL must not contain credentials",
+		}
+	}
+
+	refsURL := strings.TrimSuffix(gitURL, "/") + "/info/refs?service=git-upload-pack"
+	req, err := http.NewRequestWithContext(ctx, "GET", refsURL, nil)
+	if err != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  "git_tags",
+			Message: "failed to create request",
+			Err:     err,
+		}
+	}
+	// Some hosts only serve the smart protocol to clients identifying as git
+	req.Header.Set("User-Agent", "git/tsuku")
+	req.Header.Set("Accept", gitUploadPackAdvertisement+", */*")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, WrapNetworkError(err, "git_tags", "failed to fetch refs")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden:
+		// Private repositories answer 401/403; to tsuku they are indistinguishable from missing ones
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  "git_tags",
+			Message: fmt.Sprintf("repository not found or not public: %s", gitURL),
+		}
+	case http.StatusTooManyRequests:
+		return nil, &ResolverError{
+			Type:    ErrTypeRateLimit,
+			Source:  "git_tags",
+			Message: fmt.Sprintf("%s rate limit exceeded", u.Host),
+		}
+	default:
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  "git_tags",
+			Message: fmt.Sprintf("%s returned status %d", u.Host, resp.StatusCode),
+		}
+	}
+
+	limitedReader := io.LimitReader(resp.Body, maxGitRefsResponseSize)
+
+	var refs []string
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), gitUploadPackAdvertisement) {
+		refs, err = parseSmartRefs(limitedReader)
+	} else {
+		refs, err = parseDumbRefs(limitedReader)
+	}
+	if err != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeParsing,
+			Source:  "git_tags",
+			Message: "failed to parse ref advertisement",
+			Err:     err,
+		}
+	}
+
+	return tagsFromRefs(refs), nil
+}
+
+// parseSmartRefs parses a smart HTTP (protocol v0/v1) ref advertisement and returns ref names.
+//
+// Format: a "# service=git-upload-pack" pkt-line and flush, an optional "version 1" line,
+// then one pkt-line per ref ("<sha> <ref>", with capabilities after a NUL on the first
+// line), terminated by a flush-pkt ("0000").
+func parseSmartRefs(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+
+	var refs []string
+	flushes := 0
+	for {
+		line, flush, err := readPktLine(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if flush {
+			flushes++
+			// The ref list ends with the second flush (the first terminates the service header)
+			if flushes == 2 {
+				break
+			}
+			continue
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		if strings.HasPrefix(line, "# service=") || strings.HasPrefix(line, "version ") {
+			continue
+		}
+		if idx := strings.IndexByte(line, 0); idx != -1 {
+			line = line[:idx] // Strip capabilities
+		}
+
+		sha, ref, ok := strings.Cut(line, " ")
+		if !ok || len(sha) < 40 {
+			return nil, fmt.Errorf("malformed ref line: %q", line)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// readPktLine reads a single pkt-line. flush is true for "0000" (and the v2 "0001"/"0002"
+// delimiters, which carry no data).
+func readPktLine(br *bufio.Reader) (line string, flush bool, err error) {
+	var header [4]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return "", false, fmt.Errorf("truncated pkt-line header")
+		}
+		return "", false, err
+	}
+
+	length, err := strconv.ParseUint(string(header[:]), 16, 16)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid pkt-line length %q", string(header[:]))
+	}
+	if length < 4 {
+		return "", true, nil
+	}
+	if length > maxPktLineSize {
+		return "", false, fmt.Errorf("pkt-line too long: %d bytes", length)
+	}
+
+	data := make([]byte, length-4)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return "", false, fmt.Errorf("truncated pkt-line: %w", err)
+	}
+	return string(data), false, nil
+}
+
+// parseDumbRefs parses the dumb HTTP info/refs format: one "<sha>\t<ref>" per line
+func parseDumbRefs(r io.Reader) ([]string, error) {
+	var refs []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		_, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, fmt.Errorf("malformed ref line: %q", line)
+		}
+		refs = append(refs, ref)
+	}
+	return refs, scanner.Err()
+}
+
+// tagsFromRefs extracts unique tag names from ref names, collapsing peeled refs
+func tagsFromRefs(refs []string) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, ref := range refs {
+		tag, ok := strings.CutPrefix(ref, "refs/tags/")
+		if !ok {
+			continue
+		}
+		tag = strings.TrimSuffix(tag, "^{}")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// GitTagsProvider resolves versions from the tags of any git repository served over HTTP.
+// Implements both VersionResolver and VersionLister interfaces.
+type GitTagsProvider struct {
+	resolver  *Resolver
+	gitURL    string // repository URL (e.g., "https://git.savannah.gnu.org/git/make.git")
+	tagPrefix string // optional prefix to filter tags (e.g., "release-")
+}
+
+// NewGitTagsProvider creates a provider for tools versioned by git tags
+func NewGitTagsProvider(resolver *Resolver, gitURL, tagPrefix string) *GitTagsProvider {
+	return &GitTagsProvider{
+		resolver:  resolver,
+		gitURL:    gitURL,
+		tagPrefix: tagPrefix,
+	}
+}
+
+// tags returns tags matching the prefix as VersionInfo, newest first
+func (p *GitTagsProvider) tags(ctx context.Context) ([]VersionInfo, error) {
+	tags, err := p.resolver.ListGitTags(ctx, p.gitURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []VersionInfo
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, p.tagPrefix) {
+			continue
+		}
+		v := normalizeVersion(strings.TrimPrefix(tag, p.tagPrefix))
+		if !isValidVersion(v) {
+			continue
+		}
+		infos = append(infos, VersionInfo{Tag: tag, Version: v})
+	}
+
+	// Git advertises refs alphabetically, not by version
+	sortVersionInfos(infos)
+
+	return infos, nil
+}
+
+// ListVersions returns all tagged versions (newest first)
+func (p *GitTagsProvider) ListVersions(ctx context.Context) ([]string, error) {
+	infos, err := p.tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(infos))
+	for _, info := range infos {
+		versions = append(versions, info.Version)
+	}
+	return versions, nil
+}
+
+// ResolveLatest returns the newest stable tagged version
+func (p *GitTagsProvider) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	infos, err := p.tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(infos) == 0 {
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  "git_tags",
+			Message: fmt.Sprintf("no version tags found in %s", p.gitURL),
+		}
+	}
+
+	for i, info := range infos {
+		if isStableVersion(info.Version) {
+			return &infos[i], nil
+		}
+	}
+
+	// Fallback to newest tag if no stable version found
+	return &infos[0], nil
+}
+
+// ResolveVersion resolves a specific version, keeping the original tag.
+// Supports fuzzy matching (e.g., "4.3" matches "4.3.1")
+func (p *GitTagsProvider) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	infos, err := p.tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, ok := matchVersionInfo(infos, version); ok {
+		return info, nil
+	}
+	return nil, fmt.Errorf("version %s not found in tags of %s", version, p.gitURL)
+}
+
+// SourceDescription returns a human-readable source description
+func (p *GitTagsProvider) SourceDescription() string {
+	if p.tagPrefix != "" {
+		return fmt.Sprintf("git:%s (prefix %q)", p.gitURL, p.tagPrefix)
+	}
+	return fmt.Sprintf("git:%s", p.gitURL)
+}
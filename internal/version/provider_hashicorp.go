@@ -0,0 +1,93 @@
+package version
+
+import (
+	"context"
+	"fmt"
+)
+
+// HashiCorpProvider resolves versions from releases.hashicorp.com.
+// Implements both VersionResolver and VersionLister interfaces.
+type HashiCorpProvider struct {
+	resolver *Resolver
+	product  string // e.g., "terraform", "vault"
+}
+
+// NewHashiCorpProvider creates a provider for a HashiCorp product
+func NewHashiCorpProvider(resolver *Resolver, product string) *HashiCorpProvider {
+	return &HashiCorpProvider{
+		resolver: resolver,
+		product:  product,
+	}
+}
+
+// versions returns all versions of the product, newest first
+func (p *HashiCorpProvider) versions(ctx context.Context) ([]VersionInfo, error) {
+	versions, err := p.resolver.ListHashiCorpVersions(ctx, p.product)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  "hashicorp",
+			Message: fmt.Sprintf("no versions found for HashiCorp product %s", p.product),
+		}
+	}
+
+	// Release URLs use the bare version, so Tag and Version are the same
+	infos := make([]VersionInfo, 0, len(versions))
+	for _, v := range versions {
+		infos = append(infos, VersionInfo{Tag: v, Version: v})
+	}
+	sortVersionInfos(infos)
+	return infos, nil
+}
+
+// ListVersions returns all versions of the product (newest first)
+func (p *HashiCorpProvider) ListVersions(ctx context.Context) ([]string, error) {
+	infos, err := p.versions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(infos))
+	for _, info := range infos {
+		versions = append(versions, info.Version)
+	}
+	return versions, nil
+}
+
+// ResolveLatest returns the newest stable version
+func (p *HashiCorpProvider) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	infos, err := p.versions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, info := range infos {
+		if isStableVersion(info.Version) {
+			return &infos[i], nil
+		}
+	}
+	return &infos[0], nil
+}
+
+// ResolveVersion resolves a specific version or range.
+// Supports fuzzy matching (e.g., "1.5" matches "1.5.7") and ranges (e.g., "~1.5", ">=1.5, <1.7")
+func (p *HashiCorpProvider) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	infos, err := p.versions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := selectVersion(infos, version)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("version %s not found for HashiCorp product %s", version, p.product)
+	}
+	return info, nil
+}
+
+// SourceDescription returns a human-readable source description
+func (p *HashiCorpProvider) SourceDescription() string {
+	return fmt.Sprintf("hashicorp:%s", p.product)
+}
@@ -0,0 +1,160 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+const terraformIndexJSON = `{
+	"name": "terraform",
+	"versions": {
+		"1.5.0": {"name": "terraform", "version": "1.5.0", "builds": []},
+		"1.5.7": {"name": "terraform", "version": "1.5.7", "builds": []},
+		"1.6.6": {"name": "terraform", "version": "1.6.6", "builds": []},
+		"1.7.0-beta1": {"name": "terraform", "version": "1.7.0-beta1", "builds": []},
+		"1.6.6+ent": {"name": "terraform", "version": "1.6.6+ent", "builds": []},
+		"0.15.5": {"name": "terraform", "version": "0.15.5", "builds": []}
+	}
+}`
+
+func newHashiCorpServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/terraform/index.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(terraformIndexJSON))
+	}))
+}
+
+func TestHashiCorpProvider_ListVersions(t *testing.T) {
+	server := newHashiCorpServer(t)
+	defer server.Close()
+
+	provider := NewHashiCorpProvider(New(WithHashiCorpReleasesURL(server.URL)), "terraform")
+	versions, err := provider.ListVersions(context.Background())
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	want := []string{"1.7.0-beta1", "1.6.6", "1.5.7", "1.5.0", "0.15.5"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("ListVersions() = %v, want %v (enterprise builds excluded)", versions, want)
+	}
+}
+
+func TestHashiCorpProvider_Resolve(t *testing.T) {
+	server := newHashiCorpServer(t)
+	defer server.Close()
+
+	provider := NewHashiCorpProvider(New(WithHashiCorpReleasesURL(server.URL)), "terraform")
+	ctx := context.Background()
+
+	latest, err := provider.ResolveLatest(ctx)
+	if err != nil {
+		t.Fatalf("ResolveLatest failed: %v", err)
+	}
+	if latest.Version != "1.6.6" || latest.Tag != "1.6.6" {
+		t.Errorf("ResolveLatest() = %+v, want 1.6.6 (prerelease skipped)", latest)
+	}
+
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"1.5", "1.5.7"},
+		{"1.5.0", "1.5.0"},
+		{"~1.5", "1.5.7"},
+		{">=1.5, <1.6", "1.5.7"},
+		{"< 1.0", "0.15.5"},
+	}
+	for _, tt := range tests {
+		info, err := provider.ResolveVersion(ctx, tt.spec)
+		if err != nil {
+			t.Errorf("ResolveVersion(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if info.Version != tt.want {
+			t.Errorf("ResolveVersion(%q) = %s, want %s", tt.spec, info.Version, tt.want)
+		}
+	}
+
+	if _, err := provider.ResolveVersion(ctx, "2"); err == nil {
+		t.Error("expected error for missing version")
+	}
+	if _, err := provider.ResolveVersion(ctx, ">>1"); err == nil {
+		t.Error("expected error for malformed range")
+	}
+}
+
+func TestHashiCorpProvider_UnknownProduct(t *testing.T) {
+	server := newHashiCorpServer(t)
+	defer server.Close()
+
+	provider := NewHashiCorpProvider(New(WithHashiCorpReleasesURL(server.URL)), "nope")
+	_, err := provider.ResolveLatest(context.Background())
+	resolverErr, ok := err.(*ResolverError)
+	if !ok || resolverErr.Type != ErrTypeNotFound {
+		t.Errorf("expected NotFound ResolverError, got %v", err)
+	}
+}
+
+func TestHashiCorpSourceStrategy(t *testing.T) {
+	factory := NewProviderFactory()
+
+	tests := []struct {
+		name     string
+		recipe   *recipe.Recipe
+		wantDesc string
+		wantErr  bool
+	}{
+		{
+			name: "product from recipe name",
+			recipe: &recipe.Recipe{
+				Metadata: recipe.MetadataSection{Name: "terraform"},
+				Version:  recipe.VersionSection{Source: "hashicorp"},
+			},
+			wantDesc: "hashicorp:terraform",
+		},
+		{
+			name: "explicit product",
+			recipe: &recipe.Recipe{
+				Metadata: recipe.MetadataSection{Name: "tf-ls"},
+				Version:  recipe.VersionSection{Source: "hashicorp:terraform-ls"},
+			},
+			wantDesc: "hashicorp:terraform-ls",
+		},
+		{
+			name: "invalid product",
+			recipe: &recipe.Recipe{
+				Metadata: recipe.MetadataSection{Name: "x"},
+				Version:  recipe.VersionSection{Source: "hashicorp:../etc"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := factory.ProviderFromRecipe(New(), tt.recipe)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProviderFromRecipe failed: %v", err)
+			}
+			if got := provider.SourceDescription(); got != tt.wantDesc {
+				t.Errorf("SourceDescription() = %q, want %q", got, tt.wantDesc)
+			}
+		})
+	}
+}
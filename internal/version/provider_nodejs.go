@@ -0,0 +1,141 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// NodeJSDistProvider resolves Node.js versions from the nodejs.org dist index.
+// Implements both VersionResolver and VersionLister interfaces.
+//
+// Besides exact, fuzzy ("20") and range ("^20.10") versions, ResolveVersion
+// understands the nvm-style aliases "lts", "lts/*" and "lts/<codename>"
+// (e.g., "lts/iron"), plus "current" for the newest release.
+type NodeJSDistProvider struct {
+	resolver *Resolver
+}
+
+// NewNodeJSDistProvider creates a provider for Node.js releases
+func NewNodeJSDistProvider(resolver *Resolver) *NodeJSDistProvider {
+	return &NodeJSDistProvider{resolver: resolver}
+}
+
+// nodeVersion pairs a version with its LTS codename
+type nodeVersion struct {
+	info VersionInfo
+	lts  string
+}
+
+// releases returns all releases sorted by version, newest first
+func (p *NodeJSDistProvider) releases(ctx context.Context) ([]nodeVersion, error) {
+	releases, err := p.resolver.ListNodeJSReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  "nodejs_dist",
+			Message: "no Node.js versions found",
+		}
+	}
+
+	// The index is ordered by release date; maintenance releases of older
+	// lines can be newer than the latest major, so order by version instead
+	infos := make([]VersionInfo, 0, len(releases))
+	lts := make(map[string]string, len(releases))
+	for _, rel := range releases {
+		info := VersionInfo{Tag: rel.Version, Version: normalizeVersion(rel.Version)}
+		infos = append(infos, info)
+		lts[info.Tag] = rel.LTS
+	}
+	sortVersionInfos(infos)
+
+	versions := make([]nodeVersion, 0, len(infos))
+	for _, info := range infos {
+		versions = append(versions, nodeVersion{info: info, lts: lts[info.Tag]})
+	}
+	return versions, nil
+}
+
+// ListVersions returns all Node.js versions (newest first)
+func (p *NodeJSDistProvider) ListVersions(ctx context.Context) ([]string, error) {
+	releases, err := p.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(releases))
+	for _, rel := range releases {
+		versions = append(versions, rel.info.Version)
+	}
+	return versions, nil
+}
+
+// ResolveLatest returns the newest LTS version, falling back to the newest release
+func (p *NodeJSDistProvider) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	releases, err := p.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info := newestLTS(releases, ""); info != nil {
+		return info, nil
+	}
+	return &releases[0].info, nil
+}
+
+// ResolveVersion resolves a version, range or LTS alias
+func (p *NodeJSDistProvider) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	releases, err := p.releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := strings.ToLower(strings.TrimSpace(version))
+	switch {
+	case spec == "current":
+		return &releases[0].info, nil
+	case spec == "lts" || spec == "lts/*":
+		if info := newestLTS(releases, ""); info != nil {
+			return info, nil
+		}
+		return nil, fmt.Errorf("no Node.js LTS release found")
+	case strings.HasPrefix(spec, "lts/"):
+		codename := strings.TrimPrefix(spec, "lts/")
+		if info := newestLTS(releases, codename); info != nil {
+			return info, nil
+		}
+		return nil, fmt.Errorf("unknown Node.js LTS codename: %s", codename)
+	}
+
+	infos := make([]VersionInfo, 0, len(releases))
+	for _, rel := range releases {
+		infos = append(infos, rel.info)
+	}
+	info, err := selectVersion(infos, version)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("Node.js version %s not found", version)
+	}
+	return info, nil
+}
+
+// SourceDescription returns a human-readable source description
+func (p *NodeJSDistProvider) SourceDescription() string {
+	return "nodejs_dist"
+}
+
+// newestLTS returns the newest LTS release, restricted to codename if non-empty
+func newestLTS(releases []nodeVersion, codename string) *VersionInfo {
+	for i, rel := range releases {
+		if rel.lts == "" {
+			continue
+		}
+		if codename == "" || strings.EqualFold(rel.lts, codename) {
+			return &releases[i].info
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// nodeIndexJSON mimics nodejs.org/dist/index.json: ordered by release date, so a
+// maintenance release of an older line appears before newer majors
+const nodeIndexJSON = `[
+	{"version": "v18.20.5", "lts": "Hydrogen"},
+	{"version": "v22.1.0", "lts": false},
+	{"version": "v20.12.2", "lts": "Iron"},
+	{"version": "v20.12.1", "lts": "Iron"},
+	{"version": "v21.7.3", "lts": false},
+	{"version": "v20.9.0", "lts": "Iron"},
+	{"version": "v18.20.4", "lts": "Hydrogen"}
+]`
+
+func newNodeJSDistServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dist/index.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(nodeIndexJSON))
+	}))
+}
+
+func TestNodeJSDistProvider_ListVersions(t *testing.T) {
+	server := newNodeJSDistServer(t)
+	defer server.Close()
+
+	provider := NewNodeJSDistProvider(New(WithNodeJSDistURL(server.URL)))
+	versions, err := provider.ListVersions(context.Background())
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	want := []string{"22.1.0", "21.7.3", "20.12.2", "20.12.1", "20.9.0", "18.20.5", "18.20.4"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("ListVersions() = %v, want %v", versions, want)
+	}
+}
+
+func TestNodeJSDistProvider_ResolveLatest(t *testing.T) {
+	server := newNodeJSDistServer(t)
+	defer server.Close()
+
+	provider := NewNodeJSDistProvider(New(WithNodeJSDistURL(server.URL)))
+	info, err := provider.ResolveLatest(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveLatest failed: %v", err)
+	}
+	if info.Version != "20.12.2" || info.Tag != "v20.12.2" {
+		t.Errorf("ResolveLatest() = %+v, want newest LTS 20.12.2 (tag v20.12.2)", info)
+	}
+}
+
+func TestNodeJSDistProvider_ResolveVersion(t *testing.T) {
+	server := newNodeJSDistServer(t)
+	defer server.Close()
+
+	provider := NewNodeJSDistProvider(New(WithNodeJSDistURL(server.URL)))
+
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{"20.9.0", "20.9.0", false},
+		{"v20.9.0", "20.9.0", false},
+		{"20", "20.12.2", false},
+		{"18.20", "18.20.5", false},
+		{"^20.10", "20.12.2", false},
+		{">=18, <20", "18.20.5", false},
+		{"20.x", "20.12.2", false},
+		{"lts", "20.12.2", false},
+		{"lts/*", "20.12.2", false},
+		{"lts/hydrogen", "18.20.5", false},
+		{"LTS/Iron", "20.12.2", false},
+		{"current", "22.1.0", false},
+		{"lts/argon", "", true},
+		{"19", "", true},
+		{">=23", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			info, err := provider.ResolveVersion(context.Background(), tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResolveVersion(%q) = %+v, want error", tt.spec, info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveVersion(%q) failed: %v", tt.spec, err)
+			}
+			if info.Version != tt.want {
+				t.Errorf("ResolveVersion(%q) = %s, want %s", tt.spec, info.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeJSDistSourceStrategy(t *testing.T) {
+	server := newNodeJSDistServer(t)
+	defer server.Close()
+
+	resolver := New(WithNodeJSDistURL(server.URL))
+	provider, err := NewProviderFactory().ProviderFromRecipe(resolver, &recipe.Recipe{
+		Metadata: recipe.MetadataSection{Name: "nodejs"},
+		Version:  recipe.VersionSection{Source: "nodejs_dist"},
+	})
+	if err != nil {
+		t.Fatalf("ProviderFromRecipe failed: %v", err)
+	}
+	if _, ok := provider.(VersionLister); !ok {
+		t.Errorf("nodejs_dist provider %T should implement VersionLister", provider)
+	}
+
+	// The custom registry path resolves specific versions through the same provider
+	info, err := resolver.ResolveCustomVersion(context.Background(), "nodejs_dist", "lts/hydrogen")
+	if err != nil {
+		t.Fatalf("ResolveCustomVersion failed: %v", err)
+	}
+	if info.Tag != "v18.20.5" {
+		t.Errorf("ResolveCustomVersion() tag = %q, want v18.20.5", info.Tag)
+	}
+}
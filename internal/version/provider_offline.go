@@ -0,0 +1,96 @@
+package version
+
+import (
+	"context"
+	"fmt"
+)
+
+// OfflineProvider answers version queries from the cached version list of
+// the provider it wraps, without network access. It is used in offline mode
+// (--offline / TSUKU_OFFLINE). The cache is the one kept by
+// CachedVersionLister and by online version resolution; expired entries are
+// still used.
+type OfflineProvider struct {
+	underlying VersionResolver
+	cacheDir   string
+}
+
+// NewOfflineProvider wraps provider to resolve versions from cacheDir only
+func NewOfflineProvider(provider VersionResolver, cacheDir string) *OfflineProvider {
+	return &OfflineProvider{underlying: provider, cacheDir: cacheDir}
+}
+
+// ListVersions returns the cached version list
+func (p *OfflineProvider) ListVersions(ctx context.Context) ([]string, error) {
+	if p.cacheDir == "" {
+		return nil, p.offlineError("no version cache configured")
+	}
+	entry, err := readCacheEntry(versionCacheFile(p.cacheDir, p.SourceDescription()))
+	if err != nil {
+		return nil, p.offlineError("no cached version list")
+	}
+	return entry.Versions, nil
+}
+
+// ResolveLatest returns the newest stable cached version
+func (p *OfflineProvider) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	infos, err := p.versionInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, info := range infos {
+		if isStableVersion(info.Version) {
+			return &infos[i], nil
+		}
+	}
+	return &infos[0], nil
+}
+
+// ResolveVersion resolves a version, prefix or range against the cached versions
+func (p *OfflineProvider) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	infos, err := p.versionInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, err := selectVersion(infos, version)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, p.offlineError(fmt.Sprintf("version %s is not in the cached version list", version))
+	}
+	return info, nil
+}
+
+// SourceDescription returns the underlying provider's source description
+func (p *OfflineProvider) SourceDescription() string {
+	return p.underlying.SourceDescription()
+}
+
+// versionInfos returns the cached versions, sorted newest first
+func (p *OfflineProvider) versionInfos(ctx context.Context) ([]VersionInfo, error) {
+	versions, err := p.ListVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var infos []VersionInfo
+	for _, v := range versions {
+		normalized := normalizeVersion(v)
+		if isValidVersion(normalized) {
+			infos = append(infos, VersionInfo{Tag: v, Version: normalized})
+		}
+	}
+	if len(infos) == 0 {
+		return nil, p.offlineError("cached version list is empty")
+	}
+	sortVersionInfos(infos)
+	return infos, nil
+}
+
+func (p *OfflineProvider) offlineError(message string) error {
+	return &ResolverError{
+		Type:    ErrTypeOffline,
+		Source:  p.SourceDescription(),
+		Message: "offline mode: " + message,
+	}
+}
@@ -0,0 +1,213 @@
+package version
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+// primeVersionCache stores versions in cacheDir the way `tsuku versions` does
+func primeVersionCache(t *testing.T, cacheDir string, lister VersionLister) {
+	t.Helper()
+	cached := NewCachedVersionLister(lister, cacheDir, time.Nanosecond)
+	if _, err := cached.ListVersions(context.Background()); err != nil {
+		t.Fatalf("failed to prime cache: %v", err)
+	}
+	// Let the entry expire: offline mode must still use it
+	time.Sleep(time.Millisecond)
+}
+
+func TestOfflineProvider_UsesExpiredCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	mock := &mockVersionLister{
+		versions: []string{"2.0.0-rc.1", "1.2.0", "1.1.5", "1.1.0", "0.9.0"},
+	}
+	primeVersionCache(t, cacheDir, mock)
+	calls := mock.callCount
+
+	p := NewOfflineProvider(mock, cacheDir)
+	ctx := context.Background()
+
+	latest, err := p.ResolveLatest(ctx)
+	if err != nil {
+		t.Fatalf("ResolveLatest() error = %v", err)
+	}
+	if latest.Version != "1.2.0" {
+		t.Errorf("ResolveLatest() = %s, want 1.2.0 (newest stable)", latest.Version)
+	}
+
+	fuzzy, err := p.ResolveVersion(ctx, "1.1")
+	if err != nil {
+		t.Fatalf("ResolveVersion(1.1) error = %v", err)
+	}
+	if fuzzy.Version != "1.1.5" {
+		t.Errorf("ResolveVersion(1.1) = %s, want 1.1.5", fuzzy.Version)
+	}
+
+	versions, err := p.ListVersions(ctx)
+	if err != nil {
+		t.Fatalf("ListVersions() error = %v", err)
+	}
+	if len(versions) != 5 {
+		t.Errorf("ListVersions() returned %d versions, want 5", len(versions))
+	}
+
+	if mock.callCount != calls {
+		t.Errorf("underlying provider called %d times in offline mode, want 0", mock.callCount-calls)
+	}
+}
+
+func TestOfflineProvider_MissingVersion(t *testing.T) {
+	cacheDir := t.TempDir()
+	mock := &mockVersionLister{versions: []string{"1.0.0"}}
+	primeVersionCache(t, cacheDir, mock)
+
+	p := NewOfflineProvider(mock, cacheDir)
+	_, err := p.ResolveVersion(context.Background(), "3.0.0")
+	assertOfflineError(t, err, "3.0.0")
+}
+
+func TestOfflineProvider_NoCache(t *testing.T) {
+	mock := &mockVersionLister{versions: []string{"1.0.0"}}
+	p := NewOfflineProvider(mock, t.TempDir())
+
+	_, err := p.ResolveLatest(context.Background())
+	assertOfflineError(t, err, "no cached version list")
+	if mock.callCount != 0 {
+		t.Errorf("underlying provider called %d times, want 0", mock.callCount)
+	}
+}
+
+func TestOfflineProvider_UsesVersionsResolvedOnline(t *testing.T) {
+	cacheDir := t.TempDir()
+	ctx := context.Background()
+
+	// Sources that cannot list versions are still cached by online resolution
+	offline := NewOfflineProvider(&mockResolverOnly{}, cacheDir)
+	_, err := offline.ResolveVersion(ctx, "14")
+	assertOfflineError(t, err, "no cached version list")
+
+	online := newRecordingProvider(&mockResolverOnly{}, cacheDir)
+	if _, err := online.ResolveLatest(ctx); err != nil {
+		t.Fatalf("ResolveLatest() error = %v", err)
+	}
+
+	info, err := offline.ResolveVersion(ctx, "14")
+	if err != nil {
+		t.Fatalf("offline ResolveVersion(14) error = %v", err)
+	}
+	if info.Version != "14.1.0" || info.Tag != "v14.1.0" {
+		t.Errorf("offline ResolveVersion(14) = %s (tag %s), want 14.1.0 (tag v14.1.0)", info.Version, info.Tag)
+	}
+
+	// A recorded version is not a full list: `tsuku versions` must still fetch
+	entry, err := readCacheEntry(versionCacheFile(cacheDir, "mock:resolver-only"))
+	if err != nil {
+		t.Fatalf("failed to read cache entry: %v", err)
+	}
+	if time.Now().Before(entry.ExpiresAt) {
+		t.Errorf("recorded entry expires at %v, want it already expired", entry.ExpiresAt)
+	}
+}
+
+func TestRecordingProvider_KeepsCachedList(t *testing.T) {
+	cacheDir := t.TempDir()
+	ctx := context.Background()
+	mock := &mockVersionLister{versions: []string{"1.1.0", "1.0.0"}}
+	cached := NewCachedVersionLister(mock, cacheDir, time.Hour)
+	if _, err := cached.ListVersions(ctx); err != nil {
+		t.Fatalf("failed to prime cache: %v", err)
+	}
+	before := cached.GetCacheInfo()
+
+	provider := newRecordingProvider(mock, cacheDir)
+	if _, ok := provider.(VersionLister); !ok {
+		t.Fatalf("newRecordingProvider() = %T, want a VersionLister", provider)
+	}
+	for _, v := range []string{"1.2.0", "1.2.0", "1.0.0"} {
+		if _, err := provider.ResolveVersion(ctx, v); err != nil {
+			t.Fatalf("ResolveVersion(%s) error = %v", v, err)
+		}
+	}
+
+	after := cached.GetCacheInfo()
+	if !after.ExpiresAt.Equal(before.ExpiresAt) {
+		t.Errorf("recording changed expiry from %v to %v", before.ExpiresAt, after.ExpiresAt)
+	}
+	entry, err := readCacheEntry(cached.cacheFilePath())
+	if err != nil {
+		t.Fatalf("failed to read cache entry: %v", err)
+	}
+	want := []string{"1.1.0", "1.0.0", "1.2.0"}
+	if strings.Join(entry.Versions, ",") != strings.Join(want, ",") {
+		t.Errorf("cached versions = %v, want %v", entry.Versions, want)
+	}
+}
+
+func TestProviderFromRecipe_Offline(t *testing.T) {
+	resolver := New(WithOffline(t.TempDir()))
+	factory := NewProviderFactory()
+	r := &recipe.Recipe{
+		Version: recipe.VersionSection{Source: "nodejs_dist"},
+	}
+
+	provider, err := factory.ProviderFromRecipe(resolver, r)
+	if err != nil {
+		t.Fatalf("ProviderFromRecipe() error = %v", err)
+	}
+	if _, ok := provider.(*OfflineProvider); !ok {
+		t.Fatalf("ProviderFromRecipe() = %T, want *OfflineProvider", provider)
+	}
+}
+
+func TestProviderFromRecipe_RecordsVersions(t *testing.T) {
+	resolver := New(WithVersionCache(t.TempDir()))
+	factory := NewProviderFactory()
+	r := &recipe.Recipe{
+		Version: recipe.VersionSection{Source: "nodejs_dist"},
+	}
+
+	provider, err := factory.ProviderFromRecipe(resolver, r)
+	if err != nil {
+		t.Fatalf("ProviderFromRecipe() error = %v", err)
+	}
+	if _, ok := provider.(*recordingLister); !ok {
+		t.Fatalf("ProviderFromRecipe() = %T, want *recordingLister", provider)
+	}
+}
+
+func assertOfflineError(t *testing.T, err error, contains string) {
+	t.Helper()
+	if err == nil {
+		t.Fatal("expected an error in offline mode")
+	}
+	var resolverErr *ResolverError
+	if !errors.As(err, &resolverErr) || resolverErr.Type != ErrTypeOffline {
+		t.Fatalf("error = %v, want ResolverError of type ErrTypeOffline", err)
+	}
+	if !strings.Contains(err.Error(), contains) {
+		t.Errorf("error = %q, want it to contain %q", err.Error(), contains)
+	}
+	if resolverErr.Suggestion() == "" {
+		t.Error("offline error should carry a suggestion")
+	}
+}
+
+// mockResolverOnly is a VersionResolver that cannot list versions
+type mockResolverOnly struct{}
+
+func (m *mockResolverOnly) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	return &VersionInfo{Version: "14.1.0", Tag: "v14.1.0"}, nil
+}
+
+func (m *mockResolverOnly) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	return &VersionInfo{Version: version}, nil
+}
+
+func (m *mockResolverOnly) SourceDescription() string {
+	return "mock:resolver-only"
+}
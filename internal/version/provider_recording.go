@@ -0,0 +1,64 @@
+package version
+
+import "context"
+
+// recordingResolver records every version its provider resolves in the
+// version cache, so that offline mode can resolve it later without a
+// prior 'tsuku versions' run.
+type recordingResolver struct {
+	underlying VersionResolver
+	cacheDir   string
+}
+
+// recordingLister is a recordingResolver for providers that can list versions.
+// Keeping it a separate type preserves the VersionLister type assertion.
+type recordingLister struct {
+	recordingResolver
+	lister VersionLister
+}
+
+// newRecordingProvider wraps provider to record resolved versions in cacheDir
+func newRecordingProvider(provider VersionResolver, cacheDir string) VersionResolver {
+	r := recordingResolver{underlying: provider, cacheDir: cacheDir}
+	if lister, ok := provider.(VersionLister); ok {
+		return &recordingLister{recordingResolver: r, lister: lister}
+	}
+	return &r
+}
+
+// ResolveLatest resolves the latest version and records it
+func (p *recordingResolver) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	return p.record(p.underlying.ResolveLatest(ctx))
+}
+
+// ResolveVersion resolves a specific version and records it
+func (p *recordingResolver) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	return p.record(p.underlying.ResolveVersion(ctx, version))
+}
+
+// SourceDescription returns the underlying provider's source description
+func (p *recordingResolver) SourceDescription() string {
+	return p.underlying.SourceDescription()
+}
+
+// ListVersions delegates to the underlying provider
+func (p *recordingLister) ListVersions(ctx context.Context) ([]string, error) {
+	return p.lister.ListVersions(ctx)
+}
+
+// record adds a successfully resolved version to the cache (best effort)
+func (p *recordingResolver) record(info *VersionInfo, err error) (*VersionInfo, error) {
+	if err != nil || info == nil {
+		return info, err
+	}
+	// Prefer the tag so offline resolution can rebuild it, unless it isn't
+	// recognizable as a version (e.g. "release-2024-01")
+	tag := info.Tag
+	if !isValidVersion(normalizeVersion(tag)) {
+		tag = info.Version
+	}
+	if tag != "" {
+		_ = recordVersion(p.cacheDir, p.underlying.SourceDescription(), tag)
+	}
+	return info, nil
+}
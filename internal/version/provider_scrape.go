@@ -0,0 +1,154 @@
+package version
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ScrapeProvider resolves versions by extracting them from a vendor download page
+// (source = "url_regex") or an arbitrary JSON manifest (source = "json_path").
+// Implements both VersionResolver and VersionLister interfaces.
+type ScrapeProvider struct {
+	resolver  *Resolver
+	source    string // "url_regex" or "json_path"
+	url       string
+	expr      string // regex or JSONPath expression, as written in the recipe
+	tagPrefix string
+	extract   func(body []byte) ([]string, error)
+}
+
+// NewURLRegexProvider creates a provider that matches versions in a page with a regular expression.
+// The capture group named "version" (or else the first group, or else the whole match) is the version.
+func NewURLRegexProvider(resolver *Resolver, pageURL, pattern, tagPrefix string) (*ScrapeProvider, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version regex: %w", err)
+	}
+	return &ScrapeProvider{
+		resolver:  resolver,
+		source:    "url_regex",
+		url:       pageURL,
+		expr:      pattern,
+		tagPrefix: tagPrefix,
+		extract: func(body []byte) ([]string, error) {
+			return extractRegexVersions(re, body), nil
+		},
+	}, nil
+}
+
+// NewJSONPathProvider creates a provider that selects versions from a JSON document
+// with a JSONPath expression (e.g., "$.releases[*].version")
+func NewJSONPathProvider(resolver *Resolver, manifestURL, expr, tagPrefix string) (*ScrapeProvider, error) {
+	jp, err := compileJSONPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &ScrapeProvider{
+		resolver:  resolver,
+		source:    "json_path",
+		url:       manifestURL,
+		expr:      expr,
+		tagPrefix: tagPrefix,
+		extract: func(body []byte) ([]string, error) {
+			return extractJSONPathVersions(jp, body)
+		},
+	}, nil
+}
+
+// versions fetches the page and returns extracted versions, newest first
+func (p *ScrapeProvider) versions(ctx context.Context) ([]VersionInfo, error) {
+	body, err := p.resolver.FetchVersionPage(ctx, p.source, p.url)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := p.extract(body)
+	if err != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeParsing,
+			Source:  p.source,
+			Message: fmt.Sprintf("failed to parse %s", p.url),
+			Err:     err,
+		}
+	}
+
+	// Download pages usually mention each version several times
+	seen := make(map[string]bool)
+	var infos []VersionInfo
+	for _, tag := range raw {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, p.tagPrefix) {
+			continue
+		}
+		v := normalizeVersion(strings.TrimPrefix(tag, p.tagPrefix))
+		if !isValidVersion(v) || seen[v] {
+			continue
+		}
+		seen[v] = true
+		infos = append(infos, VersionInfo{Tag: tag, Version: v})
+	}
+
+	if len(infos) == 0 {
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  p.source,
+			Message: fmt.Sprintf("no versions matched %q in %s", p.expr, p.url),
+		}
+	}
+
+	sortVersionInfos(infos)
+	return infos, nil
+}
+
+// ListVersions returns all extracted versions (newest first)
+func (p *ScrapeProvider) ListVersions(ctx context.Context) ([]string, error) {
+	infos, err := p.versions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(infos))
+	for _, info := range infos {
+		versions = append(versions, info.Version)
+	}
+	return versions, nil
+}
+
+// ResolveLatest returns the newest stable extracted version
+func (p *ScrapeProvider) ResolveLatest(ctx context.Context) (*VersionInfo, error) {
+	infos, err := p.versions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i, info := range infos {
+		if isStableVersion(info.Version) {
+			return &infos[i], nil
+		}
+	}
+
+	// Fallback to newest version if no stable version found
+	return &infos[0], nil
+}
+
+// ResolveVersion resolves a specific version.
+// Supports fuzzy matching (e.g., "21" matches "21.0.2")
+func (p *ScrapeProvider) ResolveVersion(ctx context.Context, version string) (*VersionInfo, error) {
+	infos, err := p.versions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if info, ok := matchVersionInfo(infos, version); ok {
+		return info, nil
+	}
+	return nil, fmt.Errorf("version %s not found at %s", version, p.url)
+}
+
+// SourceDescription returns a human-readable source description.
+// The expression is included so that version caches of recipes sharing a URL don't collide.
+func (p *ScrapeProvider) SourceDescription() string {
+	if p.tagPrefix != "" {
+		return fmt.Sprintf("%s:%s (%s, prefix %q)", p.source, p.url, p.expr, p.tagPrefix)
+	}
+	return fmt.Sprintf("%s:%s (%s)", p.source, p.url, p.expr)
+}
@@ -0,0 +1,229 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tsukumogami/tsuku/internal/recipe"
+)
+
+const downloadPageHTML = `<html><body>
+<h2>Downloads</h2>
+<a href="/dl/sdk-24.1.2-linux-x64.tar.gz">sdk-24.1.2-linux-x64.tar.gz</a>
+<a href="/dl/sdk-24.1.2-macos-arm64.tar.gz">sdk-24.1.2-macos-arm64.tar.gz</a>
+<a href="/dl/sdk-24.10.0-rc1-linux-x64.tar.gz">sdk-24.10.0-rc1-linux-x64.tar.gz</a>
+<a href="/dl/sdk-24.9.0-linux-x64.tar.gz">sdk-24.9.0-linux-x64.tar.gz</a>
+<a href="/dl/sdk-23.4.0-linux-x64.tar.gz">sdk-23.4.0-linux-x64.tar.gz</a>
+</body></html>`
+
+const versionManifestJSON = `{"channels": {
+	"stable": {"releases": [{"version": "v3.1.0"}, {"version": "v3.0.4"}]},
+	"beta": {"releases": [{"version": "v3.2.0-beta.1"}]}
+}}`
+
+// newScrapeServer serves body over TLS and counts requests
+func newScrapeServer(t *testing.T, body string, hits *int32) (*httptest.Server, *Resolver) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			atomic.AddInt32(hits, 1)
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	resolver := New()
+	resolver.httpClient = server.Client()
+	return server, resolver
+}
+
+func TestExtractRegexVersions(t *testing.T) {
+	body := []byte("tool-1.0.tar.gz tool-1.1.tar.gz")
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{`tool-[\d.]+\d`, []string{"tool-1.0", "tool-1.1"}},
+		{`tool-([\d.]+\d)`, []string{"1.0", "1.1"}},
+		{`(tool)-(?P<version>[\d.]+\d)`, []string{"1.0", "1.1"}},
+	}
+	for _, tt := range tests {
+		re := regexp.MustCompile(tt.pattern)
+		if got := extractRegexVersions(re, body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("extractRegexVersions(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestURLRegexProvider(t *testing.T) {
+	server, resolver := newScrapeServer(t, downloadPageHTML, nil)
+	defer server.Close()
+
+	provider, err := NewURLRegexProvider(resolver, server.URL+"/downloads", `sdk-(\d+\.\d+\.\d+(?:-rc\d+)?)-linux`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	versions, err := provider.ListVersions(ctx)
+	if err != nil {
+		t.Fatalf("ListVersions failed: %v", err)
+	}
+	want := []string{"24.10.0-rc1", "24.9.0", "24.1.2", "23.4.0"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Errorf("ListVersions() = %v, want %v", versions, want)
+	}
+
+	latest, err := provider.ResolveLatest(ctx)
+	if err != nil {
+		t.Fatalf("ResolveLatest failed: %v", err)
+	}
+	if latest.Version != "24.9.0" {
+		t.Errorf("ResolveLatest() = %s, want 24.9.0 (prerelease skipped)", latest.Version)
+	}
+
+	info, err := provider.ResolveVersion(ctx, "23")
+	if err != nil {
+		t.Fatalf("ResolveVersion failed: %v", err)
+	}
+	if info.Version != "23.4.0" {
+		t.Errorf("ResolveVersion(23) = %s, want 23.4.0", info.Version)
+	}
+}
+
+func TestURLRegexProvider_NoMatches(t *testing.T) {
+	server, resolver := newScrapeServer(t, "<html>maintenance</html>", nil)
+	defer server.Close()
+
+	provider, err := NewURLRegexProvider(resolver, server.URL, `sdk-([\d.]+)`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = provider.ResolveLatest(context.Background())
+	resolverErr, ok := err.(*ResolverError)
+	if !ok || resolverErr.Type != ErrTypeNotFound {
+		t.Errorf("expected NotFound ResolverError, got %v", err)
+	}
+}
+
+func TestJSONPathProvider(t *testing.T) {
+	var hits int32
+	server, resolver := newScrapeServer(t, versionManifestJSON, &hits)
+	defer server.Close()
+
+	provider, err := NewJSONPathProvider(resolver, server.URL+"/versions.json", "$.channels.stable.releases[*].version", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := provider.ResolveLatest(context.Background())
+	if err != nil {
+		t.Fatalf("ResolveLatest failed: %v", err)
+	}
+	if latest.Version != "3.1.0" || latest.Tag != "v3.1.0" {
+		t.Errorf("ResolveLatest() = %+v, want 3.1.0 (tag v3.1.0)", latest)
+	}
+
+	// Listing goes through the version cache
+	cached := NewCachedVersionLister(provider, t.TempDir(), time.Hour)
+	for i := 0; i < 2; i++ {
+		versions, err := cached.ListVersions(context.Background())
+		if err != nil {
+			t.Fatalf("ListVersions failed: %v", err)
+		}
+		if !reflect.DeepEqual(versions, []string{"3.1.0", "3.0.4"}) {
+			t.Errorf("ListVersions() = %v", versions)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("server hit %d times, want 2 (one resolve, one uncached list)", got)
+	}
+}
+
+func TestJSONPathProvider_InvalidJSON(t *testing.T) {
+	server, resolver := newScrapeServer(t, "<html>not json</html>", nil)
+	defer server.Close()
+
+	provider, err := NewJSONPathProvider(resolver, server.URL, "$.version", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = provider.ListVersions(context.Background())
+	resolverErr, ok := err.(*ResolverError)
+	if !ok || resolverErr.Type != ErrTypeParsing {
+		t.Errorf("expected Parsing ResolverError, got %v", err)
+	}
+}
+
+func TestFetchVersionPage_RequiresHTTPS(t *testing.T) {
+	for _, u := range []string{"http://example.com/downloads", "ftp://example.com", "https://user:pw@example.com/", "/relative"} {
+		_, err := New().FetchVersionPage(context.Background(), "url_regex", u)
+		resolverErr, ok := err.(*ResolverError)
+		if !ok || resolverErr.Type != ErrTypeValidation {
+			t.Errorf("FetchVersionPage(%q) = %v, want validation error", u, err)
+		}
+	}
+}
+
+func TestScrapeSourceStrategies(t *testing.T) {
+	factory := NewProviderFactory()
+
+	tests := []struct {
+		name     string
+		version  recipe.VersionSection
+		wantDesc string
+		wantErr  string
+	}{
+		{
+			name:     "url_regex",
+			version:  recipe.VersionSection{Source: "url_regex", URL: "https://example.com/dl", Regex: `tool-([\d.]+)`},
+			wantDesc: `url_regex:https://example.com/dl (tool-([\d.]+))`,
+		},
+		{
+			name:     "json_path",
+			version:  recipe.VersionSection{Source: "json_path", URL: "https://example.com/v.json", JSONPath: "$[*].version", TagPrefix: "jdk-"},
+			wantDesc: `json_path:https://example.com/v.json ($[*].version, prefix "jdk-")`,
+		},
+		{
+			name:    "invalid regex",
+			version: recipe.VersionSection{Source: "url_regex", URL: "https://example.com/dl", Regex: `tool-(`},
+			wantErr: "invalid version regex",
+		},
+		{
+			name:    "invalid JSONPath",
+			version: recipe.VersionSection{Source: "json_path", URL: "https://example.com/v.json", JSONPath: "version"},
+			wantErr: "must start with '$'",
+		},
+		{
+			name:    "plain http",
+			version: recipe.VersionSection{Source: "json_path", URL: "http://example.com/v.json", JSONPath: "$.version"},
+			wantErr: "https://",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := factory.ProviderFromRecipe(New(), &recipe.Recipe{Version: tt.version})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProviderFromRecipe failed: %v", err)
+			}
+			if got := provider.SourceDescription(); got != tt.wantDesc {
+				t.Errorf("SourceDescription() = %q, want %q", got, tt.wantDesc)
+			}
+			if _, ok := provider.(VersionLister); !ok {
+				t.Error("scrape providers should implement VersionLister")
+			}
+		})
+	}
+}
@@ -26,6 +26,10 @@ var actionInference = map[string]string{
 	"cpan_install":   "metacpan",
 	"github_archive": "github_releases",
 	"github_file":    "github_releases",
+	"gitlab_archive": "gitlab_releases",
+	"gitlab_file":    "gitlab_releases",
+	"gitea_archive":  "gitea_releases",
+	"gitea_file":     "gitea_releases",
 	"go_install":     "goproxy",
 }
 
@@ -23,7 +23,8 @@ type Registry struct {
 
 // NewRegistry creates a registry with default resolvers
 // Default resolvers include nodejs_dist for Node.js and npm packages
-// Policy: Only add custom resolvers for multi-use sources; use github_file for single-use cases
+// Policy: Only add custom resolvers for multi-use sources. Vendor pages and manifests
+// should use the declarative url_regex/json_path sources instead of new registry entries.
 func NewRegistry() *Registry {
 	return &Registry{
 		resolvers: map[string]ResolverFunc{
@@ -32,8 +33,9 @@ func NewRegistry() *Registry {
 			},
 		},
 		versionResolvers: map[string]VersionResolverFunc{
-			// nodejs_dist doesn't support specific versions yet
-			// Add version resolvers here when custom sources need them
+			"nodejs_dist": func(ctx context.Context, r *Resolver, version string) (*VersionInfo, error) {
+				return NewNodeJSDistProvider(r).ResolveVersion(ctx, version)
+			},
 		},
 	}
 }
@@ -38,7 +38,12 @@ type Resolver struct {
 	homebrewRegistryURL string         // Homebrew API URL (injectable for testing)
 	goDevURL            string         // go.dev URL (injectable for testing)
 	goProxyURL          string         // Go module proxy URL (injectable for testing)
+	gitlabURL           string         // Default GitLab instance URL (injectable for testing)
+	nodejsDistURL       string         // Node.js dist site URL (injectable for testing)
+	hashicorpURL        string         // HashiCorp releases URL (injectable for testing)
 	authenticated       bool           // Whether GitHub requests are authenticated
+	offline             bool           // Answer only from cached version lists
+	versionCacheDir     string         // Version list cache (written online, read in offline mode)
 }
 
 // NewHTTPClient creates an HTTP client with security hardening and proper timeouts.
@@ -92,9 +97,20 @@ func New(opts ...Option) *Resolver {
 		metacpanRegistryURL: "https://fastapi.metacpan.org/v1", // Production default
 		goDevURL:            "https://go.dev",                  // Production default
 		goProxyURL:          "https://proxy.golang.org",        // Production default
+		gitlabURL:           DefaultGitLabURL,                  // Production default
+		nodejsDistURL:       "https://nodejs.org",              // Production default
+		hashicorpURL:        "https://releases.hashicorp.com",  // Production default
 		authenticated:       authenticated,
 	}
 
+	// Offline mode (--offline / TSUKU_OFFLINE) restricts resolution to the version cache
+	if config.IsOffline() {
+		r.offline = true
+		if cfg, err := config.DefaultConfig(); err == nil {
+			r.versionCacheDir = cfg.VersionCacheDir
+		}
+	}
+
 	// Apply options
 	for _, opt := range opts {
 		opt(r)
@@ -316,29 +332,6 @@ func (r *Resolver) ListGitHubVersions(ctx context.Context, repo string) ([]strin
 	return versions, nil
 }
 
-// ResolveHashiCorp resolves the latest version from HashiCorp releases
-// For now, this is a placeholder - real implementation would query releases.hashicorp.com
-func (r *Resolver) ResolveHashiCorp(ctx context.Context, product string) (string, error) {
-	// Placeholder: In production, this would fetch from https://releases.hashicorp.com/{product}/
-	// For validation, we'll use common known versions as fallback
-	knownVersions := map[string]string{
-		"terraform": "1.6.6",
-		"vault":     "1.15.4",
-		"consul":    "1.17.1",
-		"nomad":     "1.7.2",
-		"packer":    "1.10.0",
-		"boundary":  "0.15.0",
-		"waypoint":  "0.11.4",
-		"vagrant":   "2.4.0",
-	}
-
-	if version, ok := knownVersions[product]; ok {
-		return version, nil
-	}
-
-	return "", fmt.Errorf("unknown HashiCorp product: %s (version resolution not implemented)", product)
-}
-
 // Go toolchain API response structure
 type goRelease struct {
 	Version string `json:"version"` // e.g., "go1.23.4"
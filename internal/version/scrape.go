@@ -0,0 +1,124 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+// maxScrapeResponseSize limits download pages and manifests to prevent memory exhaustion (10MB)
+const maxScrapeResponseSize = 10 * 1024 * 1024
+
+// FetchVersionPage downloads a vendor download page or version manifest.
+//
+// Only HTTPS URLs are accepted; redirects are validated by the resolver's secure
+// HTTP client (HTTPS-only, SSRF protection). The body is capped at 10MB.
+func (r *Resolver) FetchVersionPage(ctx context.Context, source, pageURL string) ([]byte, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  source,
+			Message: fmt.Sprintf("invalid URL %q: must be an absolute https:// URL", pageURL),
+		}
+	}
+	if u.User != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeValidation,
+			Source:  source,
+			Message: "URL must not contain credentials",
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  source,
+			Message: "failed to create request",
+			Err:     err,
+		}
+	}
+	req.Header.Set("User-Agent", "tsuku/1.0 (https://github.com/tsukumogami/tsuku)")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, WrapNetworkError(err, source, fmt.Sprintf("failed to fetch %s", u.Host))
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, &ResolverError{
+			Type:    ErrTypeNotFound,
+			Source:  source,
+			Message: fmt.Sprintf("page not found: %s", pageURL),
+		}
+	case http.StatusTooManyRequests:
+		return nil, &ResolverError{
+			Type:    ErrTypeRateLimit,
+			Source:  source,
+			Message: fmt.Sprintf("%s rate limit exceeded", u.Host),
+		}
+	default:
+		return nil, &ResolverError{
+			Type:    ErrTypeNetwork,
+			Source:  source,
+			Message: fmt.Sprintf("%s returned status %d", u.Host, resp.StatusCode),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxScrapeResponseSize))
+	if err != nil {
+		return nil, WrapNetworkError(err, source, "failed to read response")
+	}
+	return body, nil
+}
+
+// extractRegexVersions returns the version strings matched by re in body.
+// The capture group named "version" is used if present, otherwise the first
+// capture group, otherwise the whole match.
+func extractRegexVersions(re *regexp.Regexp, body []byte) []string {
+	group := 0
+	if idx := re.SubexpIndex("version"); idx > 0 {
+		group = idx
+	} else if re.NumSubexp() > 0 {
+		group = 1
+	}
+
+	var versions []string
+	for _, m := range re.FindAllSubmatch(body, -1) {
+		if v := string(m[group]); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// extractJSONPathVersions evaluates jp against a JSON document and returns the
+// string (or numeric) values it selects. Objects and arrays are ignored.
+func extractJSONPathVersions(jp *jsonPath, body []byte) ([]string, error) {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, v := range jp.evaluate(doc) {
+		switch val := v.(type) {
+		case string:
+			if val != "" {
+				versions = append(versions, val)
+			}
+		case float64:
+			versions = append(versions, strconv.FormatFloat(val, 'f', -1, 64))
+		}
+	}
+	return versions, nil
+}
@@ -35,6 +35,11 @@ func (v *FactoryValidator) KnownSources() []string {
 	return []string{
 		"github_releases",
 		"github_tags",
+		"gitlab_releases",
+		"gitea_releases",
+		"git_tags",
+		"url_regex",
+		"json_path",
 		"pypi",
 		"crates_io",
 		"npm",
@@ -64,11 +69,12 @@ func (v *FactoryValidator) ValidateVersionConfig(r *recipe.Recipe) error {
 		for _, step := range r.Steps {
 			switch step.Action {
 			case "npm_install", "pipx_install", "cargo_install", "gem_install",
-				"cpan_install", "go_install", "github_archive", "github_file":
+				"cpan_install", "go_install", "github_archive", "github_file",
+				"gitlab_archive", "gitlab_file", "gitea_archive", "gitea_file":
 				// There's an action that could infer version, but it's missing required params
 				return fmt.Errorf("action '%s' could infer version source but may be missing required parameters", step.Action)
-			case "require_system":
-				// require_system doesn't need version source - it detects version directly
+			case "require_system", "system_package":
+				// System dependencies don't need version source - it detects version directly
 				return nil
 			}
 		}
@@ -2,7 +2,10 @@ package version
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/Masterminds/semver/v3"
 )
 
 // normalizeVersion strips common version prefixes and formats
@@ -82,3 +85,64 @@ func CompareVersions(v1, v2 string) int {
 
 	return 0
 }
+
+// sortVersionInfos orders versions newest first. Stable releases sort ahead of
+// prereleases that share the same numeric parts (e.g., "2.0.0" before "2.0.0-rc1").
+func sortVersionInfos(infos []VersionInfo) {
+	sort.SliceStable(infos, func(i, j int) bool {
+		if c := CompareVersions(infos[i].Version, infos[j].Version); c != 0 {
+			return c > 0
+		}
+		return isStableVersion(infos[i].Version) && !isStableVersion(infos[j].Version)
+	})
+}
+
+// matchVersionInfo finds a requested version in infos (sorted newest first).
+// An exact version or tag match wins; otherwise the newest version with the
+// requested prefix is used (e.g., "1.2" matches "1.2.3" but not "1.20.0").
+func matchVersionInfo(infos []VersionInfo, version string) (*VersionInfo, bool) {
+	want := normalizeVersion(version)
+	for i, info := range infos {
+		if info.Version == want || info.Tag == version {
+			return &infos[i], true
+		}
+	}
+	for i, info := range infos {
+		if strings.HasPrefix(info.Version, want+".") {
+			return &infos[i], true
+		}
+	}
+	return nil, false
+}
+
+// isVersionRange reports whether spec is a version range (e.g., "^1.5", ">=1.2 <2")
+// rather than an exact or partial version
+func isVersionRange(spec string) bool {
+	return strings.ContainsAny(spec, "<>=~^*|, ") ||
+		strings.HasSuffix(spec, ".x") || strings.Contains(spec, ".x.")
+}
+
+// selectVersion picks the version matching spec from infos (sorted newest first).
+// Ranges select the newest satisfying version; anything else uses exact then fuzzy
+// matching. Returns nil if nothing matches, or an error if the range is malformed.
+func selectVersion(infos []VersionInfo, spec string) (*VersionInfo, error) {
+	if !isVersionRange(spec) {
+		info, _ := matchVersionInfo(infos, spec)
+		return info, nil
+	}
+
+	constraint, err := semver.NewConstraint(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version range %q: %w", spec, err)
+	}
+	for i, info := range infos {
+		v, err := semver.NewVersion(info.Version)
+		if err != nil {
+			continue
+		}
+		if constraint.Check(v) {
+			return &infos[i], nil
+		}
+	}
+	return nil, nil
+}